@@ -5,6 +5,7 @@ import (
 	"log"
 	"minireipaz/pkg/config"
 	"minireipaz/pkg/di"
+	"minireipaz/pkg/domain/repos"
 	"minireipaz/pkg/honeycomb"
 	"minireipaz/pkg/interfaces/middlewares"
 	"minireipaz/pkg/interfaces/routes"
@@ -14,7 +15,11 @@ import (
 )
 
 var (
-	app *gin.Engine
+	app       *gin.Engine
+	scheduler repos.SchedulerService
+	outbox    repos.OutboxService
+	refresher repos.CredentialRefreshService
+	purger    repos.TrashPurger
 )
 
 // Init initializes the application without starting the server.
@@ -46,8 +51,12 @@ func InitApp() {
 
 	// Dependency injection and routes setup
 	dependencies := di.InitDependencies()
-	middlewares.Register(app, dependencies.AuthService)
+	middlewares.Register(app, dependencies.AuthService, dependencies.IdempotencyService)
 	routes.Register(app, dependencies)
+	scheduler = dependencies.Scheduler
+	outbox = dependencies.Outbox
+	refresher = dependencies.CredentialRefresher
+	purger = dependencies.TrashPurger
 }
 
 // Handler is the main function that Vercel calls to handle HTTP requests.
@@ -65,6 +74,23 @@ func Dummy() {
 }
 
 func RunWebserver() {
+	// serverless handler only lives for a request, schedules are fired by the long running server
+	if config.GetEnv("SCHEDULER_ENABLED", "y") == "y" {
+		go scheduler.Start(context.Background())
+	}
+	// commands that could not be relayed right after their write wait in the outbox for this ticker
+	if config.GetEnv("OUTBOX_RELAY_ENABLED", "y") == "y" {
+		go outbox.Start(context.Background())
+	}
+	// access tokens are also refreshed on demand, this keeps them fresh for actions that do not ask
+	if config.GetEnv("CREDENTIAL_REFRESH_ENABLED", "y") == "y" {
+		go refresher.Start(context.Background())
+	}
+	// workflows in trash past the retention are purged here, listing the trash only hides them
+	if config.GetEnv("TRASH_PURGE_ENABLED", "y") == "y" {
+		go purger.StartTrashPurge(context.Background())
+	}
+
 	addr := config.GetEnv("BACKEND_ADDR", ":4020")
 	err := app.Run(addr)
 	if err != nil {
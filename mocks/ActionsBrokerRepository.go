@@ -31,6 +31,36 @@ func (_m *ActionsBrokerRepository) Create(newAction *models.RequestGoogleAction)
 	return r0
 }
 
+// CreateCommand provides a mock function with given fields: newAction
+func (_m *ActionsBrokerRepository) CreateCommand(newAction *models.RequestGoogleAction) (*models.OutboxMessage, error) {
+	ret := _m.Called(newAction)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateCommand")
+	}
+
+	var r0 *models.OutboxMessage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*models.RequestGoogleAction) (*models.OutboxMessage, error)); ok {
+		return rf(newAction)
+	}
+	if rf, ok := ret.Get(0).(func(*models.RequestGoogleAction) *models.OutboxMessage); ok {
+		r0 = rf(newAction)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.OutboxMessage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*models.RequestGoogleAction) error); ok {
+		r1 = rf(newAction)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewActionsBrokerRepository creates a new instance of ActionsBrokerRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewActionsBrokerRepository(t interface {
@@ -13,6 +13,36 @@ type ActionsHTTPRepository struct {
 	mock.Mock
 }
 
+// GetActionByID provides a mock function with given fields: userID, actionID
+func (_m *ActionsHTTPRepository) GetActionByID(userID *string, actionID *string) (*models.InfoActions, error) {
+	ret := _m.Called(userID, actionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetActionByID")
+	}
+
+	var r0 *models.InfoActions
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string, *string) (*models.InfoActions, error)); ok {
+		return rf(userID, actionID)
+	}
+	if rf, ok := ret.Get(0).(func(*string, *string) *models.InfoActions); ok {
+		r0 = rf(userID, actionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.InfoActions)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*string, *string) error); ok {
+		r1 = rf(userID, actionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // PublishCommand provides a mock function with given fields: data, serviceUser
 func (_m *ActionsHTTPRepository) PublishCommand(data *models.ActionsCommand, serviceUser *string) *models.ResponseGetGoogleSheetByID {
 	ret := _m.Called(data, serviceUser)
@@ -43,9 +43,9 @@ func (_m *ActionsRedisRepoInterface) AcquireLock(key string, value string, expir
 	return r0, r1
 }
 
-// Create provides a mock function with given fields: newAction
-func (_m *ActionsRedisRepoInterface) Create(newAction *models.RequestGoogleAction) (bool, bool, error) {
-	ret := _m.Called(newAction)
+// Create provides a mock function with given fields: newAction, message
+func (_m *ActionsRedisRepoInterface) Create(newAction *models.RequestGoogleAction, message *models.OutboxMessage) (bool, bool, error) {
+	ret := _m.Called(newAction, message)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Create")
@@ -54,23 +54,23 @@ func (_m *ActionsRedisRepoInterface) Create(newAction *models.RequestGoogleActio
 	var r0 bool
 	var r1 bool
 	var r2 error
-	if rf, ok := ret.Get(0).(func(*models.RequestGoogleAction) (bool, bool, error)); ok {
-		return rf(newAction)
+	if rf, ok := ret.Get(0).(func(*models.RequestGoogleAction, *models.OutboxMessage) (bool, bool, error)); ok {
+		return rf(newAction, message)
 	}
-	if rf, ok := ret.Get(0).(func(*models.RequestGoogleAction) bool); ok {
-		r0 = rf(newAction)
+	if rf, ok := ret.Get(0).(func(*models.RequestGoogleAction, *models.OutboxMessage) bool); ok {
+		r0 = rf(newAction, message)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
-	if rf, ok := ret.Get(1).(func(*models.RequestGoogleAction) bool); ok {
-		r1 = rf(newAction)
+	if rf, ok := ret.Get(1).(func(*models.RequestGoogleAction, *models.OutboxMessage) bool); ok {
+		r1 = rf(newAction, message)
 	} else {
 		r1 = ret.Get(1).(bool)
 	}
 
-	if rf, ok := ret.Get(2).(func(*models.RequestGoogleAction) error); ok {
-		r2 = rf(newAction)
+	if rf, ok := ret.Get(2).(func(*models.RequestGoogleAction, *models.OutboxMessage) error); ok {
+		r2 = rf(newAction, message)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -78,6 +78,34 @@ func (_m *ActionsRedisRepoInterface) Create(newAction *models.RequestGoogleActio
 	return r0, r1, r2
 }
 
+// GetActionOwner provides a mock function with given fields: actionID
+func (_m *ActionsRedisRepoInterface) GetActionOwner(actionID *string) (string, error) {
+	ret := _m.Called(actionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetActionOwner")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string) (string, error)); ok {
+		return rf(actionID)
+	}
+	if rf, ok := ret.Get(0).(func(*string) string); ok {
+		r0 = rf(actionID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(*string) error); ok {
+		r1 = rf(actionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Remove provides a mock function with given fields: newAction
 func (_m *ActionsRedisRepoInterface) Remove(newAction *models.RequestGoogleAction) bool {
 	ret := _m.Called(newAction)
@@ -3,9 +3,12 @@
 package mocks
 
 import (
+	context "context"
 	models "minireipaz/pkg/domain/models"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // ActionsService is an autogenerated mock type for the ActionsService type
@@ -87,6 +90,84 @@ func (_m *ActionsService) CreateActionsNotion(newAction models.RequestGoogleActi
 	return r0, r1, r2
 }
 
+// CreateScheduledAction provides a mock function with given fields: newAction
+func (_m *ActionsService) CreateScheduledAction(newAction *models.RequestGoogleAction) (*string, error) {
+	ret := _m.Called(newAction)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateScheduledAction")
+	}
+
+	var r0 *string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*models.RequestGoogleAction) (*string, error)); ok {
+		return rf(newAction)
+	}
+	if rf, ok := ret.Get(0).(func(*models.RequestGoogleAction) *string); ok {
+		r0 = rf(newAction)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*models.RequestGoogleAction) error); ok {
+		r1 = rf(newAction)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetActionResult provides a mock function with given fields: ctx, userID, actionID, wait
+func (_m *ActionsService) GetActionResult(ctx context.Context, userID *string, actionID *string, wait time.Duration) (*models.ActionResult, error) {
+	ret := _m.Called(ctx, userID, actionID, wait)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetActionResult")
+	}
+
+	var r0 *models.ActionResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *string, *string, time.Duration) (*models.ActionResult, error)); ok {
+		return rf(ctx, userID, actionID, wait)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *string, *string, time.Duration) *models.ActionResult); ok {
+		r0 = rf(ctx, userID, actionID, wait)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ActionResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *string, *string, time.Duration) error); ok {
+		r1 = rf(ctx, userID, actionID, wait)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReplayDeadLetter provides a mock function with given fields: letter
+func (_m *ActionsService) ReplayDeadLetter(letter *models.DeadLetter) error {
+	ret := _m.Called(letter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReplayDeadLetter")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.DeadLetter) error); ok {
+		r0 = rf(letter)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewActionsService creates a new instance of ActionsService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewActionsService(t interface {
@@ -0,0 +1,108 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "minireipaz/pkg/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// CredentialOAuthHTTPRepository is an autogenerated mock type for the CredentialOAuthHTTPRepository type
+type CredentialOAuthHTTPRepository struct {
+	mock.Mock
+}
+
+// ExchangeCode provides a mock function with given fields: provider, stateInfo, code
+func (_m *CredentialOAuthHTTPRepository) ExchangeCode(provider *models.OAuthProvider, stateInfo *models.RequestExchangeCredential, code string) (*models.OAuthToken, error) {
+	ret := _m.Called(provider, stateInfo, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExchangeCode")
+	}
+
+	var r0 *models.OAuthToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*models.OAuthProvider, *models.RequestExchangeCredential, string) (*models.OAuthToken, error)); ok {
+		return rf(provider, stateInfo, code)
+	}
+	if rf, ok := ret.Get(0).(func(*models.OAuthProvider, *models.RequestExchangeCredential, string) *models.OAuthToken); ok {
+		r0 = rf(provider, stateInfo, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.OAuthToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*models.OAuthProvider, *models.RequestExchangeCredential, string) error); ok {
+		r1 = rf(provider, stateInfo, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GenerateAuthURL provides a mock function with given fields: provider, credential, state
+func (_m *CredentialOAuthHTTPRepository) GenerateAuthURL(provider *models.OAuthProvider, credential *models.RequestExchangeCredential, state string) *string {
+	ret := _m.Called(provider, credential, state)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GenerateAuthURL")
+	}
+
+	var r0 *string
+	if rf, ok := ret.Get(0).(func(*models.OAuthProvider, *models.RequestExchangeCredential, string) *string); ok {
+		r0 = rf(provider, credential, state)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*string)
+		}
+	}
+
+	return r0
+}
+
+// RefreshToken provides a mock function with given fields: provider, credential
+func (_m *CredentialOAuthHTTPRepository) RefreshToken(provider *models.OAuthProvider, credential *models.RequestExchangeCredential) (*models.OAuthToken, error) {
+	ret := _m.Called(provider, credential)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RefreshToken")
+	}
+
+	var r0 *models.OAuthToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*models.OAuthProvider, *models.RequestExchangeCredential) (*models.OAuthToken, error)); ok {
+		return rf(provider, credential)
+	}
+	if rf, ok := ret.Get(0).(func(*models.OAuthProvider, *models.RequestExchangeCredential) *models.OAuthToken); ok {
+		r0 = rf(provider, credential)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.OAuthToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*models.OAuthProvider, *models.RequestExchangeCredential) error); ok {
+		r1 = rf(provider, credential)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewCredentialOAuthHTTPRepository creates a new instance of CredentialOAuthHTTPRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCredentialOAuthHTTPRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CredentialOAuthHTTPRepository {
+	mock := &CredentialOAuthHTTPRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
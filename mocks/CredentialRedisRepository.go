@@ -6,6 +6,8 @@ import (
 	models "minireipaz/pkg/domain/models"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // CredentialRedisRepository is an autogenerated mock type for the CredentialRedisRepository type
@@ -41,9 +43,39 @@ func (_m *CredentialRedisRepository) AddLock(sub *string) (bool, error) {
 	return r0, r1
 }
 
-// SaveTemporalAuthURLData provides a mock function with given fields: currentCredential
-func (_m *CredentialRedisRepository) SaveTemporalAuthURLData(currentCredential *models.RequestCreateCredential) (bool, error) {
-	ret := _m.Called(currentCredential)
+// PopTemporalAuthURLData provides a mock function with given fields: stateID
+func (_m *CredentialRedisRepository) PopTemporalAuthURLData(stateID *string) (*models.RequestExchangeCredential, error) {
+	ret := _m.Called(stateID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PopTemporalAuthURLData")
+	}
+
+	var r0 *models.RequestExchangeCredential
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string) (*models.RequestExchangeCredential, error)); ok {
+		return rf(stateID)
+	}
+	if rf, ok := ret.Get(0).(func(*string) *models.RequestExchangeCredential); ok {
+		r0 = rf(stateID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.RequestExchangeCredential)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*string) error); ok {
+		r1 = rf(stateID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveTemporalAuthURLData provides a mock function with given fields: stateID, stateInfo, ttl
+func (_m *CredentialRedisRepository) SaveTemporalAuthURLData(stateID *string, stateInfo *models.RequestExchangeCredential, ttl time.Duration) (bool, error) {
+	ret := _m.Called(stateID, stateInfo, ttl)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SaveTemporalAuthURLData")
@@ -51,17 +83,17 @@ func (_m *CredentialRedisRepository) SaveTemporalAuthURLData(currentCredential *
 
 	var r0 bool
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*models.RequestCreateCredential) (bool, error)); ok {
-		return rf(currentCredential)
+	if rf, ok := ret.Get(0).(func(*string, *models.RequestExchangeCredential, time.Duration) (bool, error)); ok {
+		return rf(stateID, stateInfo, ttl)
 	}
-	if rf, ok := ret.Get(0).(func(*models.RequestCreateCredential) bool); ok {
-		r0 = rf(currentCredential)
+	if rf, ok := ret.Get(0).(func(*string, *models.RequestExchangeCredential, time.Duration) bool); ok {
+		r0 = rf(stateID, stateInfo, ttl)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
-	if rf, ok := ret.Get(1).(func(*models.RequestCreateCredential) error); ok {
-		r1 = rf(currentCredential)
+	if rf, ok := ret.Get(1).(func(*string, *models.RequestExchangeCredential, time.Duration) error); ok {
+		r1 = rf(stateID, stateInfo, ttl)
 	} else {
 		r1 = ret.Error(1)
 	}
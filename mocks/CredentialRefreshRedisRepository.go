@@ -0,0 +1,188 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "minireipaz/pkg/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// CredentialRefreshRedisRepository is an autogenerated mock type for the CredentialRefreshRedisRepository type
+type CredentialRefreshRedisRepository struct {
+	mock.Mock
+}
+
+// AcquireLeadership provides a mock function with given fields: instanceID, ttl
+func (_m *CredentialRefreshRedisRepository) AcquireLeadership(instanceID string, ttl time.Duration) (bool, error) {
+	ret := _m.Called(instanceID, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AcquireLeadership")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, time.Duration) (bool, error)); ok {
+		return rf(instanceID, ttl)
+	}
+	if rf, ok := ret.Get(0).(func(string, time.Duration) bool); ok {
+		r0 = rf(instanceID, ttl)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, time.Duration) error); ok {
+		r1 = rf(instanceID, ttl)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDueRefreshes provides a mock function with given fields: before, limit
+func (_m *CredentialRefreshRedisRepository) GetDueRefreshes(before time.Time, limit int64) ([]models.CredentialRefreshEntry, error) {
+	ret := _m.Called(before, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDueRefreshes")
+	}
+
+	var r0 []models.CredentialRefreshEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time, int64) ([]models.CredentialRefreshEntry, error)); ok {
+		return rf(before, limit)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time, int64) []models.CredentialRefreshEntry); ok {
+		r0 = rf(before, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.CredentialRefreshEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time, int64) error); ok {
+		r1 = rf(before, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LockRefresh provides a mock function with given fields: credentialID, ttl
+func (_m *CredentialRefreshRedisRepository) LockRefresh(credentialID *string, ttl time.Duration) (bool, error) {
+	ret := _m.Called(credentialID, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LockRefresh")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string, time.Duration) (bool, error)); ok {
+		return rf(credentialID, ttl)
+	}
+	if rf, ok := ret.Get(0).(func(*string, time.Duration) bool); ok {
+		r0 = rf(credentialID, ttl)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(*string, time.Duration) error); ok {
+		r1 = rf(credentialID, ttl)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReleaseLeadership provides a mock function with given fields: instanceID
+func (_m *CredentialRefreshRedisRepository) ReleaseLeadership(instanceID string) error {
+	ret := _m.Called(instanceID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseLeadership")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(instanceID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveRefresh provides a mock function with given fields: entry
+func (_m *CredentialRefreshRedisRepository) RemoveRefresh(entry *models.CredentialRefreshEntry) error {
+	ret := _m.Called(entry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveRefresh")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.CredentialRefreshEntry) error); ok {
+		r0 = rf(entry)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ScheduleRefresh provides a mock function with given fields: entry, at
+func (_m *CredentialRefreshRedisRepository) ScheduleRefresh(entry *models.CredentialRefreshEntry, at time.Time) error {
+	ret := _m.Called(entry, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ScheduleRefresh")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.CredentialRefreshEntry, time.Time) error); ok {
+		r0 = rf(entry, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UnlockRefresh provides a mock function with given fields: credentialID
+func (_m *CredentialRefreshRedisRepository) UnlockRefresh(credentialID *string) error {
+	ret := _m.Called(credentialID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnlockRefresh")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*string) error); ok {
+		r0 = rf(credentialID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewCredentialRefreshRedisRepository creates a new instance of CredentialRefreshRedisRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCredentialRefreshRedisRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CredentialRefreshRedisRepository {
+	mock := &CredentialRefreshRedisRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
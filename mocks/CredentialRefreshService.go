@@ -0,0 +1,106 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "minireipaz/pkg/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// CredentialRefreshService is an autogenerated mock type for the CredentialRefreshService type
+type CredentialRefreshService struct {
+	mock.Mock
+}
+
+// RefreshCredential provides a mock function with given fields: userID, credentialID
+func (_m *CredentialRefreshService) RefreshCredential(userID *string, credentialID *string) (*models.RequestExchangeCredential, bool, error) {
+	ret := _m.Called(userID, credentialID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RefreshCredential")
+	}
+
+	var r0 *models.RequestExchangeCredential
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(*string, *string) (*models.RequestExchangeCredential, bool, error)); ok {
+		return rf(userID, credentialID)
+	}
+	if rf, ok := ret.Get(0).(func(*string, *string) *models.RequestExchangeCredential); ok {
+		r0 = rf(userID, credentialID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.RequestExchangeCredential)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*string, *string) bool); ok {
+		r1 = rf(userID, credentialID)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(*string, *string) error); ok {
+		r2 = rf(userID, credentialID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Schedule provides a mock function with given fields: credential
+func (_m *CredentialRefreshService) Schedule(credential *models.RequestExchangeCredential) {
+	_m.Called(credential)
+}
+
+// Start provides a mock function with given fields: ctx
+func (_m *CredentialRefreshService) Start(ctx context.Context) {
+	_m.Called(ctx)
+}
+
+// Tick provides a mock function with given fields: now
+func (_m *CredentialRefreshService) Tick(now time.Time) (int, error) {
+	ret := _m.Called(now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Tick")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time) (int, error)); ok {
+		return rf(now)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time) int); ok {
+		r0 = rf(now)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewCredentialRefreshService creates a new instance of CredentialRefreshService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCredentialRefreshService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CredentialRefreshService {
+	mock := &CredentialRefreshService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
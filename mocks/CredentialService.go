@@ -82,12 +82,12 @@ func (_m *CredentialService) CreateTokenCredential(credentialFrontend *models.Re
 	return r0, r1, r2
 }
 
-// ExchangeGoogleCredential provides a mock function with given fields: currentCredential
-func (_m *CredentialService) ExchangeGoogleCredential(currentCredential *models.RequestExchangeCredential) (*string, *string, *time.Time, *models.RequestExchangeCredential, error) {
+// ExchangeOAuthCredential provides a mock function with given fields: currentCredential
+func (_m *CredentialService) ExchangeOAuthCredential(currentCredential *models.RequestExchangeCredential) (*string, *string, *time.Time, *models.RequestExchangeCredential, error) {
 	ret := _m.Called(currentCredential)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ExchangeGoogleCredential")
+		panic("no return value specified for ExchangeOAuthCredential")
 	}
 
 	var r0 *string
@@ -189,6 +189,24 @@ func (_m *CredentialService) GetCredentialByID(userID *string, credentialID *str
 	return r0
 }
 
+// ReplayDeadLetter provides a mock function with given fields: letter
+func (_m *CredentialService) ReplayDeadLetter(letter *models.DeadLetter) error {
+	ret := _m.Called(letter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReplayDeadLetter")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.DeadLetter) error); ok {
+		r0 = rf(letter)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // TransformWorkflow provides a mock function with given fields: currenteCredential, workflow
 func (_m *CredentialService) TransformWorkflow(currenteCredential *models.RequestExchangeCredential, workflow *models.Workflow) *models.Workflow {
 	ret := _m.Called(currenteCredential, workflow)
@@ -0,0 +1,46 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "minireipaz/pkg/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// DeadLetterBrokerRepository is an autogenerated mock type for the DeadLetterBrokerRepository type
+type DeadLetterBrokerRepository struct {
+	mock.Mock
+}
+
+// Publish provides a mock function with given fields: letter
+func (_m *DeadLetterBrokerRepository) Publish(letter *models.DeadLetter) bool {
+	ret := _m.Called(letter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Publish")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(*models.DeadLetter) bool); ok {
+		r0 = rf(letter)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// NewDeadLetterBrokerRepository creates a new instance of DeadLetterBrokerRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDeadLetterBrokerRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DeadLetterBrokerRepository {
+	mock := &DeadLetterBrokerRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,134 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "minireipaz/pkg/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// DeadLetterRedisRepository is an autogenerated mock type for the DeadLetterRedisRepository type
+type DeadLetterRedisRepository struct {
+	mock.Mock
+}
+
+// Get provides a mock function with given fields: id
+func (_m *DeadLetterRedisRepository) Get(id *string) (*models.DeadLetter, error) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *models.DeadLetter
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string) (*models.DeadLetter, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(*string) *models.DeadLetter); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.DeadLetter)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAll provides a mock function with given fields: limit
+func (_m *DeadLetterRedisRepository) GetAll(limit int64) ([]models.DeadLetter, error) {
+	ret := _m.Called(limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAll")
+	}
+
+	var r0 []models.DeadLetter
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) ([]models.DeadLetter, error)); ok {
+		return rf(limit)
+	}
+	if rf, ok := ret.Get(0).(func(int64) []models.DeadLetter); ok {
+		r0 = rf(limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.DeadLetter)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Remove provides a mock function with given fields: id
+func (_m *DeadLetterRedisRepository) Remove(id *string) error {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Remove")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*string) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Save provides a mock function with given fields: letter
+func (_m *DeadLetterRedisRepository) Save(letter *models.DeadLetter) (string, error) {
+	ret := _m.Called(letter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*models.DeadLetter) (string, error)); ok {
+		return rf(letter)
+	}
+	if rf, ok := ret.Get(0).(func(*models.DeadLetter) string); ok {
+		r0 = rf(letter)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(*models.DeadLetter) error); ok {
+		r1 = rf(letter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewDeadLetterRedisRepository creates a new instance of DeadLetterRedisRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDeadLetterRedisRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DeadLetterRedisRepository {
+	mock := &DeadLetterRedisRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,46 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "minireipaz/pkg/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// DeadLetterReplayer is an autogenerated mock type for the DeadLetterReplayer type
+type DeadLetterReplayer struct {
+	mock.Mock
+}
+
+// ReplayDeadLetter provides a mock function with given fields: letter
+func (_m *DeadLetterReplayer) ReplayDeadLetter(letter *models.DeadLetter) error {
+	ret := _m.Called(letter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReplayDeadLetter")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.DeadLetter) error); ok {
+		r0 = rf(letter)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewDeadLetterReplayer creates a new instance of DeadLetterReplayer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDeadLetterReplayer(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DeadLetterReplayer {
+	mock := &DeadLetterReplayer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
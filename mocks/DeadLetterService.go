@@ -0,0 +1,148 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "minireipaz/pkg/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+
+	repos "minireipaz/pkg/domain/repos"
+)
+
+// DeadLetterService is an autogenerated mock type for the DeadLetterService type
+type DeadLetterService struct {
+	mock.Mock
+}
+
+// Add provides a mock function with given fields: origin, payload, cause, attempts
+func (_m *DeadLetterService) Add(origin string, payload interface{}, cause error, attempts int) {
+	_m.Called(origin, payload, cause, attempts)
+}
+
+// Discard provides a mock function with given fields: id
+func (_m *DeadLetterService) Discard(id *string) error {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Discard")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*string) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetDeadLetter provides a mock function with given fields: id
+func (_m *DeadLetterService) GetDeadLetter(id *string) (*models.DeadLetter, error) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeadLetter")
+	}
+
+	var r0 *models.DeadLetter
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string) (*models.DeadLetter, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(*string) *models.DeadLetter); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.DeadLetter)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDeadLetters provides a mock function with given fields: query
+func (_m *DeadLetterService) GetDeadLetters(query *models.DeadLetterQuery) ([]models.DeadLetter, error) {
+	ret := _m.Called(query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeadLetters")
+	}
+
+	var r0 []models.DeadLetter
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*models.DeadLetterQuery) ([]models.DeadLetter, error)); ok {
+		return rf(query)
+	}
+	if rf, ok := ret.Get(0).(func(*models.DeadLetterQuery) []models.DeadLetter); ok {
+		r0 = rf(query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.DeadLetter)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*models.DeadLetterQuery) error); ok {
+		r1 = rf(query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RegisterReplayer provides a mock function with given fields: origin, replayer
+func (_m *DeadLetterService) RegisterReplayer(origin string, replayer repos.DeadLetterReplayer) {
+	_m.Called(origin, replayer)
+}
+
+// Replay provides a mock function with given fields: id
+func (_m *DeadLetterService) Replay(id *string) (*models.DeadLetter, error) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Replay")
+	}
+
+	var r0 *models.DeadLetter
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string) (*models.DeadLetter, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(*string) *models.DeadLetter); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.DeadLetter)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewDeadLetterService creates a new instance of DeadLetterService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDeadLetterService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DeadLetterService {
+	mock := &DeadLetterService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,100 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// FolderRedisRepository is an autogenerated mock type for the FolderRedisRepository type
+type FolderRedisRepository struct {
+	mock.Mock
+}
+
+// GetAll provides a mock function with given fields: userID
+func (_m *FolderRedisRepository) GetAll(userID *string) ([]string, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAll")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string) ([]string, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(*string) []string); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*string) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Move provides a mock function with given fields: userID, from, to
+func (_m *FolderRedisRepository) Move(userID *string, from *string, to *string) error {
+	ret := _m.Called(userID, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Move")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*string, *string, *string) error); ok {
+		r0 = rf(userID, from, to)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Save provides a mock function with given fields: userID, path
+func (_m *FolderRedisRepository) Save(userID *string, path *string) (bool, error) {
+	ret := _m.Called(userID, path)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string, *string) (bool, error)); ok {
+		return rf(userID, path)
+	}
+	if rf, ok := ret.Get(0).(func(*string, *string) bool); ok {
+		r0 = rf(userID, path)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(*string, *string) error); ok {
+		r1 = rf(userID, path)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewFolderRedisRepository creates a new instance of FolderRedisRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewFolderRedisRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *FolderRedisRepository {
+	mock := &FolderRedisRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,176 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "minireipaz/pkg/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// FolderService is an autogenerated mock type for the FolderService type
+type FolderService struct {
+	mock.Mock
+}
+
+// CreateFolder provides a mock function with given fields: userID, path
+func (_m *FolderService) CreateFolder(userID *string, path *string) (string, error) {
+	ret := _m.Called(userID, path)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateFolder")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string, *string) (string, error)); ok {
+		return rf(userID, path)
+	}
+	if rf, ok := ret.Get(0).(func(*string, *string) string); ok {
+		r0 = rf(userID, path)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(*string, *string) error); ok {
+		r1 = rf(userID, path)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetFolderTree provides a mock function with given fields: userID
+func (_m *FolderService) GetFolderTree(userID *string) (*models.Folder, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFolderTree")
+	}
+
+	var r0 *models.Folder
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string) (*models.Folder, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(*string) *models.Folder); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Folder)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*string) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MoveFolder provides a mock function with given fields: request
+func (_m *FolderService) MoveFolder(request *models.RequestMoveFolder) (*models.FolderMoveReport, error) {
+	ret := _m.Called(request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MoveFolder")
+	}
+
+	var r0 *models.FolderMoveReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*models.RequestMoveFolder) (*models.FolderMoveReport, error)); ok {
+		return rf(request)
+	}
+	if rf, ok := ret.Get(0).(func(*models.RequestMoveFolder) *models.FolderMoveReport); ok {
+		r0 = rf(request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.FolderMoveReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*models.RequestMoveFolder) error); ok {
+		r1 = rf(request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MoveWorkflows provides a mock function with given fields: request
+func (_m *FolderService) MoveWorkflows(request *models.RequestMoveWorkflows) (*models.FolderMoveReport, error) {
+	ret := _m.Called(request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MoveWorkflows")
+	}
+
+	var r0 *models.FolderMoveReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*models.RequestMoveWorkflows) (*models.FolderMoveReport, error)); ok {
+		return rf(request)
+	}
+	if rf, ok := ret.Get(0).(func(*models.RequestMoveWorkflows) *models.FolderMoveReport); ok {
+		r0 = rf(request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.FolderMoveReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*models.RequestMoveWorkflows) error); ok {
+		r1 = rf(request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RenameFolder provides a mock function with given fields: request
+func (_m *FolderService) RenameFolder(request *models.RequestRenameFolder) (*models.FolderMoveReport, error) {
+	ret := _m.Called(request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RenameFolder")
+	}
+
+	var r0 *models.FolderMoveReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*models.RequestRenameFolder) (*models.FolderMoveReport, error)); ok {
+		return rf(request)
+	}
+	if rf, ok := ret.Get(0).(func(*models.RequestRenameFolder) *models.FolderMoveReport); ok {
+		r0 = rf(request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.FolderMoveReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*models.RequestRenameFolder) error); ok {
+		r1 = rf(request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewFolderService creates a new instance of FolderService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewFolderService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *FolderService {
+	mock := &FolderService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,103 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "minireipaz/pkg/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// IdempotencyRedisRepository is an autogenerated mock type for the IdempotencyRedisRepository type
+type IdempotencyRedisRepository struct {
+	mock.Mock
+}
+
+// Remove provides a mock function with given fields: key
+func (_m *IdempotencyRedisRepository) Remove(key string) error {
+	ret := _m.Called(key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Remove")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Reserve provides a mock function with given fields: key, record, ttl
+func (_m *IdempotencyRedisRepository) Reserve(key string, record *models.IdempotencyRecord, ttl time.Duration) (bool, *models.IdempotencyRecord, error) {
+	ret := _m.Called(key, record, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Reserve")
+	}
+
+	var r0 bool
+	var r1 *models.IdempotencyRecord
+	var r2 error
+	if rf, ok := ret.Get(0).(func(string, *models.IdempotencyRecord, time.Duration) (bool, *models.IdempotencyRecord, error)); ok {
+		return rf(key, record, ttl)
+	}
+	if rf, ok := ret.Get(0).(func(string, *models.IdempotencyRecord, time.Duration) bool); ok {
+		r0 = rf(key, record, ttl)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, *models.IdempotencyRecord, time.Duration) *models.IdempotencyRecord); ok {
+		r1 = rf(key, record, ttl)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*models.IdempotencyRecord)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(string, *models.IdempotencyRecord, time.Duration) error); ok {
+		r2 = rf(key, record, ttl)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Save provides a mock function with given fields: key, record, ttl
+func (_m *IdempotencyRedisRepository) Save(key string, record *models.IdempotencyRecord, ttl time.Duration) error {
+	ret := _m.Called(key, record, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, *models.IdempotencyRecord, time.Duration) error); ok {
+		r0 = rf(key, record, ttl)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewIdempotencyRedisRepository creates a new instance of IdempotencyRedisRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIdempotencyRedisRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *IdempotencyRedisRepository {
+	mock := &IdempotencyRedisRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,94 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "minireipaz/pkg/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// IdempotencyService is an autogenerated mock type for the IdempotencyService type
+type IdempotencyService struct {
+	mock.Mock
+}
+
+// Begin provides a mock function with given fields: scope, key, requestHash
+func (_m *IdempotencyService) Begin(scope string, key string, requestHash string) (*models.IdempotencyRecord, error) {
+	ret := _m.Called(scope, key, requestHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Begin")
+	}
+
+	var r0 *models.IdempotencyRecord
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, string) (*models.IdempotencyRecord, error)); ok {
+		return rf(scope, key, requestHash)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, string) *models.IdempotencyRecord); ok {
+		r0 = rf(scope, key, requestHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.IdempotencyRecord)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
+		r1 = rf(scope, key, requestHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Complete provides a mock function with given fields: scope, key, record
+func (_m *IdempotencyService) Complete(scope string, key string, record *models.IdempotencyRecord) error {
+	ret := _m.Called(scope, key, record)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Complete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, *models.IdempotencyRecord) error); ok {
+		r0 = rf(scope, key, record)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Release provides a mock function with given fields: scope, key
+func (_m *IdempotencyService) Release(scope string, key string) error {
+	ret := _m.Called(scope, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Release")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(scope, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewIdempotencyService creates a new instance of IdempotencyService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIdempotencyService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *IdempotencyService {
+	mock := &IdempotencyService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
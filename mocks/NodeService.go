@@ -0,0 +1,96 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "minireipaz/pkg/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NodeService is an autogenerated mock type for the NodeService type
+type NodeService struct {
+	mock.Mock
+}
+
+// GetCatalog provides a mock function with no fields
+func (_m *NodeService) GetCatalog() []models.NodeTypeDefinition {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCatalog")
+	}
+
+	var r0 []models.NodeTypeDefinition
+	if rf, ok := ret.Get(0).(func() []models.NodeTypeDefinition); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.NodeTypeDefinition)
+		}
+	}
+
+	return r0
+}
+
+// GetNodeType provides a mock function with given fields: nodeType
+func (_m *NodeService) GetNodeType(nodeType string) (models.NodeTypeDefinition, bool) {
+	ret := _m.Called(nodeType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetNodeType")
+	}
+
+	var r0 models.NodeTypeDefinition
+	var r1 bool
+	if rf, ok := ret.Get(0).(func(string) (models.NodeTypeDefinition, bool)); ok {
+		return rf(nodeType)
+	}
+	if rf, ok := ret.Get(0).(func(string) models.NodeTypeDefinition); ok {
+		r0 = rf(nodeType)
+	} else {
+		r0 = ret.Get(0).(models.NodeTypeDefinition)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) bool); ok {
+		r1 = rf(nodeType)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
+// ValidateNodes provides a mock function with given fields: workflow
+func (_m *NodeService) ValidateNodes(workflow *models.Workflow) []models.GraphValidationError {
+	ret := _m.Called(workflow)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ValidateNodes")
+	}
+
+	var r0 []models.GraphValidationError
+	if rf, ok := ret.Get(0).(func(*models.Workflow) []models.GraphValidationError); ok {
+		r0 = rf(workflow)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.GraphValidationError)
+		}
+	}
+
+	return r0
+}
+
+// NewNodeService creates a new instance of NodeService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewNodeService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *NodeService {
+	mock := &NodeService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
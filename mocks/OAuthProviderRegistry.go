@@ -0,0 +1,96 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "minireipaz/pkg/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// OAuthProviderRegistry is an autogenerated mock type for the OAuthProviderRegistry type
+type OAuthProviderRegistry struct {
+	mock.Mock
+}
+
+// Get provides a mock function with given fields: credentialType
+func (_m *OAuthProviderRegistry) Get(credentialType string) (*models.OAuthProvider, error) {
+	ret := _m.Called(credentialType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *models.OAuthProvider
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*models.OAuthProvider, error)); ok {
+		return rf(credentialType)
+	}
+	if rf, ok := ret.Get(0).(func(string) *models.OAuthProvider); ok {
+		r0 = rf(credentialType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.OAuthProvider)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(credentialType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAll provides a mock function with no fields
+func (_m *OAuthProviderRegistry) GetAll() []models.OAuthProvider {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAll")
+	}
+
+	var r0 []models.OAuthProvider
+	if rf, ok := ret.Get(0).(func() []models.OAuthProvider); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.OAuthProvider)
+		}
+	}
+
+	return r0
+}
+
+// Register provides a mock function with given fields: provider
+func (_m *OAuthProviderRegistry) Register(provider *models.OAuthProvider) error {
+	ret := _m.Called(provider)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Register")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.OAuthProvider) error); ok {
+		r0 = rf(provider)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewOAuthProviderRegistry creates a new instance of OAuthProviderRegistry. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOAuthProviderRegistry(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OAuthProviderRegistry {
+	mock := &OAuthProviderRegistry{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
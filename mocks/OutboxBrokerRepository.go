@@ -0,0 +1,46 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "minireipaz/pkg/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// OutboxBrokerRepository is an autogenerated mock type for the OutboxBrokerRepository type
+type OutboxBrokerRepository struct {
+	mock.Mock
+}
+
+// Publish provides a mock function with given fields: message
+func (_m *OutboxBrokerRepository) Publish(message *models.OutboxMessage) bool {
+	ret := _m.Called(message)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Publish")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(*models.OutboxMessage) bool); ok {
+		r0 = rf(message)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// NewOutboxBrokerRepository creates a new instance of OutboxBrokerRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOutboxBrokerRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OutboxBrokerRepository {
+	mock := &OutboxBrokerRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
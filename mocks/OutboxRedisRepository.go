@@ -0,0 +1,152 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "minireipaz/pkg/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// OutboxRedisRepository is an autogenerated mock type for the OutboxRedisRepository type
+type OutboxRedisRepository struct {
+	mock.Mock
+}
+
+// Ack provides a mock function with given fields: message
+func (_m *OutboxRedisRepository) Ack(message *models.OutboxMessage) error {
+	ret := _m.Called(message)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Ack")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.OutboxMessage) error); ok {
+		r0 = rf(message)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AcquireLeadership provides a mock function with given fields: instanceID, ttl
+func (_m *OutboxRedisRepository) AcquireLeadership(instanceID string, ttl time.Duration) (bool, error) {
+	ret := _m.Called(instanceID, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AcquireLeadership")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, time.Duration) (bool, error)); ok {
+		return rf(instanceID, ttl)
+	}
+	if rf, ok := ret.Get(0).(func(string, time.Duration) bool); ok {
+		r0 = rf(instanceID, ttl)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, time.Duration) error); ok {
+		r1 = rf(instanceID, ttl)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPending provides a mock function with given fields: before, limit
+func (_m *OutboxRedisRepository) GetPending(before time.Time, limit int64) ([]models.OutboxMessage, error) {
+	ret := _m.Called(before, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPending")
+	}
+
+	var r0 []models.OutboxMessage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time, int64) ([]models.OutboxMessage, error)); ok {
+		return rf(before, limit)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time, int64) []models.OutboxMessage); ok {
+		r0 = rf(before, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.OutboxMessage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time, int64) error); ok {
+		r1 = rf(before, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsPublished provides a mock function with given fields: dedupKey
+func (_m *OutboxRedisRepository) IsPublished(dedupKey *string) (bool, error) {
+	ret := _m.Called(dedupKey)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsPublished")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string) (bool, error)); ok {
+		return rf(dedupKey)
+	}
+	if rf, ok := ret.Get(0).(func(*string) bool); ok {
+		r0 = rf(dedupKey)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(*string) error); ok {
+		r1 = rf(dedupKey)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReleaseLeadership provides a mock function with given fields: instanceID
+func (_m *OutboxRedisRepository) ReleaseLeadership(instanceID string) error {
+	ret := _m.Called(instanceID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseLeadership")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(instanceID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewOutboxRedisRepository creates a new instance of OutboxRedisRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOutboxRedisRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OutboxRedisRepository {
+	mock := &OutboxRedisRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
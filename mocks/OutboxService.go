@@ -0,0 +1,91 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// OutboxService is an autogenerated mock type for the OutboxService type
+type OutboxService struct {
+	mock.Mock
+}
+
+// Relay provides a mock function with given fields: now
+func (_m *OutboxService) Relay(now time.Time) (int, error) {
+	ret := _m.Called(now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Relay")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time) (int, error)); ok {
+		return rf(now)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time) int); ok {
+		r0 = rf(now)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Start provides a mock function with given fields: ctx
+func (_m *OutboxService) Start(ctx context.Context) {
+	_m.Called(ctx)
+}
+
+// Tick provides a mock function with given fields: now
+func (_m *OutboxService) Tick(now time.Time) (int, error) {
+	ret := _m.Called(now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Tick")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time) (int, error)); ok {
+		return rf(now)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time) int); ok {
+		r0 = rf(now)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewOutboxService creates a new instance of OutboxService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOutboxService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OutboxService {
+	mock := &OutboxService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
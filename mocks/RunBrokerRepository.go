@@ -0,0 +1,58 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "minireipaz/pkg/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RunBrokerRepository is an autogenerated mock type for the RunBrokerRepository type
+type RunBrokerRepository struct {
+	mock.Mock
+}
+
+// CreateCommand provides a mock function with given fields: run, graph
+func (_m *RunBrokerRepository) CreateCommand(run *models.Run, graph []models.RunNode) (*models.OutboxMessage, error) {
+	ret := _m.Called(run, graph)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateCommand")
+	}
+
+	var r0 *models.OutboxMessage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*models.Run, []models.RunNode) (*models.OutboxMessage, error)); ok {
+		return rf(run, graph)
+	}
+	if rf, ok := ret.Get(0).(func(*models.Run, []models.RunNode) *models.OutboxMessage); ok {
+		r0 = rf(run, graph)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.OutboxMessage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*models.Run, []models.RunNode) error); ok {
+		r1 = rf(run, graph)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewRunBrokerRepository creates a new instance of RunBrokerRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRunBrokerRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RunBrokerRepository {
+	mock := &RunBrokerRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,118 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "minireipaz/pkg/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RunHTTPRepository is an autogenerated mock type for the RunHTTPRepository type
+type RunHTTPRepository struct {
+	mock.Mock
+}
+
+// GetRun provides a mock function with given fields: userID, runID
+func (_m *RunHTTPRepository) GetRun(userID *string, runID *string) (*models.InfoRuns, error) {
+	ret := _m.Called(userID, runID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRun")
+	}
+
+	var r0 *models.InfoRuns
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string, *string) (*models.InfoRuns, error)); ok {
+		return rf(userID, runID)
+	}
+	if rf, ok := ret.Get(0).(func(*string, *string) *models.InfoRuns); ok {
+		r0 = rf(userID, runID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.InfoRuns)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*string, *string) error); ok {
+		r1 = rf(userID, runID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRunSteps provides a mock function with given fields: userID, runID
+func (_m *RunHTTPRepository) GetRunSteps(userID *string, runID *string) (*models.InfoRunSteps, error) {
+	ret := _m.Called(userID, runID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRunSteps")
+	}
+
+	var r0 *models.InfoRunSteps
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string, *string) (*models.InfoRunSteps, error)); ok {
+		return rf(userID, runID)
+	}
+	if rf, ok := ret.Get(0).(func(*string, *string) *models.InfoRunSteps); ok {
+		r0 = rf(userID, runID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.InfoRunSteps)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*string, *string) error); ok {
+		r1 = rf(userID, runID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWorkflowRuns provides a mock function with given fields: userID, workflowID, cursor, limitCount
+func (_m *RunHTTPRepository) GetWorkflowRuns(userID *string, workflowID *string, cursor *models.WorkflowCursor, limitCount uint64) (*models.InfoRuns, error) {
+	ret := _m.Called(userID, workflowID, cursor, limitCount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWorkflowRuns")
+	}
+
+	var r0 *models.InfoRuns
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string, *string, *models.WorkflowCursor, uint64) (*models.InfoRuns, error)); ok {
+		return rf(userID, workflowID, cursor, limitCount)
+	}
+	if rf, ok := ret.Get(0).(func(*string, *string, *models.WorkflowCursor, uint64) *models.InfoRuns); ok {
+		r0 = rf(userID, workflowID, cursor, limitCount)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.InfoRuns)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*string, *string, *models.WorkflowCursor, uint64) error); ok {
+		r1 = rf(userID, workflowID, cursor, limitCount)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewRunHTTPRepository creates a new instance of RunHTTPRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRunHTTPRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RunHTTPRepository {
+	mock := &RunHTTPRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,76 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "minireipaz/pkg/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RunRedisRepository is an autogenerated mock type for the RunRedisRepository type
+type RunRedisRepository struct {
+	mock.Mock
+}
+
+// Get provides a mock function with given fields: runID
+func (_m *RunRedisRepository) Get(runID *string) (*models.Run, error) {
+	ret := _m.Called(runID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *models.Run
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string) (*models.Run, error)); ok {
+		return rf(runID)
+	}
+	if rf, ok := ret.Get(0).(func(*string) *models.Run); ok {
+		r0 = rf(runID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Run)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*string) error); ok {
+		r1 = rf(runID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Save provides a mock function with given fields: run, message
+func (_m *RunRedisRepository) Save(run *models.Run, message *models.OutboxMessage) error {
+	ret := _m.Called(run, message)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.Run, *models.OutboxMessage) error); ok {
+		r0 = rf(run, message)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewRunRedisRepository creates a new instance of RunRedisRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRunRedisRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RunRedisRepository {
+	mock := &RunRedisRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
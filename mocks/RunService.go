@@ -0,0 +1,127 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "minireipaz/pkg/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RunService is an autogenerated mock type for the RunService type
+type RunService struct {
+	mock.Mock
+}
+
+// CreateRun provides a mock function with given fields: userID, workflowID, dryRun
+func (_m *RunService) CreateRun(userID *string, workflowID *string, dryRun bool) (*models.Run, []models.GraphValidationError, error) {
+	ret := _m.Called(userID, workflowID, dryRun)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateRun")
+	}
+
+	var r0 *models.Run
+	var r1 []models.GraphValidationError
+	var r2 error
+	if rf, ok := ret.Get(0).(func(*string, *string, bool) (*models.Run, []models.GraphValidationError, error)); ok {
+		return rf(userID, workflowID, dryRun)
+	}
+	if rf, ok := ret.Get(0).(func(*string, *string, bool) *models.Run); ok {
+		r0 = rf(userID, workflowID, dryRun)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Run)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*string, *string, bool) []models.GraphValidationError); ok {
+		r1 = rf(userID, workflowID, dryRun)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]models.GraphValidationError)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(*string, *string, bool) error); ok {
+		r2 = rf(userID, workflowID, dryRun)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetRun provides a mock function with given fields: userID, runID
+func (_m *RunService) GetRun(userID *string, runID *string) (*models.RunDetail, error) {
+	ret := _m.Called(userID, runID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRun")
+	}
+
+	var r0 *models.RunDetail
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string, *string) (*models.RunDetail, error)); ok {
+		return rf(userID, runID)
+	}
+	if rf, ok := ret.Get(0).(func(*string, *string) *models.RunDetail); ok {
+		r0 = rf(userID, runID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.RunDetail)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*string, *string) error); ok {
+		r1 = rf(userID, runID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWorkflowRuns provides a mock function with given fields: query
+func (_m *RunService) GetWorkflowRuns(query *models.RunListQuery) (*models.RunPage, error) {
+	ret := _m.Called(query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWorkflowRuns")
+	}
+
+	var r0 *models.RunPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*models.RunListQuery) (*models.RunPage, error)); ok {
+		return rf(query)
+	}
+	if rf, ok := ret.Get(0).(func(*models.RunListQuery) *models.RunPage); ok {
+		r0 = rf(query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.RunPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*models.RunListQuery) error); ok {
+		r1 = rf(query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewRunService creates a new instance of RunService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRunService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RunService {
+	mock := &RunService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
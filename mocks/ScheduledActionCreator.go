@@ -0,0 +1,58 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "minireipaz/pkg/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ScheduledActionCreator is an autogenerated mock type for the ScheduledActionCreator type
+type ScheduledActionCreator struct {
+	mock.Mock
+}
+
+// CreateScheduledAction provides a mock function with given fields: newAction
+func (_m *ScheduledActionCreator) CreateScheduledAction(newAction *models.RequestGoogleAction) (*string, error) {
+	ret := _m.Called(newAction)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateScheduledAction")
+	}
+
+	var r0 *string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*models.RequestGoogleAction) (*string, error)); ok {
+		return rf(newAction)
+	}
+	if rf, ok := ret.Get(0).(func(*models.RequestGoogleAction) *string); ok {
+		r0 = rf(newAction)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*models.RequestGoogleAction) error); ok {
+		r1 = rf(newAction)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewScheduledActionCreator creates a new instance of ScheduledActionCreator. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewScheduledActionCreator(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ScheduledActionCreator {
+	mock := &ScheduledActionCreator{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
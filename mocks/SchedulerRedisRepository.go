@@ -0,0 +1,248 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "minireipaz/pkg/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// SchedulerRedisRepository is an autogenerated mock type for the SchedulerRedisRepository type
+type SchedulerRedisRepository struct {
+	mock.Mock
+}
+
+// AcquireLeadership provides a mock function with given fields: instanceID, ttl
+func (_m *SchedulerRedisRepository) AcquireLeadership(instanceID string, ttl time.Duration) (bool, error) {
+	ret := _m.Called(instanceID, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AcquireLeadership")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, time.Duration) (bool, error)); ok {
+		return rf(instanceID, ttl)
+	}
+	if rf, ok := ret.Get(0).(func(string, time.Duration) bool); ok {
+		r0 = rf(instanceID, ttl)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, time.Duration) error); ok {
+		r1 = rf(instanceID, ttl)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Advance provides a mock function with given fields: schedule, expectedNextRunAt
+func (_m *SchedulerRedisRepository) Advance(schedule *models.Schedule, expectedNextRunAt string) (bool, error) {
+	ret := _m.Called(schedule, expectedNextRunAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Advance")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*models.Schedule, string) (bool, error)); ok {
+		return rf(schedule, expectedNextRunAt)
+	}
+	if rf, ok := ret.Get(0).(func(*models.Schedule, string) bool); ok {
+		r0 = rf(schedule, expectedNextRunAt)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(*models.Schedule, string) error); ok {
+		r1 = rf(schedule, expectedNextRunAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Get provides a mock function with given fields: workflowID, nodeID
+func (_m *SchedulerRedisRepository) Get(workflowID *string, nodeID *string) (*models.Schedule, error) {
+	ret := _m.Called(workflowID, nodeID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *models.Schedule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string, *string) (*models.Schedule, error)); ok {
+		return rf(workflowID, nodeID)
+	}
+	if rf, ok := ret.Get(0).(func(*string, *string) *models.Schedule); ok {
+		r0 = rf(workflowID, nodeID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Schedule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*string, *string) error); ok {
+		r1 = rf(workflowID, nodeID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByWorkflow provides a mock function with given fields: workflowID
+func (_m *SchedulerRedisRepository) GetByWorkflow(workflowID *string) ([]models.Schedule, error) {
+	ret := _m.Called(workflowID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByWorkflow")
+	}
+
+	var r0 []models.Schedule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string) ([]models.Schedule, error)); ok {
+		return rf(workflowID)
+	}
+	if rf, ok := ret.Get(0).(func(*string) []models.Schedule); ok {
+		r0 = rf(workflowID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Schedule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*string) error); ok {
+		r1 = rf(workflowID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDue provides a mock function with given fields: now, limit
+func (_m *SchedulerRedisRepository) GetDue(now time.Time, limit int64) ([]models.Schedule, error) {
+	ret := _m.Called(now, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDue")
+	}
+
+	var r0 []models.Schedule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time, int64) ([]models.Schedule, error)); ok {
+		return rf(now, limit)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time, int64) []models.Schedule); ok {
+		r0 = rf(now, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Schedule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time, int64) error); ok {
+		r1 = rf(now, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReleaseLeadership provides a mock function with given fields: instanceID
+func (_m *SchedulerRedisRepository) ReleaseLeadership(instanceID string) error {
+	ret := _m.Called(instanceID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseLeadership")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(instanceID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Remove provides a mock function with given fields: workflowID, nodeID
+func (_m *SchedulerRedisRepository) Remove(workflowID *string, nodeID *string) error {
+	ret := _m.Called(workflowID, nodeID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Remove")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*string, *string) error); ok {
+		r0 = rf(workflowID, nodeID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveByWorkflow provides a mock function with given fields: workflowID
+func (_m *SchedulerRedisRepository) RemoveByWorkflow(workflowID *string) error {
+	ret := _m.Called(workflowID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveByWorkflow")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*string) error); ok {
+		r0 = rf(workflowID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Save provides a mock function with given fields: schedule
+func (_m *SchedulerRedisRepository) Save(schedule *models.Schedule) error {
+	ret := _m.Called(schedule)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.Schedule) error); ok {
+		r0 = rf(schedule)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewSchedulerRedisRepository creates a new instance of SchedulerRedisRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSchedulerRedisRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SchedulerRedisRepository {
+	mock := &SchedulerRedisRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
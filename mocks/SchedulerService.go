@@ -0,0 +1,243 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "minireipaz/pkg/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+
+	repos "minireipaz/pkg/domain/repos"
+
+	time "time"
+)
+
+// SchedulerService is an autogenerated mock type for the SchedulerService type
+type SchedulerService struct {
+	mock.Mock
+}
+
+// GetSchedules provides a mock function with given fields: userID, workflowID
+func (_m *SchedulerService) GetSchedules(userID *string, workflowID *string) ([]models.Schedule, error) {
+	ret := _m.Called(userID, workflowID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSchedules")
+	}
+
+	var r0 []models.Schedule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string, *string) ([]models.Schedule, error)); ok {
+		return rf(userID, workflowID)
+	}
+	if rf, ok := ret.Get(0).(func(*string, *string) []models.Schedule); ok {
+		r0 = rf(userID, workflowID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Schedule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*string, *string) error); ok {
+		r1 = rf(userID, workflowID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RegisterCreator provides a mock function with given fields: creator
+func (_m *SchedulerService) RegisterCreator(creator repos.ScheduledActionCreator) {
+	_m.Called(creator)
+}
+
+// RemoveSchedule provides a mock function with given fields: userID, workflowID, nodeID
+func (_m *SchedulerService) RemoveSchedule(userID *string, workflowID *string, nodeID *string) error {
+	ret := _m.Called(userID, workflowID, nodeID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveSchedule")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*string, *string, *string) error); ok {
+		r0 = rf(userID, workflowID, nodeID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveTriggers provides a mock function with given fields: workflowID
+func (_m *SchedulerService) RemoveTriggers(workflowID *string) error {
+	ret := _m.Called(workflowID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveTriggers")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*string) error); ok {
+		r0 = rf(workflowID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RunDue provides a mock function with given fields: now
+func (_m *SchedulerService) RunDue(now time.Time) (int, error) {
+	ret := _m.Called(now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RunDue")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time) (int, error)); ok {
+		return rf(now)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time) int); ok {
+		r0 = rf(now)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveSchedule provides a mock function with given fields: request
+func (_m *SchedulerService) SaveSchedule(request *models.RequestCreateSchedule) (*models.Schedule, error) {
+	ret := _m.Called(request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveSchedule")
+	}
+
+	var r0 *models.Schedule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*models.RequestCreateSchedule) (*models.Schedule, error)); ok {
+		return rf(request)
+	}
+	if rf, ok := ret.Get(0).(func(*models.RequestCreateSchedule) *models.Schedule); ok {
+		r0 = rf(request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Schedule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*models.RequestCreateSchedule) error); ok {
+		r1 = rf(request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ScheduleAction provides a mock function with given fields: action
+func (_m *SchedulerService) ScheduleAction(action *models.RequestGoogleAction) (*models.Schedule, error) {
+	ret := _m.Called(action)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ScheduleAction")
+	}
+
+	var r0 *models.Schedule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*models.RequestGoogleAction) (*models.Schedule, error)); ok {
+		return rf(action)
+	}
+	if rf, ok := ret.Get(0).(func(*models.RequestGoogleAction) *models.Schedule); ok {
+		r0 = rf(action)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Schedule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*models.RequestGoogleAction) error); ok {
+		r1 = rf(action)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Start provides a mock function with given fields: ctx
+func (_m *SchedulerService) Start(ctx context.Context) {
+	_m.Called(ctx)
+}
+
+// SyncTriggers provides a mock function with given fields: workflowID, active
+func (_m *SchedulerService) SyncTriggers(workflowID *string, active bool) error {
+	ret := _m.Called(workflowID, active)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SyncTriggers")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*string, bool) error); ok {
+		r0 = rf(workflowID, active)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Tick provides a mock function with given fields: now
+func (_m *SchedulerService) Tick(now time.Time) (int, error) {
+	ret := _m.Called(now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Tick")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time) (int, error)); ok {
+		return rf(now)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time) int); ok {
+		r0 = rf(now)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewSchedulerService creates a new instance of SchedulerService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSchedulerService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SchedulerService {
+	mock := &SchedulerService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
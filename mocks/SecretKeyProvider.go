@@ -0,0 +1,102 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// SecretKeyProvider is an autogenerated mock type for the SecretKeyProvider type
+type SecretKeyProvider struct {
+	mock.Mock
+}
+
+// CurrentKeyID provides a mock function with no fields
+func (_m *SecretKeyProvider) CurrentKeyID() string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for CurrentKeyID")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// UnwrapKey provides a mock function with given fields: keyID, wrapped
+func (_m *SecretKeyProvider) UnwrapKey(keyID string, wrapped []byte) ([]byte, error) {
+	ret := _m.Called(keyID, wrapped)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnwrapKey")
+	}
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, []byte) ([]byte, error)); ok {
+		return rf(keyID, wrapped)
+	}
+	if rf, ok := ret.Get(0).(func(string, []byte) []byte); ok {
+		r0 = rf(keyID, wrapped)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, []byte) error); ok {
+		r1 = rf(keyID, wrapped)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WrapKey provides a mock function with given fields: keyID, dataKey
+func (_m *SecretKeyProvider) WrapKey(keyID string, dataKey []byte) ([]byte, error) {
+	ret := _m.Called(keyID, dataKey)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WrapKey")
+	}
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, []byte) ([]byte, error)); ok {
+		return rf(keyID, dataKey)
+	}
+	if rf, ok := ret.Get(0).(func(string, []byte) []byte); ok {
+		r0 = rf(keyID, dataKey)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, []byte) error); ok {
+		r1 = rf(keyID, dataKey)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewSecretKeyProvider creates a new instance of SecretKeyProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSecretKeyProvider(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SecretKeyProvider {
+	mock := &SecretKeyProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
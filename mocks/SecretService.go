@@ -0,0 +1,120 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "minireipaz/pkg/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// SecretService is an autogenerated mock type for the SecretService type
+type SecretService struct {
+	mock.Mock
+}
+
+// Decrypt provides a mock function with given fields: ciphertext
+func (_m *SecretService) Decrypt(ciphertext string) (string, error) {
+	ret := _m.Called(ciphertext)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Decrypt")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (string, error)); ok {
+		return rf(ciphertext)
+	}
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(ciphertext)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(ciphertext)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DecryptCredential provides a mock function with given fields: data
+func (_m *SecretService) DecryptCredential(data *models.DataCredential) error {
+	ret := _m.Called(data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DecryptCredential")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.DataCredential) error); ok {
+		r0 = rf(data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Encrypt provides a mock function with given fields: plaintext
+func (_m *SecretService) Encrypt(plaintext string) (string, error) {
+	ret := _m.Called(plaintext)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Encrypt")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (string, error)); ok {
+		return rf(plaintext)
+	}
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(plaintext)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(plaintext)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// EncryptCredential provides a mock function with given fields: data
+func (_m *SecretService) EncryptCredential(data *models.DataCredential) error {
+	ret := _m.Called(data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EncryptCredential")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.DataCredential) error); ok {
+		r0 = rf(data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewSecretService creates a new instance of SecretService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSecretService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SecretService {
+	mock := &SecretService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
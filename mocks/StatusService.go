@@ -0,0 +1,59 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "minireipaz/pkg/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// StatusService is an autogenerated mock type for the StatusService type
+type StatusService struct {
+	mock.Mock
+}
+
+// Subscribe provides a mock function with given fields: ctx, userID
+func (_m *StatusService) Subscribe(ctx context.Context, userID *string) (<-chan models.StatusEvent, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Subscribe")
+	}
+
+	var r0 <-chan models.StatusEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *string) (<-chan models.StatusEvent, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *string) <-chan models.StatusEvent); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan models.StatusEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewStatusService creates a new instance of StatusService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStatusService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *StatusService {
+	mock := &StatusService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,144 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "minireipaz/pkg/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TemplateRedisRepository is an autogenerated mock type for the TemplateRedisRepository type
+type TemplateRedisRepository struct {
+	mock.Mock
+}
+
+// Get provides a mock function with given fields: templateID
+func (_m *TemplateRedisRepository) Get(templateID *string) (*models.WorkflowTemplate, error) {
+	ret := _m.Called(templateID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *models.WorkflowTemplate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string) (*models.WorkflowTemplate, error)); ok {
+		return rf(templateID)
+	}
+	if rf, ok := ret.Get(0).(func(*string) *models.WorkflowTemplate); ok {
+		r0 = rf(templateID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.WorkflowTemplate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*string) error); ok {
+		r1 = rf(templateID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAll provides a mock function with no fields
+func (_m *TemplateRedisRepository) GetAll() ([]models.WorkflowTemplate, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAll")
+	}
+
+	var r0 []models.WorkflowTemplate
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]models.WorkflowTemplate, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []models.WorkflowTemplate); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.WorkflowTemplate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Remove provides a mock function with given fields: templateID
+func (_m *TemplateRedisRepository) Remove(templateID *string) (bool, error) {
+	ret := _m.Called(templateID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Remove")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string) (bool, error)); ok {
+		return rf(templateID)
+	}
+	if rf, ok := ret.Get(0).(func(*string) bool); ok {
+		r0 = rf(templateID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(*string) error); ok {
+		r1 = rf(templateID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Save provides a mock function with given fields: template
+func (_m *TemplateRedisRepository) Save(template *models.WorkflowTemplate) (bool, error) {
+	ret := _m.Called(template)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*models.WorkflowTemplate) (bool, error)); ok {
+		return rf(template)
+	}
+	if rf, ok := ret.Get(0).(func(*models.WorkflowTemplate) bool); ok {
+		r0 = rf(template)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(*models.WorkflowTemplate) error); ok {
+		r1 = rf(template)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewTemplateRedisRepository creates a new instance of TemplateRedisRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTemplateRedisRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TemplateRedisRepository {
+	mock := &TemplateRedisRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,175 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "minireipaz/pkg/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TemplateService is an autogenerated mock type for the TemplateService type
+type TemplateService struct {
+	mock.Mock
+}
+
+// CreateWorkflowFromTemplate provides a mock function with given fields: templateID, request
+func (_m *TemplateService) CreateWorkflowFromTemplate(templateID *string, request *models.RequestCreateFromTemplate) (*models.ImportedWorkflow, []models.GraphValidationError, error) {
+	ret := _m.Called(templateID, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateWorkflowFromTemplate")
+	}
+
+	var r0 *models.ImportedWorkflow
+	var r1 []models.GraphValidationError
+	var r2 error
+	if rf, ok := ret.Get(0).(func(*string, *models.RequestCreateFromTemplate) (*models.ImportedWorkflow, []models.GraphValidationError, error)); ok {
+		return rf(templateID, request)
+	}
+	if rf, ok := ret.Get(0).(func(*string, *models.RequestCreateFromTemplate) *models.ImportedWorkflow); ok {
+		r0 = rf(templateID, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ImportedWorkflow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*string, *models.RequestCreateFromTemplate) []models.GraphValidationError); ok {
+		r1 = rf(templateID, request)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]models.GraphValidationError)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(*string, *models.RequestCreateFromTemplate) error); ok {
+		r2 = rf(templateID, request)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// DeleteTemplate provides a mock function with given fields: userID, templateID
+func (_m *TemplateService) DeleteTemplate(userID *string, templateID *string) error {
+	ret := _m.Called(userID, templateID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteTemplate")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*string, *string) error); ok {
+		r0 = rf(userID, templateID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetTemplate provides a mock function with given fields: templateID
+func (_m *TemplateService) GetTemplate(templateID *string) (*models.WorkflowTemplate, error) {
+	ret := _m.Called(templateID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTemplate")
+	}
+
+	var r0 *models.WorkflowTemplate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string) (*models.WorkflowTemplate, error)); ok {
+		return rf(templateID)
+	}
+	if rf, ok := ret.Get(0).(func(*string) *models.WorkflowTemplate); ok {
+		r0 = rf(templateID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.WorkflowTemplate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*string) error); ok {
+		r1 = rf(templateID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTemplates provides a mock function with given fields: category
+func (_m *TemplateService) GetTemplates(category string) ([]models.WorkflowTemplate, error) {
+	ret := _m.Called(category)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTemplates")
+	}
+
+	var r0 []models.WorkflowTemplate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]models.WorkflowTemplate, error)); ok {
+		return rf(category)
+	}
+	if rf, ok := ret.Get(0).(func(string) []models.WorkflowTemplate); ok {
+		r0 = rf(category)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.WorkflowTemplate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(category)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PublishTemplate provides a mock function with given fields: request
+func (_m *TemplateService) PublishTemplate(request *models.RequestPublishTemplate) (*models.WorkflowTemplate, error) {
+	ret := _m.Called(request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublishTemplate")
+	}
+
+	var r0 *models.WorkflowTemplate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*models.RequestPublishTemplate) (*models.WorkflowTemplate, error)); ok {
+		return rf(request)
+	}
+	if rf, ok := ret.Get(0).(func(*models.RequestPublishTemplate) *models.WorkflowTemplate); ok {
+		r0 = rf(request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.WorkflowTemplate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*models.RequestPublishTemplate) error); ok {
+		r1 = rf(request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewTemplateService creates a new instance of TemplateService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTemplateService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TemplateService {
+	mock := &TemplateService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
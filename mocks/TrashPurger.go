@@ -0,0 +1,63 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// TrashPurger is an autogenerated mock type for the TrashPurger type
+type TrashPurger struct {
+	mock.Mock
+}
+
+// PurgeExpiredTrash provides a mock function with given fields: now
+func (_m *TrashPurger) PurgeExpiredTrash(now time.Time) (int, error) {
+	ret := _m.Called(now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeExpiredTrash")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time) (int, error)); ok {
+		return rf(now)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time) int); ok {
+		r0 = rf(now)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StartTrashPurge provides a mock function with given fields: ctx
+func (_m *TrashPurger) StartTrashPurge(ctx context.Context) {
+	_m.Called(ctx)
+}
+
+// NewTrashPurger creates a new instance of TrashPurger. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTrashPurger(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TrashPurger {
+	mock := &TrashPurger{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -13,6 +13,24 @@ type UserService struct {
 	mock.Mock
 }
 
+// ReplayDeadLetter provides a mock function with given fields: letter
+func (_m *UserService) ReplayDeadLetter(letter *models.DeadLetter) error {
+	ret := _m.Called(letter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReplayDeadLetter")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.DeadLetter) error); ok {
+		r0 = rf(letter)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SynUser provides a mock function with given fields: user
 func (_m *UserService) SynUser(user *models.SyncUserRequest) (bool, bool) {
 	ret := _m.Called(user)
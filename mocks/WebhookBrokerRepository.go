@@ -0,0 +1,46 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "minireipaz/pkg/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// WebhookBrokerRepository is an autogenerated mock type for the WebhookBrokerRepository type
+type WebhookBrokerRepository struct {
+	mock.Mock
+}
+
+// PublishTrigger provides a mock function with given fields: event
+func (_m *WebhookBrokerRepository) PublishTrigger(event *models.TriggerEvent) bool {
+	ret := _m.Called(event)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublishTrigger")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(*models.TriggerEvent) bool); ok {
+		r0 = rf(event)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// NewWebhookBrokerRepository creates a new instance of WebhookBrokerRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWebhookBrokerRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *WebhookBrokerRepository {
+	mock := &WebhookBrokerRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
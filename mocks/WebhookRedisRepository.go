@@ -0,0 +1,172 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "minireipaz/pkg/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// WebhookRedisRepository is an autogenerated mock type for the WebhookRedisRepository type
+type WebhookRedisRepository struct {
+	mock.Mock
+}
+
+// GetByToken provides a mock function with given fields: token
+func (_m *WebhookRedisRepository) GetByToken(token *string) (*models.Webhook, error) {
+	ret := _m.Called(token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByToken")
+	}
+
+	var r0 *models.Webhook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string) (*models.Webhook, error)); ok {
+		return rf(token)
+	}
+	if rf, ok := ret.Get(0).(func(*string) *models.Webhook); ok {
+		r0 = rf(token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Webhook)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*string) error); ok {
+		r1 = rf(token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByWorkflow provides a mock function with given fields: workflowID
+func (_m *WebhookRedisRepository) GetByWorkflow(workflowID *string) ([]models.Webhook, error) {
+	ret := _m.Called(workflowID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByWorkflow")
+	}
+
+	var r0 []models.Webhook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string) ([]models.Webhook, error)); ok {
+		return rf(workflowID)
+	}
+	if rf, ok := ret.Get(0).(func(*string) []models.Webhook); ok {
+		r0 = rf(workflowID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Webhook)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*string) error); ok {
+		r1 = rf(workflowID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Remove provides a mock function with given fields: workflowID, nodeID
+func (_m *WebhookRedisRepository) Remove(workflowID *string, nodeID *string) error {
+	ret := _m.Called(workflowID, nodeID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Remove")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*string, *string) error); ok {
+		r0 = rf(workflowID, nodeID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveByWorkflow provides a mock function with given fields: workflowID
+func (_m *WebhookRedisRepository) RemoveByWorkflow(workflowID *string) error {
+	ret := _m.Called(workflowID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveByWorkflow")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*string) error); ok {
+		r0 = rf(workflowID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Save provides a mock function with given fields: webhook
+func (_m *WebhookRedisRepository) Save(webhook *models.Webhook) error {
+	ret := _m.Called(webhook)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.Webhook) error); ok {
+		r0 = rf(webhook)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveNonce provides a mock function with given fields: token, nonce, ttl
+func (_m *WebhookRedisRepository) SaveNonce(token *string, nonce *string, ttl time.Duration) (bool, error) {
+	ret := _m.Called(token, nonce, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveNonce")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string, *string, time.Duration) (bool, error)); ok {
+		return rf(token, nonce, ttl)
+	}
+	if rf, ok := ret.Get(0).(func(*string, *string, time.Duration) bool); ok {
+		r0 = rf(token, nonce, ttl)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(*string, *string, time.Duration) error); ok {
+		r1 = rf(token, nonce, ttl)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewWebhookRedisRepository creates a new instance of WebhookRedisRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWebhookRedisRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *WebhookRedisRepository {
+	mock := &WebhookRedisRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,172 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "minireipaz/pkg/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// WebhookService is an autogenerated mock type for the WebhookService type
+type WebhookService struct {
+	mock.Mock
+}
+
+// CreateWebhook provides a mock function with given fields: request
+func (_m *WebhookService) CreateWebhook(request *models.RequestCreateWebhook) (*models.Webhook, error) {
+	ret := _m.Called(request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateWebhook")
+	}
+
+	var r0 *models.Webhook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*models.RequestCreateWebhook) (*models.Webhook, error)); ok {
+		return rf(request)
+	}
+	if rf, ok := ret.Get(0).(func(*models.RequestCreateWebhook) *models.Webhook); ok {
+		r0 = rf(request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Webhook)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*models.RequestCreateWebhook) error); ok {
+		r1 = rf(request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWebhooks provides a mock function with given fields: userID, workflowID
+func (_m *WebhookService) GetWebhooks(userID *string, workflowID *string) ([]models.Webhook, error) {
+	ret := _m.Called(userID, workflowID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWebhooks")
+	}
+
+	var r0 []models.Webhook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string, *string) ([]models.Webhook, error)); ok {
+		return rf(userID, workflowID)
+	}
+	if rf, ok := ret.Get(0).(func(*string, *string) []models.Webhook); ok {
+		r0 = rf(userID, workflowID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Webhook)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*string, *string) error); ok {
+		r1 = rf(userID, workflowID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReceiveWebhook provides a mock function with given fields: token, body, contentType, signature, timestamp
+func (_m *WebhookService) ReceiveWebhook(token *string, body []byte, contentType string, signature string, timestamp string) (*models.TriggerEvent, error) {
+	ret := _m.Called(token, body, contentType, signature, timestamp)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReceiveWebhook")
+	}
+
+	var r0 *models.TriggerEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string, []byte, string, string, string) (*models.TriggerEvent, error)); ok {
+		return rf(token, body, contentType, signature, timestamp)
+	}
+	if rf, ok := ret.Get(0).(func(*string, []byte, string, string, string) *models.TriggerEvent); ok {
+		r0 = rf(token, body, contentType, signature, timestamp)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.TriggerEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*string, []byte, string, string, string) error); ok {
+		r1 = rf(token, body, contentType, signature, timestamp)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveTriggers provides a mock function with given fields: workflowID
+func (_m *WebhookService) RemoveTriggers(workflowID *string) error {
+	ret := _m.Called(workflowID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveTriggers")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*string) error); ok {
+		r0 = rf(workflowID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveWebhook provides a mock function with given fields: userID, workflowID, nodeID
+func (_m *WebhookService) RemoveWebhook(userID *string, workflowID *string, nodeID *string) error {
+	ret := _m.Called(userID, workflowID, nodeID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveWebhook")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*string, *string, *string) error); ok {
+		r0 = rf(userID, workflowID, nodeID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SyncTriggers provides a mock function with given fields: workflowID, active
+func (_m *WebhookService) SyncTriggers(workflowID *string, active bool) error {
+	ret := _m.Called(workflowID, active)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SyncTriggers")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*string, bool) error); ok {
+		r0 = rf(workflowID, active)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewWebhookService creates a new instance of WebhookService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWebhookService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *WebhookService {
+	mock := &WebhookService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
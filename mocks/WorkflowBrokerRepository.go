@@ -13,40 +13,34 @@ type WorkflowBrokerRepository struct {
 	mock.Mock
 }
 
-// Create provides a mock function with given fields: workflow
-func (_m *WorkflowBrokerRepository) Create(workflow *models.Workflow) bool {
-	ret := _m.Called(workflow)
+// CreateCommand provides a mock function with given fields: workflow, commandType
+func (_m *WorkflowBrokerRepository) CreateCommand(workflow *models.Workflow, commandType string) (*models.OutboxMessage, error) {
+	ret := _m.Called(workflow, commandType)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Create")
+		panic("no return value specified for CreateCommand")
 	}
 
-	var r0 bool
-	if rf, ok := ret.Get(0).(func(*models.Workflow) bool); ok {
-		r0 = rf(workflow)
-	} else {
-		r0 = ret.Get(0).(bool)
+	var r0 *models.OutboxMessage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*models.Workflow, string) (*models.OutboxMessage, error)); ok {
+		return rf(workflow, commandType)
 	}
-
-	return r0
-}
-
-// Update provides a mock function with given fields: workflow
-func (_m *WorkflowBrokerRepository) Update(workflow *models.Workflow) bool {
-	ret := _m.Called(workflow)
-
-	if len(ret) == 0 {
-		panic("no return value specified for Update")
+	if rf, ok := ret.Get(0).(func(*models.Workflow, string) *models.OutboxMessage); ok {
+		r0 = rf(workflow, commandType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.OutboxMessage)
+		}
 	}
 
-	var r0 bool
-	if rf, ok := ret.Get(0).(func(*models.Workflow) bool); ok {
-		r0 = rf(workflow)
+	if rf, ok := ret.Get(1).(func(*models.Workflow, string) error); ok {
+		r1 = rf(workflow, commandType)
 	} else {
-		r0 = ret.Get(0).(bool)
+		r1 = ret.Error(1)
 	}
 
-	return r0
+	return r0, r1
 }
 
 // NewWorkflowBrokerRepository creates a new instance of WorkflowBrokerRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
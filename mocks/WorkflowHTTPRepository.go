@@ -73,6 +73,96 @@ func (_m *WorkflowHTTPRepository) GetWorkflowDataByID(userID *string, workflowID
 	return r0, r1
 }
 
+// GetWorkflowRevision provides a mock function with given fields: userID, workflowID, version
+func (_m *WorkflowHTTPRepository) GetWorkflowRevision(userID *string, workflowID *string, version uint32) (*models.InfoWorkflow, error) {
+	ret := _m.Called(userID, workflowID, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWorkflowRevision")
+	}
+
+	var r0 *models.InfoWorkflow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string, *string, uint32) (*models.InfoWorkflow, error)); ok {
+		return rf(userID, workflowID, version)
+	}
+	if rf, ok := ret.Get(0).(func(*string, *string, uint32) *models.InfoWorkflow); ok {
+		r0 = rf(userID, workflowID, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.InfoWorkflow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*string, *string, uint32) error); ok {
+		r1 = rf(userID, workflowID, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWorkflowRevisions provides a mock function with given fields: userID, workflowID, limitCount
+func (_m *WorkflowHTTPRepository) GetWorkflowRevisions(userID *string, workflowID *string, limitCount uint64) (*models.InfoWorkflow, error) {
+	ret := _m.Called(userID, workflowID, limitCount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWorkflowRevisions")
+	}
+
+	var r0 *models.InfoWorkflow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string, *string, uint64) (*models.InfoWorkflow, error)); ok {
+		return rf(userID, workflowID, limitCount)
+	}
+	if rf, ok := ret.Get(0).(func(*string, *string, uint64) *models.InfoWorkflow); ok {
+		r0 = rf(userID, workflowID, limitCount)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.InfoWorkflow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*string, *string, uint64) error); ok {
+		r1 = rf(userID, workflowID, limitCount)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWorkflowsPage provides a mock function with given fields: query, cursor, limitCount
+func (_m *WorkflowHTTPRepository) GetWorkflowsPage(query *models.WorkflowListQuery, cursor *models.WorkflowCursor, limitCount uint64) (*models.InfoWorkflow, error) {
+	ret := _m.Called(query, cursor, limitCount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWorkflowsPage")
+	}
+
+	var r0 *models.InfoWorkflow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*models.WorkflowListQuery, *models.WorkflowCursor, uint64) (*models.InfoWorkflow, error)); ok {
+		return rf(query, cursor, limitCount)
+	}
+	if rf, ok := ret.Get(0).(func(*models.WorkflowListQuery, *models.WorkflowCursor, uint64) *models.InfoWorkflow); ok {
+		r0 = rf(query, cursor, limitCount)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.InfoWorkflow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*models.WorkflowListQuery, *models.WorkflowCursor, uint64) error); ok {
+		r1 = rf(query, cursor, limitCount)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewWorkflowHTTPRepository creates a new instance of WorkflowHTTPRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewWorkflowHTTPRepository(t interface {
@@ -45,9 +45,62 @@ func (_m *WorkflowRedisRepoInterface) AcquireLock(key string, value string, expi
 	return r0, r1
 }
 
-// Create provides a mock function with given fields: workflow
-func (_m *WorkflowRedisRepoInterface) Create(workflow *models.Workflow) (bool, bool) {
-	ret := _m.Called(workflow)
+// ApplyUserIndex provides a mock function with given fields: change
+func (_m *WorkflowRedisRepoInterface) ApplyUserIndex(change *models.UserIndexChange) error {
+	ret := _m.Called(change)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ApplyUserIndex")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.UserIndexChange) error); ok {
+		r0 = rf(change)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CompareAndIncrVersion provides a mock function with given fields: workflowID, expected
+func (_m *WorkflowRedisRepoInterface) CompareAndIncrVersion(workflowID *string, expected uint32) (uint32, bool, error) {
+	ret := _m.Called(workflowID, expected)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompareAndIncrVersion")
+	}
+
+	var r0 uint32
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(*string, uint32) (uint32, bool, error)); ok {
+		return rf(workflowID, expected)
+	}
+	if rf, ok := ret.Get(0).(func(*string, uint32) uint32); ok {
+		r0 = rf(workflowID, expected)
+	} else {
+		r0 = ret.Get(0).(uint32)
+	}
+
+	if rf, ok := ret.Get(1).(func(*string, uint32) bool); ok {
+		r1 = rf(workflowID, expected)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(*string, uint32) error); ok {
+		r2 = rf(workflowID, expected)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Create provides a mock function with given fields: workflow, message
+func (_m *WorkflowRedisRepoInterface) Create(workflow *models.Workflow, message *models.OutboxMessage) (bool, bool) {
+	ret := _m.Called(workflow, message)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Create")
@@ -55,17 +108,17 @@ func (_m *WorkflowRedisRepoInterface) Create(workflow *models.Workflow) (bool, b
 
 	var r0 bool
 	var r1 bool
-	if rf, ok := ret.Get(0).(func(*models.Workflow) (bool, bool)); ok {
-		return rf(workflow)
+	if rf, ok := ret.Get(0).(func(*models.Workflow, *models.OutboxMessage) (bool, bool)); ok {
+		return rf(workflow, message)
 	}
-	if rf, ok := ret.Get(0).(func(*models.Workflow) bool); ok {
-		r0 = rf(workflow)
+	if rf, ok := ret.Get(0).(func(*models.Workflow, *models.OutboxMessage) bool); ok {
+		r0 = rf(workflow, message)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
-	if rf, ok := ret.Get(1).(func(*models.Workflow) bool); ok {
-		r1 = rf(workflow)
+	if rf, ok := ret.Get(1).(func(*models.Workflow, *models.OutboxMessage) bool); ok {
+		r1 = rf(workflow, message)
 	} else {
 		r1 = ret.Get(1).(bool)
 	}
@@ -103,6 +156,296 @@ func (_m *WorkflowRedisRepoInterface) GetByUUID(id uuid.UUID) (*models.Workflow,
 	return r0, r1
 }
 
+// GetIndexedUsers provides a mock function with no fields
+func (_m *WorkflowRedisRepoInterface) GetIndexedUsers() ([]string, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetIndexedUsers")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]string, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLifecycle provides a mock function with given fields: workflowID
+func (_m *WorkflowRedisRepoInterface) GetLifecycle(workflowID *string) (models.IsActive, error) {
+	ret := _m.Called(workflowID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLifecycle")
+	}
+
+	var r0 models.IsActive
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string) (models.IsActive, error)); ok {
+		return rf(workflowID)
+	}
+	if rf, ok := ret.Get(0).(func(*string) models.IsActive); ok {
+		r0 = rf(workflowID)
+	} else {
+		r0 = ret.Get(0).(models.IsActive)
+	}
+
+	if rf, ok := ret.Get(1).(func(*string) error); ok {
+		r1 = rf(workflowID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTrash provides a mock function with given fields: userID
+func (_m *WorkflowRedisRepoInterface) GetTrash(userID *string) ([]models.TrashedWorkflow, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTrash")
+	}
+
+	var r0 []models.TrashedWorkflow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string) ([]models.TrashedWorkflow, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(*string) []models.TrashedWorkflow); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.TrashedWorkflow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*string) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTrashUsers provides a mock function with no fields
+func (_m *WorkflowRedisRepoInterface) GetTrashUsers() ([]string, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTrashUsers")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]string, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserIndex provides a mock function with given fields: userID
+func (_m *WorkflowRedisRepoInterface) GetUserIndex(userID *string) (map[string]string, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserIndex")
+	}
+
+	var r0 map[string]string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string) (map[string]string, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(*string) map[string]string); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*string) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetVersion provides a mock function with given fields: workflowID
+func (_m *WorkflowRedisRepoInterface) GetVersion(workflowID *string) (uint32, error) {
+	ret := _m.Called(workflowID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetVersion")
+	}
+
+	var r0 uint32
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string) (uint32, error)); ok {
+		return rf(workflowID)
+	}
+	if rf, ok := ret.Get(0).(func(*string) uint32); ok {
+		r0 = rf(workflowID)
+	} else {
+		r0 = ret.Get(0).(uint32)
+	}
+
+	if rf, ok := ret.Get(1).(func(*string) error); ok {
+		r1 = rf(workflowID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWorkflowsIndex provides a mock function with no fields
+func (_m *WorkflowRedisRepoInterface) GetWorkflowsIndex() (map[string]string, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWorkflowsIndex")
+	}
+
+	var r0 map[string]string
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (map[string]string, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() map[string]string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MoveToTrash provides a mock function with given fields: trashed, message
+func (_m *WorkflowRedisRepoInterface) MoveToTrash(trashed *models.TrashedWorkflow, message *models.OutboxMessage) (bool, error) {
+	ret := _m.Called(trashed, message)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MoveToTrash")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*models.TrashedWorkflow, *models.OutboxMessage) (bool, error)); ok {
+		return rf(trashed, message)
+	}
+	if rf, ok := ret.Get(0).(func(*models.TrashedWorkflow, *models.OutboxMessage) bool); ok {
+		r0 = rf(trashed, message)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(*models.TrashedWorkflow, *models.OutboxMessage) error); ok {
+		r1 = rf(trashed, message)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NameExists provides a mock function with given fields: userID, name
+func (_m *WorkflowRedisRepoInterface) NameExists(userID *string, name *string) (bool, error) {
+	ret := _m.Called(userID, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NameExists")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string, *string) (bool, error)); ok {
+		return rf(userID, name)
+	}
+	if rf, ok := ret.Get(0).(func(*string, *string) bool); ok {
+		r0 = rf(userID, name)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(*string, *string) error); ok {
+		r1 = rf(userID, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NextVersion provides a mock function with given fields: workflowID
+func (_m *WorkflowRedisRepoInterface) NextVersion(workflowID *string) (uint32, error) {
+	ret := _m.Called(workflowID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NextVersion")
+	}
+
+	var r0 uint32
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string) (uint32, error)); ok {
+		return rf(workflowID)
+	}
+	if rf, ok := ret.Get(0).(func(*string) uint32); ok {
+		r0 = rf(workflowID)
+	} else {
+		r0 = ret.Get(0).(uint32)
+	}
+
+	if rf, ok := ret.Get(1).(func(*string) error); ok {
+		r1 = rf(workflowID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Remove provides a mock function with given fields: workflow
 func (_m *WorkflowRedisRepoInterface) Remove(workflow *models.Workflow) bool {
 	ret := _m.Called(workflow)
@@ -121,6 +464,34 @@ func (_m *WorkflowRedisRepoInterface) Remove(workflow *models.Workflow) bool {
 	return r0
 }
 
+// RemoveFromTrash provides a mock function with given fields: userID, workflowID, message
+func (_m *WorkflowRedisRepoInterface) RemoveFromTrash(userID *string, workflowID *string, message *models.OutboxMessage) (bool, error) {
+	ret := _m.Called(userID, workflowID, message)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveFromTrash")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string, *string, *models.OutboxMessage) (bool, error)); ok {
+		return rf(userID, workflowID, message)
+	}
+	if rf, ok := ret.Get(0).(func(*string, *string, *models.OutboxMessage) bool); ok {
+		r0 = rf(userID, workflowID, message)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(*string, *string, *models.OutboxMessage) error); ok {
+		r1 = rf(userID, workflowID, message)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // RemoveLock provides a mock function with given fields: key
 func (_m *WorkflowRedisRepoInterface) RemoveLock(key string) bool {
 	ret := _m.Called(key)
@@ -139,6 +510,130 @@ func (_m *WorkflowRedisRepoInterface) RemoveLock(key string) bool {
 	return r0
 }
 
+// RenameInIndex provides a mock function with given fields: workflow, message
+func (_m *WorkflowRedisRepoInterface) RenameInIndex(workflow *models.Workflow, message *models.OutboxMessage) (string, error) {
+	ret := _m.Called(workflow, message)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RenameInIndex")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*models.Workflow, *models.OutboxMessage) (string, error)); ok {
+		return rf(workflow, message)
+	}
+	if rf, ok := ret.Get(0).(func(*models.Workflow, *models.OutboxMessage) string); ok {
+		r0 = rf(workflow, message)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(*models.Workflow, *models.OutboxMessage) error); ok {
+		r1 = rf(workflow, message)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RestoreFromTrash provides a mock function with given fields: userID, workflowID, message
+func (_m *WorkflowRedisRepoInterface) RestoreFromTrash(userID *string, workflowID *string, message *models.OutboxMessage) (*models.TrashedWorkflow, error) {
+	ret := _m.Called(userID, workflowID, message)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreFromTrash")
+	}
+
+	var r0 *models.TrashedWorkflow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string, *string, *models.OutboxMessage) (*models.TrashedWorkflow, error)); ok {
+		return rf(userID, workflowID, message)
+	}
+	if rf, ok := ret.Get(0).(func(*string, *string, *models.OutboxMessage) *models.TrashedWorkflow); ok {
+		r0 = rf(userID, workflowID, message)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.TrashedWorkflow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*string, *string, *models.OutboxMessage) error); ok {
+		r1 = rf(userID, workflowID, message)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevertVersion provides a mock function with given fields: workflowID, version
+func (_m *WorkflowRedisRepoInterface) RevertVersion(workflowID *string, version uint32) bool {
+	ret := _m.Called(workflowID, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevertVersion")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(*string, uint32) bool); ok {
+		r0 = rf(workflowID, version)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// SetLifecycle provides a mock function with given fields: workflowID, state, message
+func (_m *WorkflowRedisRepoInterface) SetLifecycle(workflowID *string, state models.IsActive, message *models.OutboxMessage) error {
+	ret := _m.Called(workflowID, state, message)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetLifecycle")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*string, models.IsActive, *models.OutboxMessage) error); ok {
+		r0 = rf(workflowID, state, message)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TrackMissing provides a mock function with given fields: userID, workflowIDs, now
+func (_m *WorkflowRedisRepoInterface) TrackMissing(userID *string, workflowIDs []string, now time.Time) (map[string]time.Time, error) {
+	ret := _m.Called(userID, workflowIDs, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TrackMissing")
+	}
+
+	var r0 map[string]time.Time
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string, []string, time.Time) (map[string]time.Time, error)); ok {
+		return rf(userID, workflowIDs, now)
+	}
+	if rf, ok := ret.Get(0).(func(*string, []string, time.Time) map[string]time.Time); ok {
+		r0 = rf(userID, workflowIDs, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]time.Time)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*string, []string, time.Time) error); ok {
+		r1 = rf(userID, workflowIDs, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: worflow
 func (_m *WorkflowRedisRepoInterface) Update(worflow *models.Workflow) (bool, bool) {
 	ret := _m.Called(worflow)
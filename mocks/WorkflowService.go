@@ -3,9 +3,14 @@
 package mocks
 
 import (
+	context "context"
 	models "minireipaz/pkg/domain/models"
 
 	mock "github.com/stretchr/testify/mock"
+
+	repos "minireipaz/pkg/domain/repos"
+
+	time "time"
 )
 
 // WorkflowService is an autogenerated mock type for the WorkflowService type
@@ -13,6 +18,45 @@ type WorkflowService struct {
 	mock.Mock
 }
 
+// ChangeLifecycle provides a mock function with given fields: userID, workflowID, transition
+func (_m *WorkflowService) ChangeLifecycle(userID *string, workflowID *string, transition string) (*models.Workflow, []models.GraphValidationError, error) {
+	ret := _m.Called(userID, workflowID, transition)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangeLifecycle")
+	}
+
+	var r0 *models.Workflow
+	var r1 []models.GraphValidationError
+	var r2 error
+	if rf, ok := ret.Get(0).(func(*string, *string, string) (*models.Workflow, []models.GraphValidationError, error)); ok {
+		return rf(userID, workflowID, transition)
+	}
+	if rf, ok := ret.Get(0).(func(*string, *string, string) *models.Workflow); ok {
+		r0 = rf(userID, workflowID, transition)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Workflow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*string, *string, string) []models.GraphValidationError); ok {
+		r1 = rf(userID, workflowID, transition)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]models.GraphValidationError)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(*string, *string, string) error); ok {
+		r2 = rf(userID, workflowID, transition)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // CreateWorkflow provides a mock function with given fields: workflowFrontend
 func (_m *WorkflowService) CreateWorkflow(workflowFrontend *models.WorkflowFrontend) (bool, bool, *models.Workflow) {
 	ret := _m.Called(workflowFrontend)
@@ -50,6 +94,138 @@ func (_m *WorkflowService) CreateWorkflow(workflowFrontend *models.WorkflowFront
 	return r0, r1, r2
 }
 
+// DeleteWorkflow provides a mock function with given fields: userID, workflowID
+func (_m *WorkflowService) DeleteWorkflow(userID *string, workflowID *string) (bool, bool) {
+	ret := _m.Called(userID, workflowID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteWorkflow")
+	}
+
+	var r0 bool
+	var r1 bool
+	if rf, ok := ret.Get(0).(func(*string, *string) (bool, bool)); ok {
+		return rf(userID, workflowID)
+	}
+	if rf, ok := ret.Get(0).(func(*string, *string) bool); ok {
+		r0 = rf(userID, workflowID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(*string, *string) bool); ok {
+		r1 = rf(userID, workflowID)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
+// DiffWorkflowRevisions provides a mock function with given fields: userID, workflowID, fromVersion, toVersion
+func (_m *WorkflowService) DiffWorkflowRevisions(userID *string, workflowID *string, fromVersion uint32, toVersion uint32) (*models.WorkflowDiff, error) {
+	ret := _m.Called(userID, workflowID, fromVersion, toVersion)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DiffWorkflowRevisions")
+	}
+
+	var r0 *models.WorkflowDiff
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string, *string, uint32, uint32) (*models.WorkflowDiff, error)); ok {
+		return rf(userID, workflowID, fromVersion, toVersion)
+	}
+	if rf, ok := ret.Get(0).(func(*string, *string, uint32, uint32) *models.WorkflowDiff); ok {
+		r0 = rf(userID, workflowID, fromVersion, toVersion)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.WorkflowDiff)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*string, *string, uint32, uint32) error); ok {
+		r1 = rf(userID, workflowID, fromVersion, toVersion)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DuplicateWorkflow provides a mock function with given fields: userID, workflowID, keepCredentials
+func (_m *WorkflowService) DuplicateWorkflow(userID *string, workflowID *string, keepCredentials bool) (bool, bool, *models.Workflow, error) {
+	ret := _m.Called(userID, workflowID, keepCredentials)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DuplicateWorkflow")
+	}
+
+	var r0 bool
+	var r1 bool
+	var r2 *models.Workflow
+	var r3 error
+	if rf, ok := ret.Get(0).(func(*string, *string, bool) (bool, bool, *models.Workflow, error)); ok {
+		return rf(userID, workflowID, keepCredentials)
+	}
+	if rf, ok := ret.Get(0).(func(*string, *string, bool) bool); ok {
+		r0 = rf(userID, workflowID, keepCredentials)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(*string, *string, bool) bool); ok {
+		r1 = rf(userID, workflowID, keepCredentials)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(*string, *string, bool) *models.Workflow); ok {
+		r2 = rf(userID, workflowID, keepCredentials)
+	} else {
+		if ret.Get(2) != nil {
+			r2 = ret.Get(2).(*models.Workflow)
+		}
+	}
+
+	if rf, ok := ret.Get(3).(func(*string, *string, bool) error); ok {
+		r3 = rf(userID, workflowID, keepCredentials)
+	} else {
+		r3 = ret.Error(3)
+	}
+
+	return r0, r1, r2, r3
+}
+
+// ExportWorkflow provides a mock function with given fields: userID, workflowID
+func (_m *WorkflowService) ExportWorkflow(userID *string, workflowID *string) (*models.WorkflowDocument, bool) {
+	ret := _m.Called(userID, workflowID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportWorkflow")
+	}
+
+	var r0 *models.WorkflowDocument
+	var r1 bool
+	if rf, ok := ret.Get(0).(func(*string, *string) (*models.WorkflowDocument, bool)); ok {
+		return rf(userID, workflowID)
+	}
+	if rf, ok := ret.Get(0).(func(*string, *string) *models.WorkflowDocument); ok {
+		r0 = rf(userID, workflowID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.WorkflowDocument)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*string, *string) bool); ok {
+		r1 = rf(userID, workflowID)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
 // GetAllWorkflows provides a mock function with given fields: userID
 func (_m *WorkflowService) GetAllWorkflows(userID *string) ([]models.Workflow, error) {
 	ret := _m.Called(userID)
@@ -110,8 +286,372 @@ func (_m *WorkflowService) GetWorkflow(userID *string, workflowID *string) (*mod
 	return r0, r1
 }
 
+// GetWorkflowRevision provides a mock function with given fields: userID, workflowID, version
+func (_m *WorkflowService) GetWorkflowRevision(userID *string, workflowID *string, version uint32) (*models.Workflow, error) {
+	ret := _m.Called(userID, workflowID, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWorkflowRevision")
+	}
+
+	var r0 *models.Workflow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string, *string, uint32) (*models.Workflow, error)); ok {
+		return rf(userID, workflowID, version)
+	}
+	if rf, ok := ret.Get(0).(func(*string, *string, uint32) *models.Workflow); ok {
+		r0 = rf(userID, workflowID, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Workflow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*string, *string, uint32) error); ok {
+		r1 = rf(userID, workflowID, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWorkflowRevisions provides a mock function with given fields: userID, workflowID
+func (_m *WorkflowService) GetWorkflowRevisions(userID *string, workflowID *string) ([]models.Workflow, error) {
+	ret := _m.Called(userID, workflowID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWorkflowRevisions")
+	}
+
+	var r0 []models.Workflow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string, *string) ([]models.Workflow, error)); ok {
+		return rf(userID, workflowID)
+	}
+	if rf, ok := ret.Get(0).(func(*string, *string) []models.Workflow); ok {
+		r0 = rf(userID, workflowID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Workflow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*string, *string) error); ok {
+		r1 = rf(userID, workflowID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWorkflowsTrash provides a mock function with given fields: userID
+func (_m *WorkflowService) GetWorkflowsTrash(userID *string) ([]models.TrashedWorkflow, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWorkflowsTrash")
+	}
+
+	var r0 []models.TrashedWorkflow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string) ([]models.TrashedWorkflow, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(*string) []models.TrashedWorkflow); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.TrashedWorkflow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*string) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ImportWorkflow provides a mock function with given fields: userID, directoryToSave, document
+func (_m *WorkflowService) ImportWorkflow(userID *string, directoryToSave *string, document *models.WorkflowDocument) (*models.ImportedWorkflow, []models.GraphValidationError, error) {
+	ret := _m.Called(userID, directoryToSave, document)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ImportWorkflow")
+	}
+
+	var r0 *models.ImportedWorkflow
+	var r1 []models.GraphValidationError
+	var r2 error
+	if rf, ok := ret.Get(0).(func(*string, *string, *models.WorkflowDocument) (*models.ImportedWorkflow, []models.GraphValidationError, error)); ok {
+		return rf(userID, directoryToSave, document)
+	}
+	if rf, ok := ret.Get(0).(func(*string, *string, *models.WorkflowDocument) *models.ImportedWorkflow); ok {
+		r0 = rf(userID, directoryToSave, document)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ImportedWorkflow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*string, *string, *models.WorkflowDocument) []models.GraphValidationError); ok {
+		r1 = rf(userID, directoryToSave, document)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]models.GraphValidationError)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(*string, *string, *models.WorkflowDocument) error); ok {
+		r2 = rf(userID, directoryToSave, document)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// IsWorkflowActive provides a mock function with given fields: workflowID
+func (_m *WorkflowService) IsWorkflowActive(workflowID *string) (bool, error) {
+	ret := _m.Called(workflowID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsWorkflowActive")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string) (bool, error)); ok {
+		return rf(workflowID)
+	}
+	if rf, ok := ret.Get(0).(func(*string) bool); ok {
+		r0 = rf(workflowID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(*string) error); ok {
+		r1 = rf(workflowID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListWorkflows provides a mock function with given fields: query
+func (_m *WorkflowService) ListWorkflows(query *models.WorkflowListQuery) (*models.WorkflowPage, error) {
+	ret := _m.Called(query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListWorkflows")
+	}
+
+	var r0 *models.WorkflowPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*models.WorkflowListQuery) (*models.WorkflowPage, error)); ok {
+		return rf(query)
+	}
+	if rf, ok := ret.Get(0).(func(*models.WorkflowListQuery) *models.WorkflowPage); ok {
+		r0 = rf(query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.WorkflowPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*models.WorkflowListQuery) error); ok {
+		r1 = rf(query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PurgeExpiredTrash provides a mock function with given fields: now
+func (_m *WorkflowService) PurgeExpiredTrash(now time.Time) (int, error) {
+	ret := _m.Called(now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeExpiredTrash")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time) (int, error)); ok {
+		return rf(now)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time) int); ok {
+		r0 = rf(now)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PurgeWorkflow provides a mock function with given fields: userID, workflowID
+func (_m *WorkflowService) PurgeWorkflow(userID *string, workflowID *string) (bool, bool) {
+	ret := _m.Called(userID, workflowID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeWorkflow")
+	}
+
+	var r0 bool
+	var r1 bool
+	if rf, ok := ret.Get(0).(func(*string, *string) (bool, bool)); ok {
+		return rf(userID, workflowID)
+	}
+	if rf, ok := ret.Get(0).(func(*string, *string) bool); ok {
+		r0 = rf(userID, workflowID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(*string, *string) bool); ok {
+		r1 = rf(userID, workflowID)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
+// ReconcileIndexes provides a mock function with given fields: userID, prune
+func (_m *WorkflowService) ReconcileIndexes(userID *string, prune bool) ([]models.IndexReconcileReport, error) {
+	ret := _m.Called(userID, prune)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReconcileIndexes")
+	}
+
+	var r0 []models.IndexReconcileReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string, bool) ([]models.IndexReconcileReport, error)); ok {
+		return rf(userID, prune)
+	}
+	if rf, ok := ret.Get(0).(func(*string, bool) []models.IndexReconcileReport); ok {
+		r0 = rf(userID, prune)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.IndexReconcileReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*string, bool) error); ok {
+		r1 = rf(userID, prune)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RegisterTriggers provides a mock function with given fields: triggers
+func (_m *WorkflowService) RegisterTriggers(triggers repos.WorkflowTriggers) {
+	_m.Called(triggers)
+}
+
+// ReplayDeadLetter provides a mock function with given fields: letter
+func (_m *WorkflowService) ReplayDeadLetter(letter *models.DeadLetter) error {
+	ret := _m.Called(letter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReplayDeadLetter")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.DeadLetter) error); ok {
+		r0 = rf(letter)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RestoreWorkflow provides a mock function with given fields: userID, workflowID
+func (_m *WorkflowService) RestoreWorkflow(userID *string, workflowID *string) (bool, error) {
+	ret := _m.Called(userID, workflowID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreWorkflow")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string, *string) (bool, error)); ok {
+		return rf(userID, workflowID)
+	}
+	if rf, ok := ret.Get(0).(func(*string, *string) bool); ok {
+		r0 = rf(userID, workflowID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(*string, *string) error); ok {
+		r1 = rf(userID, workflowID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RollbackWorkflow provides a mock function with given fields: userID, workflowID, version
+func (_m *WorkflowService) RollbackWorkflow(userID *string, workflowID *string, version uint32) (bool, bool, error) {
+	ret := _m.Called(userID, workflowID, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RollbackWorkflow")
+	}
+
+	var r0 bool
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(*string, *string, uint32) (bool, bool, error)); ok {
+		return rf(userID, workflowID, version)
+	}
+	if rf, ok := ret.Get(0).(func(*string, *string, uint32) bool); ok {
+		r0 = rf(userID, workflowID, version)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(*string, *string, uint32) bool); ok {
+		r1 = rf(userID, workflowID, version)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(*string, *string, uint32) error); ok {
+		r2 = rf(userID, workflowID, version)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// StartTrashPurge provides a mock function with given fields: ctx
+func (_m *WorkflowService) StartTrashPurge(ctx context.Context) {
+	_m.Called(ctx)
+}
+
 // UpdateWorkflow provides a mock function with given fields: workflow
-func (_m *WorkflowService) UpdateWorkflow(workflow *models.Workflow) (bool, bool) {
+func (_m *WorkflowService) UpdateWorkflow(workflow *models.Workflow) (bool, bool, error) {
 	ret := _m.Called(workflow)
 
 	if len(ret) == 0 {
@@ -120,7 +660,8 @@ func (_m *WorkflowService) UpdateWorkflow(workflow *models.Workflow) (bool, bool
 
 	var r0 bool
 	var r1 bool
-	if rf, ok := ret.Get(0).(func(*models.Workflow) (bool, bool)); ok {
+	var r2 error
+	if rf, ok := ret.Get(0).(func(*models.Workflow) (bool, bool, error)); ok {
 		return rf(workflow)
 	}
 	if rf, ok := ret.Get(0).(func(*models.Workflow) bool); ok {
@@ -135,7 +676,13 @@ func (_m *WorkflowService) UpdateWorkflow(workflow *models.Workflow) (bool, bool
 		r1 = ret.Get(1).(bool)
 	}
 
-	return r0, r1
+	if rf, ok := ret.Get(2).(func(*models.Workflow) error); ok {
+		r2 = rf(workflow)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
 // ValidateUserWorkflowUUID provides a mock function with given fields: worklfowID, name
@@ -174,6 +721,26 @@ func (_m *WorkflowService) ValidateWorkflowGlobalUUID(uuid *string) bool {
 	return r0
 }
 
+// ValidateWorkflowGraph provides a mock function with given fields: workflow
+func (_m *WorkflowService) ValidateWorkflowGraph(workflow *models.Workflow) []models.GraphValidationError {
+	ret := _m.Called(workflow)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ValidateWorkflowGraph")
+	}
+
+	var r0 []models.GraphValidationError
+	if rf, ok := ret.Get(0).(func(*models.Workflow) []models.GraphValidationError); ok {
+		r0 = rf(workflow)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.GraphValidationError)
+		}
+	}
+
+	return r0
+}
+
 // NewWorkflowService creates a new instance of WorkflowService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewWorkflowService(t interface {
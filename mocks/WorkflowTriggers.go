@@ -0,0 +1,60 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// WorkflowTriggers is an autogenerated mock type for the WorkflowTriggers type
+type WorkflowTriggers struct {
+	mock.Mock
+}
+
+// RemoveTriggers provides a mock function with given fields: workflowID
+func (_m *WorkflowTriggers) RemoveTriggers(workflowID *string) error {
+	ret := _m.Called(workflowID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveTriggers")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*string) error); ok {
+		r0 = rf(workflowID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SyncTriggers provides a mock function with given fields: workflowID, active
+func (_m *WorkflowTriggers) SyncTriggers(workflowID *string, active bool) error {
+	ret := _m.Called(workflowID, active)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SyncTriggers")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*string, bool) error); ok {
+		r0 = rf(workflowID, active)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewWorkflowTriggers creates a new instance of WorkflowTriggers. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWorkflowTriggers(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *WorkflowTriggers {
+	mock := &WorkflowTriggers{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
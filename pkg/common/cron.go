@@ -0,0 +1,164 @@
+package common
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	// serverless images can come without zoneinfo, schedules need it for the timezones
+	_ "time/tzdata"
+)
+
+// maxCronSearchYears expressions like "0 0 30 2 *" never match, search stops after this
+const maxCronSearchYears = 5
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// CronSchedule standard 5 fields expression: minute hour day-of-month month day-of-week
+type CronSchedule struct {
+	minutes     map[int]bool
+	hours       map[int]bool
+	daysOfMonth map[int]bool
+	months      map[int]bool
+	daysOfWeek  map[int]bool
+	// like vixie cron, when both days are restricted a day matching any of them is valid
+	anyDayOfMonth bool
+	anyDayOfWeek  bool
+}
+
+func ParseCron(expression string) (*CronSchedule, error) {
+	expression = strings.TrimSpace(expression)
+	if macro, exist := cronMacros[expression]; exist {
+		expression = macro
+	}
+
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression needs 5 fields, got %d", len(fields))
+	}
+
+	cron := &CronSchedule{
+		anyDayOfMonth: fields[2] == "*",
+		anyDayOfWeek:  fields[4] == "*",
+	}
+	var err error
+	if cron.minutes, err = parseCronField(fields[0], 0, 59); err != nil {
+		return nil, fmt.Errorf("minute: %v", err)
+	}
+	if cron.hours, err = parseCronField(fields[1], 0, 23); err != nil {
+		return nil, fmt.Errorf("hour: %v", err)
+	}
+	if cron.daysOfMonth, err = parseCronField(fields[2], 1, 31); err != nil {
+		return nil, fmt.Errorf("day of month: %v", err)
+	}
+	if cron.months, err = parseCronField(fields[3], 1, 12); err != nil {
+		return nil, fmt.Errorf("month: %v", err)
+	}
+	// 7 is sunday too
+	if cron.daysOfWeek, err = parseCronField(fields[4], 0, 7); err != nil {
+		return nil, fmt.Errorf("day of week: %v", err)
+	}
+	if cron.daysOfWeek[7] {
+		cron.daysOfWeek[0] = true
+	}
+	return cron, nil
+}
+
+// Next first time strictly after the given one, in the location asked. Zero time if never matches
+func (c *CronSchedule) Next(after time.Time, location *time.Location) time.Time {
+	t := after.In(location).Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(maxCronSearchYears, 0, 0)
+
+	for t.Before(limit) {
+		if !c.months[int(t.Month())] {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, location)
+			continue
+		}
+		if !c.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, location)
+			continue
+		}
+		if !c.hours[t.Hour()] {
+			// adding to the truncated hour keeps the right hour on daylight saving changes
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, location).Add(time.Hour)
+			continue
+		}
+		if !c.minutes[t.Minute()] {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (c *CronSchedule) matchDay(t time.Time) bool {
+	dayOfMonth := c.daysOfMonth[t.Day()]
+	dayOfWeek := c.daysOfWeek[int(t.Weekday())]
+	switch {
+	case c.anyDayOfMonth && c.anyDayOfWeek:
+		return true
+	case c.anyDayOfMonth:
+		return dayOfWeek
+	case c.anyDayOfWeek:
+		return dayOfMonth
+	default:
+		return dayOfMonth || dayOfWeek
+	}
+}
+
+// parseCronField supports lists, ranges and steps: "1,5", "1-5", "*/15", "10-40/10"
+func parseCronField(field string, min, max int) (map[int]bool, error) {
+	values := make(map[int]bool)
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if index := strings.Index(part, "/"); index >= 0 {
+			parsed, err := strconv.Atoi(part[index+1:])
+			if err != nil || parsed <= 0 {
+				return nil, fmt.Errorf("invalid step in %q", part)
+			}
+			rangePart, step = part[:index], parsed
+		}
+
+		start, end := min, max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if start, err = strconv.Atoi(bounds[0]); err != nil {
+				return nil, fmt.Errorf("invalid range %q", part)
+			}
+			if end, err = strconv.Atoi(bounds[1]); err != nil {
+				return nil, fmt.Errorf("invalid range %q", part)
+			}
+		default:
+			value, err := strconv.Atoi(rangePart)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value %q", part)
+			}
+			start, end = value, value
+			// "5/10" means from 5 to the end every 10
+			if step > 1 {
+				end = max
+			}
+		}
+
+		if start < min || end > max || start > end {
+			return nil, fmt.Errorf("%q out of range %d-%d", part, min, max)
+		}
+		for value := start; value <= end; value += step {
+			values[value] = true
+		}
+	}
+	return values, nil
+}
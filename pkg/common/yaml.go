@@ -0,0 +1,24 @@
+package common
+
+import (
+	"encoding/json"
+
+	"gopkg.in/yaml.v3"
+)
+
+// JSONToYAML models only have json tags, going through json keeps the same field names in yaml
+func JSONToYAML(data []byte) ([]byte, error) {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(value)
+}
+
+func YAMLToJSON(data []byte) ([]byte, error) {
+	var value interface{}
+	if err := yaml.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
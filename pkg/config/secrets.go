@@ -0,0 +1,39 @@
+package config
+
+type EnvSecretsConfig struct{}
+
+type SecretsConfig interface {
+	// GetCredentialKeys key encryption keys as id:base64 separated by commas
+	GetCredentialKeys() string
+	// GetCredentialKeyID key for new secrets, the first one when empty
+	GetCredentialKeyID() string
+	// GetEphemeralCredentialKey only for development and tests, encrypts with a key that dies with the instance
+	GetEphemeralCredentialKey() bool
+	// GetOAuthStateSecret signs the oauth states, shared by every instance
+	GetOAuthStateSecret() string
+	GetEnv(key, fallback string) string
+}
+
+func (e *EnvSecretsConfig) GetCredentialKeys() string {
+	return GetEnv("CREDENTIAL_KEYS", "")
+}
+
+func (e *EnvSecretsConfig) GetCredentialKeyID() string {
+	return GetEnv("CREDENTIAL_KEY_ID", "")
+}
+
+func (e *EnvSecretsConfig) GetEphemeralCredentialKey() bool {
+	return GetEnv("CREDENTIAL_KEYS_EPHEMERAL", "n") == "y"
+}
+
+func (e *EnvSecretsConfig) GetOAuthStateSecret() string {
+	return GetEnv("OAUTH_STATE_SECRET", "")
+}
+
+func (e *EnvSecretsConfig) GetEnv(key, fallback string) string {
+	return GetEnv(key, fallback)
+}
+
+func NewSecretsEnvConfig() SecretsConfig {
+	return &EnvSecretsConfig{}
+}
@@ -8,6 +8,7 @@ import (
 	"minireipaz/pkg/infra/brokerclient"
 	"minireipaz/pkg/infra/httpclient"
 	"minireipaz/pkg/infra/redisclient"
+	"minireipaz/pkg/infra/secretclient"
 	"minireipaz/pkg/interfaces/controllers"
 )
 
@@ -15,12 +16,30 @@ func InitDependencies() *dimodel.Dependencies {
 	configZitadel := config.NewZitaldelEnvConfig()
 	kafkaConfig := config.NewKafkaEnvConfig()
 	clickhouseConfig := config.NewClickhouseEnvConfig()
+	secretsConfig := config.NewSecretsEnvConfig()
 
 	// init autentication
 	authContext := controllers.NewAuthContext(configZitadel)
 	authService := authContext.GetAuthService()
 	authController := authContext.GetAuthController()
 
+	deadLetterRedisClient := redisclient.NewRedisClient()
+	deadLetterBrokerClient := brokerclient.NewBrokerClient(kafkaConfig)
+	repoDeadLetterRedis := redisclient.NewDeadLetterRepository(deadLetterRedisClient)
+	repoDeadLetterBroker := brokerclient.NewDeadLetterKafkaRepository(deadLetterBrokerClient)
+	deadLetterService := services.NewDeadLetterService(repoDeadLetterRedis, repoDeadLetterBroker)
+	deadLetterController := controllers.NewDeadLetterController(deadLetterService)
+
+	outboxRedisClient := redisclient.NewRedisClient()
+	outboxBrokerClient := brokerclient.NewBrokerClient(kafkaConfig)
+	repoOutboxRedis := redisclient.NewOutboxRepository(outboxRedisClient)
+	repoOutboxBroker := brokerclient.NewOutboxKafkaRepository(outboxBrokerClient)
+	outboxService := services.NewOutboxService(repoOutboxRedis, repoOutboxBroker)
+
+	idempotencyRedisClient := redisclient.NewRedisClient()
+	repoIdempotencyRedis := redisclient.NewIdempotencyRepository(idempotencyRedisClient)
+	idempotencyService := services.NewIdempotencyService(repoIdempotencyRedis)
+
 	userRedisClient := redisclient.NewRedisClient()
 	userHTTPClient := httpclient.NewClientImpl(models.TimeoutRequest)
 	userBrokerClient := brokerclient.NewBrokerClient(kafkaConfig)
@@ -29,18 +48,21 @@ func InitDependencies() *dimodel.Dependencies {
 	repoUserHTTP := httpclient.NewUserClientHTTP(userHTTPClient)
 	repoUserBroker := brokerclient.NewUserKafkaRepository(userBrokerClient)
 
-	userService := services.NewUserService(repoUserHTTP, repoUserRedis, repoUserBroker)
+	userService := services.NewUserService(repoUserHTTP, repoUserRedis, repoUserBroker, deadLetterService)
 	userController := controllers.NewUserController(userService)
 
 	credentialHTTPClient := httpclient.NewClientImpl(models.TimeoutRequest)
 	credentialRedisClient := redisclient.NewRedisClient()
 	credentialBrokerClient := brokerclient.NewBrokerClient(kafkaConfig)
 	redisCredentialRepo := redisclient.NewCredentialRedisRepository(credentialRedisClient)
-	googleCredentialRepo := httpclient.NewGoogleCredentialRepository(credentialHTTPClient)   // same client
-	facebookCredentialRepo := httpclient.NewGoogleCredentialRepository(credentialHTTPClient) // same client
-	repoCredentialBroker := brokerclient.NewCredentialKafkaRepository(credentialBrokerClient)
+	oauthCredentialRepo := httpclient.NewOAuthCredentialRepository(credentialHTTPClient)
+	oauthProviders := services.NewOAuthProviderRegistry(models.DefaultOAuthProviders(), config.GetEnv("OAUTH_PROVIDERS", ""))
+	secretService := services.NewSecretService(secretclient.NewLocalKeyProvider(secretsConfig))
+	repoCredentialBroker := brokerclient.NewCredentialKafkaRepository(credentialBrokerClient, secretService)
 	repoCredentialHTTP := httpclient.NewCredentialRepository(credentialHTTPClient, clickhouseConfig)
-	credentialService := services.NewCredentialService(googleCredentialRepo, facebookCredentialRepo, redisCredentialRepo, repoCredentialBroker, repoCredentialHTTP)
+	repoCredentialRefreshRedis := redisclient.NewCredentialRefreshRepository(credentialRedisClient)
+	credentialRefreshService := services.NewCredentialRefreshService(oauthCredentialRepo, oauthProviders, repoCredentialRefreshRedis, repoCredentialBroker, repoCredentialHTTP, secretService, deadLetterService)
+	credentialService := services.NewCredentialService(oauthCredentialRepo, oauthProviders, redisCredentialRepo, repoCredentialBroker, repoCredentialHTTP, secretService, secretclient.OAuthStateSecret(secretsConfig), deadLetterService, credentialRefreshService)
 
 	workflowHTTPClient := httpclient.NewClientImpl(models.TimeoutRequest)
 	repoWorkflowHTTP := httpclient.NewWorkflowClientHTTP(workflowHTTPClient, clickhouseConfig)
@@ -49,10 +71,42 @@ func InitDependencies() *dimodel.Dependencies {
 	repoWorkflowRedis := redisclient.NewWorkflowRepository(workflowRedisClient)
 	repoWorkflowBroker := brokerclient.NewWorkflowKafkaRepository(workflowBrokerClient)
 	idService := services.NewUUIDService()
-	workflowService := services.NewWorkflowService(repoWorkflowRedis, repoWorkflowBroker, idService, repoWorkflowHTTP)
+	nodeService := services.NewNodeService()
+	workflowService := services.NewWorkflowService(repoWorkflowRedis, repoWorkflowBroker, idService, repoWorkflowHTTP, nodeService, deadLetterService, outboxService)
 	workflowController := controllers.NewWorkflowController(workflowService, credentialService, authService)
 
-	credentialController := controllers.NewCredentialController(credentialService, authService, workflowService)
+	templateRedisClient := redisclient.NewRedisClient()
+	repoTemplateRedis := redisclient.NewTemplateRepository(templateRedisClient)
+	templateService := services.NewTemplateService(repoTemplateRedis, workflowService, idService)
+	templateController := controllers.NewTemplateController(templateService)
+
+	statusRedisClient := redisclient.NewRedisClient()
+	statusPublisher := redisclient.NewStatusRepository(statusRedisClient)
+	statusService := services.NewStatusService(statusPublisher)
+	statusController := controllers.NewStatusController(statusService)
+
+	runHTTPClient := httpclient.NewClientImpl(models.TimeoutRequest)
+	repoRunHTTP := httpclient.NewRunClientHTTP(runHTTPClient, clickhouseConfig)
+	runRedisClient := redisclient.NewRedisClient()
+	runBrokerClient := brokerclient.NewBrokerClient(kafkaConfig)
+	repoRunRedis := redisclient.NewRunRepository(runRedisClient)
+	repoRunBroker := brokerclient.NewRunKafkaRepository(runBrokerClient)
+	runService := services.NewRunService(repoRunRedis, repoRunBroker, repoRunHTTP, workflowService, statusPublisher, outboxService)
+	runController := controllers.NewRunController(runService)
+
+	webhookRedisClient := redisclient.NewRedisClient()
+	webhookBrokerClient := brokerclient.NewBrokerClient(kafkaConfig)
+	repoWebhookRedis := redisclient.NewWebhookRepository(webhookRedisClient)
+	repoWebhookBroker := brokerclient.NewWebhookKafkaRepository(webhookBrokerClient)
+	webhookService := services.NewWebhookService(repoWebhookRedis, repoWebhookBroker, workflowService, secretService)
+	webhookController := controllers.NewWebhookController(webhookService)
+
+	folderRedisClient := redisclient.NewRedisClient()
+	repoFolderRedis := redisclient.NewFolderRepository(folderRedisClient)
+	folderService := services.NewFolderService(repoFolderRedis, workflowService)
+	folderController := controllers.NewFolderController(folderService)
+
+	credentialController := controllers.NewCredentialController(credentialService, authService, workflowService, deadLetterService, credentialRefreshService)
 
 	dashboardHTTPClient := httpclient.NewClientImpl(models.TimeoutRequest)
 	dashboardRepo := httpclient.NewDashboardRepository(dashboardHTTPClient, clickhouseConfig)
@@ -67,9 +121,25 @@ func InitDependencies() *dimodel.Dependencies {
 	// actionsRepo := httpclient.NewActionsClientHTTP(actionsHTTPClient, clickhouseConfig)
   // var actionsRepo repos.ActionsHTTPRepository
   actionsRepo := httpclient.NewActionsClientHTTP(actionsHTTPClient, clickhouseConfig)
-	actionsService := services.NewActionsService(repoActionsRedis, repoActionsBroker, actionsRepo)
+	schedulerRedisClient := redisclient.NewRedisClient()
+	repoSchedulerRedis := redisclient.NewSchedulerRepository(schedulerRedisClient)
+	schedulerService := services.NewSchedulerService(repoSchedulerRedis, workflowService)
+	schedulerController := controllers.NewSchedulerController(schedulerService)
+	actionsService := services.NewActionsService(repoActionsRedis, repoActionsBroker, actionsRepo, schedulerService, statusPublisher, authService, deadLetterService, outboxService)
 	actionsController := controllers.NewActionsController(actionsService, authService)
 
+	nodesController := controllers.NewNodesController(nodeService)
+
+	workflowService.RegisterTriggers(schedulerService)
+	workflowService.RegisterTriggers(webhookService)
+	schedulerService.RegisterCreator(actionsService)
+
+	deadLetterService.RegisterReplayer(models.DeadLetterOriginWorkflowCreate, workflowService)
+	deadLetterService.RegisterReplayer(models.DeadLetterOriginWorkflowUpdate, workflowService)
+	deadLetterService.RegisterReplayer(models.DeadLetterOriginUserSync, userService)
+	deadLetterService.RegisterReplayer(models.DeadLetterOriginCredentialSave, credentialService)
+	deadLetterService.RegisterReplayer(models.DeadLetterOriginActionCreate, actionsService)
+
 	return &dimodel.Dependencies{
 		WorkflowController:   workflowController,
 		AuthService:          &authService,
@@ -78,5 +148,19 @@ func InitDependencies() *dimodel.Dependencies {
 		AuthController:       authController,
 		CredentialController: credentialController,
 		ActionsController:    actionsController,
+		NodesController:      nodesController,
+		TemplateController:   templateController,
+		FolderController:     folderController,
+		SchedulerController:  schedulerController,
+		WebhookController:    webhookController,
+		RunController:        runController,
+		StatusController:     statusController,
+		DeadLetterController: deadLetterController,
+		Scheduler:            schedulerService,
+		Outbox:               outboxService,
+		TrashPurger:          workflowService,
+		CredentialRefresher:  credentialRefreshService,
+		IdempotencyService:   idempotencyService,
+		AdminToken:           config.GetEnv("ADMIN_TOKEN", ""),
 	}
 }
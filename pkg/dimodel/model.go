@@ -13,4 +13,19 @@ type Dependencies struct {
 	AuthController       *controllers.AuthController
 	CredentialController *controllers.CredentialController
 	ActionsController    *controllers.ActionsController
+	NodesController      *controllers.NodesController
+	TemplateController   *controllers.TemplateController
+	FolderController     *controllers.FolderController
+	SchedulerController  *controllers.SchedulerController
+	Scheduler            repos.SchedulerService
+	Outbox               repos.OutboxService
+	TrashPurger          repos.TrashPurger
+	CredentialRefresher  repos.CredentialRefreshService
+	IdempotencyService   repos.IdempotencyService
+	WebhookController    *controllers.WebhookController
+	RunController        *controllers.RunController
+	StatusController     *controllers.StatusController
+	DeadLetterController *controllers.DeadLetterController
+	// AdminToken operator endpoints are refused when it is empty
+	AdminToken string
 }
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	ActionStatusPending    = "pending"
+	ActionStatusProcessing = "processing"
+	ActionStatusCompleted  = "completed"
+	ActionStatusFailed     = "failed"
+	// long polling never holds a request longer than this
+	MaxActionWait       = 30 * time.Second
+	ActionPollInterval  = time.Second
+	ActionNotFound      = "action not found"
+	ActionResultInvalid = "wait must be between 0 and 30 seconds"
+	ActionResultKey     = "actionresult"
+)
+
+var ErrActionNotFound = errors.New(ActionNotFound)
+
+// ActionResult row of the action_workflow_data pipe, Data is the payload produced by the action
+type ActionResult struct {
+	CreatedAt  *CustomTime `json:"created_at,omitempty"`
+	UpdatedAt  *CustomTime `json:"updated_at,omitempty"`
+	ActionID   string      `json:"action_id"`
+	UserID     string      `json:"user_id"`
+	WorkflowID string      `json:"workflow_id,omitempty"`
+	NodeID     string      `json:"node_id,omitempty"`
+	Type       string      `json:"type,omitempty"`
+	Status     string      `json:"status"`
+	Data       string      `json:"data,omitempty"`
+	Error      string      `json:"error,omitempty"`
+}
+
+// Finished completed and failed never change again, polling can stop
+func (a *ActionResult) Finished() bool {
+	return a.Status == ActionStatusCompleted || a.Status == ActionStatusFailed
+}
+
+type ActionResultQuery struct {
+	Wait int `form:"wait" binding:"min=0,max=30"`
+}
+
+type InfoActions struct {
+	Rows                   *int64         `json:"rows,omitempty"`
+	RowsBeforeLimitAtLeast *int64         `json:"rows_before_limit_at_least,omitempty"`
+	Statistics             *Statistics    `json:"statistics,omitempty"`
+	Meta                   []Meta         `json:"meta,omitempty"`
+	Data                   []ActionResult `json:"data,omitempty"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	CredentialRefreshTick = 1 * time.Minute
+	// access tokens expiring this soon are refreshed, by the tick or when they are asked for
+	CredentialRefreshAhead      = 5 * time.Minute
+	CredentialRefreshLeaderTTL  = 3 * time.Minute
+	MaxCredentialRefreshPerTick = 50
+	// kept after a refresh, the saved credential can still be the previous one until then
+	CredentialRefreshLockTTL = 2 * time.Minute
+	CredentialNotFound       = "credential not found"
+	OAuthRefreshUnsupported  = "oauth provider of the credential does not refresh tokens"
+	OAuthRefreshRejected     = "refresh token rejected, the credential must be connected again"
+	OAuthRefreshInProgress   = "credential is being refreshed, try again later"
+	OAuthClientRejected      = "oauth client of the credential rejected by the provider, check its configuration"
+)
+
+var (
+	ErrCredentialNotFound      = errors.New(CredentialNotFound)
+	ErrOAuthRefreshUnsupported = errors.New(OAuthRefreshUnsupported)
+	ErrOAuthRefreshRejected    = errors.New(OAuthRefreshRejected)
+	ErrOAuthRefreshInProgress  = errors.New(OAuthRefreshInProgress)
+	ErrOAuthClientRejected     = errors.New(OAuthClientRejected)
+)
+
+// CredentialRefreshEntry credential with a refresh token, scheduled before its access token expires
+type CredentialRefreshEntry struct {
+	Sub string `json:"sub"`
+	ID  string `json:"id"`
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+const (
+	DeadLetterOriginWorkflowCreate = "workflow.create"
+	DeadLetterOriginWorkflowUpdate = "workflow.update"
+	DeadLetterOriginUserSync       = "user.sync"
+	DeadLetterOriginCredentialSave = "credential.save"
+	DeadLetterOriginActionCreate   = "action.create"
+	DefaultDeadLettersPage         = 50
+	MaxDeadLettersPage             = 500
+	DeadLetterNotFound             = "dead letter not found"
+	DeadLetterOriginUnknown        = "dead letter origin cannot be replayed"
+	DeadLetterCannotReplay         = "dead letter replay failed"
+	DeadLetterListInvalid          = "limit must be between 0 and 500"
+	DeadLetterListKey              = "deadletterlist"
+	// DeadLetterRedacted replaces the secrets of a letter before it is returned
+	DeadLetterRedacted = "[redacted]"
+)
+
+var (
+	ErrDeadLetterNotFound      = errors.New(DeadLetterNotFound)
+	ErrDeadLetterOriginUnknown = errors.New(DeadLetterOriginUnknown)
+)
+
+// DeadLetter command dropped after all retries, Payload is what the origin needs to run it again
+type DeadLetter struct {
+	Payload   json.RawMessage `json:"payload"`
+	ID        string          `json:"id"`
+	Origin    string          `json:"origin"`
+	Error     string          `json:"error"`
+	CreatedAt string          `json:"created_at"`
+	Attempts  int             `json:"attempts"`
+}
+
+type DeadLetterQuery struct {
+	Origin string `form:"origin" binding:"max=50"`
+	Limit  int64  `form:"limit" binding:"min=0,max=500"`
+}
+
+// DeadLetterCredential payload of a credential exchanged but not saved, tokens and
+// secrets of the credential are saved encrypted
+type DeadLetterCredential struct {
+	ExpiresAt  time.Time                  `json:"expires_at"`
+	Credential *RequestExchangeCredential `json:"credential"`
+	Token      string                     `json:"token"`
+	Refresh    string                     `json:"refresh"`
+}
+
+// DeadLetterRetriedAttempts retry loops start at 1 and stop before MaxAttempts
+const DeadLetterRetriedAttempts = MaxAttempts - 1
+
+const RetriesExhausted = "operation failed after all attempts"
+
+var ErrRetriesExhausted = errors.New(RetriesExhausted)
@@ -0,0 +1,73 @@
+package models
+
+import "errors"
+
+const (
+	FolderSeparator          = "/"
+	MaxFolderDepth           = 10
+	MaxFolderPathLength      = 255
+	FolderInvalid            = "folder must be names separated by / with max length of 255"
+	FolderNotFound           = "folder not found"
+	FolderExist              = "folder already exists"
+	FolderMoveInside         = "folder cannot be moved inside itself"
+	FolderCannotMoveWorkflow = "some workflows could not be moved"
+	FolderMoveReverted       = "folder not moved, some workflows could not be moved and the rest were moved back"
+	FolderCreateKey          = "foldercreate"
+	FolderRenameKey          = "folderrename"
+	FolderMoveKey            = "foldermove"
+	FolderMoveWorkflowsKey   = "foldermoveworkflows"
+)
+
+var (
+	ErrFolderInvalid      = errors.New(FolderInvalid)
+	ErrFolderNotFound     = errors.New(FolderNotFound)
+	ErrFolderExist        = errors.New(FolderExist)
+	ErrFolderMoveInside   = errors.New(FolderMoveInside)
+	ErrFolderMoveReverted = errors.New(FolderMoveReverted)
+)
+
+// Folder node of the tree built from DirectoryToSave, root has empty path
+type Folder struct {
+	Path string `json:"path"`
+	Name string `json:"name"`
+	// workflows saved directly in this folder
+	WorkflowCount int `json:"workflow_count"`
+	// workflows in this folder and all subfolders
+	TotalWorkflows int       `json:"total_workflows"`
+	Children       []*Folder `json:"children"`
+}
+
+// RequestCreateFolder UserID comes from the path like in every folder request, the one in the body must match it
+type RequestCreateFolder struct {
+	UserID string `json:"user_id" binding:"max=50"`
+	Path   string `json:"path" binding:"required,max=255"`
+}
+
+// RequestRenameFolder only the last name of the path changes
+type RequestRenameFolder struct {
+	UserID  string `json:"user_id" binding:"max=50"`
+	Path    string `json:"path" binding:"required,max=255"`
+	NewName string `json:"new_name" binding:"required,max=255"`
+}
+
+// RequestMoveFolder empty parent moves the folder to the root
+type RequestMoveFolder struct {
+	UserID    string `json:"user_id" binding:"max=50"`
+	Path      string `json:"path" binding:"required,max=255"`
+	NewParent string `json:"new_parent" binding:"max=255"`
+}
+
+type RequestMoveWorkflows struct {
+	UserID      string   `json:"user_id" binding:"max=50"`
+	WorkflowIDs []string `json:"workflow_ids" binding:"required,min=1,max=200"`
+	Path        string   `json:"path" binding:"required,max=255"`
+}
+
+// FolderMoveReport every moved workflow is an update command published, a folder move that
+// fails moves its workflows back to Reverted, the ones still in Moved could not be moved back
+type FolderMoveReport struct {
+	Path     string   `json:"path"`
+	Moved    []string `json:"moved"`
+	Failed   []string `json:"failed"`
+	Reverted []string `json:"reverted,omitempty"`
+}
@@ -27,7 +27,18 @@ const (
 	CredentialExchangeContextKey = "exchangecredential"
 	ActionGoogleKey              = "actiongoogle"
 	ActionNotionKey              = "actionnotion"
+	WorkflowRollbackKey          = "workflowrollback"
+	WorkflowImportKey            = "workflowimport"
+	WorkflowDuplicateKey         = "workflowduplicate"
+	WorkflowReconcileKey         = "workflowreconcile"
+	WorkflowListKey              = "workflowlist"
 	CommandTypeCreate            = "create"
 	CommandTypeUpdate            = "update"
 	CommandTypeDelete            = "delete"
+	CommandTypeRestore           = "restore"
+	CommandTypePurge             = "purge"
+	CommandTypeActivate          = "activate"
+	CommandTypePause             = "pause"
+	CommandTypeResume            = "resume"
+	CommandTypeDraft             = "draft"
 )
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotencyReplayedHeader = "Idempotent-Replayed"
+	MaxIdempotencyKeyLength   = 255
+	// replayed responses are kept for a day, mobile clients retry within minutes
+	IdempotencyTTL = 24 * time.Hour
+	// a request holding the key longer than this is considered dead and the key is free again
+	IdempotencyLockTTL     = 2 * time.Minute
+	IdempotencyKeyInvalid  = "Idempotency-Key header must have between 1 and 255 characters"
+	IdempotencyKeyReused   = "Idempotency-Key already used with a different request"
+	IdempotencyInProgress  = "a request with the same Idempotency-Key is still in progress"
+	IdempotencyBodyInvalid = "request body cannot be read"
+)
+
+var (
+	ErrIdempotencyKeyReused  = errors.New(IdempotencyKeyReused)
+	ErrIdempotencyInProgress = errors.New(IdempotencyInProgress)
+)
+
+// IdempotencyRecord first response of a key, until Completed the request is still running
+type IdempotencyRecord struct {
+	RequestHash string `json:"request_hash"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        string `json:"body,omitempty"`
+	CreatedAt   string `json:"created_at"`
+	Status      int    `json:"status,omitempty"`
+	Completed   bool   `json:"completed"`
+}
@@ -0,0 +1,41 @@
+package models
+
+const (
+	NodeTypeStart   = "start"
+	NodeTypeWebhook = "webhook"
+	// AnyNodeType used in allowed connections to accept every node type
+	AnyNodeType = "*"
+)
+
+// NodeTypeDefinition describes what a node type needs, frontend builds the node forms from it
+type NodeTypeDefinition struct {
+	FormSchema      FormSchema `json:"formdata_schema"`
+	Type            string     `json:"type"`
+	Label           string     `json:"label"`
+	Description     string     `json:"description"`
+	Category        string     `json:"category"`
+	CredentialType  string     `json:"credential_type,omitempty"`
+	AllowedInbound  []string   `json:"allowed_inbound"`
+	AllowedOutbound []string   `json:"allowed_outbound"`
+}
+
+// FormSchema JSON schema subset used for the formdata of a node
+type FormSchema struct {
+	Properties map[string]FormProperty `json:"properties"`
+	Type       string                  `json:"type"`
+	Required   []string                `json:"required,omitempty"`
+}
+
+type FormProperty struct {
+	Type        string   `json:"type"`
+	Title       string   `json:"title,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Default     string   `json:"default,omitempty"`
+	Enum        []string `json:"enum,omitempty"`
+}
+
+type ResponseNodeCatalog struct {
+	Error     string               `json:"error"`
+	NodeTypes []NodeTypeDefinition `json:"nodes"`
+	Status    int                  `json:"status"`
+}
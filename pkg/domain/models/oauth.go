@@ -0,0 +1,106 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// client credentials sent in the body of the token request or as basic auth
+	OAuthAuthStyleParams = "params"
+	OAuthAuthStyleHeader = "header"
+	PKCEVerifierBytes    = 32
+	OAuthStateBytes      = 32
+	// the user has this long to go through the consent screen of the provider
+	OAuthStateTTL        = 10 * time.Minute
+	OAuthProviderUnknown = "credential type has no oauth provider"
+	OAuthProviderInvalid = "oauth provider needs a name, auth url, token url and at least one credential type"
+	OAuthStateInvalid    = "oauth state is not valid, expired or already used"
+	OAuthVerifierMissing = "oauth state has no code verifier"
+)
+
+var (
+	ErrOAuthProviderUnknown = errors.New(OAuthProviderUnknown)
+	ErrOAuthProviderInvalid = errors.New(OAuthProviderInvalid)
+	ErrOAuthStateInvalid    = errors.New(OAuthStateInvalid)
+	ErrOAuthVerifierMissing = errors.New(OAuthVerifierMissing)
+)
+
+// OAuthProvider client id and secret belong to each credential, the provider only describes the endpoints
+type OAuthProvider struct {
+	// AuthParams extra query params of the consent url, e.g. access_type=offline to receive a refresh token
+	AuthParams      map[string]string `json:"auth_params,omitempty"`
+	Name            string            `json:"name"`
+	AuthURL         string            `json:"auth_url"`
+	TokenURL        string            `json:"token_url"`
+	AuthStyle       string            `json:"auth_style,omitempty"`
+	ScopeSeparator  string            `json:"scope_separator,omitempty"`
+	CredentialTypes []string          `json:"credential_types"`
+	Scopes          []string          `json:"scopes,omitempty"`
+	PKCE            bool              `json:"pkce"`
+	// Refresh the provider issues refresh tokens and access tokens expire
+	Refresh bool `json:"refresh"`
+	// RotatesRefreshToken every refresh returns a new refresh token and the previous one stops working
+	RotatesRefreshToken bool `json:"rotates_refresh_token"`
+}
+
+type OAuthToken struct {
+	Expiry       time.Time `json:"expiry"`
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	TokenType    string    `json:"token_type,omitempty"`
+}
+
+// DefaultOAuthProviders built in providers, OAUTH_PROVIDERS can add more or replace them by name
+func DefaultOAuthProviders() []OAuthProvider {
+	return []OAuthProvider{
+		{
+			Name:            "google",
+			AuthURL:         "https://accounts.google.com/o/oauth2/auth",
+			TokenURL:        "https://oauth2.googleapis.com/token",
+			AuthStyle:       OAuthAuthStyleParams,
+			CredentialTypes: []string{GoogleSheets},
+			Scopes:          []string{"https://www.googleapis.com/auth/spreadsheets.readonly"},
+			AuthParams:      map[string]string{"access_type": "offline", "prompt": "consent"},
+			PKCE:            true,
+			Refresh:         true,
+		},
+		{
+			Name:            "notion",
+			AuthURL:         "https://api.notion.com/v1/oauth/authorize",
+			TokenURL:        "https://api.notion.com/v1/oauth/token",
+			AuthStyle:       OAuthAuthStyleHeader,
+			CredentialTypes: []string{NotionOAuth},
+			AuthParams:      map[string]string{"owner": "user"},
+		},
+		{
+			Name:            "facebook",
+			AuthURL:         "https://www.facebook.com/v19.0/dialog/oauth",
+			TokenURL:        "https://graph.facebook.com/v19.0/oauth/access_token",
+			AuthStyle:       OAuthAuthStyleParams,
+			ScopeSeparator:  ",",
+			CredentialTypes: []string{"facebook"},
+			Scopes:          []string{"public_profile"},
+		},
+		{
+			Name:                "slack",
+			AuthURL:             "https://slack.com/oauth/v2/authorize",
+			TokenURL:            "https://slack.com/api/oauth.v2.access",
+			AuthStyle:           OAuthAuthStyleParams,
+			ScopeSeparator:      ",",
+			CredentialTypes:     []string{"slack"},
+			Scopes:              []string{"chat:write"},
+			Refresh:             true,
+			RotatesRefreshToken: true,
+		},
+		{
+			Name:            "github",
+			AuthURL:         "https://github.com/login/oauth/authorize",
+			TokenURL:        "https://github.com/login/oauth/access_token",
+			AuthStyle:       OAuthAuthStyleParams,
+			CredentialTypes: []string{"github"},
+			Scopes:          []string{"repo"},
+			PKCE:            true,
+		},
+	}
+}
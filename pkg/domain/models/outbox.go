@@ -0,0 +1,29 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	OutboxRelayTick    = 5 * time.Second
+	OutboxLeaderTTL    = 15 * time.Second
+	MaxOutboxPerTick   = 100
+	OutboxPublishedTTL = 24 * time.Hour
+	// consumers drop messages with a dedup key already seen, the relay delivers at least once
+	OutboxDedupHeader   = "dedup-key"
+	OutboxCannotPublish = "outbox message cannot be published"
+)
+
+var ErrOutboxCannotPublish = errors.New(OutboxCannotPublish)
+
+// OutboxMessage broker message written in the same redis transaction as the state it belongs to,
+// ID is the id of the stream entry
+type OutboxMessage struct {
+	ID        string `json:"id,omitempty"`
+	Topic     string `json:"topic"`
+	Key       string `json:"key"`
+	DedupKey  string `json:"dedup_key"`
+	Value     string `json:"value"`
+	CreatedAt string `json:"created_at"`
+}
@@ -0,0 +1,125 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	RunStatusQueued    = "queued"
+	RunStatusRunning   = "running"
+	RunStatusSucceeded = "succeeded"
+	RunStatusFailed    = "failed"
+	StepStatusPending  = "pending"
+	RunTriggerManual   = "manual"
+	// run records expire, they are kept only while the frontend polls them
+	RunRecordTTL    = 24 * time.Hour
+	RunNotFound     = "run not found"
+	RunGraphInvalid = "workflow graph is not valid to be run"
+	RunCreateKey    = "runcreate"
+	RunListKey      = "runlist"
+	RunListInvalid  = "invalid cursor or limit for run list"
+	// SortByStartedAt runs are always listed newest first
+	SortByStartedAt = "started_at"
+)
+
+var (
+	ErrRunNotFound     = errors.New(RunNotFound)
+	ErrRunGraphInvalid = errors.New(RunGraphInvalid)
+)
+
+// Run one execution of a whole workflow, Steps follow the order the graph is walked
+type Run struct {
+	RunID      string    `json:"run_id"`
+	WorkflowID string    `json:"workflow_id"`
+	UserID     string    `json:"user_id"`
+	Version    uint32    `json:"version"`
+	Status     string    `json:"status"`
+	Trigger    string    `json:"trigger"`
+	Steps      []RunStep `json:"steps"`
+	CreatedAt  string    `json:"created_at"`
+	DryRun     bool      `json:"dry_run"`
+}
+
+type RunStep struct {
+	NodeID string `json:"node_id"`
+	Type   string `json:"type"`
+	Status string `json:"status"`
+}
+
+// RunNode node of the resolved graph, Next are the nodes that run after it
+type RunNode struct {
+	Data *DataNode `json:"data,omitempty"`
+	ID   string    `json:"id"`
+	Type string    `json:"type"`
+	Next []string  `json:"next"`
+}
+
+type RequestCreateRun struct {
+	UserID string `json:"user_id" binding:"required,max=50"`
+	// DryRun executors walk the graph without calling external services
+	DryRun bool `json:"dry_run"`
+}
+
+// RunListQuery same cursor and limit as the workflow list, without limit a page of DefaultWorkflowPageSize is returned
+type RunListQuery struct {
+	UserID     string `form:"-"`
+	WorkflowID string `form:"-"`
+	Cursor     string `form:"cursor" binding:"max=512"`
+	Limit      uint64 `form:"limit" binding:"omitempty,min=1,max=200"`
+}
+
+type RunPage struct {
+	Runs       []RunSummary `json:"runs"`
+	NextCursor string       `json:"next_cursor"`
+	HasMore    bool         `json:"has_more"`
+}
+
+// RunSummary row of the workflow_runs pipe, written by executors while the run advances
+type RunSummary struct {
+	StartedAt  *CustomTime `json:"started_at,omitempty"`
+	FinishedAt *CustomTime `json:"finished_at,omitempty"`
+	RunID      string      `json:"run_id"`
+	WorkflowID string      `json:"workflow_id"`
+	UserID     string      `json:"user_id"`
+	Status     string      `json:"status"`
+	Trigger    string      `json:"trigger"`
+	DurationMs int64       `json:"duration_ms"`
+	Version    uint32      `json:"version"`
+	DryRun     bool        `json:"dry_run"`
+}
+
+// RunStepResult row of the run_steps pipe, input and output are the json exchanged by the node
+type RunStepResult struct {
+	StartedAt  *CustomTime `json:"started_at,omitempty"`
+	FinishedAt *CustomTime `json:"finished_at,omitempty"`
+	NodeID     string      `json:"node_id"`
+	NodeType   string      `json:"node_type"`
+	Status     string      `json:"status"`
+	Input      string      `json:"input,omitempty"`
+	Output     string      `json:"output,omitempty"`
+	Error      string      `json:"error,omitempty"`
+	DurationMs int64       `json:"duration_ms"`
+	Attempts   uint32      `json:"attempts"`
+}
+
+type RunDetail struct {
+	RunSummary
+	Steps []RunStepResult `json:"steps"`
+}
+
+type InfoRuns struct {
+	Rows                   *int64       `json:"rows,omitempty"`
+	RowsBeforeLimitAtLeast *int64       `json:"rows_before_limit_at_least,omitempty"`
+	Statistics             *Statistics  `json:"statistics,omitempty"`
+	Meta                   []Meta       `json:"meta,omitempty"`
+	Data                   []RunSummary `json:"data,omitempty"`
+}
+
+type InfoRunSteps struct {
+	Rows                   *int64          `json:"rows,omitempty"`
+	RowsBeforeLimitAtLeast *int64          `json:"rows_before_limit_at_least,omitempty"`
+	Statistics             *Statistics     `json:"statistics,omitempty"`
+	Meta                   []Meta          `json:"meta,omitempty"`
+	Data                   []RunStepResult `json:"data,omitempty"`
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	ScheduleKindCron     = "cron"
+	ScheduleKindInterval = "interval"
+	DefaultTimezone      = "UTC"
+	// polling faster than this is not allowed, connectors have rate limits
+	MinScheduleInterval = time.Minute
+	SchedulerTick       = 15 * time.Second
+	// leadership outlives some ticks, an instance that dies is replaced after it
+	SchedulerLeaderTTL    = 45 * time.Second
+	MaxSchedulesPerTick   = 100
+	ScheduleInvalid       = "schedule must be a cron expression or an interval of at least one minute"
+	TimezoneInvalid       = "timezone is not valid"
+	ScheduleNotFound      = "schedule not found"
+	ScheduleNeverFires    = "schedule has no next fire time"
+	ScheduleCreateKey     = "schedulecreate"
+	ScheduledActionFailed = "cannot create scheduled action"
+)
+
+var (
+	ErrScheduleInvalid       = errors.New(ScheduleInvalid)
+	ErrTimezoneInvalid       = errors.New(TimezoneInvalid)
+	ErrScheduleNotFound      = errors.New(ScheduleNotFound)
+	ErrScheduleNeverFires    = errors.New(ScheduleNeverFires)
+	ErrScheduledActionFailed = errors.New(ScheduledActionFailed)
+)
+
+// Schedule trigger of a polling node, Action is the template of the action emitted every time it fires
+type Schedule struct {
+	Action          RequestGoogleAction `json:"action"`
+	WorkflowID      string              `json:"workflow_id"`
+	NodeID          string              `json:"node_id"`
+	UserID          string              `json:"user_id"`
+	Kind            string              `json:"kind"`
+	Expression      string              `json:"expression,omitempty"`
+	Timezone        string              `json:"timezone"`
+	NextRunAt       string              `json:"next_run_at"`
+	LastRunAt       string              `json:"last_run_at,omitempty"`
+	IntervalSeconds int64               `json:"interval_seconds,omitempty"`
+	// Disabled while the workflow is not active, it is out of the due set until the workflow runs again
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+type RequestCreateSchedule struct {
+	Action          RequestGoogleAction `json:"action"`
+	UserID          string              `json:"user_id" binding:"required,max=50"`
+	WorkflowID      string              `json:"workflow_id" binding:"required,max=50"`
+	NodeID          string              `json:"node_id" binding:"required,max=255"`
+	Kind            string              `json:"kind" binding:"required,oneof=cron interval"`
+	Expression      string              `json:"expression" binding:"max=255"`
+	Timezone        string              `json:"timezone" binding:"max=64"`
+	IntervalSeconds int64               `json:"interval_seconds" binding:"min=0"`
+}
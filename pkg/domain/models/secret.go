@@ -0,0 +1,20 @@
+package models
+
+import "errors"
+
+const (
+	// values without it are plaintext saved before secrets were encrypted, they are read as they are
+	SecretPrefix       = "enc:v1:"
+	SecretDataKeyBytes = 32
+	SecretKeyUnknown   = "secret key id is not known"
+	SecretMalformed    = "encrypted secret is malformed or was modified"
+	SecretKeyInvalid   = "secret keys need an id without ':' or ',' and 16, 24 or 32 bytes in base64"
+	SecretKeysMissing  = "no secret keys configured"
+)
+
+var (
+	ErrSecretKeyUnknown  = errors.New(SecretKeyUnknown)
+	ErrSecretMalformed   = errors.New(SecretMalformed)
+	ErrSecretKeyInvalid  = errors.New(SecretKeyInvalid)
+	ErrSecretKeysMissing = errors.New(SecretKeysMissing)
+)
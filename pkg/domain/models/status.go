@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+const (
+	StatusKindAction = "action"
+	StatusKindRun    = "run"
+	// events are dropped for a subscriber that does not read its buffer, the stream is only a hint to refresh
+	StatusEventBuffer = 32
+	StatusHeartbeat   = 15 * time.Second
+	StatusCannotOpen  = "cannot open status stream"
+)
+
+// StatusEvent transition of an action or a run, written by this service and by the executors
+type StatusEvent struct {
+	Kind       string `json:"kind"`
+	ID         string `json:"id"`
+	UserID     string `json:"user_id"`
+	WorkflowID string `json:"workflow_id,omitempty"`
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+	At         string `json:"at"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import "errors"
+
+const (
+	TemplateNotFound       = "template not found"
+	TemplateNotOwner       = "template can only be removed by its author"
+	TemplateCannotPublish  = "cannot publish template"
+	TemplateCannotDelete   = "cannot delete template"
+	TemplateCannotUse      = "cannot create workflow from template"
+	TemplateCategoryBasic  = "basic"
+	TemplateCategoryGoogle = "google"
+	TemplateCategoryNotion = "notion"
+	TemplatePublishKey     = "templatepublish"
+	TemplateCreateFromKey  = "templatecreatefrom"
+)
+
+var (
+	ErrTemplateNotFound = errors.New(TemplateNotFound)
+	ErrTemplateNotOwner = errors.New(TemplateNotOwner)
+)
+
+// WorkflowTemplate graph is stored as a workflow document so credentials are never part of a template
+type WorkflowTemplate struct {
+	Graph       *WorkflowDocument `json:"graph"`
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Category    string            `json:"category"`
+	Description string            `json:"description,omitempty"`
+	AuthorID    string            `json:"author_id,omitempty"`
+	CreatedAt   string            `json:"created_at,omitempty"`
+	Builtin     bool              `json:"builtin"`
+}
+
+// RequestPublishTemplate UserID comes from the path, the one in the body must match it
+type RequestPublishTemplate struct {
+	UserID      string `json:"user_id"`
+	WorkflowID  string `json:"workflow_id" binding:"required"`
+	Name        string `json:"name" binding:"required,max=255"`
+	Category    string `json:"category" binding:"required,max=50"`
+	Description string `json:"description,omitempty" binding:"max=1000"`
+}
+
+type RequestCreateFromTemplate struct {
+	UserID          string `json:"user_id" binding:"required"`
+	Name            string `json:"name" binding:"required,max=255"`
+	DirectoryToSave string `json:"directory_to_save" binding:"required,max=255"`
+}
@@ -55,6 +55,8 @@ const (
 	UserInvalidStatus      = "Invalid status"
 	UserInvalidRole        = "Invalid role ID"
 	AuthInvalid            = "Authorization header is required"
+	AdminInvalid           = "admin token is not valid"
+	AdminTokenHeader       = "X-Admin-Token"
 )
 
 func (s UserStatus) String() string {
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	HooksPath              = "/api/v1/hooks/"
+	WebhookTokenBytes      = 32
+	WebhookSecretBytes     = 32
+	MaxWebhookBodySize     = 1 << 20 // 1MB
+	WebhookSignatureHeader = "X-Minireipaz-Signature"
+	WebhookSignaturePrefix = "sha256="
+	WebhookTimestampHeader = "X-Minireipaz-Timestamp"
+	TriggerSourceWebhook   = "webhook"
+	WebhookNotFound        = "webhook not found"
+	WebhookSignatureFailed = "webhook signature is not valid"
+	WebhookNodeInvalid     = "node is not a webhook trigger of the workflow"
+	WebhookCannotPublish   = "cannot publish webhook event"
+	WebhookBodyTooLarge    = "webhook body is too large"
+	WebhookDisabled        = "workflow of the webhook is not active"
+	WebhookReplayed        = "webhook request already received"
+	WebhookCreateKey       = "webhookcreate"
+)
+
+// WebhookTimestampTolerance signed requests older or newer than this are rejected, seen signatures are kept twice as long
+const WebhookTimestampTolerance = 5 * time.Minute
+
+var (
+	ErrWebhookNotFound        = errors.New(WebhookNotFound)
+	ErrWebhookSignatureFailed = errors.New(WebhookSignatureFailed)
+	ErrWebhookNodeInvalid     = errors.New(WebhookNodeInvalid)
+	ErrWebhookCannotPublish   = errors.New(WebhookCannotPublish)
+	ErrWebhookDisabled        = errors.New(WebhookDisabled)
+	ErrWebhookReplayed        = errors.New(WebhookReplayed)
+)
+
+// Webhook url of a webhook trigger node, secret is only returned when the webhook is created and stored encrypted
+type Webhook struct {
+	Token      string `json:"token"`
+	URL        string `json:"url"`
+	WorkflowID string `json:"workflow_id"`
+	NodeID     string `json:"node_id"`
+	UserID     string `json:"user_id"`
+	Secret     string `json:"secret,omitempty"`
+	CreatedAt  string `json:"created_at"`
+	// Disabled while the workflow is not active, the url is kept for when it runs again
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+type RequestCreateWebhook struct {
+	UserID     string `json:"user_id" binding:"required,max=50"`
+	WorkflowID string `json:"workflow_id" binding:"required,max=50"`
+	NodeID     string `json:"node_id" binding:"required,max=255"`
+	// Signed requests must send the unix seconds in WebhookTimestampHeader and
+	// the HMAC SHA256 of "<timestamp>.<body>" in WebhookSignatureHeader
+	Signed bool `json:"signed"`
+}
+
+// TriggerEvent published to the broker, executors start the workflow from NodeID
+type TriggerEvent struct {
+	EventID     string `json:"event_id"`
+	Source      string `json:"source"`
+	WorkflowID  string `json:"workflow_id"`
+	NodeID      string `json:"node_id"`
+	UserID      string `json:"user_id"`
+	ContentType string `json:"content_type"`
+	Body        string `json:"body"`
+	ReceivedAt  string `json:"received_at"`
+}
@@ -2,6 +2,8 @@ package models
 
 import (
 	"encoding/json"
+	"errors"
+	"math"
 	"time"
 )
 
@@ -20,7 +22,38 @@ const (
 	WorkflowDirectoryInvalid      = "Directory to save must be alphanumeric with max length of 255"
 	UUIDInvalid                   = "UUID must be a valid UUID"
 	WorkflowDateInvalid           = "Invalid date"
+	WorkflowNotInTrash            = "workflow not found in trash"
+	WorkflowCannotDelete          = "cannot delete workflow"
+	WorkflowCannotRestore         = "cannot restore workflow"
+	WorkflowRevisionNotFound      = "workflow revision not found"
+	WorkflowCannotRollback        = "cannot rollback workflow"
+	WorkflowRevisionInvalid       = "workflow revision must be a positive number"
+	WorkflowVersionConflict       = "workflow was updated by someone else, merge with the current version"
+	WorkflowVersionRequired       = "workflow version is required, send If-Match with the current version or * to overwrite"
+	WorkflowGraphInvalid          = "workflow graph is not valid"
+	WorkflowNotFound              = "workflow not found"
+	InitialNodeID                 = "initial-node"
+	MaxWorkflowNameLength         = 255
+	MaxWorkflowCopies             = 100
 	RateLimitUpdate               = 10 * time.Second
+	TrashRetention                = 30 * 24 * time.Hour
+	// one instance purges expired trash every tick, the lock expires before the next one
+	TrashPurgeTick    = 1 * time.Hour
+	TrashPurgeLockTTL = 50 * time.Minute
+	TrashPurgeLockKey = "lock:trash:purge"
+)
+
+// AnyVersion sent as If-Match: *, the only save that does not compare the version
+const AnyVersion uint32 = math.MaxUint32
+
+var (
+	ErrWorkflowNotInTrash   = errors.New(WorkflowNotInTrash)
+	ErrWorkflowNameExist    = errors.New(WorkflowNameExist)
+	ErrRevisionNotFound     = errors.New(WorkflowRevisionNotFound)
+	ErrVersionConflict      = errors.New(WorkflowVersionConflict)
+	ErrWorkflowNotFound     = errors.New(WorkflowNotFound)
+	ErrWorkflowGraphInvalid = errors.New(WorkflowGraphInvalid)
+	ErrUUIDInvalid          = errors.New(UUIDInvalid)
 )
 
 type WorkflowFrontend struct {
@@ -75,6 +108,7 @@ type Workflow struct {
 	Description       string     `json:"description,omitempty"`
 	Nodes             []Node     `json:"nodes,omitempty"`
 	Edges             []Edge     `json:"edges,omitempty"`
+	Version           uint32     `json:"version,omitempty"`
 	Status            Status     `json:"status,omitempty"`
 	IsActive          IsActive   `json:"is_active,omitempty"`
 }
@@ -104,6 +138,101 @@ type WorkflowPayload struct {
 	IsActive          IsActive   `json:"is_active,omitempty"`
 }
 
+// TrashedWorkflow is the entry kept in the per-user trash until it is restored or purged
+type TrashedWorkflow struct {
+	UUID            string `json:"id"`
+	UserID          string `json:"user_id"`
+	Name            string `json:"name"`
+	Description     string `json:"description,omitempty"`
+	DirectoryToSave string `json:"directory_to_save"`
+	DeletedAt       string `json:"deleted_at"`
+	ExpiresAt       string `json:"expires_at"`
+	// Workflow full copy, restore and purge commands carry the graph it had when deleted
+	Workflow *Workflow `json:"workflow,omitempty"`
+}
+
+const (
+	GraphErrorEmptyNodeID          = "empty_node_id"
+	GraphErrorDuplicateNodeID      = "duplicate_node_id"
+	GraphErrorMissingStartNode     = "missing_start_node"
+	GraphErrorMultipleStartNodes   = "multiple_start_nodes"
+	GraphErrorStartNodeInbound     = "start_node_inbound"
+	GraphErrorDuplicateEdgeID      = "duplicate_edge_id"
+	GraphErrorEdgeUnknownSource    = "edge_unknown_source"
+	GraphErrorEdgeUnknownTarget    = "edge_unknown_target"
+	GraphErrorCycle                = "cycle"
+	GraphErrorUnknownNodeType      = "unknown_node_type"
+	GraphErrorInvalidFormField     = "invalid_form_field"
+	GraphErrorMissingFormField     = "missing_form_field"
+	GraphErrorMissingCredential    = "missing_credential"
+	GraphErrorInvalidCredential    = "invalid_credential"
+	GraphErrorConnectionNotAllowed = "connection_not_allowed"
+)
+
+// GraphValidationError one problem found in a node or edge of the workflow graph
+type GraphValidationError struct {
+	NodeID  string `json:"node_id,omitempty"`
+	EdgeID  string `json:"edge_id,omitempty"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// GraphInvalidError returned by saves of a graph not valid, errors.Is matches ErrWorkflowGraphInvalid
+type GraphInvalidError struct {
+	Errors []GraphValidationError
+}
+
+func (e *GraphInvalidError) Error() string {
+	return WorkflowGraphInvalid
+}
+
+func (e *GraphInvalidError) Is(target error) bool {
+	return target == ErrWorkflowGraphInvalid
+}
+
+// RequestRollbackWorkflow UserID comes from the path, the one in the body must match it
+type RequestRollbackWorkflow struct {
+	UserID  string `json:"user_id" binding:"max=50"`
+	Version uint32 `json:"version" binding:"required"`
+}
+
+type RequestDuplicateWorkflow struct {
+	UserID          string `json:"user_id" binding:"required,max=50"`
+	KeepCredentials bool   `json:"keep_credentials"`
+}
+
+// WorkflowDiff node/edge level changes needed to go from FromVersion to ToVersion
+type WorkflowDiff struct {
+	Nodes       NodesDiff `json:"nodes"`
+	Edges       EdgesDiff `json:"edges"`
+	FromVersion uint32    `json:"from_version"`
+	ToVersion   uint32    `json:"to_version"`
+}
+
+type NodesDiff struct {
+	Added   []Node       `json:"added"`
+	Removed []Node       `json:"removed"`
+	Changed []NodeChange `json:"changed"`
+}
+
+type NodeChange struct {
+	Before Node   `json:"before"`
+	After  Node   `json:"after"`
+	ID     string `json:"id"`
+}
+
+type EdgesDiff struct {
+	Added   []Edge       `json:"added"`
+	Removed []Edge       `json:"removed"`
+	Changed []EdgeChange `json:"changed"`
+}
+
+type EdgeChange struct {
+	Before Edge   `json:"before"`
+	After  Edge   `json:"after"`
+	ID     string `json:"id"`
+}
+
 type WorkflowDetail struct {
 	WorkflowDescription *string     `json:"workflow_description,omitempty"`
 	WorkflowStatus      *int        `json:"workflow_status,omitempty"`
@@ -0,0 +1,48 @@
+package models
+
+import "errors"
+
+const (
+	WorkflowDocumentKind          = "minireipaz/workflow"
+	WorkflowDocumentSchemaVersion = 1
+	WorkflowDocumentInvalid       = "workflow document is not valid"
+	WorkflowCannotImport          = "cannot import workflow"
+	WorkflowExportFormatInvalid   = "export format must be json or yaml"
+	FormatJSON                    = "json"
+	FormatYAML                    = "yaml"
+)
+
+var (
+	ErrWorkflowDocumentInvalid = errors.New(WorkflowDocumentInvalid)
+	ErrWorkflowCannotImport    = errors.New(WorkflowCannotImport)
+)
+
+// WorkflowDocument portable copy of a workflow, without ids bound to an account or credential data
+type WorkflowDocument struct {
+	Viewport      *Viewport               `json:"viewport,omitempty"`
+	Kind          string                  `json:"kind"`
+	Name          string                  `json:"name"`
+	Description   string                  `json:"description,omitempty"`
+	Nodes         []Node                  `json:"nodes"`
+	Edges         []Edge                  `json:"edges"`
+	Credentials   []CredentialPlaceholder `json:"credentials"`
+	SchemaVersion int                     `json:"schema_version"`
+}
+
+// CredentialPlaceholder credential type a node needs, user must bind one of its own credentials
+type CredentialPlaceholder struct {
+	NodeID string `json:"node_id"`
+	Type   string `json:"type"`
+}
+
+type ImportedWorkflow struct {
+	Workflow    *Workflow               `json:"workflow"`
+	NodeIDs     map[string]string       `json:"node_ids"`
+	Credentials []CredentialPlaceholder `json:"credentials"`
+}
+
+type RequestImportWorkflow struct {
+	Document        WorkflowDocument
+	UserID          string
+	DirectoryToSave string
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	IndexChanged         = "workflow index changed while reconciling"
+	IndexCannotReconcile = "cannot reconcile workflow index"
+	// IndexPruneGrace prune only removes uuids missing in database for longer than this,
+	// workflows just created can still be in the broker or not replicated yet
+	IndexPruneGrace = 1 * time.Hour
+)
+
+var ErrIndexChanged = errors.New(IndexChanged)
+
+// UserIndexChange changes computed from Snapshot, only applied if the index still is the snapshot
+type UserIndexChange struct {
+	Snapshot        map[string]string
+	SetNames        map[string]string
+	UserID          string
+	RemoveNames     []string
+	SetWorkflows    []string
+	RemoveWorkflows []string
+}
+
+type IndexReconcileReport struct {
+	UserID string `json:"user_id"`
+	// names added or moved to another uuid
+	Fixed []string `json:"fixed"`
+	// names removed from the index
+	Removed []string `json:"removed"`
+	// uuids with a name already used by a newer workflow of the user
+	Conflicts []string `json:"conflicts"`
+	// uuids indexed but not found in database, can still be waiting in the broker
+	Missing []string `json:"missing"`
+	// missing uuids kept by prune because they are missing since less than IndexPruneGrace
+	Pending []string `json:"pending"`
+}
+
+type RequestReconcileIndexes struct {
+	UserID string `json:"user_id,omitempty" binding:"max=50"`
+	// Prune removes the uuids not found in database since IndexPruneGrace
+	Prune bool `json:"prune"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import "errors"
+
+const (
+	TransitionActivate        = "activate"
+	TransitionPause           = "pause"
+	TransitionResume          = "resume"
+	TransitionDraft           = "draft"
+	WorkflowTransitionInvalid = "workflow cannot change to this state from the current one"
+	WorkflowCannotChangeState = "cannot change state of workflow"
+	WorkflowLifecycleKey      = "workflowlifecycle"
+	WorkflowCannotActivate    = "workflow graph is not valid to be activated"
+)
+
+var (
+	ErrWorkflowTransitionInvalid = errors.New(WorkflowTransitionInvalid)
+	ErrWorkflowCannotActivate    = errors.New(WorkflowCannotActivate)
+)
+
+// LifecycleTransition allowed states before the transition and the state after it
+type LifecycleTransition struct {
+	From []IsActive
+	To   IsActive
+}
+
+// LifecycleTransitions state machine of IsActive, draft -> active <-> paused, both can go back to draft
+var LifecycleTransitions = map[string]LifecycleTransition{
+	TransitionActivate: {From: []IsActive{Draft}, To: Active},
+	TransitionPause:    {From: []IsActive{Active}, To: Paused},
+	TransitionResume:   {From: []IsActive{Paused}, To: Active},
+	TransitionDraft:    {From: []IsActive{Active, Paused}, To: Draft},
+}
+
+type RequestLifecycleWorkflow struct {
+	UserID string `json:"user_id" binding:"required,max=50"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import "errors"
+
+const (
+	// DefaultWorkflowPageSize pages following a cursor without limit, without both the whole list is returned
+	DefaultWorkflowPageSize = 50
+	MaxWorkflowPageSize     = 200
+	SortByUpdatedAt         = "updated_at"
+	SortByCreatedAt         = "created_at"
+	SortByName              = "name"
+	SortOrderAsc            = "asc"
+	SortOrderDesc           = "desc"
+	WorkflowListInvalid     = "invalid filters or sorting for workflow list"
+	WorkflowCursorInvalid   = "cursor is not valid for this listing"
+)
+
+var ErrWorkflowCursorInvalid = errors.New(WorkflowCursorInvalid)
+
+// WorkflowListQuery filters and sorting are passed as they are to the pipe, empty ones are not sent
+type WorkflowListQuery struct {
+	UserID          string   `form:"-"`
+	Cursor          string   `form:"cursor" binding:"max=512"`
+	Name            string   `form:"name" binding:"max=255"`
+	DirectoryToSave string   `form:"directory_to_save" binding:"max=255"`
+	SortBy          string   `form:"sort_by" binding:"omitempty,oneof=updated_at created_at name"`
+	Order           string   `form:"order" binding:"omitempty,oneof=asc desc"`
+	Limit           uint64   `form:"limit" binding:"omitempty,min=1,max=200"`
+	Status          Status   `form:"status" binding:"omitempty,min=1,max=5"`
+	IsActive        IsActive `form:"is_active" binding:"omitempty,min=1,max=3"`
+}
+
+// WorkflowCursor keyset position, the last row of the page by sort value and uuid to break ties.
+// Sorting is kept inside so a cursor cannot be reused with another order
+type WorkflowCursor struct {
+	SortBy string `json:"s"`
+	Order  string `json:"o"`
+	Value  string `json:"v"`
+	ID     string `json:"id"`
+}
+
+type WorkflowPage struct {
+	Workflows  []Workflow `json:"workflow"`
+	NextCursor string     `json:"next_cursor"`
+	HasMore    bool       `json:"has_more"`
+}
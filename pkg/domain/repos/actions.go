@@ -1,6 +1,7 @@
 package repos
 
 import (
+	"context"
 	"minireipaz/pkg/domain/models"
 	"time"
 )
@@ -8,22 +9,30 @@ import (
 type ActionsService interface {
 	CreateActionsGoogleSheet(newAction models.RequestGoogleAction, actionUserToken *string) (sendedBroker bool, sendedToService bool, actionID *string)
 	CreateActionsNotion(newAction models.RequestGoogleAction, actionUserToken *string) (sendedBroker bool, sendedToService bool, actionID *string)
+	GetActionResult(ctx context.Context, userID, actionID *string, wait time.Duration) (result *models.ActionResult, err error)
+	ScheduledActionCreator
+	DeadLetterReplayer
 }
 
 type ActionsHTTPRepository interface {
 	SendAction(newAction *models.RequestGoogleAction, actionUserToken *string) bool
 	PublishCommand(data *models.ActionsCommand, serviceUser *string) *models.ResponseGetGoogleSheetByID
+	GetActionByID(userID, actionID *string) (*models.InfoActions, error)
 }
 
 type ActionsRedisRepoInterface interface {
-	Create(newAction *models.RequestGoogleAction) (created bool, exist bool, err error)
+	// Create message is written to the outbox in the same transaction, nil writes only the action
+	Create(newAction *models.RequestGoogleAction, message *models.OutboxMessage) (created bool, exist bool, err error)
 	Remove(newAction *models.RequestGoogleAction) (removed bool)
 	ValidateActionGlobalUUID(field *string) (bool, error)
 	AcquireLock(key, value string, expiration time.Duration) (locked bool, err error)
 	RemoveLock(key string) bool
 	SetNX(hashKey, actionID string, expiration time.Duration) (bool, error)
+	GetActionOwner(actionID *string) (sub string, err error)
 }
 
 type ActionsBrokerRepository interface {
 	Create(newAction *models.RequestGoogleAction) bool
+	// CreateCommand builds the create command for the outbox without publishing it
+	CreateCommand(newAction *models.RequestGoogleAction) (message *models.OutboxMessage, err error)
 }
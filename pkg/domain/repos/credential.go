@@ -1,6 +1,7 @@
 package repos
 
 import (
+	"context"
 	"minireipaz/pkg/domain/models"
 	"time"
 )
@@ -8,10 +9,11 @@ import (
 type CredentialService interface {
 	CreateCredential(credentialFrontend *models.RequestCreateCredential) (*models.RequestExchangeCredential, error)
 	CreateTokenCredential(credentialFrontend *models.RequestCreateCredential) (saved bool, transformedCredentialID *string, err error)
-	ExchangeGoogleCredential(currentCredential *models.RequestExchangeCredential) (token, refresh *string, expire *time.Time, stateInfo *models.RequestExchangeCredential, err error)
+	ExchangeOAuthCredential(currentCredential *models.RequestExchangeCredential) (token, refresh *string, expire *time.Time, stateInfo *models.RequestExchangeCredential, err error)
 	GetAllCredentials(userID *string) (*models.ResponseGetCredential, bool)
 	TransformWorkflow(currenteCredential *models.RequestExchangeCredential, workflow *models.Workflow) *models.Workflow
 	GetCredentialByID(userID *string, credentialID *string) (response *models.ResponseGetCredential)
+	DeadLetterReplayer
 }
 
 type CredentialHTTPRepository interface {
@@ -19,19 +21,49 @@ type CredentialHTTPRepository interface {
 	GetCredentialByID(userID *string, credentialID *string, limitCount uint64) (*[]models.RequestExchangeCredential, error)
 }
 
-type CredentialGoogleHTTPRepository interface {
-	GenerateAuthURL(credential *models.RequestExchangeCredential, credentialCreatedNew *bool) *string
-	ExchangeGoogleCredential(currentCredential *models.RequestExchangeCredential) (token, refresh *string, expire *time.Time, stateInfo *models.RequestExchangeCredential, err error)
+// CredentialOAuthHTTPRepository same flow for every provider, the differences are in models.OAuthProvider
+type CredentialOAuthHTTPRepository interface {
+	GenerateAuthURL(provider *models.OAuthProvider, credential *models.RequestExchangeCredential, state string) (authURL *string)
+	ExchangeCode(provider *models.OAuthProvider, stateInfo *models.RequestExchangeCredential, code string) (token *models.OAuthToken, err error)
+	// RefreshToken ErrOAuthRefreshRejected when the provider does not accept the refresh token anymore
+	RefreshToken(provider *models.OAuthProvider, credential *models.RequestExchangeCredential) (token *models.OAuthToken, err error)
 }
 
-type CredentialFacebookHTTPRepository interface {
+type OAuthProviderRegistry interface {
+	// Get provider of a credential type, ErrOAuthProviderUnknown when it is not an oauth credential
+	Get(credentialType string) (provider *models.OAuthProvider, err error)
+	// Register replaces the provider with the same name
+	Register(provider *models.OAuthProvider) (err error)
+	GetAll() (providers []models.OAuthProvider)
 }
 
 type CredentialRedisRepository interface {
-	SaveTemporalAuthURLData(currentCredential *models.RequestCreateCredential) (inserted bool, err error)
+	SaveTemporalAuthURLData(stateID *string, stateInfo *models.RequestExchangeCredential, ttl time.Duration) (inserted bool, err error)
+	// PopTemporalAuthURLData a state is read only once, ErrOAuthStateInvalid when missing or expired
+	PopTemporalAuthURLData(stateID *string) (stateInfo *models.RequestExchangeCredential, err error)
 	AddLock(sub *string) (locked bool, err error)
 }
 
 type CredentialBrokerRepository interface {
 	CreateCredential(token, refresh *string, expire *time.Time, stateInfo *models.RequestExchangeCredential) (sended bool)
 }
+
+// CredentialRefreshService exchanges the refresh token of a credential for a new access token,
+// a rejected refresh token leaves the credential inactive
+type CredentialRefreshService interface {
+	// RefreshCredential only refreshes when the access token expires within models.CredentialRefreshAhead
+	RefreshCredential(userID, credentialID *string) (credential *models.RequestExchangeCredential, refreshed bool, err error)
+	Schedule(credential *models.RequestExchangeCredential)
+	Tick(now time.Time) (refreshed int, err error)
+	Start(ctx context.Context)
+}
+
+type CredentialRefreshRedisRepository interface {
+	ScheduleRefresh(entry *models.CredentialRefreshEntry, at time.Time) (err error)
+	GetDueRefreshes(before time.Time, limit int64) (entries []models.CredentialRefreshEntry, err error)
+	RemoveRefresh(entry *models.CredentialRefreshEntry) (err error)
+	LockRefresh(credentialID *string, ttl time.Duration) (locked bool, err error)
+	UnlockRefresh(credentialID *string) (err error)
+	AcquireLeadership(instanceID string, ttl time.Duration) (leader bool, err error)
+	ReleaseLeadership(instanceID string) (err error)
+}
@@ -0,0 +1,29 @@
+package repos
+
+import "minireipaz/pkg/domain/models"
+
+type DeadLetterService interface {
+	// Add never fails the caller, the command is already lost if it cannot be stored
+	Add(origin string, payload interface{}, cause error, attempts int)
+	RegisterReplayer(origin string, replayer DeadLetterReplayer)
+	GetDeadLetters(query *models.DeadLetterQuery) (letters []models.DeadLetter, err error)
+	GetDeadLetter(id *string) (letter *models.DeadLetter, err error)
+	Replay(id *string) (letter *models.DeadLetter, err error)
+	Discard(id *string) (err error)
+}
+
+// DeadLetterReplayer implemented by the services owning the origins
+type DeadLetterReplayer interface {
+	ReplayDeadLetter(letter *models.DeadLetter) (err error)
+}
+
+type DeadLetterRedisRepository interface {
+	Save(letter *models.DeadLetter) (id string, err error)
+	GetAll(limit int64) (letters []models.DeadLetter, err error)
+	Get(id *string) (letter *models.DeadLetter, err error)
+	Remove(id *string) (err error)
+}
+
+type DeadLetterBrokerRepository interface {
+	Publish(letter *models.DeadLetter) (sended bool)
+}
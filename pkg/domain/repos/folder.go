@@ -0,0 +1,17 @@
+package repos
+
+import "minireipaz/pkg/domain/models"
+
+type FolderService interface {
+	GetFolderTree(userID *string) (root *models.Folder, err error)
+	CreateFolder(userID, path *string) (folder string, err error)
+	RenameFolder(request *models.RequestRenameFolder) (report *models.FolderMoveReport, err error)
+	MoveFolder(request *models.RequestMoveFolder) (report *models.FolderMoveReport, err error)
+	MoveWorkflows(request *models.RequestMoveWorkflows) (report *models.FolderMoveReport, err error)
+}
+
+type FolderRedisRepository interface {
+	Save(userID, path *string) (created bool, err error)
+	GetAll(userID *string) (paths []string, err error)
+	Move(userID, from, to *string) (err error)
+}
@@ -0,0 +1,21 @@
+package repos
+
+import (
+	"minireipaz/pkg/domain/models"
+	"time"
+)
+
+type IdempotencyService interface {
+	// Begin nil when the key is reserved for this request, the stored record when it must be replayed
+	Begin(scope, key, requestHash string) (stored *models.IdempotencyRecord, err error)
+	Complete(scope, key string, record *models.IdempotencyRecord) (err error)
+	// Release frees the key of a failed request so the client can retry it
+	Release(scope, key string) (err error)
+}
+
+type IdempotencyRedisRepository interface {
+	// Reserve saves the record only when the key is free, otherwise returns the one already stored
+	Reserve(key string, record *models.IdempotencyRecord, ttl time.Duration) (reserved bool, stored *models.IdempotencyRecord, err error)
+	Save(key string, record *models.IdempotencyRecord, ttl time.Duration) (err error)
+	Remove(key string) (err error)
+}
@@ -0,0 +1,9 @@
+package repos
+
+import "minireipaz/pkg/domain/models"
+
+type NodeService interface {
+	GetCatalog() []models.NodeTypeDefinition
+	GetNodeType(nodeType string) (definition models.NodeTypeDefinition, exist bool)
+	ValidateNodes(workflow *models.Workflow) []models.GraphValidationError
+}
@@ -0,0 +1,28 @@
+package repos
+
+import (
+	"context"
+	"minireipaz/pkg/domain/models"
+	"time"
+)
+
+type OutboxService interface {
+	// Tick is also called right after a transaction writes to the outbox, so commands do not wait for the next tick
+	Tick(now time.Time) (relayed int, err error)
+	Relay(now time.Time) (relayed int, err error)
+	Start(ctx context.Context)
+}
+
+type OutboxRedisRepository interface {
+	// GetPending oldest first, only messages written before the given time
+	GetPending(before time.Time, limit int64) (messages []models.OutboxMessage, err error)
+	IsPublished(dedupKey *string) (published bool, err error)
+	// Ack marks the dedup key as published and removes the message from the outbox
+	Ack(message *models.OutboxMessage) (err error)
+	AcquireLeadership(instanceID string, ttl time.Duration) (leader bool, err error)
+	ReleaseLeadership(instanceID string) (err error)
+}
+
+type OutboxBrokerRepository interface {
+	Publish(message *models.OutboxMessage) (sended bool)
+}
@@ -0,0 +1,24 @@
+package repos
+
+import "minireipaz/pkg/domain/models"
+
+type RunService interface {
+	CreateRun(userID, workflowID *string, dryRun bool) (run *models.Run, graphErrors []models.GraphValidationError, err error)
+	GetWorkflowRuns(query *models.RunListQuery) (page *models.RunPage, err error)
+	GetRun(userID, runID *string) (run *models.RunDetail, err error)
+}
+
+type RunRedisRepository interface {
+	Save(run *models.Run, message *models.OutboxMessage) (err error)
+	Get(runID *string) (run *models.Run, err error)
+}
+
+type RunBrokerRepository interface {
+	CreateCommand(run *models.Run, graph []models.RunNode) (message *models.OutboxMessage, err error)
+}
+
+type RunHTTPRepository interface {
+	GetWorkflowRuns(userID, workflowID *string, cursor *models.WorkflowCursor, limitCount uint64) (*models.InfoRuns, error)
+	GetRun(userID, runID *string) (*models.InfoRuns, error)
+	GetRunSteps(userID, runID *string) (*models.InfoRunSteps, error)
+}
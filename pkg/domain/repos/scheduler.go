@@ -0,0 +1,38 @@
+package repos
+
+import (
+	"context"
+	"minireipaz/pkg/domain/models"
+	"time"
+)
+
+type SchedulerService interface {
+	SaveSchedule(request *models.RequestCreateSchedule) (schedule *models.Schedule, err error)
+	ScheduleAction(action *models.RequestGoogleAction) (schedule *models.Schedule, err error)
+	GetSchedules(userID, workflowID *string) (schedules []models.Schedule, err error)
+	RemoveSchedule(userID, workflowID, nodeID *string) (err error)
+	Tick(now time.Time) (fired int, err error)
+	RunDue(now time.Time) (fired int, err error)
+	Start(ctx context.Context)
+	RegisterCreator(creator ScheduledActionCreator)
+	WorkflowTriggers
+}
+
+// ScheduledActionCreator creates every run of a schedule, it is registered later because the creator schedules polling actions too
+type ScheduledActionCreator interface {
+	CreateScheduledAction(newAction *models.RequestGoogleAction) (actionID *string, err error)
+}
+
+type SchedulerRedisRepository interface {
+	// Save disabled schedules are kept out of the due set
+	Save(schedule *models.Schedule) (err error)
+	Get(workflowID, nodeID *string) (schedule *models.Schedule, err error)
+	GetByWorkflow(workflowID *string) (schedules []models.Schedule, err error)
+	Remove(workflowID, nodeID *string) (err error)
+	RemoveByWorkflow(workflowID *string) (err error)
+	GetDue(now time.Time, limit int64) (schedules []models.Schedule, err error)
+	// Advance moves the next fire time only if nobody fired it before, false when already claimed
+	Advance(schedule *models.Schedule, expectedNextRunAt string) (advanced bool, err error)
+	AcquireLeadership(instanceID string, ttl time.Duration) (leader bool, err error)
+	ReleaseLeadership(instanceID string) (err error)
+}
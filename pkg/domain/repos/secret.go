@@ -0,0 +1,21 @@
+package repos
+
+import "minireipaz/pkg/domain/models"
+
+// SecretKeyProvider holds the key encryption keys, it only wraps the data keys of the secrets.
+// Old keys are kept after a rotation to unwrap what was saved with them
+type SecretKeyProvider interface {
+	// CurrentKeyID key that wraps new data keys
+	CurrentKeyID() string
+	WrapKey(keyID string, dataKey []byte) (wrapped []byte, err error)
+	// UnwrapKey ErrSecretKeyUnknown when the key is not in the provider anymore
+	UnwrapKey(keyID string, wrapped []byte) (dataKey []byte, err error)
+}
+
+type SecretService interface {
+	Encrypt(plaintext string) (ciphertext string, err error)
+	Decrypt(ciphertext string) (plaintext string, err error)
+	// EncryptCredential token, refresh token and client secret
+	EncryptCredential(data *models.DataCredential) (err error)
+	DecryptCredential(data *models.DataCredential) (err error)
+}
@@ -0,0 +1,16 @@
+package repos
+
+import (
+	"context"
+	"minireipaz/pkg/domain/models"
+)
+
+type StatusService interface {
+	Subscribe(ctx context.Context, userID *string) (events <-chan models.StatusEvent, err error)
+}
+
+// StatusPublisher events channel is closed when ctx is done
+type StatusPublisher interface {
+	Publish(event *models.StatusEvent) (err error)
+	Subscribe(ctx context.Context, userID *string) (events <-chan models.StatusEvent, err error)
+}
@@ -0,0 +1,18 @@
+package repos
+
+import "minireipaz/pkg/domain/models"
+
+type TemplateService interface {
+	GetTemplates(category string) (templates []models.WorkflowTemplate, err error)
+	GetTemplate(templateID *string) (template *models.WorkflowTemplate, err error)
+	PublishTemplate(request *models.RequestPublishTemplate) (template *models.WorkflowTemplate, err error)
+	DeleteTemplate(userID, templateID *string) (err error)
+	CreateWorkflowFromTemplate(templateID *string, request *models.RequestCreateFromTemplate) (imported *models.ImportedWorkflow, graphErrors []models.GraphValidationError, err error)
+}
+
+type TemplateRedisRepository interface {
+	Save(template *models.WorkflowTemplate) (saved bool, err error)
+	GetAll() (templates []models.WorkflowTemplate, err error)
+	Get(templateID *string) (template *models.WorkflowTemplate, err error)
+	Remove(templateID *string) (removed bool, err error)
+}
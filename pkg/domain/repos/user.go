@@ -4,6 +4,7 @@ import "minireipaz/pkg/domain/models"
 
 type UserService interface {
 	SynUser(user *models.SyncUserRequest) (created, exist bool)
+	DeadLetterReplayer
 }
 
 type UserRedisRepository interface {
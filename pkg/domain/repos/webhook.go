@@ -0,0 +1,29 @@
+package repos
+
+import (
+	"minireipaz/pkg/domain/models"
+	"time"
+)
+
+type WebhookService interface {
+	CreateWebhook(request *models.RequestCreateWebhook) (webhook *models.Webhook, err error)
+	GetWebhooks(userID, workflowID *string) (webhooks []models.Webhook, err error)
+	RemoveWebhook(userID, workflowID, nodeID *string) (err error)
+	ReceiveWebhook(token *string, body []byte, contentType, signature, timestamp string) (event *models.TriggerEvent, err error)
+	WorkflowTriggers
+}
+
+type WebhookRedisRepository interface {
+	// Save replaces the previous token of the node
+	Save(webhook *models.Webhook) (err error)
+	GetByToken(token *string) (webhook *models.Webhook, err error)
+	GetByWorkflow(workflowID *string) (webhooks []models.Webhook, err error)
+	Remove(workflowID, nodeID *string) (err error)
+	RemoveByWorkflow(workflowID *string) (err error)
+	// SaveNonce false when the nonce of the webhook was already seen before it expired
+	SaveNonce(token, nonce *string, ttl time.Duration) (saved bool, err error)
+}
+
+type WebhookBrokerRepository interface {
+	PublishTrigger(event *models.TriggerEvent) (sended bool)
+}
@@ -1,6 +1,7 @@
 package repos
 
 import (
+	"context"
 	"minireipaz/pkg/domain/models"
 	"time"
 
@@ -11,28 +12,90 @@ type WorkflowService interface {
 	CreateWorkflow(workflowFrontend *models.WorkflowFrontend) (created bool, exist bool, workflow *models.Workflow)
 	GetWorkflow(userID, workflowID *string) (newWorkflow *models.Workflow, exist bool)
 	GetAllWorkflows(userID *string) (allWorkflows []models.Workflow, err error)
-	UpdateWorkflow(workflow *models.Workflow) (updated bool, exist bool)
+	ListWorkflows(query *models.WorkflowListQuery) (page *models.WorkflowPage, err error)
+	UpdateWorkflow(workflow *models.Workflow) (updated bool, exist bool, err error)
+	DuplicateWorkflow(userID, workflowID *string, keepCredentials bool) (created bool, exist bool, workflow *models.Workflow, err error)
+	ReconcileIndexes(userID *string, prune bool) (reports []models.IndexReconcileReport, err error)
+	DeleteWorkflow(userID, workflowID *string) (deleted bool, exist bool)
+	GetWorkflowsTrash(userID *string) (trashed []models.TrashedWorkflow, err error)
+	RestoreWorkflow(userID, workflowID *string) (restored bool, err error)
+	PurgeWorkflow(userID, workflowID *string) (purged bool, exist bool)
+	GetWorkflowRevisions(userID, workflowID *string) (revisions []models.Workflow, err error)
+	GetWorkflowRevision(userID, workflowID *string, version uint32) (revision *models.Workflow, err error)
+	DiffWorkflowRevisions(userID, workflowID *string, fromVersion, toVersion uint32) (diff *models.WorkflowDiff, err error)
+	RollbackWorkflow(userID, workflowID *string, version uint32) (updated bool, exist bool, err error)
+	ChangeLifecycle(userID, workflowID *string, transition string) (workflow *models.Workflow, graphErrors []models.GraphValidationError, err error)
+	ValidateWorkflowGraph(workflow *models.Workflow) (graphErrors []models.GraphValidationError)
+	ExportWorkflow(userID, workflowID *string) (document *models.WorkflowDocument, exist bool)
+	ImportWorkflow(userID, directoryToSave *string, document *models.WorkflowDocument) (imported *models.ImportedWorkflow, graphErrors []models.GraphValidationError, err error)
 	ValidateWorkflowGlobalUUID(uuid *string) bool
 	ValidateUserWorkflowUUID(worklfowID, name *string) bool
+	// IsWorkflowActive false for paused, draft, trashed and purged workflows
+	IsWorkflowActive(workflowID *string) (active bool, err error)
+	RegisterTriggers(triggers WorkflowTriggers)
+	TrashPurger
+	DeadLetterReplayer
+}
+
+// TrashPurger removes for good the workflows whose trash retention expired
+type TrashPurger interface {
+	PurgeExpiredTrash(now time.Time) (purged int, err error)
+	StartTrashPurge(ctx context.Context)
+}
+
+// WorkflowTriggers what fires a workflow by itself, follows every lifecycle change of the workflow
+type WorkflowTriggers interface {
+	// SyncTriggers triggers only fire while the workflow is active, disabled ones are kept to enable them again
+	SyncTriggers(workflowID *string, active bool) (err error)
+	// RemoveTriggers the workflow was purged
+	RemoveTriggers(workflowID *string) (err error)
 }
 
 type WorkflowRedisRepoInterface interface {
-	Create(workflow *models.Workflow) (created bool, exist bool)
+	// Create message is written to the outbox in the same transaction, nil writes only the index
+	Create(workflow *models.Workflow, message *models.OutboxMessage) (created bool, exist bool)
 	Update(worflow *models.Workflow) (updated bool, exist bool)
 	Remove(workflow *models.Workflow) (removed bool)
 	ValidateWorkflowGlobalUUID(uuid *string) bool
 	ValidateUserWorkflowUUID(userID, name *string) bool
+	// NameExists unlike ValidateUserWorkflowUUID a failed read is returned, not taken as an existing name
+	NameExists(userID, name *string) (exist bool, err error)
 	GetByUUID(id uuid.UUID) (*models.Workflow, error)
 	AcquireLock(key, value string, expiration time.Duration) (locked bool, err error)
 	RemoveLock(key string) bool
+	// MoveToTrash, RestoreFromTrash, RemoveFromTrash, RenameInIndex and SetLifecycle write the message
+	// to the outbox in the same transaction as the state, nil writes only the state
+	MoveToTrash(trashed *models.TrashedWorkflow, message *models.OutboxMessage) (moved bool, err error)
+	GetTrash(userID *string) (trashed []models.TrashedWorkflow, err error)
+	GetTrashUsers() (userIDs []string, err error)
+	RestoreFromTrash(userID, workflowID *string, message *models.OutboxMessage) (restored *models.TrashedWorkflow, err error)
+	// RemoveFromTrash the stored state of the workflow goes with it
+	RemoveFromTrash(userID, workflowID *string, message *models.OutboxMessage) (removed bool, err error)
+	NextVersion(workflowID *string) (version uint32, err error)
+	GetVersion(workflowID *string) (version uint32, err error)
+	CompareAndIncrVersion(workflowID *string, expected uint32) (version uint32, matched bool, err error)
+	RevertVersion(workflowID *string, version uint32) (reverted bool)
+	RenameInIndex(workflow *models.Workflow, message *models.OutboxMessage) (previousName string, err error)
+	GetUserIndex(userID *string) (names map[string]string, err error)
+	GetWorkflowsIndex() (owners map[string]string, err error)
+	GetIndexedUsers() (userIDs []string, err error)
+	ApplyUserIndex(change *models.UserIndexChange) (err error)
+	TrackMissing(userID *string, workflowIDs []string, now time.Time) (since map[string]time.Time, err error)
+	// GetLifecycle stored state of the workflow, the one sent by clients is never trusted
+	GetLifecycle(workflowID *string) (state models.IsActive, err error)
+	SetLifecycle(workflowID *string, state models.IsActive, message *models.OutboxMessage) (err error)
 }
 
+// WorkflowBrokerRepository every workflow command goes through the outbox, nothing is published directly
 type WorkflowBrokerRepository interface {
-	Create(workflow *models.Workflow) (sended bool)
-	Update(workflow *models.Workflow) (sended bool)
+	// CreateCommand builds the command of the given type for the outbox without publishing it
+	CreateCommand(workflow *models.Workflow, commandType string) (message *models.OutboxMessage, err error)
 }
 
 type WorkflowHTTPRepository interface {
 	GetWorkflowDataByID(userID, workflowID *string, limitCount uint64) (*models.InfoWorkflow, error)
 	GetAllWorkflows(userID *string, limitCount uint64) (*models.InfoWorkflow, error)
+	GetWorkflowsPage(query *models.WorkflowListQuery, cursor *models.WorkflowCursor, limitCount uint64) (*models.InfoWorkflow, error)
+	GetWorkflowRevisions(userID, workflowID *string, limitCount uint64) (*models.InfoWorkflow, error)
+	GetWorkflowRevision(userID, workflowID *string, version uint32) (*models.InfoWorkflow, error)
 }
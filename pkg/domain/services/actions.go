@@ -1,6 +1,9 @@
 package services
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"minireipaz/pkg/common"
@@ -13,56 +16,50 @@ import (
 )
 
 type ActionsServiceImpl struct {
-	redisRepo  repos.ActionsRedisRepoInterface
-	brokerRepo repos.ActionsBrokerRepository
-	httpRepo   repos.ActionsHTTPRepository
+	redisRepo   repos.ActionsRedisRepoInterface
+	brokerRepo  repos.ActionsBrokerRepository
+	httpRepo    repos.ActionsHTTPRepository
+	scheduler   repos.SchedulerService
+	publisher   repos.StatusPublisher
+	authService repos.AuthService
+	deadLetters repos.DeadLetterService
+	outbox      repos.OutboxService
 }
 
-func NewActionsService(repoRedis repos.ActionsRedisRepoInterface, repoBroker repos.ActionsBrokerRepository, repoHTTP repos.ActionsHTTPRepository) repos.ActionsService {
+func NewActionsService(repoRedis repos.ActionsRedisRepoInterface, repoBroker repos.ActionsBrokerRepository, repoHTTP repos.ActionsHTTPRepository, scheduler repos.SchedulerService, publisher repos.StatusPublisher, authService repos.AuthService, deadLetters repos.DeadLetterService, outbox repos.OutboxService) repos.ActionsService {
 	return &ActionsServiceImpl{
-		redisRepo:  repoRedis,
-		brokerRepo: repoBroker,
-		httpRepo:   repoHTTP,
+		redisRepo:   repoRedis,
+		brokerRepo:  repoBroker,
+		httpRepo:    repoHTTP,
+		scheduler:   scheduler,
+		publisher:   publisher,
+		authService: authService,
+		deadLetters: deadLetters,
+		outbox:      outbox,
 	}
 }
 
 func (a *ActionsServiceImpl) CreateActionsGoogleSheet(newAction models.RequestGoogleAction, actionUserToken *string) (sendedBroker bool, sendedToService bool, actionID *string) {
-	for i := 1; i < models.MaxAttempts; i++ {
-		now := time.Now().UTC().Format(models.LayoutTimestamp)
-		// looped 10 times with time.sleep in case uuid collisions
-		// locked with same uuid for 30 seconds
-		locked, err := a.setActionID(&newAction, &now)
-		if err != nil || !locked { //  in case that 10 loops cannot get new UUID just return because cannot get new uuid
-			return false, locked, nil
-		}
-		// dont create lock, just check if exist lock and in case not exist lock return false
-		sendedBroker, sendedToService = a.retriesCreateAction(&newAction, now, actionUserToken, sendedBroker, sendedToService)
-		if sendedBroker && sendedToService { // happy path
-			// remove lock in case not passed 30 seconds
-			a.removeLockActionID(&newAction.ActionID)
-			return sendedBroker, sendedToService, &newAction.ActionID
-		}
-		// if !sendedBroker && !sendedToService {
-		// 	return true, true, nil
-		// }
-
-		waitTime := common.RandomDuration(models.MaxRangeSleepDuration, models.MinRangeSleepDuration, i)
-		log.Printf("WARNING | Failed to create action %s for user %s , attempt %d:. Retrying in %v", newAction.ActionID, newAction.Sub, i, waitTime)
-		time.Sleep(waitTime)
-	}
-	log.Print("ERROR | Needs to add to Dead Letter. Cannot send action to broker or service")
-	// TODO: dead letter
-	return false, false, nil
+	return a.createAction(newAction, actionUserToken)
 }
 
 func (a *ActionsServiceImpl) ValidateActionGlobalUUID(field *string) (bool, error) {
 	return a.redisRepo.ValidateActionGlobalUUID(field)
 }
 
-// sendedBroker passed to Function CDC problema
-func (a *ActionsServiceImpl) retriesCreateAction(newAction *models.RequestGoogleAction, now string, actionUserToken *string, sendedBroker bool, sendedToService bool) (bool, bool) {
+// sendedBroker passed to Function CDC problema, scheduled runs are sent to the service even if they have pollmode
+func (a *ActionsServiceImpl) retriesCreateAction(newAction *models.RequestGoogleAction, now string, actionUserToken *string, sendedBroker bool, sendedToService bool, scheduled bool) (bool, bool) {
 	newAction.CreatedAt = now
-	created, exist, err := a.redisRepo.Create(newAction)
+	var message *models.OutboxMessage
+	if !sendedBroker {
+		var err error
+		message, err = a.brokerRepo.CreateCommand(newAction)
+		if err != nil {
+			log.Printf("ERROR | Cannot build create command for action %s: %v", newAction.ActionID, err)
+			return false, false
+		}
+	}
+	created, exist, err := a.redisRepo.Create(newAction, message)
 	if err != nil {
 		log.Printf("ERROR | acquiring lock for retriesCreateAction: %v", err)
 		return false, false
@@ -74,12 +71,9 @@ func (a *ActionsServiceImpl) retriesCreateAction(newAction *models.RequestGoogle
 	if !sendedBroker {
 		// case you dont have a connection of type http (http sink, http connect, ...) activated/created in the broker,
 		//  enable sending to the service that processes the action. sending is done via http
-		sendedBroker = a.brokerRepo.Create(newAction)
-		if !sendedBroker {
-			log.Printf("ERROR | SendBroker Failed to publish action event %v", newAction)
-			a.redisRepo.Remove(newAction)
-			return false, false
-		}
+		// the command is in the outbox with the action, the ticker publishes it if this relay fails
+		dispatchOutbox(a.outbox, message)
+		sendedBroker = true
 	}
 
 	// this section is necessary if not set http sink from kafka connect, connector, etc...
@@ -87,15 +81,19 @@ func (a *ActionsServiceImpl) retriesCreateAction(newAction *models.RequestGoogle
 	if config.GetEnv("CONNECTOR_HTTP_SINK_ENABLED", "n") == "n" {
 		if !sendedToService {
 			// TODO: mode testmode for testing pourpose Pollmode maybe can be omitted
-			if newAction.Testmode || newAction.Pollmode == models.NopollNode {
+			if newAction.Testmode || newAction.Pollmode == models.NopollNode || scheduled {
 				sendedToService = a.httpRepo.SendAction(newAction, actionUserToken)
 				if !sendedToService {
 					log.Printf("ERROR | SendedtoService Failed to publish action event %v", newAction)
 					return false, false
 				}
 			} else {
-				// TODO: scheduler
-				log.Printf("TODO: implement scheduler")
+				// polling nodes are sent later by the scheduler every time they are due
+				if _, err := a.scheduler.ScheduleAction(newAction); err != nil {
+					log.Printf("ERROR | Cannot schedule action %s with pollmode %s: %v", newAction.ActionID, newAction.Pollmode, err)
+					return false, false
+				}
+				sendedToService = true
 			}
 		}
 	}
@@ -147,8 +145,12 @@ func (a *ActionsServiceImpl) setActionID(newAction *models.RequestGoogleAction,
 	return locked, nil
 }
 
-// maybe can be merged
 func (a *ActionsServiceImpl) CreateActionsNotion(newAction models.RequestGoogleAction, actionUserToken *string) (sendedBroker bool, sendedToService bool, actionID *string) {
+	return a.createAction(newAction, actionUserToken)
+}
+
+// createAction same flow for every type of action, the type only changes the uri of the action service
+func (a *ActionsServiceImpl) createAction(newAction models.RequestGoogleAction, actionUserToken *string) (sendedBroker bool, sendedToService bool, actionID *string) {
 	for i := 1; i < models.MaxAttempts; i++ {
 		now := time.Now().UTC().Format(models.LayoutTimestamp)
 		// looped 10 times with time.sleep in case uuid collisions
@@ -158,10 +160,11 @@ func (a *ActionsServiceImpl) CreateActionsNotion(newAction models.RequestGoogleA
 			return false, locked, nil
 		}
 		// dont create lock, just check if exist lock and in case not exist lock return false
-		sendedBroker, sendedToService = a.retriesCreateAction(&newAction, now, actionUserToken, sendedBroker, sendedToService)
+		sendedBroker, sendedToService = a.retriesCreateAction(&newAction, now, actionUserToken, sendedBroker, sendedToService, false)
 		if sendedBroker && sendedToService { // happy path
 			// remove lock in case not passed 30 seconds
 			a.removeLockActionID(&newAction.ActionID)
+			publishStatus(a.publisher, models.StatusKindAction, newAction.ActionID, newAction.Sub, newAction.WorkflowID, models.ActionStatusPending)
 			return sendedBroker, sendedToService, &newAction.ActionID
 		}
 		// if !sendedBroker && !sendedToService {
@@ -172,7 +175,122 @@ func (a *ActionsServiceImpl) CreateActionsNotion(newAction models.RequestGoogleA
 		log.Printf("WARNING | Failed to create action %s for user %s , attempt %d:. Retrying in %v", newAction.ActionID, newAction.Sub, i, waitTime)
 		time.Sleep(waitTime)
 	}
-	log.Print("ERROR | Needs to add to Dead Letter. Cannot send action to broker or service")
-	// TODO: dead letter
+	log.Printf("ERROR | Cannot send action %s to broker or service, added to dead letter", newAction.ActionID)
+	addDeadLetter(a.deadLetters, models.DeadLetterOriginActionCreate, newAction, models.ErrRetriesExhausted, models.DeadLetterRetriedAttempts)
 	return false, false, nil
 }
+
+// ReplayDeadLetter sent again with a new action id, the old one was released or never reached the executors
+func (a *ActionsServiceImpl) ReplayDeadLetter(letter *models.DeadLetter) (err error) {
+	if letter.Origin != models.DeadLetterOriginActionCreate {
+		return models.ErrDeadLetterOriginUnknown
+	}
+	var newAction models.RequestGoogleAction
+	if err := json.Unmarshal(letter.Payload, &newAction); err != nil {
+		return err
+	}
+	actionUserToken, err := a.authService.GetActionUserAccessToken()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC().Format(models.LayoutTimestamp)
+	locked, err := a.setActionID(&newAction, &now)
+	if err != nil {
+		return err
+	}
+	if !locked {
+		return errors.New(models.DeadLetterCannotReplay)
+	}
+	defer a.removeLockActionID(&newAction.ActionID)
+
+	sendedBroker, sendedToService := a.retriesCreateAction(&newAction, now, actionUserToken, false, false, false)
+	if !sendedBroker || !sendedToService {
+		return errors.New(models.DeadLetterCannotReplay)
+	}
+	publishStatus(a.publisher, models.StatusKindAction, newAction.ActionID, newAction.Sub, newAction.WorkflowID, models.ActionStatusPending)
+	return nil
+}
+
+// CreateScheduledAction one run of a polling node, stored with its owner and the outbox command like the actions of the users
+func (a *ActionsServiceImpl) CreateScheduledAction(newAction *models.RequestGoogleAction) (actionID *string, err error) {
+	actionUserToken, err := a.authService.GetActionUserAccessToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC().Format(models.LayoutTimestamp)
+	locked, err := a.setActionID(newAction, &now)
+	if err != nil {
+		return nil, err
+	}
+	if !locked {
+		return nil, models.ErrScheduledActionFailed
+	}
+	defer a.removeLockActionID(&newAction.ActionID)
+
+	sendedBroker, sendedToService := a.retriesCreateAction(newAction, now, actionUserToken, false, false, true)
+	if !sendedBroker {
+		return nil, models.ErrScheduledActionFailed
+	}
+	if !sendedToService {
+		// the command is already in the outbox, firing the schedule again would run it twice
+		log.Printf("WARN | Scheduled action %s not sent to the service, left to the broker", newAction.ActionID)
+	}
+	publishStatus(a.publisher, models.StatusKindAction, newAction.ActionID, newAction.Sub, newAction.WorkflowID, models.ActionStatusPending)
+	return &newAction.ActionID, nil
+}
+
+// GetActionResult long polling, asks again every ActionPollInterval until the action finishes, wait expires or the client leaves
+func (a *ActionsServiceImpl) GetActionResult(ctx context.Context, userID, actionID *string, wait time.Duration) (result *models.ActionResult, err error) {
+	owner, err := a.redisRepo.GetActionOwner(actionID)
+	if err != nil {
+		return nil, err
+	}
+	if owner != *userID {
+		return nil, models.ErrActionNotFound
+	}
+
+	if wait > models.MaxActionWait {
+		wait = models.MaxActionWait
+	}
+	deadline := time.NewTimer(wait)
+	defer deadline.Stop()
+	ticker := time.NewTicker(models.ActionPollInterval)
+	defer ticker.Stop()
+
+	for {
+		result, err = a.lastActionResult(userID, actionID)
+		if err != nil {
+			return nil, err
+		}
+		if result.Finished() || wait == 0 {
+			return result, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return result, nil
+		case <-deadline.C:
+			return result, nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// lastActionResult the action exists in redis but the executor did not write anything yet
+func (a *ActionsServiceImpl) lastActionResult(userID, actionID *string) (*models.ActionResult, error) {
+	response, err := a.httpRepo.GetActionByID(userID, actionID)
+	if err != nil {
+		log.Printf("ERROR | Cannot get result of action %s: %v", *actionID, err)
+		return nil, err
+	}
+	if response == nil || len(response.Data) == 0 {
+		return &models.ActionResult{
+			ActionID: *actionID,
+			UserID:   *userID,
+			Status:   models.ActionStatusPending,
+		}, nil
+	}
+	return &response.Data[0], nil
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
@@ -16,24 +17,36 @@ import (
 )
 
 type CredentialServiceImpl struct {
-	googleOAuthRepo      repos.CredentialGoogleHTTPRepository
-	facebookOAuthRepo    repos.CredentialFacebookHTTPRepository
+	oauthRepo            repos.CredentialOAuthHTTPRepository
+	providers            repos.OAuthProviderRegistry
 	redisRepo            repos.CredentialRedisRepository
 	credentialBrokerRepo repos.CredentialBrokerRepository
 	credentialHTTP       repos.CredentialHTTPRepository
+	secrets              repos.SecretService
+	stateSecret          []byte
+	deadLetters          repos.DeadLetterService
+	refresher            repos.CredentialRefreshService
 }
 
-func NewCredentialService(googleRepo repos.CredentialGoogleHTTPRepository,
-	facebookRepo repos.CredentialFacebookHTTPRepository,
+func NewCredentialService(oauthRepo repos.CredentialOAuthHTTPRepository,
+	providers repos.OAuthProviderRegistry,
 	redisCli repos.CredentialRedisRepository,
 	brokerRepo repos.CredentialBrokerRepository,
-	credentialRepo repos.CredentialHTTPRepository) repos.CredentialService {
+	credentialRepo repos.CredentialHTTPRepository,
+	secrets repos.SecretService,
+	stateSecret []byte,
+	deadLetters repos.DeadLetterService,
+	refresher repos.CredentialRefreshService) repos.CredentialService {
 	return &CredentialServiceImpl{
-		googleOAuthRepo:      googleRepo,
-		facebookOAuthRepo:    facebookRepo,
+		oauthRepo:            oauthRepo,
+		providers:            providers,
 		redisRepo:            redisCli,
 		credentialBrokerRepo: brokerRepo,
 		credentialHTTP:       credentialRepo,
+		secrets:              secrets,
+		stateSecret:          stateSecret,
+		deadLetters:          deadLetters,
+		refresher:            refresher,
 	}
 }
 
@@ -48,17 +61,74 @@ func (c *CredentialServiceImpl) CreateCredential(credentialFrontend *models.Requ
 	if transformedCredential == nil {
 		return nil, fmt.Errorf("cannot saved")
 	}
-	switch transformedCredential.Type { // TODO: refactor
-	case "googlesheets":
-		authURL := c.googleOAuthRepo.GenerateAuthURL(transformedCredential, &transformedCredential.CredentialCreatedNew)
-		credentialFrontend.Data.RedirectURL = *authURL
-	default:
-		return nil, errors.New("ERROR | currentCredential Type not supported")
+	provider, err := c.providers.Get(transformedCredential.Type)
+	if err != nil {
+		return nil, err
+	}
+	state, err := c.prepareAuthorization(provider, transformedCredential)
+	if err != nil {
+		return nil, err
 	}
+	authURL := c.oauthRepo.GenerateAuthURL(provider, transformedCredential, state)
+	credentialFrontend.Data.RedirectURL = *authURL
 
 	return transformedCredential, nil
 }
 
+// prepareAuthorization the credential is saved in redis until the exchange, the provider only sees a signed id,
+// scopes sent by the client replace the default ones of the provider
+func (c *CredentialServiceImpl) prepareAuthorization(provider *models.OAuthProvider, credential *models.RequestExchangeCredential) (state string, err error) {
+	if len(credential.Data.Scopes) == 0 {
+		credential.Data.Scopes = provider.Scopes
+	}
+	credential.Data.OAuthURL = provider.AuthURL
+	credential.Data.Code = ""
+	credential.Data.CodeVerifier = ""
+	if provider.PKCE {
+		credential.Data.CodeVerifier, err = randomToken(models.PKCEVerifierBytes)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	stateID, err := randomToken(models.OAuthStateBytes)
+	if err != nil {
+		return "", err
+	}
+	inserted, err := c.redisRepo.SaveTemporalAuthURLData(&stateID, credential, models.OAuthStateTTL)
+	if err != nil {
+		return "", err
+	}
+	if !inserted {
+		return "", models.ErrOAuthStateInvalid
+	}
+	return signOAuthState(c.stateSecret, stateID, credential.Sub), nil
+}
+
+// readState removes the state, a second exchange with it fails even if the first one did too
+func (c *CredentialServiceImpl) readState(state, sub string) (stateInfo *models.RequestExchangeCredential, provider *models.OAuthProvider, err error) {
+	stateID, valid := verifyOAuthState(c.stateSecret, state, sub)
+	if !valid {
+		return nil, nil, models.ErrOAuthStateInvalid
+	}
+	stateInfo, err = c.redisRepo.PopTemporalAuthURLData(&stateID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if stateInfo.Sub != sub {
+		return nil, nil, models.ErrOAuthStateInvalid
+	}
+
+	provider, err = c.providers.Get(stateInfo.Type)
+	if err != nil {
+		return nil, nil, err
+	}
+	if provider.PKCE && stateInfo.Data.CodeVerifier == "" {
+		return nil, nil, models.ErrOAuthVerifierMissing
+	}
+	return stateInfo, provider, nil
+}
+
 //  func (c *CredentialServiceImpl) generateNewIDCredential(currentCredential *models.RequestCreateCredential) string {
 // 	now := time.Now().UTC().Unix()
 // 	return fmt.Sprintf("credential_%s_%s_%s_%s_%d", currentCredential.Sub, currentCredential.WorkflowID, currentCredential.NodeID, currentCredential.Type, now)
@@ -84,9 +154,16 @@ func (c *CredentialServiceImpl) generateNewRequestID() string {
 	return newCredentialID
 }
 
-func (c *CredentialServiceImpl) ExchangeGoogleCredential(currentCredential *models.RequestExchangeCredential) (token, refresh *string, expire *time.Time, stateInfo *models.RequestExchangeCredential, err error) {
+func (c *CredentialServiceImpl) ExchangeOAuthCredential(currentCredential *models.RequestExchangeCredential) (token, refresh *string, expire *time.Time, stateInfo *models.RequestExchangeCredential, err error) {
 	ctx, cancel := context.WithTimeout(context.Background(), models.MaxTimeoutContext)
 	defer cancel()
+	// a bad state never succeeds, it is rejected before taking the lock and retrying
+	stateInfo, provider, err := c.readState(currentCredential.Data.State, currentCredential.Sub)
+	if err != nil {
+		return token, refresh, nil, nil, err
+	}
+	stateInfo.Data.Code = currentCredential.Data.Code
+
 	// check if it's inserted locked
 	locked, err := c.insertLocker(currentCredential)
 	if err != nil {
@@ -97,18 +174,23 @@ func (c *CredentialServiceImpl) ExchangeGoogleCredential(currentCredential *mode
 		return token, refresh, nil, nil, fmt.Errorf("ERROR | Wait 5 seconds")
 	}
 
+	var exchanged *models.OAuthToken
 	err = c.retryTemplateWithError(ctx, models.MaxAttempts, func() error {
 		var lastError error
-		token, refresh, expire, stateInfo, lastError = c.googleOAuthRepo.ExchangeGoogleCredential(currentCredential)
+		exchanged, lastError = c.oauthRepo.ExchangeCode(provider, stateInfo, currentCredential.Data.Code)
 		return lastError
 	})
 
 	if err != nil {
 		return token, refresh, nil, nil, err
 	}
+	token, refresh, expire = &exchanged.AccessToken, &exchanged.RefreshToken, &exchanged.Expiry
 	sended := c.saveCredentialExchange(token, refresh, expire, stateInfo)
 	if !sended {
-		log.Printf("ERROR | Cannot save Credential NOT added to dead letter %v", currentCredential)
+		log.Printf("ERROR | Cannot save Credential %s, added to dead letter", currentCredential.ID)
+		addCredentialDeadLetter(c.deadLetters, c.secrets, credentialDeadLetter(token, refresh, expire, stateInfo), models.ErrRetriesExhausted, 1)
+	} else {
+		scheduleRefresh(c.refresher, stateInfo)
 	}
 	return token, refresh, expire, stateInfo, err
 }
@@ -131,6 +213,80 @@ func (c *CredentialServiceImpl) saveCredentialExchange(token, refresh *string, e
 	return sended
 }
 
+// ReplayDeadLetter the credential was already exchanged with the provider, only saving it is retried
+func (c *CredentialServiceImpl) ReplayDeadLetter(letter *models.DeadLetter) (err error) {
+	if letter.Origin != models.DeadLetterOriginCredentialSave {
+		return models.ErrDeadLetterOriginUnknown
+	}
+	var saved models.DeadLetterCredential
+	if err := json.Unmarshal(letter.Payload, &saved); err != nil {
+		return err
+	}
+	if saved.Credential == nil {
+		return errors.New(models.DeadLetterCannotReplay)
+	}
+	if err := openCredentialDeadLetter(c.secrets, &saved); err != nil {
+		return err
+	}
+
+	if !c.saveCredentialExchange(&saved.Token, &saved.Refresh, &saved.ExpiresAt, saved.Credential) {
+		return errors.New(models.DeadLetterCannotReplay)
+	}
+	return nil
+}
+
+// credentialDeadLetter the credential is copied, its secrets are encrypted without touching the caller's
+func credentialDeadLetter(token, refresh *string, expire *time.Time, credential *models.RequestExchangeCredential) models.DeadLetterCredential {
+	copied := *credential
+	saved := models.DeadLetterCredential{Credential: &copied}
+	if token != nil {
+		saved.Token = *token
+	}
+	if refresh != nil {
+		saved.Refresh = *refresh
+	}
+	if expire != nil {
+		saved.ExpiresAt = *expire
+	}
+	return saved
+}
+
+// addCredentialDeadLetter the letter is kept in redis and kafka, secrets never reach them in plaintext,
+// a letter that cannot be encrypted is not added
+func addCredentialDeadLetter(deadLetters repos.DeadLetterService, secrets repos.SecretService, saved models.DeadLetterCredential, cause error, attempts int) {
+	if deadLetters == nil {
+		return
+	}
+	if err := sealCredentialDeadLetter(secrets, &saved); err != nil {
+		log.Printf("ERROR | Cannot encrypt dead letter of credential %s, credential lost: %v", saved.Credential.ID, err)
+		return
+	}
+	deadLetters.Add(models.DeadLetterOriginCredentialSave, saved, cause, attempts)
+}
+
+func sealCredentialDeadLetter(secrets repos.SecretService, saved *models.DeadLetterCredential) (err error) {
+	for _, field := range credentialDeadLetterSecrets(saved) {
+		if *field, err = secrets.Encrypt(*field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func openCredentialDeadLetter(secrets repos.SecretService, saved *models.DeadLetterCredential) (err error) {
+	for _, field := range credentialDeadLetterSecrets(saved) {
+		if *field, err = secrets.Decrypt(*field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func credentialDeadLetterSecrets(saved *models.DeadLetterCredential) []*string {
+	return []*string{&saved.Token, &saved.Refresh, &saved.Credential.Data.Token, &saved.Credential.Data.TokenRefresh,
+		&saved.Credential.Data.ClientSecret, &saved.Credential.Data.CodeVerifier}
+}
+
 func (c *CredentialServiceImpl) retryTemplateWithError(ctx context.Context, maxAttempts int, operation func() error) error {
 	for i := 1; i < maxAttempts; i++ {
 		if err := operation(); err == nil {
@@ -314,11 +470,15 @@ func (c *CredentialServiceImpl) saveTokenCredential(transformedCredential *model
 		var lastError error
 		sended = c.saveCredentialExchange(&transformedCredential.Data.Token, &transformedCredential.Data.TokenRefresh, &transformedCredential.ExpiresAt.Time, transformedCredential)
 		if !sended {
-			log.Printf("ERROR | Cannot save Credential NOT added to dead letter %v", transformedCredential)
-			lastError = fmt.Errorf("ERROR | Cannot save Credential NOT added to dead letter %v", transformedCredential)
+			log.Printf("ERROR | Cannot save Credential %s", transformedCredential.ID)
+			lastError = fmt.Errorf("ERROR | Cannot save Credential %s", transformedCredential.ID)
 		}
 		return lastError
 	})
+	if err != nil {
+		log.Printf("ERROR | Credential %s added to dead letter", transformedCredential.ID)
+		addCredentialDeadLetter(c.deadLetters, c.secrets, credentialDeadLetter(&transformedCredential.Data.Token, &transformedCredential.Data.TokenRefresh, &transformedCredential.ExpiresAt.Time, transformedCredential), err, models.DeadLetterRetriedAttempts)
+	}
 
 	return sended, err
 }
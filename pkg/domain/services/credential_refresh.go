@@ -0,0 +1,255 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"minireipaz/pkg/common"
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/domain/repos"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CredentialRefreshServiceImpl struct {
+	oauthRepo      repos.CredentialOAuthHTTPRepository
+	providers      repos.OAuthProviderRegistry
+	redisRepo      repos.CredentialRefreshRedisRepository
+	brokerRepo     repos.CredentialBrokerRepository
+	credentialHTTP repos.CredentialHTTPRepository
+	secrets        repos.SecretService
+	deadLetters    repos.DeadLetterService
+	instanceID     string
+}
+
+func NewCredentialRefreshService(oauthRepo repos.CredentialOAuthHTTPRepository,
+	providers repos.OAuthProviderRegistry,
+	redisRepo repos.CredentialRefreshRedisRepository,
+	brokerRepo repos.CredentialBrokerRepository,
+	credentialRepo repos.CredentialHTTPRepository,
+	secrets repos.SecretService,
+	deadLetters repos.DeadLetterService) repos.CredentialRefreshService {
+	return &CredentialRefreshServiceImpl{
+		oauthRepo:      oauthRepo,
+		providers:      providers,
+		redisRepo:      redisRepo,
+		brokerRepo:     brokerRepo,
+		credentialHTTP: credentialRepo,
+		secrets:        secrets,
+		deadLetters:    deadLetters,
+		instanceID:     uuid.New().String(),
+	}
+}
+
+func (c *CredentialRefreshServiceImpl) RefreshCredential(userID, credentialID *string) (credential *models.RequestExchangeCredential, refreshed bool, err error) {
+	credential, err = c.getCredential(userID, credentialID)
+	if err != nil {
+		return nil, false, err
+	}
+	if !expiresSoon(credential, time.Now().UTC()) {
+		return credential, false, nil
+	}
+	if err := c.refresh(credential); err != nil {
+		return nil, false, err
+	}
+	return credential, true, nil
+}
+
+// Schedule only credentials of providers that refresh, and that received a refresh token
+func (c *CredentialRefreshServiceImpl) Schedule(credential *models.RequestExchangeCredential) {
+	provider, err := c.providers.Get(credential.Type)
+	if err != nil || !provider.Refresh || credential.Data.TokenRefresh == "" || credential.ExpiresAt == nil {
+		return
+	}
+	entry := &models.CredentialRefreshEntry{Sub: credential.Sub, ID: credential.ID}
+	if err := c.redisRepo.ScheduleRefresh(entry, credential.ExpiresAt.Time.Add(-models.CredentialRefreshAhead)); err != nil {
+		log.Printf("ERROR | Cannot schedule refresh of credential %s, refreshed only on demand: %v", credential.ID, err)
+	}
+}
+
+// Tick only the leader refreshes, the others keep trying to take the leadership
+func (c *CredentialRefreshServiceImpl) Tick(now time.Time) (refreshed int, err error) {
+	leader, err := c.redisRepo.AcquireLeadership(c.instanceID, models.CredentialRefreshLeaderTTL)
+	if err != nil || !leader {
+		return 0, err
+	}
+	return c.refreshDue(now)
+}
+
+func (c *CredentialRefreshServiceImpl) Start(ctx context.Context) {
+	ticker := time.NewTicker(models.CredentialRefreshTick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := c.redisRepo.ReleaseLeadership(c.instanceID); err != nil {
+				log.Printf("ERROR | Cannot release credential refresh leadership: %v", err)
+			}
+			return
+		case now := <-ticker.C:
+			if _, err := c.Tick(now.UTC()); err != nil {
+				log.Printf("ERROR | Credential refresh tick failed: %v", err)
+			}
+		}
+	}
+}
+
+// refreshDue a failed refresh stays scheduled and is tried again in the next tick,
+// credentials that cannot be refreshed anymore are removed
+func (c *CredentialRefreshServiceImpl) refreshDue(now time.Time) (refreshed int, err error) {
+	due, err := c.redisRepo.GetDueRefreshes(now, models.MaxCredentialRefreshPerTick)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range due {
+		credential, err := c.getCredential(&due[i].Sub, &due[i].ID)
+		if errors.Is(err, models.ErrCredentialNotFound) {
+			c.unschedule(&due[i])
+			continue
+		}
+		if err != nil {
+			log.Printf("ERROR | Cannot get credential %s to refresh: %v", due[i].ID, err)
+			continue
+		}
+
+		err = c.refresh(credential)
+		switch {
+		case err == nil:
+			refreshed++
+		case errors.Is(err, models.ErrOAuthRefreshUnsupported), errors.Is(err, models.ErrOAuthProviderUnknown):
+			c.unschedule(&due[i])
+		case errors.Is(err, models.ErrOAuthRefreshInProgress), errors.Is(err, models.ErrOAuthRefreshRejected):
+		case errors.Is(err, models.ErrOAuthClientRejected):
+			log.Printf("ERROR | Client of credential %s rejected, kept until its configuration is fixed: %v", due[i].ID, err)
+		default:
+			log.Printf("ERROR | Cannot refresh credential %s: %v", due[i].ID, err)
+		}
+	}
+	return refreshed, nil
+}
+
+// refresh the lock is kept after a refresh, the saved credential can still be the previous one
+// and refreshing it again would send a refresh token that was rotated
+func (c *CredentialRefreshServiceImpl) refresh(credential *models.RequestExchangeCredential) (err error) {
+	provider, err := c.providers.Get(credential.Type)
+	if err != nil {
+		return err
+	}
+	if !provider.Refresh {
+		return models.ErrOAuthRefreshUnsupported
+	}
+	locked, err := c.redisRepo.LockRefresh(&credential.ID, models.CredentialRefreshLockTTL)
+	if err != nil {
+		return err
+	}
+	if !locked {
+		return models.ErrOAuthRefreshInProgress
+	}
+
+	token, err := c.requestToken(provider, credential)
+	if errors.Is(err, models.ErrOAuthRefreshRejected) {
+		c.invalidate(credential)
+		return err
+	}
+	if err != nil {
+		c.unlock(credential)
+		return err
+	}
+
+	credential.Data.Token = token.AccessToken
+	if token.RefreshToken != "" {
+		credential.Data.TokenRefresh = token.RefreshToken
+	}
+	credential.ExpiresAt = &models.CustomTime{Time: token.Expiry}
+	credential.CredentialCreatedNew = false
+	credential.UpdatedAt = &models.CustomTime{Time: time.Now().UTC()}
+	if !c.brokerRepo.CreateCredential(&token.AccessToken, &credential.Data.TokenRefresh, &token.Expiry, credential) {
+		if !provider.RotatesRefreshToken {
+			// the refresh token still works, the next tick refreshes it again
+			c.unlock(credential)
+			return models.ErrRetriesExhausted
+		}
+		// the previous refresh token stopped working, the new one only exists here
+		log.Printf("ERROR | Cannot save refreshed credential %s, added to dead letter", credential.ID)
+		addCredentialDeadLetter(c.deadLetters, c.secrets, credentialDeadLetter(&token.AccessToken, &credential.Data.TokenRefresh, &token.Expiry, credential), models.ErrRetriesExhausted, models.DeadLetterRetriedAttempts)
+	}
+	c.Schedule(credential)
+	return nil
+}
+
+// requestToken a rejected refresh token or client is not retried
+func (c *CredentialRefreshServiceImpl) requestToken(provider *models.OAuthProvider, credential *models.RequestExchangeCredential) (token *models.OAuthToken, err error) {
+	for i := 1; i < models.MaxAttempts; i++ {
+		token, err = c.oauthRepo.RefreshToken(provider, credential)
+		if err == nil || errors.Is(err, models.ErrOAuthRefreshRejected) || errors.Is(err, models.ErrOAuthClientRejected) {
+			return token, err
+		}
+
+		waitTime := common.RandomDuration(models.MaxRangeSleepDuration, models.MinRangeSleepDuration, i)
+		log.Printf("ERROR | Cannot refresh credential %s, attempt %d: %v. Retrying in %v", credential.ID, i, err, waitTime)
+		time.Sleep(waitTime)
+	}
+	return nil, err
+}
+
+// invalidate actions using the credential fail until the user connects it again
+func (c *CredentialRefreshServiceImpl) invalidate(credential *models.RequestExchangeCredential) {
+	now := time.Now().UTC()
+	expire := now
+	if credential.ExpiresAt != nil {
+		expire = credential.ExpiresAt.Time
+	}
+	credential.IsActive = false
+	credential.CredentialCreatedNew = false
+	credential.RevokedAt = &models.CustomTime{Time: now}
+	credential.UpdatedAt = &models.CustomTime{Time: now}
+	log.Printf("WARN | Refresh token of credential %s rejected, marked as inactive", credential.ID)
+	if !c.brokerRepo.CreateCredential(&credential.Data.Token, &credential.Data.TokenRefresh, &expire, credential) {
+		log.Printf("ERROR | Cannot save inactive credential %s, added to dead letter", credential.ID)
+		addCredentialDeadLetter(c.deadLetters, c.secrets, credentialDeadLetter(&credential.Data.Token, &credential.Data.TokenRefresh, &expire, credential), models.ErrOAuthRefreshRejected, models.DeadLetterRetriedAttempts)
+	}
+	c.unschedule(&models.CredentialRefreshEntry{Sub: credential.Sub, ID: credential.ID})
+}
+
+func (c *CredentialRefreshServiceImpl) getCredential(userID, credentialID *string) (credential *models.RequestExchangeCredential, err error) {
+	credentials, err := c.credentialHTTP.GetCredentialByID(userID, credentialID, 1)
+	if err != nil {
+		return nil, err
+	}
+	if credentials == nil || len(*credentials) == 0 {
+		return nil, models.ErrCredentialNotFound
+	}
+	// secrets are saved encrypted, they are only readable from here on
+	credential = &(*credentials)[0]
+	if err := c.secrets.DecryptCredential(&credential.Data); err != nil {
+		return nil, err
+	}
+	return credential, nil
+}
+
+func (c *CredentialRefreshServiceImpl) unschedule(entry *models.CredentialRefreshEntry) {
+	if err := c.redisRepo.RemoveRefresh(entry); err != nil {
+		log.Printf("ERROR | Cannot remove refresh of credential %s: %v", entry.ID, err)
+	}
+}
+
+func (c *CredentialRefreshServiceImpl) unlock(credential *models.RequestExchangeCredential) {
+	if err := c.redisRepo.UnlockRefresh(&credential.ID); err != nil {
+		log.Printf("ERROR | Cannot unlock refresh of credential %s: %v", credential.ID, err)
+	}
+}
+
+func expiresSoon(credential *models.RequestExchangeCredential, now time.Time) bool {
+	return credential.ExpiresAt == nil || !credential.ExpiresAt.Time.After(now.Add(models.CredentialRefreshAhead))
+}
+
+// scheduleRefresh services can be built without the refresher, access tokens are not refreshed then
+func scheduleRefresh(refresher repos.CredentialRefreshService, credential *models.RequestExchangeCredential) {
+	if refresher == nil || credential == nil {
+		return
+	}
+	refresher.Schedule(credential)
+}
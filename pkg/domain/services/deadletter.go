@@ -0,0 +1,167 @@
+package services
+
+import (
+	"encoding/json"
+	"log"
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/domain/repos"
+	"strings"
+	"sync"
+	"time"
+)
+
+type DeadLetterServiceImpl struct {
+	redisRepo  repos.DeadLetterRedisRepository
+	brokerRepo repos.DeadLetterBrokerRepository
+	replayers  map[string]repos.DeadLetterReplayer
+	mu         sync.RWMutex
+}
+
+func NewDeadLetterService(repoRedis repos.DeadLetterRedisRepository, repoBroker repos.DeadLetterBrokerRepository) repos.DeadLetterService {
+	return &DeadLetterServiceImpl{
+		redisRepo:  repoRedis,
+		brokerRepo: repoBroker,
+		replayers:  make(map[string]repos.DeadLetterReplayer),
+	}
+}
+
+// Add redis keeps the letters to list and replay them, the broker topic is a copy for consumers outside this service
+func (d *DeadLetterServiceImpl) Add(origin string, payload interface{}, cause error, attempts int) {
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("ERROR | Cannot transform dead letter of %s to JSON %v", origin, err)
+		return
+	}
+
+	letter := &models.DeadLetter{
+		Payload:   payloadJSON,
+		Origin:    origin,
+		CreatedAt: time.Now().UTC().Format(models.LayoutTimestamp),
+		Attempts:  attempts,
+	}
+	if cause != nil {
+		letter.Error = cause.Error()
+	}
+
+	id, err := d.redisRepo.Save(letter)
+	if err != nil {
+		log.Printf("ERROR | Cannot save dead letter of %s: %v", origin, err)
+	}
+	letter.ID = id
+	sended := d.brokerRepo.Publish(letter)
+	if err != nil && !sended {
+		log.Printf("ERROR | Dead letter of %s lost, redis and broker failed", origin)
+	}
+}
+
+func (d *DeadLetterServiceImpl) RegisterReplayer(origin string, replayer repos.DeadLetterReplayer) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.replayers[origin] = replayer
+}
+
+func (d *DeadLetterServiceImpl) GetDeadLetters(query *models.DeadLetterQuery) (letters []models.DeadLetter, err error) {
+	limit := query.Limit
+	if limit <= 0 {
+		limit = models.DefaultDeadLettersPage
+	}
+	if query.Origin == "" {
+		letters, err = d.redisRepo.GetAll(limit)
+		if err != nil {
+			return nil, err
+		}
+		return redactDeadLetters(letters), nil
+	}
+
+	// the stream is not indexed by origin, the filter runs over the newest page
+	all, err := d.redisRepo.GetAll(models.MaxDeadLettersPage)
+	if err != nil {
+		return nil, err
+	}
+	letters = make([]models.DeadLetter, 0, len(all))
+	for i := range all {
+		if strings.EqualFold(all[i].Origin, query.Origin) {
+			letters = append(letters, all[i])
+		}
+		if int64(len(letters)) == limit {
+			break
+		}
+	}
+	return redactDeadLetters(letters), nil
+}
+
+func (d *DeadLetterServiceImpl) GetDeadLetter(id *string) (letter *models.DeadLetter, err error) {
+	letter, err = d.redisRepo.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	return redactDeadLetter(letter), nil
+}
+
+// Replay the letter is kept when the replay fails, it can be replayed again or discarded
+func (d *DeadLetterServiceImpl) Replay(id *string) (letter *models.DeadLetter, err error) {
+	letter, err = d.redisRepo.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	d.mu.RLock()
+	replayer, known := d.replayers[letter.Origin]
+	d.mu.RUnlock()
+	if !known {
+		return redactDeadLetter(letter), models.ErrDeadLetterOriginUnknown
+	}
+
+	if err := replayer.ReplayDeadLetter(letter); err != nil {
+		log.Printf("ERROR | Cannot replay dead letter %s of %s: %v", letter.ID, letter.Origin, err)
+		return redactDeadLetter(letter), err
+	}
+	if err := d.redisRepo.Remove(id); err != nil {
+		log.Printf("WARN | Dead letter %s replayed but not removed: %v", letter.ID, err)
+	}
+	return redactDeadLetter(letter), nil
+}
+
+func (d *DeadLetterServiceImpl) Discard(id *string) (err error) {
+	return d.redisRepo.Remove(id)
+}
+
+func redactDeadLetters(letters []models.DeadLetter) []models.DeadLetter {
+	for i := range letters {
+		letters[i] = *redactDeadLetter(&letters[i])
+	}
+	return letters
+}
+
+// redactDeadLetter secrets are encrypted in new letters but older ones kept them in plaintext,
+// neither of them leaves the service
+func redactDeadLetter(letter *models.DeadLetter) *models.DeadLetter {
+	if letter == nil || letter.Origin != models.DeadLetterOriginCredentialSave {
+		return letter
+	}
+	var saved models.DeadLetterCredential
+	if err := json.Unmarshal(letter.Payload, &saved); err != nil || saved.Credential == nil {
+		return &models.DeadLetter{ID: letter.ID, Origin: letter.Origin, Error: letter.Error, CreatedAt: letter.CreatedAt, Attempts: letter.Attempts}
+	}
+	for _, field := range credentialDeadLetterSecrets(&saved) {
+		if *field != "" {
+			*field = models.DeadLetterRedacted
+		}
+	}
+	redacted := *letter
+	payload, err := json.Marshal(saved)
+	if err != nil {
+		redacted.Payload = nil
+		return &redacted
+	}
+	redacted.Payload = payload
+	return &redacted
+}
+
+// addDeadLetter services can be built without dead letters, the failure is only logged then
+func addDeadLetter(deadLetters repos.DeadLetterService, origin string, payload interface{}, cause error, attempts int) {
+	if deadLetters == nil {
+		return
+	}
+	deadLetters.Add(origin, payload, cause, attempts)
+}
@@ -0,0 +1,276 @@
+package services
+
+import (
+	"log"
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/domain/repos"
+	"sort"
+	"strings"
+)
+
+type FolderServiceImpl struct {
+	redisRepo       repos.FolderRedisRepository
+	workflowService repos.WorkflowService
+}
+
+func NewFolderService(repoRedis repos.FolderRedisRepository, workflowService repos.WorkflowService) repos.FolderService {
+	return &FolderServiceImpl{
+		redisRepo:       repoRedis,
+		workflowService: workflowService,
+	}
+}
+
+// GetFolderTree folders come from DirectoryToSave of the workflows plus the empty ones saved in redis
+func (f *FolderServiceImpl) GetFolderTree(userID *string) (root *models.Folder, err error) {
+	workflows, err := f.workflowService.GetAllWorkflows(userID)
+	if err != nil {
+		return nil, err
+	}
+	paths, err := f.redisRepo.GetAll(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	root = &models.Folder{Children: []*models.Folder{}}
+	folders := map[string]*models.Folder{"": root}
+	for _, path := range paths {
+		if normalized, valid := normalizeFolderPath(path); valid {
+			addFolder(folders, normalized)
+		}
+	}
+	for i := range workflows {
+		path, valid := normalizeFolderPath(workflows[i].DirectoryToSave)
+		if !valid {
+			path = ""
+		}
+		addFolder(folders, path).WorkflowCount++
+	}
+
+	countWorkflows(root)
+	return root, nil
+}
+
+func (f *FolderServiceImpl) CreateFolder(userID, path *string) (folder string, err error) {
+	folder, valid := normalizeFolderPath(*path)
+	if !valid || folder == "" {
+		return "", models.ErrFolderInvalid
+	}
+
+	created, err := f.redisRepo.Save(userID, &folder)
+	if err != nil {
+		return "", err
+	}
+	if !created {
+		return "", models.ErrFolderExist
+	}
+	return folder, nil
+}
+
+func (f *FolderServiceImpl) RenameFolder(request *models.RequestRenameFolder) (report *models.FolderMoveReport, err error) {
+	from, valid := normalizeFolderPath(request.Path)
+	if !valid || from == "" || strings.Contains(request.NewName, models.FolderSeparator) {
+		return nil, models.ErrFolderInvalid
+	}
+	return f.moveFolder(&request.UserID, from, joinFolderPath(parentFolder(from), request.NewName))
+}
+
+func (f *FolderServiceImpl) MoveFolder(request *models.RequestMoveFolder) (report *models.FolderMoveReport, err error) {
+	from, valid := normalizeFolderPath(request.Path)
+	if !valid || from == "" {
+		return nil, models.ErrFolderInvalid
+	}
+	parent, valid := normalizeFolderPath(request.NewParent)
+	if !valid {
+		return nil, models.ErrFolderInvalid
+	}
+	if isInFolder(parent, from) {
+		return nil, models.ErrFolderMoveInside
+	}
+	return f.moveFolder(&request.UserID, from, joinFolderPath(parent, folderName(from)))
+}
+
+// MoveWorkflows every workflow is updated on its own, failed ones are reported and the rest still moved
+func (f *FolderServiceImpl) MoveWorkflows(request *models.RequestMoveWorkflows) (report *models.FolderMoveReport, err error) {
+	path, valid := normalizeFolderPath(request.Path)
+	if !valid || path == "" {
+		return nil, models.ErrFolderInvalid
+	}
+	// keep the folder in the tree even if workflows are moved out later
+	if _, err := f.redisRepo.Save(&request.UserID, &path); err != nil {
+		return nil, err
+	}
+
+	report = &models.FolderMoveReport{Path: path, Moved: []string{}, Failed: []string{}}
+	for i := range request.WorkflowIDs {
+		workflow, exist := f.workflowService.GetWorkflow(&request.UserID, &request.WorkflowIDs[i])
+		if !exist {
+			report.Failed = append(report.Failed, request.WorkflowIDs[i])
+			continue
+		}
+		f.moveWorkflow(workflow, path, report)
+	}
+	return report, nil
+}
+
+func (f *FolderServiceImpl) moveFolder(userID *string, from, to string) (report *models.FolderMoveReport, err error) {
+	to, valid := normalizeFolderPath(to)
+	if !valid || to == "" {
+		return nil, models.ErrFolderInvalid
+	}
+	if to == from {
+		return &models.FolderMoveReport{Path: to, Moved: []string{}, Failed: []string{}}, nil
+	}
+
+	root, err := f.GetFolderTree(userID)
+	if err != nil {
+		return nil, err
+	}
+	if findFolder(root, from) == nil {
+		return nil, models.ErrFolderNotFound
+	}
+	if findFolder(root, to) != nil {
+		return nil, models.ErrFolderExist
+	}
+
+	workflows, err := f.workflowService.GetAllWorkflows(userID)
+	if err != nil {
+		return nil, err
+	}
+	// workflows go first, the folder is only moved in redis once all of them are in it
+	report = &models.FolderMoveReport{Path: to, Moved: []string{}, Failed: []string{}}
+	moved := map[string]string{}
+	for i := range workflows {
+		current, valid := normalizeFolderPath(workflows[i].DirectoryToSave)
+		if !valid || !isInFolder(current, from) {
+			continue
+		}
+		if f.moveWorkflow(&workflows[i], to+strings.TrimPrefix(current, from), report) {
+			moved[workflows[i].UUID] = workflows[i].DirectoryToSave
+			workflows[i].DirectoryToSave = current
+		}
+	}
+	if len(report.Failed) > 0 {
+		f.revertMove(workflows, moved, report)
+		return report, models.ErrFolderMoveReverted
+	}
+
+	if err := f.redisRepo.Move(userID, &from, &to); err != nil {
+		f.revertMove(workflows, moved, report)
+		return report, err
+	}
+	return report, nil
+}
+
+// revertMove puts back the moved workflows in their previous folder, moved holds their new folder
+func (f *FolderServiceImpl) revertMove(workflows []models.Workflow, moved map[string]string, report *models.FolderMoveReport) {
+	report.Moved = []string{}
+	report.Reverted = []string{}
+	for i := range workflows {
+		if _, exist := moved[workflows[i].UUID]; !exist {
+			continue
+		}
+		previous := workflows[i]
+		updated, _, err := f.workflowService.UpdateWorkflow(&previous)
+		if err != nil || !updated {
+			log.Printf("ERROR | Cannot move back workflow %s to folder %s: %v", previous.UUID, previous.DirectoryToSave, err)
+			report.Moved = append(report.Moved, previous.UUID)
+			continue
+		}
+		report.Reverted = append(report.Reverted, previous.UUID)
+	}
+}
+
+func (f *FolderServiceImpl) moveWorkflow(workflow *models.Workflow, path string, report *models.FolderMoveReport) (moved bool) {
+	previous := workflow.DirectoryToSave
+	workflow.DirectoryToSave = path
+	updated, _, err := f.workflowService.UpdateWorkflow(workflow)
+	if err != nil || !updated {
+		log.Printf("ERROR | Cannot move workflow %s to folder %s: %v", workflow.UUID, path, err)
+		workflow.DirectoryToSave = previous
+		report.Failed = append(report.Failed, workflow.UUID)
+		return false
+	}
+	report.Moved = append(report.Moved, workflow.UUID)
+	return true
+}
+
+// normalizeFolderPath trims separators and spaces, empty names and dot names are not valid
+func normalizeFolderPath(path string) (normalized string, valid bool) {
+	path = strings.Trim(strings.TrimSpace(path), models.FolderSeparator)
+	if path == "" {
+		return "", true
+	}
+	if len(path) > models.MaxFolderPathLength {
+		return "", false
+	}
+
+	names := strings.Split(path, models.FolderSeparator)
+	if len(names) > models.MaxFolderDepth {
+		return "", false
+	}
+	for i, name := range names {
+		names[i] = strings.TrimSpace(name)
+		if names[i] == "" || names[i] == "." || names[i] == ".." {
+			return "", false
+		}
+	}
+	return strings.Join(names, models.FolderSeparator), true
+}
+
+func isInFolder(path, folder string) bool {
+	return path == folder || strings.HasPrefix(path, folder+models.FolderSeparator)
+}
+
+func joinFolderPath(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + models.FolderSeparator + name
+}
+
+func parentFolder(path string) string {
+	index := strings.LastIndex(path, models.FolderSeparator)
+	if index < 0 {
+		return ""
+	}
+	return path[:index]
+}
+
+func folderName(path string) string {
+	return path[strings.LastIndex(path, models.FolderSeparator)+1:]
+}
+
+// addFolder creates the missing parents too, returns the folder of the path
+func addFolder(folders map[string]*models.Folder, path string) *models.Folder {
+	if folder, exist := folders[path]; exist {
+		return folder
+	}
+	parent := addFolder(folders, parentFolder(path))
+	folder := &models.Folder{Path: path, Name: folderName(path), Children: []*models.Folder{}}
+	parent.Children = append(parent.Children, folder)
+	folders[path] = folder
+	return folder
+}
+
+func countWorkflows(folder *models.Folder) int {
+	sort.Slice(folder.Children, func(i, j int) bool {
+		return folder.Children[i].Name < folder.Children[j].Name
+	})
+	folder.TotalWorkflows = folder.WorkflowCount
+	for _, child := range folder.Children {
+		folder.TotalWorkflows += countWorkflows(child)
+	}
+	return folder.TotalWorkflows
+}
+
+func findFolder(folder *models.Folder, path string) *models.Folder {
+	if folder.Path == path {
+		return folder
+	}
+	for _, child := range folder.Children {
+		if isInFolder(path, child.Path) {
+			return findFolder(child, path)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package services
+
+import (
+	"fmt"
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/domain/repos"
+)
+
+// GraphValidator checks the structure of a workflow graph, all problems are returned not only the first one
+type GraphValidator struct {
+	nodeService repos.NodeService
+}
+
+// NewGraphValidator without node service only the structure of the graph is checked
+func NewGraphValidator(nodeService repos.NodeService) *GraphValidator {
+	return &GraphValidator{nodeService: nodeService}
+}
+
+func (g *GraphValidator) Validate(workflow *models.Workflow) []models.GraphValidationError {
+	graphErrors := []models.GraphValidationError{}
+
+	nodeIDs := make(map[string]bool, len(workflow.Nodes))
+	for _, node := range workflow.Nodes {
+		if node.ID == "" {
+			graphErrors = append(graphErrors, models.GraphValidationError{
+				Code:    models.GraphErrorEmptyNodeID,
+				Message: "node id is required",
+			})
+			continue
+		}
+		if nodeIDs[node.ID] {
+			graphErrors = append(graphErrors, models.GraphValidationError{
+				NodeID:  node.ID,
+				Code:    models.GraphErrorDuplicateNodeID,
+				Message: fmt.Sprintf("node id %s is repeated", node.ID),
+			})
+			continue
+		}
+		nodeIDs[node.ID] = true
+	}
+
+	graphErrors = append(graphErrors, validateStartNode(workflow)...)
+
+	// only edges with both ends in the graph are used to look for cycles
+	adjacency := make(map[string][]string, len(nodeIDs))
+	edgeIDs := make(map[string]bool, len(workflow.Edges))
+	for i := range workflow.Edges {
+		edge := &workflow.Edges[i]
+		id := edgeKey(edge)
+		if edgeIDs[id] {
+			graphErrors = append(graphErrors, models.GraphValidationError{
+				EdgeID:  id,
+				Code:    models.GraphErrorDuplicateEdgeID,
+				Message: fmt.Sprintf("edge id %s is repeated", id),
+			})
+			continue
+		}
+		edgeIDs[id] = true
+
+		source, target := "", ""
+		if edge.Source != nil {
+			source = *edge.Source
+		}
+		if edge.Target != nil {
+			target = *edge.Target
+		}
+
+		validEnds := true
+		if !nodeIDs[source] {
+			validEnds = false
+			graphErrors = append(graphErrors, models.GraphValidationError{
+				EdgeID:  id,
+				Code:    models.GraphErrorEdgeUnknownSource,
+				Message: fmt.Sprintf("source %s is not a node of the workflow", source),
+			})
+		}
+		if !nodeIDs[target] {
+			validEnds = false
+			graphErrors = append(graphErrors, models.GraphValidationError{
+				EdgeID:  id,
+				Code:    models.GraphErrorEdgeUnknownTarget,
+				Message: fmt.Sprintf("target %s is not a node of the workflow", target),
+			})
+		}
+		if !validEnds {
+			continue
+		}
+
+		if target == models.InitialNodeID {
+			graphErrors = append(graphErrors, models.GraphValidationError{
+				NodeID:  target,
+				EdgeID:  id,
+				Code:    models.GraphErrorStartNodeInbound,
+				Message: "start node cannot have inbound edges",
+			})
+		}
+		adjacency[source] = append(adjacency[source], target)
+	}
+
+	graphErrors = append(graphErrors, g.findCycles(workflow, adjacency)...)
+	if g.nodeService != nil {
+		graphErrors = append(graphErrors, g.nodeService.ValidateNodes(workflow)...)
+	}
+	return graphErrors
+}
+
+// validateStartNode exactly one node of start type, and it is the one runs begin from
+func validateStartNode(workflow *models.Workflow) []models.GraphValidationError {
+	startErrors := []models.GraphValidationError{}
+	starts := 0
+	initialIsStart := false
+	for i := range workflow.Nodes {
+		node := &workflow.Nodes[i]
+		if nodeTypeOf(node) != models.NodeTypeStart {
+			continue
+		}
+		starts++
+		if node.ID == models.InitialNodeID {
+			initialIsStart = true
+			continue
+		}
+		startErrors = append(startErrors, models.GraphValidationError{
+			NodeID:  node.ID,
+			Code:    models.GraphErrorMultipleStartNodes,
+			Message: fmt.Sprintf("only the node %s can be a start node", models.InitialNodeID),
+		})
+	}
+	if !initialIsStart {
+		startErrors = append(startErrors, models.GraphValidationError{
+			Code:    models.GraphErrorMissingStartNode,
+			Message: fmt.Sprintf("workflow needs one start node with id %s, found %d start nodes", models.InitialNodeID, starts),
+		})
+	}
+	return startErrors
+}
+
+// findCycles depth first search, every back edge closes a cycle
+func (g *GraphValidator) findCycles(workflow *models.Workflow, adjacency map[string][]string) []models.GraphValidationError {
+	const (
+		notVisited = iota
+		inStack
+		done
+	)
+	cycleErrors := []models.GraphValidationError{}
+	state := make(map[string]int, len(adjacency))
+	reported := make(map[string]bool)
+
+	var visit func(nodeID string)
+	visit = func(nodeID string) {
+		state[nodeID] = inStack
+		for _, next := range adjacency[nodeID] {
+			switch state[next] {
+			case notVisited:
+				visit(next)
+			case inStack:
+				key := nodeID + "->" + next
+				if reported[key] {
+					continue
+				}
+				reported[key] = true
+				cycleErrors = append(cycleErrors, models.GraphValidationError{
+					NodeID:  next,
+					EdgeID:  g.findEdgeID(workflow, nodeID, next),
+					Code:    models.GraphErrorCycle,
+					Message: fmt.Sprintf("edge from %s to %s closes a cycle", nodeID, next),
+				})
+			}
+		}
+		state[nodeID] = done
+	}
+
+	for _, node := range workflow.Nodes {
+		if node.ID != "" && state[node.ID] == notVisited {
+			visit(node.ID)
+		}
+	}
+	return cycleErrors
+}
+
+func (g *GraphValidator) findEdgeID(workflow *models.Workflow, source, target string) string {
+	for i := range workflow.Edges {
+		edge := &workflow.Edges[i]
+		if edge.Source != nil && edge.Target != nil && *edge.Source == source && *edge.Target == target {
+			return edgeKey(edge)
+		}
+	}
+	return ""
+}
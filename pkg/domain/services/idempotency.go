@@ -0,0 +1,55 @@
+package services
+
+import (
+	"fmt"
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/domain/repos"
+	"time"
+)
+
+type IdempotencyServiceImpl struct {
+	redisRepo repos.IdempotencyRedisRepository
+}
+
+func NewIdempotencyService(repoRedis repos.IdempotencyRedisRepository) repos.IdempotencyService {
+	return &IdempotencyServiceImpl{
+		redisRepo: repoRedis,
+	}
+}
+
+func (i *IdempotencyServiceImpl) Begin(scope, key, requestHash string) (stored *models.IdempotencyRecord, err error) {
+	record := &models.IdempotencyRecord{
+		RequestHash: requestHash,
+		CreatedAt:   time.Now().UTC().Format(models.LayoutTimestamp),
+	}
+	reserved, stored, err := i.redisRepo.Reserve(idempotencyKey(scope, key), record, models.IdempotencyLockTTL)
+	if err != nil {
+		return nil, err
+	}
+	if reserved {
+		return nil, nil
+	}
+
+	// same key with another body is a client bug, replaying would hide it
+	if stored.RequestHash != requestHash {
+		return nil, models.ErrIdempotencyKeyReused
+	}
+	if !stored.Completed {
+		return nil, models.ErrIdempotencyInProgress
+	}
+	return stored, nil
+}
+
+func (i *IdempotencyServiceImpl) Complete(scope, key string, record *models.IdempotencyRecord) (err error) {
+	record.Completed = true
+	return i.redisRepo.Save(idempotencyKey(scope, key), record, models.IdempotencyTTL)
+}
+
+func (i *IdempotencyServiceImpl) Release(scope, key string) (err error) {
+	return i.redisRepo.Remove(idempotencyKey(scope, key))
+}
+
+// idempotencyKey the same key sent to two endpoints are two different requests
+func idempotencyKey(scope, key string) string {
+	return fmt.Sprintf("idempotency:%s:%s", scope, key)
+}
@@ -0,0 +1,252 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/domain/repos"
+	"slices"
+)
+
+// NodeServiceImpl registry of the node types a workflow can use
+type NodeServiceImpl struct {
+	nodeTypes map[string]models.NodeTypeDefinition
+	order     []string
+}
+
+func NewNodeService() repos.NodeService {
+	service := &NodeServiceImpl{
+		nodeTypes: make(map[string]models.NodeTypeDefinition),
+	}
+	for _, definition := range builtinNodeTypes() {
+		service.register(definition)
+	}
+	return service
+}
+
+func (n *NodeServiceImpl) register(definition models.NodeTypeDefinition) {
+	if _, exist := n.nodeTypes[definition.Type]; !exist {
+		n.order = append(n.order, definition.Type)
+	}
+	n.nodeTypes[definition.Type] = definition
+}
+
+func (n *NodeServiceImpl) GetCatalog() []models.NodeTypeDefinition {
+	catalog := make([]models.NodeTypeDefinition, 0, len(n.order))
+	for _, nodeType := range n.order {
+		catalog = append(catalog, n.nodeTypes[nodeType])
+	}
+	return catalog
+}
+
+func (n *NodeServiceImpl) GetNodeType(nodeType string) (definition models.NodeTypeDefinition, exist bool) {
+	definition, exist = n.nodeTypes[nodeType]
+	return definition, exist
+}
+
+// ValidateNodes checks every node against its type definition,
+// required formdata fields and credential are only enforced for active workflows so drafts can be saved half configured,
+// IsActive must come from the stored state, saves and activation set it before validating
+func (n *NodeServiceImpl) ValidateNodes(workflow *models.Workflow) []models.GraphValidationError {
+	nodeErrors := []models.GraphValidationError{}
+	complete := workflow.IsActive == models.Active
+
+	typesByNode := make(map[string]string, len(workflow.Nodes))
+	for i := range workflow.Nodes {
+		node := &workflow.Nodes[i]
+		// empty ids are reported by the graph validator
+		if node.ID == "" {
+			continue
+		}
+		nodeType := nodeTypeOf(node)
+		// nodes saved before the catalog existed have no type, there is nothing to check them against
+		if nodeType == "" {
+			continue
+		}
+		definition, exist := n.nodeTypes[nodeType]
+		if !exist {
+			nodeErrors = append(nodeErrors, models.GraphValidationError{
+				NodeID:  node.ID,
+				Code:    models.GraphErrorUnknownNodeType,
+				Message: fmt.Sprintf("node type %q is not in the catalog", nodeType),
+			})
+			continue
+		}
+		typesByNode[node.ID] = nodeType
+		nodeErrors = append(nodeErrors, n.validateFormData(node, &definition, complete)...)
+	}
+
+	for i := range workflow.Edges {
+		edge := &workflow.Edges[i]
+		// inbound edges to the start node are reported by the graph validator
+		if edge.Source == nil || edge.Target == nil || *edge.Target == models.InitialNodeID {
+			continue
+		}
+		sourceType, okSource := typesByNode[*edge.Source]
+		targetType, okTarget := typesByNode[*edge.Target]
+		if !okSource || !okTarget {
+			continue
+		}
+		if !allowsNodeType(n.nodeTypes[sourceType].AllowedOutbound, targetType) || !allowsNodeType(n.nodeTypes[targetType].AllowedInbound, sourceType) {
+			nodeErrors = append(nodeErrors, models.GraphValidationError{
+				EdgeID:  edgeKey(edge),
+				Code:    models.GraphErrorConnectionNotAllowed,
+				Message: fmt.Sprintf("node type %s cannot be connected to %s", sourceType, targetType),
+			})
+		}
+	}
+	return nodeErrors
+}
+
+func (n *NodeServiceImpl) validateFormData(node *models.Node, definition *models.NodeTypeDefinition, complete bool) []models.GraphValidationError {
+	formErrors := []models.GraphValidationError{}
+	data := &models.DataNode{}
+	if node.Data != nil {
+		data = node.Data
+	}
+
+	// formdata fields are plain strings, the json names are the ones used in the schema
+	fields := map[string]string{}
+	rawFormData, err := json.Marshal(data.ContentData)
+	if err == nil {
+		err = json.Unmarshal(rawFormData, &fields)
+	}
+	if err != nil {
+		return append(formErrors, models.GraphValidationError{
+			NodeID:  node.ID,
+			Code:    models.GraphErrorInvalidFormField,
+			Message: "formdata cannot be read",
+		})
+	}
+
+	for name, property := range definition.FormSchema.Properties {
+		value := fields[name]
+		if value != "" && len(property.Enum) > 0 && !slices.Contains(property.Enum, value) {
+			formErrors = append(formErrors, models.GraphValidationError{
+				NodeID:  node.ID,
+				Code:    models.GraphErrorInvalidFormField,
+				Message: fmt.Sprintf("field %s must be one of %v", name, property.Enum),
+			})
+		}
+	}
+
+	if data.CredentialData.Type != "" && data.CredentialData.Type != definition.CredentialType {
+		formErrors = append(formErrors, models.GraphValidationError{
+			NodeID:  node.ID,
+			Code:    models.GraphErrorInvalidCredential,
+			Message: fmt.Sprintf("node type %s needs a %s credential", definition.Type, definition.CredentialType),
+		})
+	}
+
+	if !complete {
+		return formErrors
+	}
+
+	for _, name := range definition.FormSchema.Required {
+		if fields[name] == "" {
+			formErrors = append(formErrors, models.GraphValidationError{
+				NodeID:  node.ID,
+				Code:    models.GraphErrorMissingFormField,
+				Message: fmt.Sprintf("field %s is required", name),
+			})
+		}
+	}
+	if definition.CredentialType != "" && data.CredentialData.ID == "" {
+		formErrors = append(formErrors, models.GraphValidationError{
+			NodeID:  node.ID,
+			Code:    models.GraphErrorMissingCredential,
+			Message: fmt.Sprintf("node type %s needs a %s credential", definition.Type, definition.CredentialType),
+		})
+	}
+	return formErrors
+}
+
+// nodeTypeOf start nodes saved before the catalog existed have no type
+func nodeTypeOf(node *models.Node) string {
+	if node.Data != nil && node.Data.Type != "" {
+		return node.Data.Type
+	}
+	if node.ID == models.InitialNodeID {
+		return models.NodeTypeStart
+	}
+	return ""
+}
+
+func allowsNodeType(allowed []string, nodeType string) bool {
+	return slices.Contains(allowed, models.AnyNodeType) || slices.Contains(allowed, nodeType)
+}
+
+func builtinNodeTypes() []models.NodeTypeDefinition {
+	notionForm := models.FormSchema{
+		Type: "object",
+		Properties: map[string]models.FormProperty{
+			"pollmode":       {Type: "string", Title: "Poll mode", Default: models.NopollNode},
+			"selectdocument": {Type: "string", Title: "Select page", Description: "How the page is chosen"},
+			"document":       {Type: "string", Title: "Page", Description: "Notion page or database id"},
+			"operation":      {Type: "string", Title: "Operation"},
+		},
+		Required: []string{"document", "operation"},
+	}
+
+	return []models.NodeTypeDefinition{
+		{
+			Type:            models.NodeTypeStart,
+			Label:           "Start Point",
+			Description:     "Starting point of the workflow",
+			Category:        "core",
+			FormSchema:      models.FormSchema{Type: "object", Properties: map[string]models.FormProperty{}},
+			AllowedInbound:  []string{},
+			AllowedOutbound: []string{models.AnyNodeType},
+		},
+		{
+			Type:            models.NodeTypeWebhook,
+			Label:           "Webhook",
+			Description:     "Starts the workflow when an external system calls its url",
+			Category:        "core",
+			FormSchema:      models.FormSchema{Type: "object", Properties: map[string]models.FormProperty{}},
+			AllowedInbound:  []string{},
+			AllowedOutbound: []string{models.AnyNodeType},
+		},
+		{
+			Type:           models.GoogleSheets,
+			Label:          "Google Sheets read",
+			Description:    "Reads rows from a sheet of a Google Sheets document",
+			Category:       "google",
+			CredentialType: models.GoogleSheets,
+			FormSchema: models.FormSchema{
+				Type: "object",
+				Properties: map[string]models.FormProperty{
+					"pollmode":       {Type: "string", Title: "Poll mode", Default: models.NopollNode},
+					"selectdocument": {Type: "string", Title: "Select document", Description: "How the document is chosen"},
+					"document":       {Type: "string", Title: "Document", Description: "Google Sheets document id"},
+					"selectsheet":    {Type: "string", Title: "Select sheet", Description: "How the sheet is chosen"},
+					"sheet":          {Type: "string", Title: "Sheet"},
+					"operation":      {Type: "string", Title: "Operation"},
+				},
+				Required: []string{"pollmode", "document", "sheet"},
+			},
+			AllowedInbound:  []string{models.AnyNodeType},
+			AllowedOutbound: []string{models.AnyNodeType},
+		},
+		{
+			Type:            models.NotionToken,
+			Label:           "Notion write",
+			Description:     "Writes into a Notion page using an integration token",
+			Category:        "notion",
+			CredentialType:  models.NotionToken,
+			FormSchema:      notionForm,
+			AllowedInbound:  []string{models.AnyNodeType},
+			AllowedOutbound: []string{models.AnyNodeType},
+		},
+		{
+			Type:            models.NotionOAuth,
+			Label:           "Notion write (OAuth)",
+			Description:     "Writes into a Notion page using an OAuth connection",
+			Category:        "notion",
+			CredentialType:  models.NotionOAuth,
+			FormSchema:      notionForm,
+			AllowedInbound:  []string{models.AnyNodeType},
+			AllowedOutbound: []string{models.AnyNodeType},
+		},
+	}
+}
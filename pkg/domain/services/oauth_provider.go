@@ -0,0 +1,89 @@
+package services
+
+import (
+	"encoding/json"
+	"log"
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/domain/repos"
+	"sort"
+	"sync"
+)
+
+type OAuthProviderRegistryImpl struct {
+	providers map[string]models.OAuthProvider
+	// byType credential type to provider name
+	byType map[string]string
+	mu     sync.RWMutex
+}
+
+// NewOAuthProviderRegistry providersJSON is a list of providers added over the defaults,
+// an invalid list is ignored so a bad config never leaves the service without providers
+func NewOAuthProviderRegistry(defaults []models.OAuthProvider, providersJSON string) repos.OAuthProviderRegistry {
+	registry := &OAuthProviderRegistryImpl{
+		providers: make(map[string]models.OAuthProvider),
+		byType:    make(map[string]string),
+	}
+	for i := range defaults {
+		if err := registry.Register(&defaults[i]); err != nil {
+			log.Printf("ERROR | Cannot register oauth provider %s: %v", defaults[i].Name, err)
+		}
+	}
+	if providersJSON == "" {
+		return registry
+	}
+
+	var configured []models.OAuthProvider
+	if err := json.Unmarshal([]byte(providersJSON), &configured); err != nil {
+		log.Printf("ERROR | Cannot read configured oauth providers: %v", err)
+		return registry
+	}
+	for i := range configured {
+		if err := registry.Register(&configured[i]); err != nil {
+			log.Printf("ERROR | Cannot register oauth provider %s: %v", configured[i].Name, err)
+		}
+	}
+	return registry
+}
+
+func (o *OAuthProviderRegistryImpl) Get(credentialType string) (provider *models.OAuthProvider, err error) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	name, exist := o.byType[credentialType]
+	if !exist {
+		return nil, models.ErrOAuthProviderUnknown
+	}
+	found := o.providers[name]
+	return &found, nil
+}
+
+func (o *OAuthProviderRegistryImpl) Register(provider *models.OAuthProvider) (err error) {
+	if provider.Name == "" || provider.AuthURL == "" || provider.TokenURL == "" || len(provider.CredentialTypes) == 0 {
+		return models.ErrOAuthProviderInvalid
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if previous, exist := o.providers[provider.Name]; exist {
+		for _, credentialType := range previous.CredentialTypes {
+			delete(o.byType, credentialType)
+		}
+	}
+	o.providers[provider.Name] = *provider
+	for _, credentialType := range provider.CredentialTypes {
+		o.byType[credentialType] = provider.Name
+	}
+	return nil
+}
+
+func (o *OAuthProviderRegistryImpl) GetAll() (providers []models.OAuthProvider) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	providers = make([]models.OAuthProvider, 0, len(o.providers))
+	for name := range o.providers {
+		providers = append(providers, o.providers[name])
+	}
+	sort.Slice(providers, func(i, j int) bool {
+		return providers[i].Name < providers[j].Name
+	})
+	return providers
+}
@@ -0,0 +1,34 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+)
+
+// signOAuthState the state sent to the provider is the id of the saved state and its signature for the user
+func signOAuthState(secret []byte, stateID, sub string) string {
+	return stateID + "." + base64.RawURLEncoding.EncodeToString(oauthStateMAC(secret, stateID, sub))
+}
+
+// verifyOAuthState states of another user or made up ones fail here, before redis is read
+func verifyOAuthState(secret []byte, state, sub string) (stateID string, valid bool) {
+	stateID, signature, found := strings.Cut(state, ".")
+	if !found || stateID == "" {
+		return "", false
+	}
+	received, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return "", false
+	}
+	return stateID, hmac.Equal(received, oauthStateMAC(secret, stateID, sub))
+}
+
+func oauthStateMAC(secret []byte, stateID, sub string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(stateID))
+	mac.Write([]byte{0})
+	mac.Write([]byte(sub))
+	return mac.Sum(nil)
+}
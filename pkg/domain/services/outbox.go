@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"log"
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/domain/repos"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OutboxServiceImpl struct {
+	redisRepo  repos.OutboxRedisRepository
+	brokerRepo repos.OutboxBrokerRepository
+	instanceID string
+	// the ticker and the requests that wrote to the outbox relay from the same instance
+	relaying sync.Mutex
+}
+
+func NewOutboxService(repoRedis repos.OutboxRedisRepository, repoBroker repos.OutboxBrokerRepository) repos.OutboxService {
+	return &OutboxServiceImpl{
+		redisRepo:  repoRedis,
+		brokerRepo: repoBroker,
+		instanceID: uuid.New().String(),
+	}
+}
+
+// Tick only the leader relays, the others keep trying to take the leadership
+func (o *OutboxServiceImpl) Tick(now time.Time) (relayed int, err error) {
+	leader, err := o.redisRepo.AcquireLeadership(o.instanceID, models.OutboxLeaderTTL)
+	if err != nil || !leader {
+		return 0, err
+	}
+	return o.Relay(now)
+}
+
+// Relay publishes in the order they were written and stops at the first failure,
+// a message is never published before an older one of the same stream
+func (o *OutboxServiceImpl) Relay(now time.Time) (relayed int, err error) {
+	o.relaying.Lock()
+	defer o.relaying.Unlock()
+
+	pending, err := o.redisRepo.GetPending(now, models.MaxOutboxPerTick)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range pending {
+		published, err := o.redisRepo.IsPublished(&pending[i].DedupKey)
+		if err != nil {
+			return relayed, err
+		}
+		// published before a crash removed it from the outbox
+		if published {
+			o.ack(&pending[i])
+			continue
+		}
+		if !o.brokerRepo.Publish(&pending[i]) {
+			return relayed, models.ErrOutboxCannotPublish
+		}
+		o.ack(&pending[i])
+		relayed++
+	}
+	return relayed, nil
+}
+
+func (o *OutboxServiceImpl) Start(ctx context.Context) {
+	ticker := time.NewTicker(models.OutboxRelayTick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := o.redisRepo.ReleaseLeadership(o.instanceID); err != nil {
+				log.Printf("ERROR | Cannot release outbox leadership: %v", err)
+			}
+			return
+		case now := <-ticker.C:
+			if _, err := o.Tick(now.UTC()); err != nil {
+				log.Printf("ERROR | Outbox relay tick failed: %v", err)
+			}
+		}
+	}
+}
+
+// ack a message left in the outbox is published again, consumers drop it by its dedup key
+func (o *OutboxServiceImpl) ack(message *models.OutboxMessage) {
+	if err := o.redisRepo.Ack(message); err != nil {
+		log.Printf("ERROR | Outbox message %s published but not acknowledged: %v", message.DedupKey, err)
+	}
+}
+
+// dispatchOutbox relays right after the message was written instead of waiting for the next tick,
+// publishing goes through the relay so older commands of the same key always go first.
+// Services can be built without the outbox, the ticker publishes the message then
+func dispatchOutbox(outbox repos.OutboxService, message *models.OutboxMessage) {
+	if outbox == nil || message == nil {
+		return
+	}
+	if _, err := outbox.Tick(time.Now().UTC()); err != nil {
+		log.Printf("WARN | Outbox message %s not published, left for the relay: %v", message.DedupKey, err)
+	}
+}
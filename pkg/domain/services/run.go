@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/domain/repos"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RunServiceImpl struct {
+	redisRepo       repos.RunRedisRepository
+	brokerRepo      repos.RunBrokerRepository
+	httpRepo        repos.RunHTTPRepository
+	workflowService repos.WorkflowService
+	publisher       repos.StatusPublisher
+	outbox          repos.OutboxService
+}
+
+func NewRunService(repoRedis repos.RunRedisRepository, repoBroker repos.RunBrokerRepository, repoHTTP repos.RunHTTPRepository, workflowService repos.WorkflowService, publisher repos.StatusPublisher, outbox repos.OutboxService) repos.RunService {
+	return &RunServiceImpl{
+		redisRepo:       repoRedis,
+		brokerRepo:      repoBroker,
+		httpRepo:        repoHTTP,
+		workflowService: workflowService,
+		publisher:       publisher,
+		outbox:          outbox,
+	}
+}
+
+// CreateRun runs the saved version of the workflow whatever its state, drafts can be tested before activating them
+func (r *RunServiceImpl) CreateRun(userID, workflowID *string, dryRun bool) (run *models.Run, graphErrors []models.GraphValidationError, err error) {
+	workflow, exist := r.workflowService.GetWorkflow(userID, workflowID)
+	if !exist {
+		return nil, nil, models.ErrWorkflowNotFound
+	}
+
+	graphErrors = r.workflowService.ValidateWorkflowGraph(workflow)
+	if len(graphErrors) > 0 {
+		return nil, graphErrors, models.ErrRunGraphInvalid
+	}
+
+	graph := resolveRunGraph(workflow)
+	run = &models.Run{
+		RunID:      uuid.New().String(),
+		WorkflowID: workflow.UUID,
+		UserID:     workflow.UserID,
+		Version:    workflow.Version,
+		Status:     models.RunStatusQueued,
+		Trigger:    models.RunTriggerManual,
+		Steps:      make([]models.RunStep, 0, len(graph)),
+		CreatedAt:  time.Now().UTC().Format(models.LayoutTimestamp),
+		DryRun:     dryRun,
+	}
+	for i := range graph {
+		run.Steps = append(run.Steps, models.RunStep{
+			NodeID: graph[i].ID,
+			Type:   graph[i].Type,
+			Status: models.StepStatusPending,
+		})
+	}
+
+	message, err := r.brokerRepo.CreateCommand(run, graph)
+	if err != nil {
+		log.Printf("ERROR | Cannot create command of run %s: %v", run.RunID, err)
+		return nil, nil, err
+	}
+	// the run and its command are saved together, the relay publishes it even if this instance stops here
+	if err := r.redisRepo.Save(run, message); err != nil {
+		log.Printf("ERROR | Cannot save run of workflow %s: %v", workflow.UUID, err)
+		return nil, nil, err
+	}
+	dispatchOutbox(r.outbox, message)
+	publishStatus(r.publisher, models.StatusKindRun, run.RunID, run.UserID, run.WorkflowID, run.Status)
+	return run, nil, nil
+}
+
+// GetWorkflowRuns one page of runs, newest first, one row more is asked to know if there is a next page
+func (r *RunServiceImpl) GetWorkflowRuns(query *models.RunListQuery) (page *models.RunPage, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), models.MaxTimeoutContext)
+	defer cancel()
+
+	if !r.workflowService.ValidateWorkflowGlobalUUID(&query.WorkflowID) {
+		return nil, models.ErrWorkflowNotFound
+	}
+	if query.Limit == 0 {
+		query.Limit = models.DefaultWorkflowPageSize
+	}
+	if query.Limit > models.MaxWorkflowPageSize {
+		query.Limit = models.MaxWorkflowPageSize
+	}
+	var cursor *models.WorkflowCursor
+	if query.Cursor != "" {
+		cursor, err = decodeCursor(query.Cursor, models.SortByStartedAt, models.SortOrderDesc)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var response *models.InfoRuns
+	err = retryWithError(ctx, models.MaxAttempts, func() error {
+		var lastError error
+		response, lastError = r.httpRepo.GetWorkflowRuns(&query.UserID, &query.WorkflowID, cursor, query.Limit+1)
+		return lastError
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	page = &models.RunPage{Runs: []models.RunSummary{}}
+	if response == nil || response.Data == nil {
+		return page, nil
+	}
+	page.Runs = response.Data
+	if uint64(len(page.Runs)) > query.Limit {
+		page.Runs = page.Runs[:query.Limit]
+		page.HasMore = true
+		page.NextCursor = encodeRunCursor(&page.Runs[len(page.Runs)-1])
+	}
+	return page, nil
+}
+
+// GetRun executors write to clickhouse once they pick the run, until then the record saved when it was created is returned
+func (r *RunServiceImpl) GetRun(userID, runID *string) (run *models.RunDetail, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), models.MaxTimeoutContext)
+	defer cancel()
+
+	var summary *models.InfoRuns
+	var steps *models.InfoRunSteps
+	err = retryWithError(ctx, models.MaxAttempts, func() error {
+		var lastError error
+		summary, lastError = r.httpRepo.GetRun(userID, runID)
+		if lastError != nil {
+			return lastError
+		}
+		if summary == nil || len(summary.Data) == 0 {
+			return nil
+		}
+		steps, lastError = r.httpRepo.GetRunSteps(userID, runID)
+		return lastError
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if summary != nil && len(summary.Data) > 0 {
+		run = &models.RunDetail{RunSummary: summary.Data[0], Steps: []models.RunStepResult{}}
+		if steps != nil && steps.Data != nil {
+			run.Steps = steps.Data
+		}
+		return run, nil
+	}
+
+	queued, err := r.redisRepo.Get(runID)
+	if errors.Is(err, models.ErrRunNotFound) || (err == nil && queued.UserID != *userID) {
+		return nil, models.ErrRunNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return queuedRunDetail(queued), nil
+}
+
+func encodeRunCursor(last *models.RunSummary) string {
+	value := ""
+	if last.StartedAt != nil {
+		value = last.StartedAt.Time.Format(models.LayoutTimestamp)
+	}
+	return encodeCursor(&models.WorkflowCursor{SortBy: models.SortByStartedAt, Order: models.SortOrderDesc, Value: value, ID: last.RunID})
+}
+
+func queuedRunDetail(run *models.Run) *models.RunDetail {
+	detail := &models.RunDetail{
+		RunSummary: models.RunSummary{
+			RunID:      run.RunID,
+			WorkflowID: run.WorkflowID,
+			UserID:     run.UserID,
+			Status:     run.Status,
+			Trigger:    run.Trigger,
+			Version:    run.Version,
+			DryRun:     run.DryRun,
+		},
+		Steps: make([]models.RunStepResult, 0, len(run.Steps)),
+	}
+	for i := range run.Steps {
+		detail.Steps = append(detail.Steps, models.RunStepResult{
+			NodeID:   run.Steps[i].NodeID,
+			NodeType: run.Steps[i].Type,
+			Status:   run.Steps[i].Status,
+		})
+	}
+	return detail
+}
+
+// resolveRunGraph nodes reachable from the start node in topological order, the graph is already validated so it has no cycles
+func resolveRunGraph(workflow *models.Workflow) []models.RunNode {
+	nodes := make(map[string]*models.Node, len(workflow.Nodes))
+	for i := range workflow.Nodes {
+		nodes[workflow.Nodes[i].ID] = &workflow.Nodes[i]
+	}
+
+	next := make(map[string][]string, len(nodes))
+	for i := range workflow.Edges {
+		edge := &workflow.Edges[i]
+		if edge.Source == nil || edge.Target == nil {
+			continue
+		}
+		next[*edge.Source] = append(next[*edge.Source], *edge.Target)
+	}
+
+	reachable := map[string]bool{models.InitialNodeID: true}
+	pending := []string{models.InitialNodeID}
+	for len(pending) > 0 {
+		current := pending[0]
+		pending = pending[1:]
+		for _, target := range next[current] {
+			if !reachable[target] {
+				reachable[target] = true
+				pending = append(pending, target)
+			}
+		}
+	}
+
+	inbound := make(map[string]int, len(reachable))
+	for source := range reachable {
+		for _, target := range next[source] {
+			inbound[target]++
+		}
+	}
+
+	graph := make([]models.RunNode, 0, len(reachable))
+	ready := []string{models.InitialNodeID}
+	for len(ready) > 0 {
+		current := ready[0]
+		ready = ready[1:]
+		node := nodes[current]
+		graph = append(graph, models.RunNode{
+			Data: node.Data,
+			ID:   node.ID,
+			Type: nodeTypeOf(node),
+			Next: next[current],
+		})
+		for _, target := range next[current] {
+			inbound[target]--
+			if inbound[target] == 0 {
+				ready = append(ready, target)
+			}
+		}
+	}
+	return graph
+}
@@ -0,0 +1,316 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"minireipaz/pkg/common"
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/domain/repos"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SchedulerServiceImpl struct {
+	redisRepo       repos.SchedulerRedisRepository
+	workflowService repos.WorkflowService
+	creator         repos.ScheduledActionCreator
+	instanceID      string
+}
+
+func NewSchedulerService(repoRedis repos.SchedulerRedisRepository, workflowService repos.WorkflowService) repos.SchedulerService {
+	return &SchedulerServiceImpl{
+		redisRepo:       repoRedis,
+		workflowService: workflowService,
+		instanceID:      uuid.New().String(),
+	}
+}
+
+func (s *SchedulerServiceImpl) RegisterCreator(creator repos.ScheduledActionCreator) {
+	s.creator = creator
+}
+
+func (s *SchedulerServiceImpl) SaveSchedule(request *models.RequestCreateSchedule) (schedule *models.Schedule, err error) {
+	schedule = &models.Schedule{
+		Action:          request.Action,
+		WorkflowID:      request.WorkflowID,
+		NodeID:          request.NodeID,
+		UserID:          request.UserID,
+		Kind:            request.Kind,
+		Expression:      strings.TrimSpace(request.Expression),
+		Timezone:        request.Timezone,
+		IntervalSeconds: request.IntervalSeconds,
+	}
+	if schedule.Timezone == "" {
+		schedule.Timezone = models.DefaultTimezone
+	}
+	// fired actions always belong to the node of the schedule
+	schedule.Action.WorkflowID = request.WorkflowID
+	schedule.Action.NodeID = request.NodeID
+	schedule.Action.Sub = request.UserID
+
+	next, err := nextRunAfter(schedule, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	schedule.NextRunAt = next.Format(models.LayoutTimestamp)
+
+	// the node id alone keys the schedule, only the owner of the workflow can write it
+	if _, exist := s.workflowService.GetWorkflow(&request.UserID, &request.WorkflowID); !exist {
+		return nil, models.ErrScheduleNotFound
+	}
+	stored, err := s.redisRepo.Get(&schedule.WorkflowID, &schedule.NodeID)
+	if err != nil && !errors.Is(err, models.ErrScheduleNotFound) {
+		return nil, err
+	}
+	if stored != nil && stored.UserID != schedule.UserID {
+		return nil, models.ErrScheduleNotFound
+	}
+
+	active, err := s.workflowService.IsWorkflowActive(&schedule.WorkflowID)
+	if err != nil {
+		return nil, err
+	}
+	// enabled again when the workflow is activated or resumed
+	schedule.Disabled = !active
+
+	if err := s.redisRepo.Save(schedule); err != nil {
+		log.Printf("ERROR | Cannot save schedule of node %s in workflow %s: %v", schedule.NodeID, schedule.WorkflowID, err)
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// ScheduleAction pollmode is a cron expression ("*/5 * * * *", "@hourly") or an interval ("15m")
+func (s *SchedulerServiceImpl) ScheduleAction(action *models.RequestGoogleAction) (schedule *models.Schedule, err error) {
+	request := &models.RequestCreateSchedule{
+		Action:     *action,
+		UserID:     action.Sub,
+		WorkflowID: action.WorkflowID,
+		NodeID:     action.NodeID,
+	}
+
+	pollmode := strings.TrimSpace(action.Pollmode)
+	if strings.HasPrefix(pollmode, "@") || strings.Contains(pollmode, " ") {
+		request.Kind = models.ScheduleKindCron
+		request.Expression = pollmode
+	} else {
+		interval, err := time.ParseDuration(pollmode)
+		if err != nil {
+			return nil, models.ErrScheduleInvalid
+		}
+		request.Kind = models.ScheduleKindInterval
+		request.IntervalSeconds = int64(interval.Seconds())
+	}
+	return s.SaveSchedule(request)
+}
+
+func (s *SchedulerServiceImpl) GetSchedules(userID, workflowID *string) (schedules []models.Schedule, err error) {
+	all, err := s.redisRepo.GetByWorkflow(workflowID)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules = make([]models.Schedule, 0, len(all))
+	for i := range all {
+		if all[i].UserID == *userID {
+			schedules = append(schedules, all[i])
+		}
+	}
+	sort.Slice(schedules, func(i, j int) bool {
+		return schedules[i].NodeID < schedules[j].NodeID
+	})
+	return schedules, nil
+}
+
+func (s *SchedulerServiceImpl) RemoveSchedule(userID, workflowID, nodeID *string) (err error) {
+	schedule, err := s.redisRepo.Get(workflowID, nodeID)
+	if err != nil {
+		return err
+	}
+	if schedule.UserID != *userID {
+		return models.ErrScheduleNotFound
+	}
+	return s.redisRepo.Remove(workflowID, nodeID)
+}
+
+// Tick only the leader fires, the others keep trying to take the leadership
+func (s *SchedulerServiceImpl) Tick(now time.Time) (fired int, err error) {
+	leader, err := s.redisRepo.AcquireLeadership(s.instanceID, models.SchedulerLeaderTTL)
+	if err != nil || !leader {
+		return 0, err
+	}
+	return s.RunDue(now)
+}
+
+// RunDue every due schedule is claimed moving its next fire time before the action is published,
+// so a schedule is never fired twice even if two instances think they are the leader.
+// Schedules of workflows that cannot run are disabled, they missed the change of state
+func (s *SchedulerServiceImpl) RunDue(now time.Time) (fired int, err error) {
+	due, err := s.redisRepo.GetDue(now, models.MaxSchedulesPerTick)
+	if err != nil {
+		return 0, err
+	}
+
+	activeWorkflows := make(map[string]bool, len(due))
+	for i := range due {
+		active, checked := activeWorkflows[due[i].WorkflowID]
+		if !checked {
+			active, err = s.workflowService.IsWorkflowActive(&due[i].WorkflowID)
+			if err != nil {
+				log.Printf("ERROR | Cannot check state of workflow %s, schedule of node %s skipped: %v", due[i].WorkflowID, due[i].NodeID, err)
+				continue
+			}
+			activeWorkflows[due[i].WorkflowID] = active
+		}
+		if !active {
+			s.disable(&due[i])
+			continue
+		}
+		if s.fire(&due[i], now) {
+			fired++
+		}
+	}
+	return fired, nil
+}
+
+// SyncTriggers enabled schedules start counting from now, runs missed while disabled are not fired
+func (s *SchedulerServiceImpl) SyncTriggers(workflowID *string, active bool) (err error) {
+	schedules, err := s.redisRepo.GetByWorkflow(workflowID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	for i := range schedules {
+		if schedules[i].Disabled == !active {
+			continue
+		}
+		schedules[i].Disabled = !active
+		if active {
+			schedules[i].NextRunAt = ""
+			next, err := nextRunAfter(&schedules[i], now)
+			if err != nil {
+				log.Printf("ERROR | Cannot enable schedule of node %s in workflow %s: %v", schedules[i].NodeID, *workflowID, err)
+				continue
+			}
+			schedules[i].NextRunAt = next.Format(models.LayoutTimestamp)
+		}
+		if err := s.redisRepo.Save(&schedules[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *SchedulerServiceImpl) RemoveTriggers(workflowID *string) (err error) {
+	return s.redisRepo.RemoveByWorkflow(workflowID)
+}
+
+func (s *SchedulerServiceImpl) Start(ctx context.Context) {
+	ticker := time.NewTicker(models.SchedulerTick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := s.redisRepo.ReleaseLeadership(s.instanceID); err != nil {
+				log.Printf("ERROR | Cannot release scheduler leadership: %v", err)
+			}
+			return
+		case now := <-ticker.C:
+			if _, err := s.Tick(now.UTC()); err != nil {
+				log.Printf("ERROR | Scheduler tick failed: %v", err)
+			}
+		}
+	}
+}
+
+func (s *SchedulerServiceImpl) disable(schedule *models.Schedule) {
+	schedule.Disabled = true
+	if err := s.redisRepo.Save(schedule); err != nil {
+		log.Printf("ERROR | Cannot disable schedule of node %s in workflow %s: %v", schedule.NodeID, schedule.WorkflowID, err)
+	}
+}
+
+func (s *SchedulerServiceImpl) fire(schedule *models.Schedule, now time.Time) (fired bool) {
+	previous := *schedule
+	next, err := nextRunAfter(schedule, now)
+	if err != nil {
+		log.Printf("ERROR | Removing schedule of node %s in workflow %s: %v", schedule.NodeID, schedule.WorkflowID, err)
+		if err := s.redisRepo.Remove(&schedule.WorkflowID, &schedule.NodeID); err != nil {
+			log.Printf("ERROR | Cannot remove schedule of node %s in workflow %s: %v", schedule.NodeID, schedule.WorkflowID, err)
+		}
+		return false
+	}
+
+	schedule.LastRunAt = now.Format(models.LayoutTimestamp)
+	schedule.NextRunAt = next.Format(models.LayoutTimestamp)
+	advanced, err := s.redisRepo.Advance(schedule, previous.NextRunAt)
+	if err != nil || !advanced {
+		return false
+	}
+
+	if !s.createAction(schedule, now) {
+		// due again in the next tick
+		if _, err := s.redisRepo.Advance(&previous, schedule.NextRunAt); err != nil {
+			log.Printf("ERROR | Cannot revert schedule of node %s in workflow %s: %v", schedule.NodeID, schedule.WorkflowID, err)
+		}
+		return false
+	}
+	return true
+}
+
+// createAction the creator gives the action id and writes it with its owner and the outbox command
+func (s *SchedulerServiceImpl) createAction(schedule *models.Schedule, now time.Time) (created bool) {
+	if s.creator == nil {
+		log.Printf("ERROR | No creator registered for scheduled action of node %s in workflow %s", schedule.NodeID, schedule.WorkflowID)
+		return false
+	}
+	action := schedule.Action
+	action.CreatedAt = now.Format(models.LayoutTimestamp)
+	action.Testmode = false
+	if _, err := s.creator.CreateScheduledAction(&action); err != nil {
+		log.Printf("ERROR | Cannot create scheduled action of node %s in workflow %s: %v", schedule.NodeID, schedule.WorkflowID, err)
+		return false
+	}
+	return true
+}
+
+// nextRunAfter intervals keep their cadence from the last fire time, missed ones are skipped not fired together
+func nextRunAfter(schedule *models.Schedule, now time.Time) (next time.Time, err error) {
+	location, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return time.Time{}, models.ErrTimezoneInvalid
+	}
+
+	switch schedule.Kind {
+	case models.ScheduleKindCron:
+		cron, err := common.ParseCron(schedule.Expression)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%w: %v", models.ErrScheduleInvalid, err)
+		}
+		next = cron.Next(now, location)
+		if next.IsZero() {
+			return time.Time{}, models.ErrScheduleNeverFires
+		}
+		return next.UTC(), nil
+
+	case models.ScheduleKindInterval:
+		interval := time.Duration(schedule.IntervalSeconds) * time.Second
+		if interval < models.MinScheduleInterval {
+			return time.Time{}, models.ErrScheduleInvalid
+		}
+		last, err := time.Parse(models.LayoutTimestamp, schedule.NextRunAt)
+		if err != nil || last.After(now) {
+			return now.Add(interval).UTC(), nil
+		}
+		missed := now.Sub(last) / interval
+		return last.Add((missed + 1) * interval).UTC(), nil
+	}
+	return time.Time{}, models.ErrScheduleInvalid
+}
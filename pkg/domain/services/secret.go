@@ -0,0 +1,89 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"minireipaz/pkg/common"
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/domain/repos"
+	"strings"
+)
+
+type SecretServiceImpl struct {
+	keyProvider repos.SecretKeyProvider
+}
+
+func NewSecretService(keyProvider repos.SecretKeyProvider) repos.SecretService {
+	return &SecretServiceImpl{keyProvider: keyProvider}
+}
+
+// Encrypt every value has its own data key, saved next to it wrapped by the key provider as
+// enc:v1:<key id>:<wrapped data key>:<nonce and ciphertext>
+func (s *SecretServiceImpl) Encrypt(plaintext string) (ciphertext string, err error) {
+	if plaintext == "" || strings.HasPrefix(plaintext, models.SecretPrefix) {
+		return plaintext, nil
+	}
+	dataKey := make([]byte, models.SecretDataKeyBytes)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", err
+	}
+	sealed, err := common.SealAESGCM(dataKey, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+	keyID := s.keyProvider.CurrentKeyID()
+	wrapped, err := s.keyProvider.WrapKey(keyID, dataKey)
+	if err != nil {
+		return "", err
+	}
+	return models.SecretPrefix + keyID + ":" + base64.RawURLEncoding.EncodeToString(wrapped) + ":" + base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+func (s *SecretServiceImpl) Decrypt(ciphertext string) (plaintext string, err error) {
+	if !strings.HasPrefix(ciphertext, models.SecretPrefix) {
+		return ciphertext, nil
+	}
+	parts := strings.Split(strings.TrimPrefix(ciphertext, models.SecretPrefix), ":")
+	if len(parts) != 3 {
+		return "", models.ErrSecretMalformed
+	}
+	wrapped, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", models.ErrSecretMalformed
+	}
+	sealed, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", models.ErrSecretMalformed
+	}
+	dataKey, err := s.keyProvider.UnwrapKey(parts[0], wrapped)
+	if err != nil {
+		return "", err
+	}
+	opened, err := common.OpenAESGCM(dataKey, sealed)
+	if err != nil {
+		return "", models.ErrSecretMalformed
+	}
+	return string(opened), nil
+}
+
+func (s *SecretServiceImpl) EncryptCredential(data *models.DataCredential) (err error) {
+	for _, field := range credentialSecrets(data) {
+		if *field, err = s.Encrypt(*field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *SecretServiceImpl) DecryptCredential(data *models.DataCredential) (err error) {
+	for _, field := range credentialSecrets(data) {
+		if *field, err = s.Decrypt(*field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func credentialSecrets(data *models.DataCredential) []*string {
+	return []*string{&data.Token, &data.TokenRefresh, &data.ClientSecret}
+}
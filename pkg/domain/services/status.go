@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+	"log"
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/domain/repos"
+	"time"
+)
+
+type StatusServiceImpl struct {
+	publisher repos.StatusPublisher
+}
+
+func NewStatusService(publisher repos.StatusPublisher) repos.StatusService {
+	return &StatusServiceImpl{
+		publisher: publisher,
+	}
+}
+
+func (s *StatusServiceImpl) Subscribe(ctx context.Context, userID *string) (events <-chan models.StatusEvent, err error) {
+	return s.publisher.Subscribe(ctx, userID)
+}
+
+// publishStatus the stream is a notification, losing an event never fails the operation that produced it
+func publishStatus(publisher repos.StatusPublisher, kind, id, userID, workflowID, status string) {
+	if publisher == nil {
+		return
+	}
+	err := publisher.Publish(&models.StatusEvent{
+		Kind:       kind,
+		ID:         id,
+		UserID:     userID,
+		WorkflowID: workflowID,
+		Status:     status,
+		At:         time.Now().UTC().Format(models.LayoutTimestamp),
+	})
+	if err != nil {
+		log.Printf("WARN | Cannot publish status %s of %s %s: %v", status, kind, id, err)
+	}
+}
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"log"
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/domain/repos"
+	"sort"
+	"time"
+)
+
+type TemplateServiceImpl struct {
+	redisRepo       repos.TemplateRedisRepository
+	workflowService repos.WorkflowService
+	idGenerator     IDService
+}
+
+func NewTemplateService(repoRedis repos.TemplateRedisRepository, workflowService repos.WorkflowService, idGenerator IDService) repos.TemplateService {
+	return &TemplateServiceImpl{
+		redisRepo:       repoRedis,
+		workflowService: workflowService,
+		idGenerator:     idGenerator,
+	}
+}
+
+// GetTemplates built-in templates go first, published ones from newest to oldest
+func (t *TemplateServiceImpl) GetTemplates(category string) (templates []models.WorkflowTemplate, err error) {
+	published, err := t.redisRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(published, func(i, j int) bool {
+		return published[i].CreatedAt > published[j].CreatedAt
+	})
+
+	templates = []models.WorkflowTemplate{}
+	for _, template := range append(builtinTemplates(), published...) {
+		if category == "" || template.Category == category {
+			templates = append(templates, template)
+		}
+	}
+	return templates, nil
+}
+
+func (t *TemplateServiceImpl) GetTemplate(templateID *string) (template *models.WorkflowTemplate, err error) {
+	for _, builtin := range builtinTemplates() {
+		if builtin.ID == *templateID {
+			return &builtin, nil
+		}
+	}
+	return t.redisRepo.Get(templateID)
+}
+
+// PublishTemplate the graph is exported from the workflow, so credentials are left behind
+func (t *TemplateServiceImpl) PublishTemplate(request *models.RequestPublishTemplate) (template *models.WorkflowTemplate, err error) {
+	document, exist := t.workflowService.ExportWorkflow(&request.UserID, &request.WorkflowID)
+	if !exist {
+		return nil, models.ErrWorkflowNotFound
+	}
+	document.Name = request.Name
+	document.Description = request.Description
+
+	template = &models.WorkflowTemplate{
+		ID:          t.idGenerator.GenerateWorkflowID(),
+		Name:        request.Name,
+		Category:    request.Category,
+		Description: request.Description,
+		AuthorID:    request.UserID,
+		CreatedAt:   time.Now().UTC().Format(models.LayoutTimestamp),
+		Graph:       document,
+	}
+	if _, err := t.redisRepo.Save(template); err != nil {
+		log.Printf("ERROR | Cannot publish template from workflow %s: %v", request.WorkflowID, err)
+		return nil, err
+	}
+	return template, nil
+}
+
+func (t *TemplateServiceImpl) DeleteTemplate(userID, templateID *string) (err error) {
+	template, err := t.GetTemplate(templateID)
+	if err != nil {
+		return err
+	}
+	if template.Builtin || template.AuthorID != *userID {
+		return models.ErrTemplateNotOwner
+	}
+
+	removed, err := t.redisRepo.Remove(templateID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return models.ErrTemplateNotFound
+	}
+	return nil
+}
+
+func (t *TemplateServiceImpl) CreateWorkflowFromTemplate(templateID *string, request *models.RequestCreateFromTemplate) (imported *models.ImportedWorkflow, graphErrors []models.GraphValidationError, err error) {
+	template, err := t.GetTemplate(templateID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	document := *template.Graph
+	document.Name = request.Name
+	return t.workflowService.ImportWorkflow(&request.UserID, &request.DirectoryToSave, &document)
+}
+
+// builtinTemplates built every time, callers can modify them freely
+func builtinTemplates() []models.WorkflowTemplate {
+	sheetNode := func(id string, x float64) models.Node {
+		return models.Node{
+			ID:       id,
+			Type:     "wrapperNode",
+			Position: &models.Position{X: x, Y: 0},
+			Data: &models.DataNode{
+				ID:             id,
+				NodeID:         id,
+				Label:          "Google Sheets",
+				Description:    "Read rows from a sheet",
+				Type:           models.GoogleSheets,
+				ContentData:    models.FormData{Pollmode: models.NopollNode},
+				CredentialData: models.RequestCreateCredential{Type: models.GoogleSheets},
+			},
+		}
+	}
+	notionNode := func(id string, x float64) models.Node {
+		return models.Node{
+			ID:       id,
+			Type:     "wrapperNode",
+			Position: &models.Position{X: x, Y: 0},
+			Data: &models.DataNode{
+				ID:             id,
+				NodeID:         id,
+				Label:          "Notion",
+				Description:    "Write into a Notion page",
+				Type:           models.NotionToken,
+				ContentData:    models.FormData{Pollmode: models.NopollNode},
+				CredentialData: models.RequestCreateCredential{Type: models.NotionToken},
+			},
+		}
+	}
+	edge := func(id, source, target string) models.Edge {
+		return models.Edge{ID: &id, Source: &source, Target: &target}
+	}
+	document := func(name, description string, nodes []models.Node, edges []models.Edge) *models.WorkflowDocument {
+		credentials := []models.CredentialPlaceholder{}
+		for _, node := range nodes {
+			if node.Data != nil && node.Data.CredentialData.Type != "" {
+				credentials = append(credentials, models.CredentialPlaceholder{NodeID: node.ID, Type: node.Data.CredentialData.Type})
+			}
+		}
+		return &models.WorkflowDocument{
+			Kind:          models.WorkflowDocumentKind,
+			SchemaVersion: models.WorkflowDocumentSchemaVersion,
+			Name:          name,
+			Description:   description,
+			Viewport:      defaultViewport(),
+			Nodes:         nodes,
+			Edges:         edges,
+			Credentials:   credentials,
+		}
+	}
+
+	return []models.WorkflowTemplate{
+		{
+			ID:          "builtin-blank",
+			Name:        "Blank workflow",
+			Category:    models.TemplateCategoryBasic,
+			Description: "Only the start point, build everything from scratch",
+			Builtin:     true,
+			Graph:       document("Blank workflow", "", []models.Node{createInitialNode()}, []models.Edge{}),
+		},
+		{
+			ID:          "builtin-read-sheet",
+			Name:        "Read a Google Sheet",
+			Category:    models.TemplateCategoryGoogle,
+			Description: "Reads the rows of a sheet every time the workflow runs",
+			Builtin:     true,
+			Graph: document("Read a Google Sheet", "",
+				[]models.Node{createInitialNode(), sheetNode("sheet", 200)},
+				[]models.Edge{edge("start-sheet", models.InitialNodeID, "sheet")}),
+		},
+		{
+			ID:          "builtin-sheet-to-notion",
+			Name:        "Google Sheet to Notion",
+			Category:    models.TemplateCategoryNotion,
+			Description: "Copies the rows of a sheet into a Notion page",
+			Builtin:     true,
+			Graph: document("Google Sheet to Notion", "",
+				[]models.Node{createInitialNode(), sheetNode("sheet", 200), notionNode("notion", 400)},
+				[]models.Edge{edge("start-sheet", models.InitialNodeID, "sheet"), edge("sheet-notion", "sheet", "notion")}),
+		},
+	}
+}
@@ -1,6 +1,8 @@
 package services
 
 import (
+	"encoding/json"
+	"errors"
 	"log"
 	"minireipaz/pkg/domain/models"
 	"minireipaz/pkg/domain/repos"
@@ -10,40 +12,71 @@ type userServiceImpl struct {
 	userHTTPRepo   repos.UserHTTPRepository
 	userRedisRepo  repos.UserRedisRepository
 	userBrokerRepo repos.UserBrokerRepository
+	deadLetters    repos.DeadLetterService
 }
 
-func NewUserService(repoHTTP repos.UserHTTPRepository, repoRedis repos.UserRedisRepository, repoBroker repos.UserBrokerRepository) repos.UserService {
+func NewUserService(repoHTTP repos.UserHTTPRepository, repoRedis repos.UserRedisRepository, repoBroker repos.UserBrokerRepository, deadLetters repos.DeadLetterService) repos.UserService {
 	return &userServiceImpl{
 		userHTTPRepo:   repoHTTP,
 		userRedisRepo:  repoRedis,
 		userBrokerRepo: repoBroker,
+		deadLetters:    deadLetters,
 	}
 }
 
 func (u *userServiceImpl) SynUser(user *models.SyncUserRequest) (created, exist bool) {
+	created, exist, insertErr := u.syncUser(user)
+	if insertErr != nil {
+		log.Printf("ERROR | User %s added to dead letter", user.Sub)
+		addDeadLetter(u.deadLetters, models.DeadLetterOriginUserSync, user, insertErr, 1)
+	}
+	return created, exist
+}
+
+// ReplayDeadLetter the user is synced again from the request saved
+func (u *userServiceImpl) ReplayDeadLetter(letter *models.DeadLetter) (err error) {
+	if letter.Origin != models.DeadLetterOriginUserSync {
+		return models.ErrDeadLetterOriginUnknown
+	}
+	var user models.SyncUserRequest
+	if err := json.Unmarshal(letter.Payload, &user); err != nil {
+		return err
+	}
+
+	created, exist, err := u.syncUser(&user)
+	if err != nil {
+		return err
+	}
+	if !created && !exist {
+		return errors.New(models.DeadLetterCannotReplay)
+	}
+	return nil
+}
+
+// syncUser insertErr is the only failure that loses the user, the others are answered to the caller
+func (u *userServiceImpl) syncUser(user *models.SyncUserRequest) (created, exist bool, insertErr error) {
 	exist, err := u.userRedisRepo.CheckUserExist(user)
 	if err != nil {
 		log.Printf("ERROR | Cannot access to repo redis %v", err)
-		return false, false
+		return false, false, nil
 	}
 	if exist {
-		return false, true
+		return false, true, nil
 	}
 
 	// new user
 	exist, err = u.userRedisRepo.CheckLockExist(user)
 	if err != nil {
 		log.Printf("ERROR | Cannot access to repo redis %v", err)
-		return false, false
+		return false, false, nil
 	}
 	if exist {
-		return false, false
+		return false, false, nil
 	}
 	// InsertUser insert user and generate a lock for about 20 seconds
-	locked, lockExists, userExists, err := u.userRedisRepo.InsertUser(user)
-	if err != nil {
-		// TODO: Dead letter
-		log.Printf("ERROR | Needs to Added to dead letter %s", user.Sub)
+	locked, lockExists, userExists, insertErr := u.userRedisRepo.InsertUser(user)
+	if insertErr != nil {
+		log.Printf("ERROR | Cannot insert user %s in redis %v", user.Sub, insertErr)
 	}
 
 	if !locked {
@@ -51,11 +84,11 @@ func (u *userServiceImpl) SynUser(user *models.SyncUserRequest) (created, exist
 	}
 
 	if userExists {
-		return false, true
+		return false, true, insertErr
 	}
 
 	if userExists && !lockExists {
-		return false, true
+		return false, true, insertErr
 	}
 
 	setUserDefaults(user) // default roleID
@@ -64,7 +97,7 @@ func (u *userServiceImpl) SynUser(user *models.SyncUserRequest) (created, exist
 	// in case cannot get autoremoved
 	u.userRedisRepo.RemoveLock(user)
 
-	return sended, false
+	return sended, false, insertErr
 }
 
 func setUserDefaults(user *models.SyncUserRequest) {
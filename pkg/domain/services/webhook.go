@@ -0,0 +1,243 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"log"
+	"minireipaz/pkg/config"
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/domain/repos"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WebhookServiceImpl struct {
+	redisRepo       repos.WebhookRedisRepository
+	brokerRepo      repos.WebhookBrokerRepository
+	workflowService repos.WorkflowService
+	secrets         repos.SecretService
+}
+
+func NewWebhookService(repoRedis repos.WebhookRedisRepository, repoBroker repos.WebhookBrokerRepository, workflowService repos.WorkflowService, secrets repos.SecretService) repos.WebhookService {
+	return &WebhookServiceImpl{
+		redisRepo:       repoRedis,
+		brokerRepo:      repoBroker,
+		workflowService: workflowService,
+		secrets:         secrets,
+	}
+}
+
+// CreateWebhook creating it again for the same node rotates the url and the secret
+func (w *WebhookServiceImpl) CreateWebhook(request *models.RequestCreateWebhook) (webhook *models.Webhook, err error) {
+	workflow, exist := w.workflowService.GetWorkflow(&request.UserID, &request.WorkflowID)
+	if !exist {
+		return nil, models.ErrWorkflowNotFound
+	}
+	if !hasWebhookNode(workflow, request.NodeID) {
+		return nil, models.ErrWebhookNodeInvalid
+	}
+
+	token, err := randomToken(models.WebhookTokenBytes)
+	if err != nil {
+		return nil, err
+	}
+	webhook = &models.Webhook{
+		Token:      token,
+		URL:        hookURL(token),
+		WorkflowID: request.WorkflowID,
+		NodeID:     request.NodeID,
+		UserID:     request.UserID,
+		CreatedAt:  time.Now().UTC().Format(models.LayoutTimestamp),
+	}
+	active, err := w.workflowService.IsWorkflowActive(&request.WorkflowID)
+	if err != nil {
+		return nil, err
+	}
+	// enabled when the workflow is activated or resumed
+	webhook.Disabled = !active
+	if request.Signed {
+		secret := make([]byte, models.WebhookSecretBytes)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+		webhook.Secret = hex.EncodeToString(secret)
+	}
+
+	// the caller gets the secret once, redis only keeps it encrypted
+	stored := *webhook
+	stored.Secret, err = w.secrets.Encrypt(webhook.Secret)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.redisRepo.Save(&stored); err != nil {
+		log.Printf("ERROR | Cannot save webhook of node %s in workflow %s: %v", request.NodeID, request.WorkflowID, err)
+		return nil, err
+	}
+	return webhook, nil
+}
+
+func (w *WebhookServiceImpl) GetWebhooks(userID, workflowID *string) (webhooks []models.Webhook, err error) {
+	all, err := w.redisRepo.GetByWorkflow(workflowID)
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks = make([]models.Webhook, 0, len(all))
+	for i := range all {
+		if all[i].UserID != *userID {
+			continue
+		}
+		all[i].Secret = ""
+		webhooks = append(webhooks, all[i])
+	}
+	sort.Slice(webhooks, func(i, j int) bool {
+		return webhooks[i].NodeID < webhooks[j].NodeID
+	})
+	return webhooks, nil
+}
+
+func (w *WebhookServiceImpl) RemoveWebhook(userID, workflowID, nodeID *string) (err error) {
+	webhooks, err := w.GetWebhooks(userID, workflowID)
+	if err != nil {
+		return err
+	}
+	for i := range webhooks {
+		if webhooks[i].NodeID == *nodeID {
+			return w.redisRepo.Remove(workflowID, nodeID)
+		}
+	}
+	return models.ErrWebhookNotFound
+}
+
+// ReceiveWebhook hooks of paused, draft and trashed workflows are rejected even if they missed being disabled,
+// signed ones are accepted once and only inside WebhookTimestampTolerance
+func (w *WebhookServiceImpl) ReceiveWebhook(token *string, body []byte, contentType, signature, timestamp string) (event *models.TriggerEvent, err error) {
+	webhook, err := w.redisRepo.GetByToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if webhook.Disabled {
+		return nil, models.ErrWebhookDisabled
+	}
+	active, err := w.workflowService.IsWorkflowActive(&webhook.WorkflowID)
+	if err != nil {
+		return nil, err
+	}
+	if !active {
+		return nil, models.ErrWebhookDisabled
+	}
+	if webhook.Secret != "" {
+		if err := w.verifySigned(webhook, body, signature, timestamp, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+
+	event = &models.TriggerEvent{
+		EventID:     uuid.New().String(),
+		Source:      models.TriggerSourceWebhook,
+		WorkflowID:  webhook.WorkflowID,
+		NodeID:      webhook.NodeID,
+		UserID:      webhook.UserID,
+		ContentType: contentType,
+		Body:        string(body),
+		ReceivedAt:  time.Now().UTC().Format(models.LayoutTimestamp),
+	}
+	if !w.brokerRepo.PublishTrigger(event) {
+		log.Printf("ERROR | Cannot publish webhook event of node %s in workflow %s", webhook.NodeID, webhook.WorkflowID)
+		return nil, models.ErrWebhookCannotPublish
+	}
+	return event, nil
+}
+
+// SyncTriggers urls and secrets are kept, only Disabled changes
+func (w *WebhookServiceImpl) SyncTriggers(workflowID *string, active bool) (err error) {
+	webhooks, err := w.redisRepo.GetByWorkflow(workflowID)
+	if err != nil {
+		return err
+	}
+
+	for i := range webhooks {
+		if webhooks[i].Disabled == !active {
+			continue
+		}
+		webhooks[i].Disabled = !active
+		if err := w.redisRepo.Save(&webhooks[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *WebhookServiceImpl) RemoveTriggers(workflowID *string) (err error) {
+	return w.redisRepo.RemoveByWorkflow(workflowID)
+}
+
+// verifySigned the signature is the nonce, a captured request cannot be sent again while its timestamp is accepted
+func (w *WebhookServiceImpl) verifySigned(webhook *models.Webhook, body []byte, signature, timestamp string, now time.Time) (err error) {
+	sentAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return models.ErrWebhookSignatureFailed
+	}
+	age := now.Sub(time.Unix(sentAt, 0))
+	if age > models.WebhookTimestampTolerance || age < -models.WebhookTimestampTolerance {
+		return models.ErrWebhookSignatureFailed
+	}
+
+	secret, err := w.secrets.Decrypt(webhook.Secret)
+	if err != nil {
+		log.Printf("ERROR | Cannot decrypt secret of webhook of node %s in workflow %s: %v", webhook.NodeID, webhook.WorkflowID, err)
+		return err
+	}
+	if !validSignature(secret, timestamp, body, signature) {
+		return models.ErrWebhookSignatureFailed
+	}
+
+	saved, err := w.redisRepo.SaveNonce(&webhook.Token, &signature, 2*models.WebhookTimestampTolerance)
+	if err != nil {
+		return err
+	}
+	if !saved {
+		return models.ErrWebhookReplayed
+	}
+	return nil
+}
+
+func hasWebhookNode(workflow *models.Workflow, nodeID string) bool {
+	for i := range workflow.Nodes {
+		if workflow.Nodes[i].ID == nodeID {
+			return nodeTypeOf(&workflow.Nodes[i]) == models.NodeTypeWebhook
+		}
+	}
+	return false
+}
+
+// validSignature signature is "sha256=" followed by the hex HMAC of "<timestamp>.<body>", compared in constant time
+func validSignature(secret, timestamp string, body []byte, signature string) bool {
+	received, err := hex.DecodeString(strings.TrimPrefix(signature, models.WebhookSignaturePrefix))
+	if err != nil || !strings.HasPrefix(signature, models.WebhookSignaturePrefix) {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hmac.Equal(received, mac.Sum(nil))
+}
+
+func randomToken(size int) (string, error) {
+	token := make([]byte, size)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
+
+func hookURL(token string) string {
+	return strings.TrimSuffix(config.GetEnv("BACKEND_PUBLIC_URL", ""), "/") + models.HooksPath + token
+}
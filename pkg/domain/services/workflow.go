@@ -2,27 +2,42 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"minireipaz/pkg/common"
 	"minireipaz/pkg/domain/models"
 	"minireipaz/pkg/domain/repos"
+	"regexp"
+	"slices"
 	"time"
+	"unicode/utf8"
 )
 
+var copySuffix = regexp.MustCompile(` \(copy( \d+)?\)$`)
+
 type WorkflowServiceImpl struct {
-	redisRepo   repos.WorkflowRedisRepoInterface
-	brokerRepo  repos.WorkflowBrokerRepository
-	idGenerator IDService
-	httpRepo    repos.WorkflowHTTPRepository
+	redisRepo      repos.WorkflowRedisRepoInterface
+	brokerRepo     repos.WorkflowBrokerRepository
+	idGenerator    IDService
+	httpRepo       repos.WorkflowHTTPRepository
+	nodeService    repos.NodeService
+	graphValidator *GraphValidator
+	deadLetters    repos.DeadLetterService
+	outbox         repos.OutboxService
+	triggers       []repos.WorkflowTriggers
 }
 
-func NewWorkflowService(repoRedis repos.WorkflowRedisRepoInterface, repoBroker repos.WorkflowBrokerRepository, idGenerator IDService, repoHTTP repos.WorkflowHTTPRepository) repos.WorkflowService {
+func NewWorkflowService(repoRedis repos.WorkflowRedisRepoInterface, repoBroker repos.WorkflowBrokerRepository, idGenerator IDService, repoHTTP repos.WorkflowHTTPRepository, nodeService repos.NodeService, deadLetters repos.DeadLetterService, outbox repos.OutboxService) repos.WorkflowService {
 	return &WorkflowServiceImpl{
-		redisRepo:   repoRedis,
-		brokerRepo:  repoBroker,
-		idGenerator: idGenerator,
-		httpRepo:    repoHTTP,
+		redisRepo:      repoRedis,
+		brokerRepo:     repoBroker,
+		idGenerator:    idGenerator,
+		httpRepo:       repoHTTP,
+		nodeService:    nodeService,
+		graphValidator: NewGraphValidator(nodeService),
+		deadLetters:    deadLetters,
+		outbox:         outbox,
 	}
 }
 
@@ -34,27 +49,105 @@ func (s *WorkflowServiceImpl) CreateWorkflow(workflowFrontend *models.WorkflowFr
 	if exist {
 		return false, exist, workflow
 	}
+	created, exist = s.createWithRetries(workflow)
+	return created, exist, workflow
+}
+
+func (s *WorkflowServiceImpl) createWithRetries(workflow *models.Workflow) (created bool, exist bool) {
 	for i := 1; i < models.MaxAttempts; i++ {
 		created, exist = s.retriesCreateWorkflow(workflow)
 		if !exist && created {
-			return created, exist, workflow
+			return created, exist
 		}
 		if exist {
-			return false, true, workflow
+			return false, true
 		}
 
 		waitTime := common.RandomDuration(models.MaxRangeSleepDuration, models.MinRangeSleepDuration, i)
 		log.Printf("WARNING | Failed to create workflow, attempt %d:. Retrying in %v", i, waitTime)
 		time.Sleep(waitTime)
 	}
-	log.Print("ERROR | Needs to add to Dead Letter. Cannot create workflow")
-	// TODO: dead letter
-	return false, false, workflow
+	log.Printf("ERROR | Cannot create workflow %s, added to dead letter", workflow.Name)
+	addDeadLetter(s.deadLetters, models.DeadLetterOriginWorkflowCreate, workflow, models.ErrRetriesExhausted, models.DeadLetterRetriedAttempts)
+	return false, false
+}
+
+// DuplicateWorkflow copies the graph under a free "(copy N)" name, credentials are only kept when asked
+func (s *WorkflowServiceImpl) DuplicateWorkflow(userID, workflowID *string, keepCredentials bool) (created bool, exist bool, workflow *models.Workflow, err error) {
+	source, exist := s.GetWorkflow(userID, workflowID)
+	if !exist {
+		return false, false, nil, nil
+	}
+
+	workflow = &models.Workflow{
+		Description:     source.Description,
+		DirectoryToSave: source.DirectoryToSave,
+		UserID:          *userID,
+		Nodes:           s.cloneNodes(source.Nodes, keepCredentials),
+		Edges:           append([]models.Edge{}, source.Edges...),
+		Viewport:        source.Viewport,
+	}
+	baseName := copySuffix.ReplaceAllString(source.Name, "")
+	for copyNumber := 1; copyNumber <= models.MaxWorkflowCopies; copyNumber++ {
+		workflow.Name = copyName(baseName, copyNumber)
+		// a failed read says nothing about the name, trying the next ones would skip free names
+		nameExists, err := s.redisRepo.NameExists(userID, &workflow.Name)
+		if err != nil {
+			log.Printf("ERROR | Cannot check copy name of workflow %s: %v", *workflowID, err)
+			return false, true, workflow, err
+		}
+		if nameExists {
+			continue
+		}
+		// name taken between the check and the create is tried again with the next number
+		created, exist = s.createWithRetries(workflow)
+		if created {
+			return true, true, workflow, nil
+		}
+		if !exist {
+			return false, true, workflow, nil
+		}
+	}
+	log.Printf("ERROR | No free name to duplicate workflow %s", *workflowID)
+	return false, true, workflow, nil
+}
+
+func (s *WorkflowServiceImpl) cloneNodes(nodes []models.Node, keepCredentials bool) []models.Node {
+	cloned := make([]models.Node, 0, len(nodes))
+	for _, node := range nodes {
+		if node.Data != nil {
+			data := *node.Data
+			if !keepCredentials {
+				data.CredentialData = models.RequestCreateCredential{Type: s.credentialTypeOf(&node)}
+			}
+			node.Data = &data
+		}
+		cloned = append(cloned, node)
+	}
+	return cloned
+}
+
+func copyName(name string, copyNumber int) string {
+	suffix := " (copy)"
+	if copyNumber > 1 {
+		suffix = fmt.Sprintf(" (copy %d)", copyNumber)
+	}
+	// cut on a rune boundary, half of a multibyte character is not valid utf-8
+	for len(name)+len(suffix) > models.MaxWorkflowNameLength {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	return name + suffix
 }
 
 func (s *WorkflowServiceImpl) retriesCreateWorkflow(workflow *models.Workflow) (bool, bool) {
 	workflow.UUID = s.idGenerator.GenerateWorkflowID()
-	existWorkflowUUID := s.ValidateUserWorkflowUUID(&workflow.UUID, &workflow.Name) // check individual
+	for i := range workflow.Nodes {
+		if workflow.Nodes[i].Data != nil {
+			workflow.Nodes[i].Data.WorkflowID = workflow.UUID
+		}
+	}
+	existWorkflowUUID := s.ValidateUserWorkflowUUID(&workflow.UserID, &workflow.Name) // check individual
 	if existWorkflowUUID {
 		return false, true
 	}
@@ -74,25 +167,32 @@ func (s *WorkflowServiceImpl) retriesCreateWorkflow(workflow *models.Workflow) (
 
 	workflow.CreatedAt = time.Now().UTC().Format(models.LayoutTimestamp) // right now not controlled by db
 	workflow.UpdatedAt = workflow.CreatedAt                              // right now not controlled by db
-	workflow.IsActive = models.Active                                    // right now not controlled by db
+	workflow.IsActive = models.Draft                                     // activation is the one checking the workflow is complete to run
 	workflow.Status = models.Initial                                     // right now not controlled by db
 	workflow.WorkflowInit = models.CustomTime{Time: models.TimeDefault}
 	workflow.WorkflowCompleted = models.CustomTime{Time: models.TimeDefault}
 
-	createdRedis, existRedis := s.redisRepo.Create(workflow)
-	if existRedis {
-		return false, true
+	workflow.Version, err = s.redisRepo.NextVersion(&workflow.UUID)
+	if err != nil {
+		log.Printf("ERROR | Cannot generate version for workflow %s: %v", workflow.UUID, err)
+		return false, false
 	}
-	if !createdRedis {
+
+	// written with the index, the relay publishes it even if this instance dies right after
+	message, err := s.brokerRepo.CreateCommand(workflow, models.CommandTypeCreate)
+	if err != nil {
+		log.Printf("ERROR | Cannot build create command for workflow %s: %v", workflow.UUID, err)
 		return false, false
 	}
 
-	sended := s.brokerRepo.Create(workflow)
-	if !sended {
-		log.Printf("ERROR | Failed to publish workflow event")
-		s.redisRepo.Remove(workflow)
+	createdRedis, existRedis := s.redisRepo.Create(workflow, message)
+	if existRedis {
+		return false, true
+	}
+	if !createdRedis {
 		return false, false
 	}
+	dispatchOutbox(s.outbox, message)
 	return createdRedis, existRedis
 }
 
@@ -109,12 +209,11 @@ func (s *WorkflowServiceImpl) GetWorkflow(userID, workflowID *string) (newWorkfl
 		newWorkflow, exist = s.retriesGetWorkflow(userID, workflowID)
 		return exist
 	})
-	if exist {
-		return newWorkflow, exist
+	// nothing is lost when a read fails, only failed writes go to the dead letter
+	if !exist {
+		log.Printf("ERROR | Cannot get workflow %s", *workflowID)
 	}
-
-	// TODO: dead letter
-	return nil, false
+	return newWorkflow, exist
 }
 
 func (s *WorkflowServiceImpl) GetAllWorkflows(userID *string) (allWorkflows []models.Workflow, err error) {
@@ -132,6 +231,11 @@ func (s *WorkflowServiceImpl) GetAllWorkflows(userID *string) (allWorkflows []mo
 }
 
 func (s *WorkflowServiceImpl) retryTemplateWithError(ctx context.Context, maxAttempts int, operation func() error) error {
+	return retryWithError(ctx, maxAttempts, operation)
+}
+
+// retryWithError services without their own retry template use this one
+func retryWithError(ctx context.Context, maxAttempts int, operation func() error) error {
 	for i := 1; i < maxAttempts; i++ {
 		if err := operation(); err == nil {
 			return nil
@@ -164,20 +268,259 @@ func (s *WorkflowServiceImpl) retryTemplateWithBool(ctx context.Context, maxAtte
 	return false
 }
 
-func (s *WorkflowServiceImpl) UpdateWorkflow(workflow *models.Workflow) (updated bool, exist bool) {
+func (s *WorkflowServiceImpl) UpdateWorkflow(workflow *models.Workflow) (updated bool, exist bool, err error) {
 	ctx, cancel := context.WithTimeout(context.Background(), models.MaxTimeoutContext)
 	defer cancel()
 
 	exist = s.ValidateWorkflowGlobalUUID(&workflow.UUID)
 	if !exist {
-		return false, exist
+		return false, exist, nil
+	}
+	// required fields depend on the stored state, the one sent by the client is not trusted
+	workflow.IsActive, err = s.redisRepo.GetLifecycle(&workflow.UUID)
+	if err != nil {
+		log.Printf("ERROR | Cannot get state of workflow %s: %v", workflow.UUID, err)
+		return false, true, err
+	}
+	// every save goes through here, rollbacks, imports and folder moves too
+	if graphErrors := s.ValidateWorkflowGraph(workflow); len(graphErrors) > 0 {
+		return false, true, &models.GraphInvalidError{Errors: graphErrors}
 	}
 
-	exist = s.retryTemplateWithBool(ctx, models.MaxAttempts, func() bool {
-		updated, exist = s.retriesUpdateWorkflow(workflow)
-		return exist
+	s.retryTemplateWithBool(ctx, models.MaxAttempts, func() bool {
+		updated, exist, err = s.retriesUpdateWorkflow(workflow)
+		// a stale copy or a taken name cannot be fixed retrying
+		return updated || !exist || errors.Is(err, models.ErrVersionConflict) || errors.Is(err, models.ErrWorkflowNameExist) || errors.Is(err, models.ErrWorkflowNotFound)
+	})
+	return updated, exist, err
+}
+
+func (s *WorkflowServiceImpl) DeleteWorkflow(userID, workflowID *string) (deleted bool, exist bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), models.MaxTimeoutContext)
+	defer cancel()
+
+	workflow, exist := s.GetWorkflow(userID, workflowID)
+	if !exist {
+		return false, false
+	}
+
+	deleted = s.retryTemplateWithBool(ctx, models.MaxAttempts, func() bool {
+		return s.retriesDeleteWorkflow(workflow)
 	})
-	return updated, exist
+	return deleted, true
+}
+
+// GetWorkflowsTrash returns the trash of the user, expired entries are hidden until the purge tick removes them
+func (s *WorkflowServiceImpl) GetWorkflowsTrash(userID *string) (trashed []models.TrashedWorkflow, err error) {
+	allTrashed, err := s.redisRepo.GetTrash(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	trashed = make([]models.TrashedWorkflow, 0, len(allTrashed))
+	for i := range allTrashed {
+		if s.isTrashExpired(&allTrashed[i], now) {
+			continue
+		}
+		trashed = append(trashed, allTrashed[i])
+	}
+	return trashed, nil
+}
+
+func (s *WorkflowServiceImpl) RestoreWorkflow(userID, workflowID *string) (restored bool, err error) {
+	// expired entries wait for the purge but cannot be restored
+	trash, err := s.GetWorkflowsTrash(userID)
+	if err != nil {
+		return false, err
+	}
+	index := slices.IndexFunc(trash, func(trashed models.TrashedWorkflow) bool { return trashed.UUID == *workflowID })
+	if index < 0 {
+		return false, models.ErrWorkflowNotInTrash
+	}
+
+	workflow, err := s.fromTrashedToWorkflow(&trash[index])
+	if err != nil {
+		log.Printf("ERROR | Cannot get graph of trashed workflow %s: %v", *workflowID, err)
+		return false, err
+	}
+	workflow.UpdatedAt = time.Now().UTC().Format(models.LayoutTimestamp)
+	message, err := s.brokerRepo.CreateCommand(workflow, models.CommandTypeRestore)
+	if err != nil {
+		log.Printf("ERROR | Cannot build restore command for workflow %s: %v", workflow.UUID, err)
+		return false, err
+	}
+	if _, err := s.redisRepo.RestoreFromTrash(userID, workflowID, message); err != nil {
+		return false, err
+	}
+	dispatchOutbox(s.outbox, message)
+	// back to the state it had before the trash
+	active, err := s.IsWorkflowActive(&workflow.UUID)
+	if err != nil {
+		log.Printf("ERROR | Cannot get state of restored workflow %s: %v", workflow.UUID, err)
+		return true, nil
+	}
+	s.syncTriggers(&workflow.UUID, active)
+	return true, nil
+}
+
+func (s *WorkflowServiceImpl) PurgeWorkflow(userID, workflowID *string) (purged bool, exist bool) {
+	trashed, err := s.redisRepo.GetTrash(userID)
+	if err != nil {
+		log.Printf("ERROR | Cannot get trash for user %s: %v", *userID, err)
+		return false, false
+	}
+
+	for i := range trashed {
+		if trashed[i].UUID == *workflowID {
+			return s.purgeTrashed(&trashed[i]), true
+		}
+	}
+	return false, false
+}
+
+func (s *WorkflowServiceImpl) retriesDeleteWorkflow(workflow *models.Workflow) (deleted bool) {
+	lockKey := "lock:" + workflow.UUID
+	acquired, err := s.redisRepo.AcquireLock(lockKey, "_", models.MaxTimeForLocks)
+	if err != nil {
+		log.Printf("ERROR | acquiring lock: %v", err)
+		return false
+	}
+	if !acquired {
+		return false
+	}
+
+	defer s.redisRepo.RemoveLock(lockKey) // in case
+
+	now := time.Now().UTC()
+	trashed := &models.TrashedWorkflow{
+		UUID:            workflow.UUID,
+		UserID:          workflow.UserID,
+		Name:            workflow.Name,
+		Description:     workflow.Description,
+		DirectoryToSave: workflow.DirectoryToSave,
+		DeletedAt:       now.Format(models.LayoutTimestamp),
+		ExpiresAt:       now.Add(models.TrashRetention).Format(models.LayoutTimestamp),
+		Workflow:        workflow,
+	}
+
+	workflow.UpdatedAt = trashed.DeletedAt
+	message, err := s.brokerRepo.CreateCommand(workflow, models.CommandTypeDelete)
+	if err != nil {
+		log.Printf("ERROR | Cannot build delete command for workflow %s: %v", workflow.UUID, err)
+		return false
+	}
+
+	moved, err := s.redisRepo.MoveToTrash(trashed, message)
+	if err != nil || !moved {
+		return false
+	}
+	dispatchOutbox(s.outbox, message)
+	s.syncTriggers(&workflow.UUID, false)
+	return true
+}
+
+func (s *WorkflowServiceImpl) purgeTrashed(trashed *models.TrashedWorkflow) (purged bool) {
+	workflow, err := s.fromTrashedToWorkflow(trashed)
+	if err != nil {
+		log.Printf("ERROR | Cannot get graph of trashed workflow %s: %v", trashed.UUID, err)
+		return false
+	}
+	message, err := s.brokerRepo.CreateCommand(workflow, models.CommandTypePurge)
+	if err != nil {
+		log.Printf("ERROR | Cannot build purge command for workflow %s: %v", trashed.UUID, err)
+		return false
+	}
+
+	// a concurrent purge already removed it and wrote its own command
+	removed, err := s.redisRepo.RemoveFromTrash(&trashed.UserID, &trashed.UUID, message)
+	if err != nil {
+		log.Printf("ERROR | Cannot remove workflow %s from trash: %v", trashed.UUID, err)
+		return false
+	}
+	if !removed {
+		return false
+	}
+	dispatchOutbox(s.outbox, message)
+	s.removeTriggers(&trashed.UUID)
+	return true
+}
+
+// PurgeExpiredTrash only one instance purges every tick, the others find the lock taken
+func (s *WorkflowServiceImpl) PurgeExpiredTrash(now time.Time) (purged int, err error) {
+	acquired, err := s.redisRepo.AcquireLock(models.TrashPurgeLockKey, "_", models.TrashPurgeLockTTL)
+	if err != nil || !acquired {
+		return 0, err
+	}
+
+	userIDs, err := s.redisRepo.GetTrashUsers()
+	if err != nil {
+		return 0, err
+	}
+	for i := range userIDs {
+		trashed, err := s.redisRepo.GetTrash(&userIDs[i])
+		if err != nil {
+			log.Printf("ERROR | Cannot get trash for user %s: %v", userIDs[i], err)
+			continue
+		}
+		for j := range trashed {
+			if !s.isTrashExpired(&trashed[j], now) {
+				continue
+			}
+			if !s.purgeTrashed(&trashed[j]) {
+				log.Printf("ERROR | Cannot purge expired workflow %s from trash", trashed[j].UUID)
+				continue
+			}
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func (s *WorkflowServiceImpl) StartTrashPurge(ctx context.Context) {
+	ticker := time.NewTicker(models.TrashPurgeTick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if _, err := s.PurgeExpiredTrash(now.UTC()); err != nil {
+				log.Printf("ERROR | Trash purge tick failed: %v", err)
+			}
+		}
+	}
+}
+
+func (s *WorkflowServiceImpl) isTrashExpired(trashed *models.TrashedWorkflow, now time.Time) bool {
+	expiresAt, err := time.Parse(models.LayoutTimestamp, trashed.ExpiresAt)
+	if err != nil {
+		log.Printf("ERROR | Invalid expiration for workflow %s in trash: %v", trashed.UUID, err)
+		return false
+	}
+	return now.After(expiresAt)
+}
+
+// fromTrashedToWorkflow entries trashed before the copy was kept read the graph from ClickHouse,
+// trashed workflows are not in the global index so GetWorkflow cannot be used
+func (s *WorkflowServiceImpl) fromTrashedToWorkflow(trashed *models.TrashedWorkflow) (*models.Workflow, error) {
+	if trashed.Workflow != nil {
+		workflow := *trashed.Workflow
+		workflow.UpdatedAt = trashed.DeletedAt
+		return &workflow, nil
+	}
+
+	response, err := s.httpRepo.GetWorkflowDataByID(&trashed.UserID, &trashed.UUID, 1)
+	if err != nil {
+		return nil, err
+	}
+	if response == nil || len(response.Data) != 1 {
+		return nil, models.ErrWorkflowNotFound
+	}
+	workflow := response.Data[0]
+	workflow.UpdatedAt = trashed.DeletedAt
+	return &workflow, nil
 }
 
 func (s *WorkflowServiceImpl) fromWorkflowFrontendToBackend(fw *models.WorkflowFrontend) *models.Workflow {
@@ -187,16 +530,16 @@ func (s *WorkflowServiceImpl) fromWorkflowFrontendToBackend(fw *models.WorkflowF
 		DirectoryToSave: fw.DirectoryToSave,
 		UserID:          fw.UserID,
 		Nodes: []models.Node{
-			s.createInitialNode(),
-		},
-		Viewport: &models.Viewport{
-			X:    234.5,
-			Y:    534.5,
-			Zoom: 1,
+			createInitialNode(),
 		},
+		Viewport: defaultViewport(),
 	}
 }
 
+func (s *WorkflowServiceImpl) ValidateWorkflowGraph(workflow *models.Workflow) (graphErrors []models.GraphValidationError) {
+	return s.graphValidator.Validate(workflow)
+}
+
 func (s *WorkflowServiceImpl) ValidateWorkflowGlobalUUID(uuid *string) bool {
 	return s.redisRepo.ValidateWorkflowGlobalUUID(uuid)
 }
@@ -206,37 +549,56 @@ func (s *WorkflowServiceImpl) ValidateUserWorkflowUUID(worklfowID, name *string)
 }
 
 // TODO: what happens when workflows is already running and user update workflow??
-func (s *WorkflowServiceImpl) retriesUpdateWorkflow(workflow *models.Workflow) (updated, exist bool) {
+func (s *WorkflowServiceImpl) retriesUpdateWorkflow(workflow *models.Workflow) (updated, exist bool, err error) {
 	exist = s.ValidateWorkflowGlobalUUID(&workflow.UUID)
 	if !exist {
-		return false, exist
+		return false, exist, nil
 	}
 
 	lockKey := "lock:" + workflow.UUID
-	// case lockkey cannot be created, it is assumed here the "10 seconds rate-limit" time has not passed
-	acquired, err := s.redisRepo.AcquireLock(lockKey, "", models.RateLimitUpdate)
+	// concurrent editors are controlled by version, lock only covers check version and publish
+	acquired, err := s.redisRepo.AcquireLock(lockKey, "", models.MaxTimeForLocks)
 	if err != nil {
 		log.Printf("ERROR | acquiring lock: %v", err)
-		return false, false
+		return false, true, err
 	}
 	if !acquired {
-		// Cannot acquire lock, maybe already exist UUID
-		return false, true
+		return false, true, nil
 	}
 
 	defer s.redisRepo.RemoveLock(lockKey) // in case
 
 	workflow.UpdatedAt = time.Now().UTC().Format(models.LayoutTimestamp) // right now not controlled by db
+	// every update is a new immutable revision
+	expectedVersion := workflow.Version
+	version, matched, err := s.redisRepo.CompareAndIncrVersion(&workflow.UUID, expectedVersion)
+	if err != nil {
+		log.Printf("ERROR | Cannot generate version for workflow %s: %v", workflow.UUID, err)
+		return false, true, err
+	}
+	if !matched {
+		log.Printf("WARN | Stale update for workflow %s, expected version %d current %d", workflow.UUID, expectedVersion, version)
+		return false, true, models.ErrVersionConflict
+	}
+	workflow.Version = version
 	// workflow.WorkflowInit = models.CustomTime{Time: models.TimeDefault}
 	// workflow.WorkflowCompleted = models.CustomTime{Time: models.TimeDefault}
 
-	updated = s.brokerRepo.Update(workflow)
-	if !updated {
-		log.Printf("ERROR | Failed to publish workflow event")
-		s.redisRepo.Remove(workflow)
-		return false, false
+	message, err := s.brokerRepo.CreateCommand(workflow, models.CommandTypeUpdate)
+	if err != nil {
+		log.Printf("ERROR | Cannot build update command for workflow %s: %v", workflow.UUID, err)
+		s.redisRepo.RevertVersion(&workflow.UUID, version)
+		workflow.Version = expectedVersion
+		return false, true, err
 	}
-	return updated, exist
+	// name index follows renames in the same transaction as the command, name of another workflow cannot be taken
+	if _, err := s.redisRepo.RenameInIndex(workflow, message); err != nil {
+		s.redisRepo.RevertVersion(&workflow.UUID, version)
+		workflow.Version = expectedVersion
+		return false, true, err
+	}
+	dispatchOutbox(s.outbox, message)
+	return true, exist, nil
 }
 
 func (s *WorkflowServiceImpl) retriesGetWorkflow(userID, workflowID *string) (newWorkflow *models.Workflow, exist bool) {
@@ -281,21 +643,30 @@ func (s *WorkflowServiceImpl) retriesGetAllWorkflows(_ context.Context, userID *
 	return allWorkflows.Data, err
 }
 
-func (s *WorkflowServiceImpl) createInitialNode() models.Node {
+func defaultViewport() *models.Viewport {
+	return &models.Viewport{
+		X:    234.5,
+		Y:    534.5,
+		Zoom: 1,
+	}
+}
+
+func createInitialNode() models.Node {
 	return models.Node{
-		ID:   "initial-node",
+		ID:   models.InitialNodeID,
 		Type: "wrapperNode",
 		Position: &models.Position{
 			X: 2,
 			Y: 0,
 		},
 		Data: &models.DataNode{
-			ID:             "initial-node",
+			ID:             models.InitialNodeID,
 			Label:          "Start Point",
 			Options:        "Initial Options",
 			Description:    "This is the starting point of your workflow",
 			WorkflowID:     "", // asigned later
-			NodeID:         "initial-node",
+			NodeID:         models.InitialNodeID,
+			Type:           models.NodeTypeStart,
 			CredentialData: models.RequestCreateCredential{},
 		},
 		Measured: &models.Measured{
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"minireipaz/pkg/domain/models"
+)
+
+// ReplayDeadLetter one attempt for each replay, the admin decides when to try again
+func (s *WorkflowServiceImpl) ReplayDeadLetter(letter *models.DeadLetter) (err error) {
+	switch letter.Origin {
+	case models.DeadLetterOriginWorkflowCreate:
+		var workflow models.Workflow
+		if err := json.Unmarshal(letter.Payload, &workflow); err != nil {
+			return err
+		}
+		created, exist := s.retriesCreateWorkflow(&workflow)
+		if exist {
+			return models.ErrWorkflowNameExist
+		}
+		if !created {
+			return errors.New(models.DeadLetterCannotReplay)
+		}
+		return nil
+
+	case models.DeadLetterOriginWorkflowUpdate:
+		var workflow models.Workflow
+		if err := json.Unmarshal(letter.Payload, &workflow); err != nil {
+			return err
+		}
+		updated, exist, err := s.UpdateWorkflow(&workflow)
+		if err != nil {
+			return err
+		}
+		if !exist {
+			return models.ErrWorkflowNotFound
+		}
+		if !updated {
+			return errors.New(models.DeadLetterCannotReplay)
+		}
+		return nil
+	}
+	return models.ErrDeadLetterOriginUnknown
+}
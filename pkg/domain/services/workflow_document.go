@@ -0,0 +1,150 @@
+package services
+
+import (
+	"minireipaz/pkg/domain/models"
+)
+
+// ExportWorkflow credentials are replaced by the credential type the node needs
+func (s *WorkflowServiceImpl) ExportWorkflow(userID, workflowID *string) (document *models.WorkflowDocument, exist bool) {
+	workflow, exist := s.GetWorkflow(userID, workflowID)
+	if !exist {
+		return nil, false
+	}
+
+	document = &models.WorkflowDocument{
+		Kind:          models.WorkflowDocumentKind,
+		SchemaVersion: models.WorkflowDocumentSchemaVersion,
+		Name:          workflow.Name,
+		Description:   workflow.Description,
+		Viewport:      workflow.Viewport,
+		Nodes:         make([]models.Node, 0, len(workflow.Nodes)),
+		Edges:         make([]models.Edge, 0, len(workflow.Edges)),
+		Credentials:   []models.CredentialPlaceholder{},
+	}
+	for _, node := range workflow.Nodes {
+		if node.Data != nil {
+			data := *node.Data
+			data.WorkflowID = ""
+			data.CredentialData = models.RequestCreateCredential{Type: s.credentialTypeOf(&node)}
+			if data.CredentialData.Type != "" {
+				document.Credentials = append(document.Credentials, models.CredentialPlaceholder{
+					NodeID: node.ID,
+					Type:   data.CredentialData.Type,
+				})
+			}
+			node.Data = &data
+		}
+		document.Nodes = append(document.Nodes, node)
+	}
+	document.Edges = append(document.Edges, workflow.Edges...)
+	return document, true
+}
+
+// ImportWorkflow creates a new workflow from a document, imported workflows are drafts until credentials are bound again
+func (s *WorkflowServiceImpl) ImportWorkflow(userID, directoryToSave *string, document *models.WorkflowDocument) (imported *models.ImportedWorkflow, graphErrors []models.GraphValidationError, err error) {
+	if document.Kind != models.WorkflowDocumentKind || document.SchemaVersion < 1 || document.SchemaVersion > models.WorkflowDocumentSchemaVersion {
+		return nil, nil, models.ErrWorkflowDocumentInvalid
+	}
+
+	imported = &models.ImportedWorkflow{
+		NodeIDs:     make(map[string]string, len(document.Nodes)),
+		Credentials: []models.CredentialPlaceholder{},
+	}
+	workflow := &models.Workflow{
+		Name:            document.Name,
+		Description:     document.Description,
+		DirectoryToSave: *directoryToSave,
+		UserID:          *userID,
+		IsActive:        models.Draft,
+		Viewport:        document.Viewport,
+		Nodes:           s.remapNodes(document.Nodes, imported),
+		Edges:           s.remapEdges(document.Edges, imported.NodeIDs),
+	}
+	if workflow.Viewport == nil {
+		workflow.Viewport = defaultViewport()
+	}
+
+	graphErrors = s.ValidateWorkflowGraph(workflow)
+	if len(graphErrors) > 0 {
+		return nil, graphErrors, models.ErrWorkflowDocumentInvalid
+	}
+
+	// created with the whole graph in one command, a failure never leaves an empty workflow behind
+	created, exist := s.createWithRetries(workflow)
+	if exist {
+		return nil, nil, models.ErrWorkflowNameExist
+	}
+	if !created {
+		return nil, nil, models.ErrWorkflowCannotImport
+	}
+
+	imported.Workflow = workflow
+	return imported, nil, nil
+}
+
+// remapNodes every node gets a new id except the start node, credentials needed are collected with the new ids
+func (s *WorkflowServiceImpl) remapNodes(nodes []models.Node, imported *models.ImportedWorkflow) []models.Node {
+	for _, node := range nodes {
+		if node.ID == "" || node.ID == models.InitialNodeID {
+			imported.NodeIDs[node.ID] = node.ID
+			continue
+		}
+		imported.NodeIDs[node.ID] = s.idGenerator.GenerateWorkflowID()
+	}
+
+	remapped := make([]models.Node, 0, len(nodes))
+	for _, node := range nodes {
+		node.ID = imported.NodeIDs[node.ID]
+		if node.Data != nil {
+			data := *node.Data
+			data.ID = node.ID
+			data.NodeID = node.ID
+			data.WorkflowID = ""
+			data.CredentialData = models.RequestCreateCredential{Type: s.credentialTypeOf(&node)}
+			if data.CredentialData.Type != "" {
+				imported.Credentials = append(imported.Credentials, models.CredentialPlaceholder{
+					NodeID: node.ID,
+					Type:   data.CredentialData.Type,
+				})
+			}
+			node.Data = &data
+		}
+		remapped = append(remapped, node)
+	}
+	return remapped
+}
+
+func (s *WorkflowServiceImpl) remapEdges(edges []models.Edge, nodeIDs map[string]string) []models.Edge {
+	remapped := make([]models.Edge, 0, len(edges))
+	for _, edge := range edges {
+		if edge.Source != nil {
+			if newID, ok := nodeIDs[*edge.Source]; ok {
+				edge.Source = &newID
+			}
+		}
+		if edge.Target != nil {
+			if newID, ok := nodeIDs[*edge.Target]; ok {
+				edge.Target = &newID
+			}
+		}
+		edgeID := s.idGenerator.GenerateWorkflowID()
+		edge.ID = &edgeID
+		remapped = append(remapped, edge)
+	}
+	return remapped
+}
+
+// credentialTypeOf type saved with the credential, if missing the one declared in the catalog
+func (s *WorkflowServiceImpl) credentialTypeOf(node *models.Node) string {
+	if node.Data != nil && node.Data.CredentialData.Type != "" {
+		return node.Data.CredentialData.Type
+	}
+	if s.nodeService == nil {
+		return ""
+	}
+	definition, exist := s.nodeService.GetNodeType(nodeTypeOf(node))
+	if !exist {
+		return ""
+	}
+	return definition.CredentialType
+}
@@ -0,0 +1,220 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"minireipaz/pkg/domain/models"
+	"slices"
+	"sort"
+	"time"
+)
+
+// ReconcileIndexes rebuilds users:<id> and workflows:all from database, without user every indexed user is reconciled.
+// Workflows only in redis can still be waiting in the broker, they are only removed with prune
+func (s *WorkflowServiceImpl) ReconcileIndexes(userID *string, prune bool) (reports []models.IndexReconcileReport, err error) {
+	owners, err := s.redisRepo.GetWorkflowsIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	var userIDs []string
+	if userID != nil && *userID != "" {
+		userIDs = []string{*userID}
+	} else {
+		userIDs, err = s.redisRepo.GetIndexedUsers()
+		if err != nil {
+			return nil, err
+		}
+		for _, owner := range owners {
+			if !slices.Contains(userIDs, owner) {
+				userIDs = append(userIDs, owner)
+			}
+		}
+	}
+
+	reports = []models.IndexReconcileReport{}
+	failed := []string{}
+	for i := range userIDs {
+		report, err := s.reconcileUserIndex(&userIDs[i], owners, prune)
+		if err != nil {
+			log.Printf("ERROR | Cannot reconcile index of user %s: %v", userIDs[i], err)
+			failed = append(failed, userIDs[i])
+			continue
+		}
+		reports = append(reports, *report)
+	}
+	if len(failed) > 0 {
+		return reports, fmt.Errorf("%s for users %v", models.IndexCannotReconcile, failed)
+	}
+	return reports, nil
+}
+
+// reconcileUserIndex every workflow of the user is paged from database, a capped listing would prune valid entries
+func (s *WorkflowServiceImpl) reconcileUserIndex(userID *string, owners map[string]string, prune bool) (*models.IndexReconcileReport, error) {
+	page, err := s.ListWorkflows(&models.WorkflowListQuery{UserID: *userID})
+	if err != nil {
+		return nil, err
+	}
+	trashed, err := s.redisRepo.GetTrash(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 1; i < models.MaxAttempts; i++ {
+		snapshot, err := s.redisRepo.GetUserIndex(userID)
+		if err != nil {
+			return nil, err
+		}
+
+		change, report := buildIndexChange(*userID, snapshot, page.Workflows, trashed, owners, nil)
+		prunable, err := s.prunableWorkflows(userID, report, prune)
+		if err != nil {
+			return nil, err
+		}
+		if prune {
+			change, report = buildIndexChange(*userID, snapshot, page.Workflows, trashed, owners, prunable)
+		}
+		err = s.redisRepo.ApplyUserIndex(change)
+		if err == nil {
+			return report, nil
+		}
+		// someone created or renamed a workflow meanwhile, compute again
+		if !errors.Is(err, models.ErrIndexChanged) {
+			return nil, err
+		}
+	}
+	return nil, models.ErrIndexChanged
+}
+
+// prunableWorkflows missing uuids are tracked even without prune, so a later prune knows since when
+func (s *WorkflowServiceImpl) prunableWorkflows(userID *string, report *models.IndexReconcileReport, prune bool) (prunable map[string]bool, err error) {
+	now := time.Now().UTC()
+	since, err := s.redisRepo.TrackMissing(userID, report.Missing, now)
+	if err != nil {
+		return nil, err
+	}
+	if !prune {
+		return nil, nil
+	}
+
+	prunable = make(map[string]bool, len(since))
+	for _, workflowID := range report.Missing {
+		if now.Sub(since[workflowID]) >= models.IndexPruneGrace {
+			prunable[workflowID] = true
+		}
+	}
+	return prunable, nil
+}
+
+// buildIndexChange when two workflows have the same name the last updated keeps it, uuids missing in database
+// are only removed when prunable
+func buildIndexChange(userID string, snapshot map[string]string, workflows []models.Workflow, trashed []models.TrashedWorkflow, owners map[string]string, prunable map[string]bool) (*models.UserIndexChange, *models.IndexReconcileReport) {
+	change := &models.UserIndexChange{
+		UserID:          userID,
+		Snapshot:        snapshot,
+		SetNames:        map[string]string{},
+		RemoveNames:     []string{},
+		SetWorkflows:    []string{},
+		RemoveWorkflows: []string{},
+	}
+	report := &models.IndexReconcileReport{
+		UserID:    userID,
+		Fixed:     []string{},
+		Removed:   []string{},
+		Conflicts: []string{},
+		Missing:   []string{},
+		Pending:   []string{},
+	}
+
+	inTrash := make(map[string]bool, len(trashed))
+	for _, current := range trashed {
+		inTrash[current.UUID] = true
+	}
+
+	// database can return more than one row by workflow, last version wins
+	latest := make(map[string]models.Workflow, len(workflows))
+	for _, workflow := range workflows {
+		if inTrash[workflow.UUID] {
+			continue
+		}
+		if previous, exist := latest[workflow.UUID]; !exist || workflow.Version > previous.Version {
+			latest[workflow.UUID] = workflow
+		}
+	}
+	ordered := make([]models.Workflow, 0, len(latest))
+	for _, workflow := range latest {
+		ordered = append(ordered, workflow)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		if ordered[i].UpdatedAt != ordered[j].UpdatedAt {
+			return ordered[i].UpdatedAt > ordered[j].UpdatedAt
+		}
+		return ordered[i].UUID < ordered[j].UUID
+	})
+
+	desired := make(map[string]string, len(ordered))
+	for _, workflow := range ordered {
+		if _, taken := desired[workflow.Name]; taken {
+			report.Conflicts = append(report.Conflicts, workflow.UUID)
+			continue
+		}
+		desired[workflow.Name] = workflow.UUID
+	}
+
+	for name, workflowID := range desired {
+		if snapshot[name] != workflowID {
+			change.SetNames[name] = workflowID
+			report.Fixed = append(report.Fixed, name)
+		}
+	}
+
+	for name, workflowID := range snapshot {
+		if desired[name] == workflowID {
+			continue
+		}
+		if _, overwritten := change.SetNames[name]; overwritten {
+			continue
+		}
+		_, inDatabase := latest[workflowID]
+		if inDatabase || inTrash[workflowID] || prunable[workflowID] {
+			change.RemoveNames = append(change.RemoveNames, name)
+			report.Removed = append(report.Removed, name)
+		}
+		if !inDatabase && !inTrash[workflowID] && !slices.Contains(report.Missing, workflowID) {
+			report.Missing = append(report.Missing, workflowID)
+		}
+	}
+
+	for workflowID := range latest {
+		if owners[workflowID] != userID {
+			change.SetWorkflows = append(change.SetWorkflows, workflowID)
+		}
+	}
+	for workflowID, owner := range owners {
+		if owner != userID {
+			continue
+		}
+		if _, inDatabase := latest[workflowID]; inDatabase {
+			continue
+		}
+		if inTrash[workflowID] || prunable[workflowID] {
+			change.RemoveWorkflows = append(change.RemoveWorkflows, workflowID)
+		}
+		if !inTrash[workflowID] && !slices.Contains(report.Missing, workflowID) {
+			report.Missing = append(report.Missing, workflowID)
+		}
+	}
+
+	sort.Strings(report.Fixed)
+	sort.Strings(report.Removed)
+	sort.Strings(report.Conflicts)
+	for _, workflowID := range report.Missing {
+		if prunable != nil && !prunable[workflowID] {
+			report.Pending = append(report.Pending, workflowID)
+		}
+	}
+	sort.Strings(report.Missing)
+	sort.Strings(report.Pending)
+	return change, report
+}
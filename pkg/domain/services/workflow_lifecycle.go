@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/domain/repos"
+	"slices"
+	"time"
+)
+
+// ChangeLifecycle moves IsActive through the state machine, activation needs a graph valid to run
+func (s *WorkflowServiceImpl) ChangeLifecycle(userID, workflowID *string, transition string) (workflow *models.Workflow, graphErrors []models.GraphValidationError, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), models.MaxTimeoutContext)
+	defer cancel()
+
+	next, known := models.LifecycleTransitions[transition]
+	if !known {
+		return nil, nil, models.ErrWorkflowTransitionInvalid
+	}
+
+	workflow, exist := s.GetWorkflow(userID, workflowID)
+	if !exist {
+		return nil, nil, models.ErrWorkflowNotFound
+	}
+
+	// the copy in database can be behind the last transition
+	current, err := s.redisRepo.GetLifecycle(workflowID)
+	if err != nil {
+		log.Printf("ERROR | Cannot get state of workflow %s: %v", *workflowID, err)
+		return workflow, nil, err
+	}
+	workflow.IsActive = current
+	if !slices.Contains(next.From, current) {
+		return workflow, nil, models.ErrWorkflowTransitionInvalid
+	}
+
+	workflow.IsActive = next.To
+	if next.To == models.Active {
+		graphErrors = s.ValidateWorkflowGraph(workflow)
+		if len(graphErrors) > 0 {
+			workflow.IsActive = current
+			return workflow, graphErrors, models.ErrWorkflowCannotActivate
+		}
+	}
+
+	var changed bool
+	s.retryTemplateWithBool(ctx, models.MaxAttempts, func() bool {
+		changed, err = s.retriesChangeLifecycle(workflow, transition)
+		return changed || errors.Is(err, models.ErrVersionConflict)
+	})
+	if !changed {
+		workflow.IsActive = current
+		if err == nil {
+			err = errors.New(models.WorkflowCannotChangeState)
+		}
+		return workflow, nil, err
+	}
+	s.syncTriggers(&workflow.UUID, workflow.IsActive == models.Active)
+	return workflow, nil, nil
+}
+
+// retriesChangeLifecycle a transition is a new revision too, so a concurrent update makes it stale
+func (s *WorkflowServiceImpl) retriesChangeLifecycle(workflow *models.Workflow, transition string) (changed bool, err error) {
+	lockKey := "lock:" + workflow.UUID
+	acquired, err := s.redisRepo.AcquireLock(lockKey, "", models.MaxTimeForLocks)
+	if err != nil {
+		log.Printf("ERROR | acquiring lock: %v", err)
+		return false, err
+	}
+	if !acquired {
+		return false, nil
+	}
+
+	defer s.redisRepo.RemoveLock(lockKey)
+
+	// the copy from ClickHouse lags behind the last update, the version is the one in redis
+	expectedVersion, err := s.redisRepo.GetVersion(&workflow.UUID)
+	if err != nil {
+		log.Printf("ERROR | Cannot get version of workflow %s: %v", workflow.UUID, err)
+		return false, err
+	}
+	version, matched, err := s.redisRepo.CompareAndIncrVersion(&workflow.UUID, expectedVersion)
+	if err != nil {
+		log.Printf("ERROR | Cannot generate version for workflow %s: %v", workflow.UUID, err)
+		return false, err
+	}
+	if !matched {
+		log.Printf("WARN | Stale %s for workflow %s, expected version %d current %d", transition, workflow.UUID, expectedVersion, version)
+		return false, models.ErrVersionConflict
+	}
+	workflow.Version = version
+	workflow.UpdatedAt = time.Now().UTC().Format(models.LayoutTimestamp)
+
+	message, err := s.brokerRepo.CreateCommand(workflow, lifecycleCommand(transition))
+	if err != nil {
+		log.Printf("ERROR | Cannot build %s command for workflow %s: %v", transition, workflow.UUID, err)
+		s.redisRepo.RevertVersion(&workflow.UUID, version)
+		workflow.Version = expectedVersion
+		return false, err
+	}
+	if err := s.redisRepo.SetLifecycle(&workflow.UUID, workflow.IsActive, message); err != nil {
+		log.Printf("ERROR | Cannot store state of workflow %s: %v", workflow.UUID, err)
+		s.redisRepo.RevertVersion(&workflow.UUID, version)
+		workflow.Version = expectedVersion
+		return false, err
+	}
+	dispatchOutbox(s.outbox, message)
+	return true, nil
+}
+
+// lifecycleCommand every transition has its own command so executors know when to start or stop scheduling
+func lifecycleCommand(transition string) string {
+	switch transition {
+	case models.TransitionActivate:
+		return models.CommandTypeActivate
+	case models.TransitionPause:
+		return models.CommandTypePause
+	case models.TransitionResume:
+		return models.CommandTypeResume
+	}
+	return models.CommandTypeDraft
+}
+
+// IsWorkflowActive trashed and purged workflows are not in the global index
+func (s *WorkflowServiceImpl) IsWorkflowActive(workflowID *string) (active bool, err error) {
+	if !s.ValidateWorkflowGlobalUUID(workflowID) {
+		return false, nil
+	}
+	state, err := s.redisRepo.GetLifecycle(workflowID)
+	if err != nil {
+		return false, err
+	}
+	return state == models.Active, nil
+}
+
+func (s *WorkflowServiceImpl) RegisterTriggers(triggers repos.WorkflowTriggers) {
+	s.triggers = append(s.triggers, triggers)
+}
+
+// syncTriggers errors are only logged, triggers check the state of the workflow again before firing
+func (s *WorkflowServiceImpl) syncTriggers(workflowID *string, active bool) {
+	for _, triggers := range s.triggers {
+		if err := triggers.SyncTriggers(workflowID, active); err != nil {
+			log.Printf("ERROR | Cannot sync triggers of workflow %s: %v", *workflowID, err)
+		}
+	}
+}
+
+func (s *WorkflowServiceImpl) removeTriggers(workflowID *string) {
+	for _, triggers := range s.triggers {
+		if err := triggers.RemoveTriggers(workflowID); err != nil {
+			log.Printf("ERROR | Cannot remove triggers of workflow %s: %v", *workflowID, err)
+		}
+	}
+}
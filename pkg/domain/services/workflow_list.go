@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"minireipaz/pkg/domain/models"
+)
+
+// ListWorkflows one page of the workflows of the user, one row more is asked to know if there is a next page.
+// Without limit and cursor every workflow is returned, as the listing did before it had pages
+func (s *WorkflowServiceImpl) ListWorkflows(query *models.WorkflowListQuery) (page *models.WorkflowPage, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), models.MaxTimeoutContext)
+	defer cancel()
+
+	listAll := query.Limit == 0 && query.Cursor == ""
+	normalizeListQuery(query)
+	if listAll {
+		query.Limit = models.MaxRowsFromDB
+	}
+	var cursor *models.WorkflowCursor
+	if query.Cursor != "" {
+		cursor, err = decodeWorkflowCursor(query)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	page = &models.WorkflowPage{Workflows: []models.Workflow{}}
+	for {
+		current, err := s.getWorkflowsPage(ctx, query, cursor)
+		if err != nil {
+			return nil, err
+		}
+		page.Workflows = append(page.Workflows, current.Workflows...)
+		page.NextCursor, page.HasMore = current.NextCursor, current.HasMore
+		if !listAll || !current.HasMore {
+			break
+		}
+		last := &current.Workflows[len(current.Workflows)-1]
+		cursor = &models.WorkflowCursor{SortBy: query.SortBy, Order: query.Order, Value: cursorValue(query, last), ID: last.UUID}
+	}
+	if listAll {
+		page.NextCursor, page.HasMore = "", false
+	}
+	return page, nil
+}
+
+func (s *WorkflowServiceImpl) getWorkflowsPage(ctx context.Context, query *models.WorkflowListQuery, cursor *models.WorkflowCursor) (page *models.WorkflowPage, err error) {
+	var response *models.InfoWorkflow
+	err = s.retryTemplateWithError(ctx, models.MaxAttempts, func() error {
+		var lastError error
+		response, lastError = s.httpRepo.GetWorkflowsPage(query, cursor, query.Limit+1)
+		return lastError
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	page = &models.WorkflowPage{Workflows: []models.Workflow{}}
+	if response == nil {
+		return page, nil
+	}
+	page.Workflows = response.Data
+	if uint64(len(page.Workflows)) > query.Limit {
+		page.Workflows = page.Workflows[:query.Limit]
+		page.HasMore = true
+		page.NextCursor = encodeWorkflowCursor(query, &page.Workflows[len(page.Workflows)-1])
+	}
+	return page, nil
+}
+
+func normalizeListQuery(query *models.WorkflowListQuery) {
+	if query.SortBy == "" {
+		query.SortBy = models.SortByUpdatedAt
+	}
+	if query.Order == "" {
+		query.Order = models.SortOrderDesc
+	}
+	if query.Limit == 0 {
+		query.Limit = models.DefaultWorkflowPageSize
+	}
+	if query.Limit > models.MaxWorkflowPageSize {
+		query.Limit = models.MaxWorkflowPageSize
+	}
+}
+
+func encodeWorkflowCursor(query *models.WorkflowListQuery, last *models.Workflow) string {
+	return encodeCursor(&models.WorkflowCursor{
+		SortBy: query.SortBy,
+		Order:  query.Order,
+		Value:  cursorValue(query, last),
+		ID:     last.UUID,
+	})
+}
+
+// encodeCursor shared by every keyset listing, runs use it too
+func encodeCursor(cursor *models.WorkflowCursor) string {
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func cursorValue(query *models.WorkflowListQuery, last *models.Workflow) string {
+	switch query.SortBy {
+	case models.SortByCreatedAt:
+		return last.CreatedAt
+	case models.SortByName:
+		return last.Name
+	default:
+		return last.UpdatedAt
+	}
+}
+
+func decodeWorkflowCursor(query *models.WorkflowListQuery) (*models.WorkflowCursor, error) {
+	return decodeCursor(query.Cursor, query.SortBy, query.Order)
+}
+
+// decodeCursor a cursor is only valid for the sorting it was created with
+func decodeCursor(encoded, sortBy, order string) (*models.WorkflowCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, models.ErrWorkflowCursorInvalid
+	}
+	var cursor models.WorkflowCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil || cursor.ID == "" {
+		return nil, models.ErrWorkflowCursorInvalid
+	}
+	if cursor.SortBy != sortBy || cursor.Order != order {
+		return nil, models.ErrWorkflowCursorInvalid
+	}
+	return &cursor, nil
+}
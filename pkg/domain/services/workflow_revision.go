@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"minireipaz/pkg/domain/models"
+	"reflect"
+)
+
+func (s *WorkflowServiceImpl) GetWorkflowRevisions(userID, workflowID *string) (revisions []models.Workflow, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), models.MaxTimeoutContext)
+	defer cancel()
+
+	if !s.ValidateWorkflowGlobalUUID(workflowID) {
+		return nil, models.ErrRevisionNotFound
+	}
+
+	err = s.retryTemplateWithError(ctx, models.MaxAttempts, func() error {
+		response, lastError := s.httpRepo.GetWorkflowRevisions(userID, workflowID, models.MaxRowsFromDB)
+		if lastError != nil {
+			return lastError
+		}
+		if response != nil {
+			revisions = response.Data
+		}
+		return nil
+	})
+	return revisions, err
+}
+
+func (s *WorkflowServiceImpl) GetWorkflowRevision(userID, workflowID *string, version uint32) (revision *models.Workflow, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), models.MaxTimeoutContext)
+	defer cancel()
+
+	if !s.ValidateWorkflowGlobalUUID(workflowID) {
+		return nil, models.ErrRevisionNotFound
+	}
+
+	var response *models.InfoWorkflow
+	err = s.retryTemplateWithError(ctx, models.MaxAttempts, func() error {
+		var lastError error
+		response, lastError = s.httpRepo.GetWorkflowRevision(userID, workflowID, version)
+		return lastError
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// only one row by version
+	if response == nil || len(response.Data) != 1 {
+		return nil, models.ErrRevisionNotFound
+	}
+	return &response.Data[0], nil
+}
+
+func (s *WorkflowServiceImpl) DiffWorkflowRevisions(userID, workflowID *string, fromVersion, toVersion uint32) (diff *models.WorkflowDiff, err error) {
+	from, err := s.GetWorkflowRevision(userID, workflowID, fromVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := s.GetWorkflowRevision(userID, workflowID, toVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	diff = s.diffWorkflows(from, to)
+	diff.FromVersion = fromVersion
+	diff.ToVersion = toVersion
+	return diff, nil
+}
+
+// RollbackWorkflow publishes the graph of an old revision as a new update, so history is never rewritten
+func (s *WorkflowServiceImpl) RollbackWorkflow(userID, workflowID *string, version uint32) (updated bool, exist bool, err error) {
+	current, exist := s.GetWorkflow(userID, workflowID)
+	if !exist {
+		return false, false, nil
+	}
+
+	revision, err := s.GetWorkflowRevision(userID, workflowID, version)
+	if errors.Is(err, models.ErrRevisionNotFound) {
+		return false, false, nil
+	}
+	if err != nil {
+		log.Printf("ERROR | Cannot get revision %d of workflow %s: %v", version, *workflowID, err)
+		return false, true, err
+	}
+
+	// the copy from ClickHouse lags behind the last update, the version is the one in redis
+	current.Version, err = s.redisRepo.GetVersion(workflowID)
+	if err != nil {
+		log.Printf("ERROR | Cannot get version of workflow %s: %v", *workflowID, err)
+		return false, true, err
+	}
+	current.Nodes = revision.Nodes
+	current.Edges = revision.Edges
+	current.Viewport = revision.Viewport
+	current.Description = revision.Description
+	updated, exist, err = s.UpdateWorkflow(current)
+	if err != nil {
+		log.Printf("ERROR | Cannot rollback workflow %s to revision %d: %v", *workflowID, version, err)
+	}
+	return updated, exist, err
+}
+
+func (s *WorkflowServiceImpl) diffWorkflows(from, to *models.Workflow) *models.WorkflowDiff {
+	diff := &models.WorkflowDiff{
+		Nodes: models.NodesDiff{
+			Added:   []models.Node{},
+			Removed: []models.Node{},
+			Changed: []models.NodeChange{},
+		},
+		Edges: models.EdgesDiff{
+			Added:   []models.Edge{},
+			Removed: []models.Edge{},
+			Changed: []models.EdgeChange{},
+		},
+	}
+
+	fromNodes := make(map[string]models.Node, len(from.Nodes))
+	for _, node := range from.Nodes {
+		fromNodes[node.ID] = node
+	}
+	toNodes := make(map[string]bool, len(to.Nodes))
+	for _, node := range to.Nodes {
+		toNodes[node.ID] = true
+		before, ok := fromNodes[node.ID]
+		if !ok {
+			diff.Nodes.Added = append(diff.Nodes.Added, node)
+			continue
+		}
+		if !reflect.DeepEqual(before, node) {
+			diff.Nodes.Changed = append(diff.Nodes.Changed, models.NodeChange{ID: node.ID, Before: before, After: node})
+		}
+	}
+	for _, node := range from.Nodes {
+		if !toNodes[node.ID] {
+			diff.Nodes.Removed = append(diff.Nodes.Removed, node)
+		}
+	}
+
+	fromEdges := make(map[string]models.Edge, len(from.Edges))
+	for _, edge := range from.Edges {
+		fromEdges[edgeKey(&edge)] = edge
+	}
+	toEdges := make(map[string]bool, len(to.Edges))
+	for _, edge := range to.Edges {
+		key := edgeKey(&edge)
+		toEdges[key] = true
+		before, ok := fromEdges[key]
+		if !ok {
+			diff.Edges.Added = append(diff.Edges.Added, edge)
+			continue
+		}
+		if !reflect.DeepEqual(before, edge) {
+			diff.Edges.Changed = append(diff.Edges.Changed, models.EdgeChange{ID: key, Before: before, After: edge})
+		}
+	}
+	for _, edge := range from.Edges {
+		if !toEdges[edgeKey(&edge)] {
+			diff.Edges.Removed = append(diff.Edges.Removed, edge)
+		}
+	}
+
+	return diff
+}
+
+// edgeKey edges created by the frontend always have id, in case not use source->target
+func edgeKey(edge *models.Edge) string {
+	if edge.ID != nil && *edge.ID != "" {
+		return *edge.ID
+	}
+	var source, target string
+	if edge.Source != nil {
+		source = *edge.Source
+	}
+	if edge.Target != nil {
+		target = *edge.Target
+	}
+	return fmt.Sprintf("%s->%s", source, target)
+}
@@ -2,6 +2,7 @@ package brokerclient
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
 	"minireipaz/pkg/domain/models"
 	"time"
@@ -31,6 +32,27 @@ func (a *ActionsKafkaRepository) Create(newAction *models.RequestGoogleAction) (
 	return sended
 }
 
+// CreateCommand the create command is not published here, it is written to the outbox with the action
+func (a *ActionsKafkaRepository) CreateCommand(newAction *models.RequestGoogleAction) (message *models.OutboxMessage, err error) {
+	now := time.Now().UTC()
+	command, err := json.Marshal(models.ActionsCommand{
+		Actions:   newAction,
+		Type:      CommandTypeCreate,
+		Timestamp: now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.OutboxMessage{
+		Topic:     TopicName,
+		Key:       newAction.ActionID,
+		DedupKey:  fmt.Sprintf("action:%s:%s", CommandTypeCreate, newAction.ActionID),
+		Value:     string(command),
+		CreatedAt: now.Format(models.LayoutTimestamp),
+	}, nil
+}
+
 func (a *ActionsKafkaRepository) PublishCommand(payload models.ActionsCommand, key string) bool {
 	command, err := json.Marshal(payload)
 	if err != nil {
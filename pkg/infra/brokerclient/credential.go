@@ -5,6 +5,7 @@ import (
 	"log"
 	"minireipaz/pkg/common"
 	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/domain/repos"
 	"time"
 )
 
@@ -15,12 +16,14 @@ type CredentialCommand struct {
 }
 
 type CredentialKafkaRepository struct {
-	client KafkaClient
+	client  KafkaClient
+	secrets repos.SecretService
 }
 
-func NewCredentialKafkaRepository(client KafkaClient) *CredentialKafkaRepository {
+func NewCredentialKafkaRepository(client KafkaClient, secrets repos.SecretService) *CredentialKafkaRepository {
 	return &CredentialKafkaRepository{
-		client: client,
+		client:  client,
+		secrets: secrets,
 	}
 }
 
@@ -63,9 +66,15 @@ func (c *CredentialKafkaRepository) credentialToPayload(stateInfo *models.Reques
 	// stateInfo.Version = 1
 	// stateInfo.IsActive = true
 
-	dataCredential, err := json.Marshal(stateInfo.Data)
+	// secrets leave encrypted, stateInfo keeps them in plaintext for the caller
+	encrypted := stateInfo.Data
+	if err := c.secrets.EncryptCredential(&encrypted); err != nil {
+		log.Printf("ERROR | Cannot encrypt secrets of credential %s: %v", stateInfo.ID, err)
+		return nil
+	}
+	dataCredential, err := json.Marshal(encrypted)
 	if err != nil {
-		log.Printf("ERROR | Cannot convert to json %v", stateInfo.Data)
+		log.Printf("ERROR | Cannot convert to json data of credential %s", stateInfo.ID)
 		return nil
 	}
 
@@ -73,6 +82,7 @@ func (c *CredentialKafkaRepository) credentialToPayload(stateInfo *models.Reques
 		RequestExchangeCredential: *stateInfo,
 		Data:                      string(dataCredential),
 	}
+	payload.RequestExchangeCredential.Data = encrypted
 	return payload
 }
 
@@ -0,0 +1,32 @@
+package brokerclient
+
+import (
+	"encoding/json"
+	"log"
+	"minireipaz/pkg/domain/models"
+)
+
+type DeadLetterKafkaRepository struct {
+	client KafkaClient
+}
+
+func NewDeadLetterKafkaRepository(client KafkaClient) *DeadLetterKafkaRepository {
+	return &DeadLetterKafkaRepository{
+		client: client,
+	}
+}
+
+// Publish single attempt, the broker being down is often why the command ended here
+func (d *DeadLetterKafkaRepository) Publish(letter *models.DeadLetter) (sended bool) {
+	letterJSON, err := json.Marshal(letter)
+	if err != nil {
+		log.Printf("ERROR | Cannot transform to JSON %v", err)
+		return false
+	}
+
+	if err := d.client.Produce("deadletters", []byte(letter.Origin), letterJSON); err != nil {
+		log.Printf("ERROR | Cannot publish dead letter %s of %s: %v", letter.ID, letter.Origin, err)
+		return false
+	}
+	return true
+}
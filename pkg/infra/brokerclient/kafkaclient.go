@@ -9,6 +9,7 @@ import (
 
 type KafkaClient interface {
 	Produce(topic string, key []byte, value []byte) error
+	ProduceWithHeaders(topic string, key []byte, value []byte, headers map[string]string) error
 	Close()
 }
 
@@ -30,11 +31,18 @@ func NewBrokerClient(kafkaConfig config.KafkaConfig) KafkaClient {
 }
 
 func (k *KafkaClientImpl) Produce(topic string, key []byte, value []byte) error {
+	return k.ProduceWithHeaders(topic, key, value, nil)
+}
+
+func (k *KafkaClientImpl) ProduceWithHeaders(topic string, key []byte, value []byte, headers map[string]string) error {
 	message := &kafka.Message{
 		TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: kafka.PartitionAny},
 		Key:            key,
 		Value:          value,
 	}
+	for name, header := range headers {
+		message.Headers = append(message.Headers, kafka.Header{Key: name, Value: []byte(header)})
+	}
 
 	deliveryChan := make(chan kafka.Event)
 	err := k.producer.Produce(message, deliveryChan)
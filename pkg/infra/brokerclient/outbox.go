@@ -0,0 +1,28 @@
+package brokerclient
+
+import (
+	"log"
+	"minireipaz/pkg/domain/models"
+)
+
+type OutboxKafkaRepository struct {
+	client KafkaClient
+}
+
+func NewOutboxKafkaRepository(client KafkaClient) *OutboxKafkaRepository {
+	return &OutboxKafkaRepository{
+		client: client,
+	}
+}
+
+// Publish one attempt, a failed message stays in the outbox until the next relay tick
+func (o *OutboxKafkaRepository) Publish(message *models.OutboxMessage) (sended bool) {
+	err := o.client.ProduceWithHeaders(message.Topic, []byte(message.Key), []byte(message.Value), map[string]string{
+		models.OutboxDedupHeader: message.DedupKey,
+	})
+	if err != nil {
+		log.Printf("ERROR | Cannot publish outbox message %s to %s: %v", message.DedupKey, message.Topic, err)
+		return false
+	}
+	return true
+}
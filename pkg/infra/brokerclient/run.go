@@ -0,0 +1,52 @@
+package brokerclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"minireipaz/pkg/domain/models"
+	"time"
+)
+
+const (
+	CommandTypeRun = "run"
+	RunsTopic      = "workflows.runs"
+)
+
+// RunCommand Graph is already resolved, executors run the nodes in the order they come
+type RunCommand struct {
+	Type      string           `json:"type"`
+	Run       *models.Run      `json:"run"`
+	Graph     []models.RunNode `json:"graph"`
+	Timestamp time.Time        `json:"timestamp"`
+}
+
+type RunKafkaRepository struct {
+	client KafkaClient
+}
+
+func NewRunKafkaRepository(client KafkaClient) *RunKafkaRepository {
+	return &RunKafkaRepository{
+		client: client,
+	}
+}
+
+// CreateCommand the run is written to the outbox with its record, the relay publishes it
+func (r *RunKafkaRepository) CreateCommand(run *models.Run, graph []models.RunNode) (message *models.OutboxMessage, err error) {
+	command, err := json.Marshal(RunCommand{
+		Type:      CommandTypeRun,
+		Run:       run,
+		Graph:     graph,
+		Timestamp: time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.OutboxMessage{
+		Topic:     RunsTopic,
+		Key:       run.WorkflowID,
+		DedupKey:  fmt.Sprintf("run:%s", run.RunID),
+		Value:     string(command),
+		CreatedAt: time.Now().UTC().Format(models.LayoutTimestamp),
+	}, nil
+}
@@ -0,0 +1,53 @@
+package brokerclient
+
+import (
+	"encoding/json"
+	"log"
+	"minireipaz/pkg/common"
+	"minireipaz/pkg/domain/models"
+	"time"
+)
+
+const CommandTypeTrigger = "trigger"
+
+type TriggerCommand struct {
+	Type      string               `json:"type"`
+	Event     *models.TriggerEvent `json:"event"`
+	Timestamp time.Time            `json:"timestamp"`
+}
+
+type WebhookKafkaRepository struct {
+	client KafkaClient
+}
+
+func NewWebhookKafkaRepository(client KafkaClient) *WebhookKafkaRepository {
+	return &WebhookKafkaRepository{
+		client: client,
+	}
+}
+
+// PublishTrigger keyed by workflow so events of the same workflow keep their order
+func (w *WebhookKafkaRepository) PublishTrigger(event *models.TriggerEvent) (sended bool) {
+	command, err := json.Marshal(TriggerCommand{
+		Type:      CommandTypeTrigger,
+		Event:     event,
+		Timestamp: time.Now().UTC(),
+	})
+	if err != nil {
+		log.Printf("ERROR | Cannot transform to JSON %v", err)
+		return false
+	}
+
+	for i := 1; i < models.MaxAttempts; i++ {
+		err = w.client.Produce("workflows.trigger", []byte(event.WorkflowID), command)
+		if err == nil {
+			return true
+		}
+
+		waitTime := common.RandomDuration(models.MaxRangeSleepDuration, models.MinRangeSleepDuration, i)
+		log.Printf("ERROR | Cannot connect to Broker, attempt %d: %v. Retrying in %v", i, err, waitTime)
+		time.Sleep(waitTime)
+	}
+
+	return false
+}
@@ -3,20 +3,26 @@ package brokerclient
 import (
 	"encoding/json"
 	"fmt"
-	"log"
-	"minireipaz/pkg/common"
 	"minireipaz/pkg/domain/models"
 	"time"
 )
 
+const WorkflowsTopic = "workflows.command"
+
 type WorkflowKafkaRepository struct {
 	client KafkaClient
 }
 
 const (
-	CommandTypeCreate = "create"
-	CommandTypeUpdate = "update"
-	CommandTypeDelete = "delete"
+	CommandTypeCreate   = "create"
+	CommandTypeUpdate   = "update"
+	CommandTypeDelete   = "delete"
+	CommandTypeRestore  = "restore"
+	CommandTypePurge    = "purge"
+	CommandTypeActivate = "activate"
+	CommandTypePause    = "pause"
+	CommandTypeResume   = "resume"
+	CommandTypeDraft    = "draft"
 )
 
 type WorkflowCommand struct {
@@ -31,34 +37,26 @@ func NewWorkflowKafkaRepository(client KafkaClient) *WorkflowKafkaRepository {
 	}
 }
 
-func (w *WorkflowKafkaRepository) Create(workflow *models.Workflow) (sended bool) {
-	payload, err := w.workflowToPayload(workflow, CommandTypeCreate)
+// CreateCommand commands are not published here, they are written to the outbox with the state they belong to,
+// the relay publishes them when the request that wrote them cannot
+func (w *WorkflowKafkaRepository) CreateCommand(workflow *models.Workflow, commandType string) (message *models.OutboxMessage, err error) {
+	payload, err := w.workflowToPayload(workflow, commandType)
 	if err != nil {
-		log.Printf("ERROR | Cannot convert workflow to payload: %v", err)
-		return false
-	}
-	command := WorkflowCommand{
-		Workflow: payload,
-		// Type:      CommandTypeCreate,
-		// Timestamp: time.Now(),
+		return nil, err
 	}
-	sended = w.PublishCommand(command, workflow.UUID)
-	return sended
-}
-
-func (w *WorkflowKafkaRepository) Update(workflow *models.Workflow) (sended bool) {
-	payload, err := w.workflowToPayload(workflow, CommandTypeUpdate)
+	command, err := json.Marshal(WorkflowCommand{Workflow: payload})
 	if err != nil {
-		log.Printf("ERROR | Cannot convert workflow to payload: %v", err)
-		return false
-	}
-	command := WorkflowCommand{
-		Workflow: payload,
-		// Type:     CommandTypeUpdate,
-		// Timestamp: time.Now(),
+		return nil, err
 	}
-	sended = w.PublishCommand(command, workflow.UUID)
-	return sended
+
+	return &models.OutboxMessage{
+		Topic: WorkflowsTopic,
+		Key:   workflow.UUID,
+		// trash commands do not change the version, a delete after a restore needs its own key
+		DedupKey:  fmt.Sprintf("workflow:%s:%s:%d:%s", commandType, workflow.UUID, workflow.Version, workflow.UpdatedAt),
+		Value:     string(command),
+		CreatedAt: time.Now().UTC().Format(models.LayoutTimestamp),
+	}, nil
 }
 
 // use sync.pool in serverless not necessary
@@ -78,6 +76,11 @@ func (w *WorkflowKafkaRepository) workflowToPayload(workflow *models.Workflow, c
 		return nil, err
 	}
 
+	var version *uint32
+	if workflow.Version > 0 {
+		version = &workflow.Version
+	}
+
 	return &models.WorkflowPayload{
 		UUID:              workflow.UUID,
 		UserID:            workflow.UserID,
@@ -93,6 +96,7 @@ func (w *WorkflowKafkaRepository) workflowToPayload(workflow *models.Workflow, c
 		Nodes:             nodesJSON,
 		Edges:             edgesJSON,
 		Viewport:          viewportJSON,
+		Version:           version,
 		TypeCommand:       commandType,
 	}, nil
 }
@@ -109,24 +113,3 @@ func (w *WorkflowKafkaRepository) serializeToJSON(params interface{}) (*string,
 	str := string(bytes)
 	return &str, nil
 }
-
-func (w *WorkflowKafkaRepository) PublishCommand(payload WorkflowCommand, key string) bool {
-	command, err := json.Marshal(payload)
-	if err != nil {
-		log.Printf("ERROR | Cannot transform to JSON %v", err)
-		return false
-	}
-
-	for i := 1; i < models.MaxAttempts; i++ {
-		err = w.client.Produce("workflows.command", []byte(key), command)
-		if err == nil {
-			return true
-		}
-
-		waitTime := common.RandomDuration(models.MaxRangeSleepDuration, models.MinRangeSleepDuration, i)
-		log.Printf("ERROR | Cannot connect to Broker, attempt %d: %v. Retrying in %v", i, err, waitTime)
-		time.After(waitTime)
-	}
-
-	return false
-}
@@ -2,11 +2,14 @@ package httpclient
 
 import (
 	"encoding/json"
+	"fmt"
+	"io"
 	"log"
 	"minireipaz/pkg/config"
 	"minireipaz/pkg/domain/models"
 	"minireipaz/pkg/domain/repos"
 	"net/http"
+	"net/url"
 )
 
 var _ repos.ActionsHTTPRepository = (*ActionsHTTPRepository)(nil)
@@ -66,45 +69,42 @@ func (a *ActionsHTTPRepository) PublishCommand(data *models.ActionsCommand, serv
 	return &response
 }
 
-// func (a *ActionsHTTPRepository) GetActionByID(actionID *string, userID *string, limitCount uint64) (data *string, err error) {
-// 	u, err := url.Parse(a.databaseHTTPURL + "/action_workflow_data.json")
-// 	if err != nil {
-// 		return nil, err
-// 	}
+// GetActionByID last state of the action, executors insert a new row on every change
+func (a *ActionsHTTPRepository) GetActionByID(userID, actionID *string) (*models.InfoActions, error) {
+	u, err := url.Parse(a.databaseHTTPURL + "/action_workflow_data.json")
+	if err != nil {
+		return nil, err
+	}
 
-// 	q := u.Query()
-// 	q.Set("token", a.token)
-// 	q.Set("action_id", *actionID)
-// 	q.Set("user_id", *userID)
-// 	q.Set("limit_count", fmt.Sprintf("%d", limitCount))
-// 	u.RawQuery = q.Encode()
+	q := u.Query()
+	q.Set("token", a.token)
+	q.Set("action_id", *actionID)
+	q.Set("user_id", *userID)
+	q.Set("limit_count", "1")
+	u.RawQuery = q.Encode()
 
-// 	req, err := http.NewRequest("GET", u.String(), nil)
-// 	if err != nil {
-// 		return nil, err
-// 	}
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
 
-// 	resp, err := a.client.Do(req)
-// 	if err != nil {
-// 		return nil, err
-// 	}
-// 	defer resp.Body.Close()
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
 
-// 	if resp.StatusCode != http.StatusOK {
-// 		bodyBytes, _ := io.ReadAll(resp.Body)
-// 		return nil, fmt.Errorf("ERROR | response: %d, body: %s", resp.StatusCode, string(bodyBytes))
-// 	}
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("ERROR | response: %d, body: %s", resp.StatusCode, string(bodyBytes))
+	}
 
-// 	var result *models.InfoWorkflow
-// 	// if err := json.Unmarshal(bodyBytes, &result); err != nil {
-// 	// 	log.Printf("ERROR | cannot decode body: %s %v", string(bodyBytes), err)
-// 	// 	return models.InfoDashboard{}, fmt.Errorf("ERROR | cannot decode token: %v", err)
-// 	// }
-// 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-// 		bodyBytes, _ := io.ReadAll(resp.Body)
-// 		log.Printf("ERROR | cannot decode body: %s %v", string(bodyBytes), err)
-// 		return nil, fmt.Errorf("ERROR | cannot decode token: %v", err)
-// 	}
+	var result *models.InfoActions
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		log.Printf("ERROR | cannot decode body: %s %v", string(bodyBytes), err)
+		return nil, fmt.Errorf("ERROR | cannot decode token: %v", err)
+	}
 
-// 	return result, nil
-// }
+	return result, nil
+}
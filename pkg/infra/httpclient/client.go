@@ -85,3 +85,36 @@ func validateURL(rawURL string) (string, error) {
 
 	return parsedURL.String(), nil
 }
+
+// queryPipe GET to a clickhouse pipe, the token is added here and the json response decoded in result
+func queryPipe(client HTTPClient, databaseHTTPURL, token, pipe string, params url.Values, result interface{}) error {
+	u, err := url.Parse(databaseHTTPURL + pipe)
+	if err != nil {
+		return err
+	}
+
+	params.Set("token", token)
+	u.RawQuery = params.Encode()
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("ERROR | response: %d, body: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		log.Printf("ERROR | cannot decode response of pipe %s: %v", pipe, err)
+		return fmt.Errorf("ERROR | cannot decode response of pipe %s: %v", pipe, err)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"minireipaz/pkg/domain/models"
+	"strings"
+
+	"golang.org/x/oauth2"
+)
+
+type CredentialOAuthHTTPRepository struct {
+	client HTTPClient
+}
+
+func NewOAuthCredentialRepository(httpCli HTTPClient) *CredentialOAuthHTTPRepository {
+	return &CredentialOAuthHTTPRepository{
+		client: httpCli,
+	}
+}
+
+func (c *CredentialOAuthHTTPRepository) GenerateAuthURL(provider *models.OAuthProvider, credential *models.RequestExchangeCredential, state string) (authURL *string) {
+	config := oauthConfig(provider, credential)
+	opts := make([]oauth2.AuthCodeOption, 0, len(provider.AuthParams)+2)
+	for name, value := range provider.AuthParams {
+		opts = append(opts, oauth2.SetAuthURLParam(name, value))
+	}
+	// some providers do not accept the space the oauth2 package joins scopes with
+	if provider.ScopeSeparator != "" {
+		opts = append(opts, oauth2.SetAuthURLParam("scope", strings.Join(credential.Data.Scopes, provider.ScopeSeparator)))
+	}
+	if provider.PKCE {
+		opts = append(opts, oauth2.S256ChallengeOption(credential.Data.CodeVerifier))
+	}
+
+	url := config.AuthCodeURL(state, opts...)
+	return &url
+}
+
+func (c *CredentialOAuthHTTPRepository) ExchangeCode(provider *models.OAuthProvider, stateInfo *models.RequestExchangeCredential, code string) (token *models.OAuthToken, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), models.MaxTimeoutContext)
+	defer cancel()
+
+	opts := []oauth2.AuthCodeOption{}
+	if provider.PKCE {
+		opts = append(opts, oauth2.VerifierOption(stateInfo.Data.CodeVerifier))
+	}
+	exchanged, err := oauthConfig(provider, stateInfo).Exchange(ctx, code, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("ERROR | cannot exchange code with %s: %v", provider.Name, err)
+	}
+	return &models.OAuthToken{
+		AccessToken:  exchanged.AccessToken,
+		RefreshToken: exchanged.RefreshToken,
+		TokenType:    exchanged.TokenType,
+		Expiry:       exchanged.Expiry,
+	}, nil
+}
+
+func (c *CredentialOAuthHTTPRepository) RefreshToken(provider *models.OAuthProvider, credential *models.RequestExchangeCredential) (token *models.OAuthToken, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), models.MaxTimeoutContext)
+	defer cancel()
+
+	// without an access token the source goes straight to the token url, an empty refresh token from
+	// the provider is replaced by the one sent
+	source := oauthConfig(provider, credential).TokenSource(ctx, &oauth2.Token{RefreshToken: credential.Data.TokenRefresh})
+	refreshed, err := source.Token()
+	// only invalid_grant is about the refresh token of the user, unauthorized_client is about the client
+	var retrieveErr *oauth2.RetrieveError
+	if errors.As(err, &retrieveErr) && retrieveErr.ErrorCode == "invalid_grant" {
+		return nil, models.ErrOAuthRefreshRejected
+	}
+	if errors.As(err, &retrieveErr) && retrieveErr.ErrorCode == "unauthorized_client" {
+		return nil, models.ErrOAuthClientRejected
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ERROR | cannot refresh token with %s: %v", provider.Name, err)
+	}
+	return &models.OAuthToken{
+		AccessToken:  refreshed.AccessToken,
+		RefreshToken: refreshed.RefreshToken,
+		TokenType:    refreshed.TokenType,
+		Expiry:       refreshed.Expiry,
+	}, nil
+}
+
+func oauthConfig(provider *models.OAuthProvider, credential *models.RequestExchangeCredential) *oauth2.Config {
+	config := &oauth2.Config{
+		RedirectURL:  credential.Data.RedirectURL,
+		ClientID:     credential.Data.ClientID,
+		ClientSecret: credential.Data.ClientSecret,
+		Scopes:       credential.Data.Scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  provider.AuthURL,
+			TokenURL: provider.TokenURL,
+		},
+	}
+	switch provider.AuthStyle {
+	case models.OAuthAuthStyleParams:
+		config.Endpoint.AuthStyle = oauth2.AuthStyleInParams
+	case models.OAuthAuthStyleHeader:
+		config.Endpoint.AuthStyle = oauth2.AuthStyleInHeader
+	}
+	if provider.ScopeSeparator != "" {
+		config.Scopes = nil
+	}
+	return config
+}
@@ -0,0 +1,60 @@
+package httpclient
+
+import (
+	"fmt"
+	"minireipaz/pkg/config"
+	"minireipaz/pkg/domain/models"
+	"net/url"
+)
+
+type RunHTTPRepository struct {
+	databaseHTTPURL string
+	token           string
+	client          HTTPClient
+}
+
+func NewRunClientHTTP(client HTTPClient, clickhouseConfig config.ClickhouseConfig) *RunHTTPRepository {
+	return &RunHTTPRepository{
+		client:          client,
+		databaseHTTPURL: clickhouseConfig.GetClickhouseURI(),
+		token:           clickhouseConfig.GetClickhouseToken(),
+	}
+}
+
+// GetWorkflowRuns newest runs first, the keyset cursor is applied by the pipe
+func (r *RunHTTPRepository) GetWorkflowRuns(userID, workflowID *string, cursor *models.WorkflowCursor, limitCount uint64) (*models.InfoRuns, error) {
+	q := url.Values{}
+	q.Set("workflow_id", *workflowID)
+	q.Set("user_id", *userID)
+	q.Set("limit_count", fmt.Sprintf("%d", limitCount))
+	if cursor != nil {
+		q.Set("cursor_value", cursor.Value)
+		q.Set("cursor_id", cursor.ID)
+	}
+
+	var result *models.InfoRuns
+	err := queryPipe(r.client, r.databaseHTTPURL, r.token, "/workflow_runs.json", q, &result)
+	return result, err
+}
+
+func (r *RunHTTPRepository) GetRun(userID, runID *string) (*models.InfoRuns, error) {
+	q := url.Values{}
+	q.Set("run_id", *runID)
+	q.Set("user_id", *userID)
+	q.Set("limit_count", "1")
+
+	var result *models.InfoRuns
+	err := queryPipe(r.client, r.databaseHTTPURL, r.token, "/workflow_runs.json", q, &result)
+	return result, err
+}
+
+// GetRunSteps one row per node executed, ordered by start time
+func (r *RunHTTPRepository) GetRunSteps(userID, runID *string) (*models.InfoRunSteps, error) {
+	q := url.Values{}
+	q.Set("run_id", *runID)
+	q.Set("user_id", *userID)
+
+	var result *models.InfoRunSteps
+	err := queryPipe(r.client, r.databaseHTTPURL, r.token, "/run_steps.json", q, &result)
+	return result, err
+}
@@ -109,3 +109,54 @@ func (w *WorkflowHTTPRepository) GetAllWorkflows(userID *string, limitCount uint
 
 	return result, nil
 }
+
+// GetWorkflowsPage filters, sorting and keyset cursor are applied by the pipe, sort_by and order are already normalized
+func (w *WorkflowHTTPRepository) GetWorkflowsPage(query *models.WorkflowListQuery, cursor *models.WorkflowCursor, limitCount uint64) (*models.InfoWorkflow, error) {
+	q := url.Values{}
+	q.Set("user_id", query.UserID)
+	q.Set("limit_count", fmt.Sprintf("%d", limitCount))
+	q.Set("sort_by", query.SortBy)
+	q.Set("order", query.Order)
+	if query.Status != 0 {
+		q.Set("status", fmt.Sprintf("%d", query.Status))
+	}
+	if query.IsActive != 0 {
+		q.Set("is_active", fmt.Sprintf("%d", query.IsActive))
+	}
+	if query.DirectoryToSave != "" {
+		q.Set("directory_to_save", query.DirectoryToSave)
+	}
+	if query.Name != "" {
+		q.Set("name", query.Name)
+	}
+	if cursor != nil {
+		q.Set("cursor_value", cursor.Value)
+		q.Set("cursor_id", cursor.ID)
+	}
+	return w.queryWorkflowPipe("/all_workflow_data.json", q)
+}
+
+func (w *WorkflowHTTPRepository) GetWorkflowRevisions(userID, workflowID *string, limitCount uint64) (*models.InfoWorkflow, error) {
+	q := url.Values{}
+	q.Set("workflow_id", *workflowID)
+	q.Set("user_id", *userID)
+	q.Set("limit_count", fmt.Sprintf("%d", limitCount))
+	return w.queryWorkflowPipe("/workflow_revisions.json", q)
+}
+
+func (w *WorkflowHTTPRepository) GetWorkflowRevision(userID, workflowID *string, version uint32) (*models.InfoWorkflow, error) {
+	q := url.Values{}
+	q.Set("workflow_id", *workflowID)
+	q.Set("user_id", *userID)
+	q.Set("version", fmt.Sprintf("%d", version))
+	return w.queryWorkflowPipe("/workflow_revision_data.json", q)
+}
+
+// queryWorkflowPipe calls a pipe returning workflow rows
+func (w *WorkflowHTTPRepository) queryWorkflowPipe(pipe string, params url.Values) (*models.InfoWorkflow, error) {
+	var result *models.InfoWorkflow
+	if err := queryPipe(w.client, w.databaseHTTPURL, w.token, pipe, params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,55 @@
+package memoryclient
+
+import (
+	"context"
+	"minireipaz/pkg/domain/models"
+	"sync"
+)
+
+// StatusPublisher in process replacement of the redis channels, only reaches subscribers of the same instance
+type StatusPublisher struct {
+	subscribers map[string]map[chan models.StatusEvent]struct{}
+	mu          sync.Mutex
+}
+
+func NewStatusPublisher() *StatusPublisher {
+	return &StatusPublisher{
+		subscribers: make(map[string]map[chan models.StatusEvent]struct{}),
+	}
+}
+
+func (s *StatusPublisher) Publish(event *models.StatusEvent) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for events := range s.subscribers[event.UserID] {
+		select {
+		case events <- *event:
+		default:
+		}
+	}
+	return nil
+}
+
+func (s *StatusPublisher) Subscribe(ctx context.Context, userID *string) (<-chan models.StatusEvent, error) {
+	events := make(chan models.StatusEvent, models.StatusEventBuffer)
+
+	s.mu.Lock()
+	if s.subscribers[*userID] == nil {
+		s.subscribers[*userID] = make(map[chan models.StatusEvent]struct{})
+	}
+	s.subscribers[*userID][events] = struct{}{}
+	s.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.subscribers[*userID], events)
+		if len(s.subscribers[*userID]) == 0 {
+			delete(s.subscribers, *userID)
+		}
+		close(events)
+	}()
+	return events, nil
+}
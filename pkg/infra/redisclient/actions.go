@@ -40,8 +40,15 @@ func (a *ActionsRepository) ValidateActionGlobalUUID(uuid *string) (bool, error)
 	return exists, err
 }
 
-func (a *ActionsRepository) Create(newAction *models.RequestGoogleAction) (created bool, existed bool, err error) {
+// GetActionOwner empty when the action was never created
+func (a *ActionsRepository) GetActionOwner(actionID *string) (sub string, err error) {
+	return a.redisClient.HgetValue(ActionsGlobalAll, *actionID)
+}
+
+// Create message is optional, when given it is written to the outbox in the same transaction as the action
+func (a *ActionsRepository) Create(newAction *models.RequestGoogleAction, message *models.OutboxMessage) (created bool, existed bool, err error) {
 	ctx := context.Background()
+	var outboxCmd *redis.StringCmd
 
 	txf := func(tx *redis.Tx) error {
 		// used lock to check if exists
@@ -71,7 +78,15 @@ func (a *ActionsRepository) Create(newAction *models.RequestGoogleAction) (creat
 		}
 
 		// Proceed to set the action
-		_, err = tx.HSet(ctx, key, field, value).Result()
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.HSet(ctx, key, field, value)
+			if message == nil {
+				return nil
+			}
+			var err error
+			outboxCmd, err = addToOutbox(ctx, pipe, message)
+			return err
+		})
 		if err != nil {
 			// Clean up the lock if setting the action fails
 			tx.Del(ctx, lockKey)
@@ -82,6 +97,9 @@ func (a *ActionsRepository) Create(newAction *models.RequestGoogleAction) (creat
 	}
 
 	created, existed, err = a.redisClient.SetAction(ctx, newAction, txf)
+	if created && outboxCmd != nil {
+		message.ID = outboxCmd.Val()
+	}
 	return created, existed, err
 }
 
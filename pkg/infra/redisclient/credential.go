@@ -1,11 +1,14 @@
 package redisclient
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
 	"minireipaz/pkg/common"
 	"minireipaz/pkg/domain/models"
 	"time"
+
+	"github.com/go-redis/redis/v8"
 )
 
 type CredentialRedisRepository struct {
@@ -18,20 +21,42 @@ func NewCredentialRedisRepository(newRedisClient *RedisClient) *CredentialRedisR
 	}
 }
 
-func (c *CredentialRedisRepository) SaveTemporalAuthURLData(currentCredential *models.RequestCreateCredential) (inserted bool, err error) {
-	userKey := fmt.Sprintf("temp:code:%s", currentCredential.Data.RedirectURL)
+// SaveTemporalAuthURLData the credential with its client secret and code verifier stays here,
+// only the id of the state travels to the provider
+func (c *CredentialRedisRepository) SaveTemporalAuthURLData(stateID *string, stateInfo *models.RequestExchangeCredential, ttl time.Duration) (inserted bool, err error) {
+	stateKey := oauthStateKey(stateID)
+	stateJSON, err := json.Marshal(stateInfo)
+	if err != nil {
+		return false, err
+	}
 
 	for i := 1; i < models.MaxAttempts; i++ {
-		saved, err := c.redisClient.SetEx(userKey, currentCredential.Sub, 12*time.Hour)
-		if err == nil && saved {
-			return true, nil
+		inserted, err = c.redisClient.Client.SetNX(c.redisClient.Ctx, stateKey, stateJSON, ttl).Result()
+		if err == nil {
+			return inserted, nil
 		}
 
 		waitTime := common.RandomDuration(models.MaxRangeSleepDuration, models.MinRangeSleepDuration, i)
-		log.Printf("ERROR | Cannot check if exist lock for user %s, attempt %d: %v. Retrying in %v", currentCredential.Sub, i, err, waitTime)
+		log.Printf("ERROR | Cannot save oauth state for user %s, attempt %d: %v. Retrying in %v", stateInfo.Sub, i, err, waitTime)
 		time.Sleep(waitTime)
 	}
-	return inserted, fmt.Errorf("ERROR | Cannot insert user %s. More than 10 intents", currentCredential.Sub)
+	return false, fmt.Errorf("ERROR | Cannot save oauth state for user %s. More than 10 intents", stateInfo.Sub)
+}
+
+func (c *CredentialRedisRepository) PopTemporalAuthURLData(stateID *string) (stateInfo *models.RequestExchangeCredential, err error) {
+	stateJSON, err := c.redisClient.Client.GetDel(c.redisClient.Ctx, oauthStateKey(stateID)).Result()
+	if err == redis.Nil {
+		return nil, models.ErrOAuthStateInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	stateInfo = &models.RequestExchangeCredential{}
+	if err := json.Unmarshal([]byte(stateJSON), stateInfo); err != nil {
+		return nil, models.ErrOAuthStateInvalid
+	}
+	return stateInfo, nil
 }
 
 func (c *CredentialRedisRepository) AddLock(sub *string) (locked bool, err error) {
@@ -68,3 +93,7 @@ func (c *CredentialRedisRepository) RemoveLock(sub *string) (removedLock bool) {
 	}
 	return false
 }
+
+func oauthStateKey(stateID *string) string {
+	return fmt.Sprintf("oauth:state:%s", *stateID)
+}
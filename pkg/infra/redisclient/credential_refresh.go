@@ -0,0 +1,122 @@
+package redisclient
+
+import (
+	"context"
+	"fmt"
+	"minireipaz/pkg/domain/models"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	CredentialsRefreshDue    = "credentials:refresh:due"
+	CredentialsRefreshLeader = "credentials:refresh:leader"
+)
+
+type CredentialRefreshRepository struct {
+	redisClient *RedisClient
+}
+
+func NewCredentialRefreshRepository(redisClient *RedisClient) *CredentialRefreshRepository {
+	return &CredentialRefreshRepository{redisClient: redisClient}
+}
+
+// ScheduleRefresh a credential is scheduled once, saving it again moves its refresh time
+func (c *CredentialRefreshRepository) ScheduleRefresh(entry *models.CredentialRefreshEntry, at time.Time) (err error) {
+	return c.redisClient.Client.ZAdd(c.redisClient.Ctx, CredentialsRefreshDue, &redis.Z{Score: float64(at.Unix()), Member: refreshMember(entry)}).Err()
+}
+
+func (c *CredentialRefreshRepository) GetDueRefreshes(before time.Time, limit int64) (entries []models.CredentialRefreshEntry, err error) {
+	members, err := c.redisClient.ZRangeByScore(CredentialsRefreshDue, fmt.Sprintf("%d", before.Unix()), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	entries = make([]models.CredentialRefreshEntry, 0, len(members))
+	for _, member := range members {
+		sub, credentialID, found := strings.Cut(member, ":")
+		if !found {
+			c.redisClient.ZRem(CredentialsRefreshDue, member)
+			continue
+		}
+		entries = append(entries, models.CredentialRefreshEntry{Sub: sub, ID: credentialID})
+	}
+	return entries, nil
+}
+
+func (c *CredentialRefreshRepository) RemoveRefresh(entry *models.CredentialRefreshEntry) (err error) {
+	_, err = c.redisClient.ZRem(CredentialsRefreshDue, refreshMember(entry))
+	return err
+}
+
+func (c *CredentialRefreshRepository) LockRefresh(credentialID *string, ttl time.Duration) (locked bool, err error) {
+	return c.redisClient.AcquireLock(refreshLockKey(credentialID), "refresh", ttl)
+}
+
+func (c *CredentialRefreshRepository) UnlockRefresh(credentialID *string) (err error) {
+	_, err = c.redisClient.RemoveLock(refreshLockKey(credentialID))
+	return err
+}
+
+// AcquireLeadership takes the leader key when free or renews it when it is ours
+func (c *CredentialRefreshRepository) AcquireLeadership(instanceID string, ttl time.Duration) (leader bool, err error) {
+	ctx := context.Background()
+	txf := func(tx *redis.Tx) error {
+		current, err := tx.Get(ctx, CredentialsRefreshLeader).Result()
+		if err != nil && err != redis.Nil {
+			return err
+		}
+		if current != "" && current != instanceID {
+			leader = false
+			return nil
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, CredentialsRefreshLeader, instanceID, ttl)
+			return nil
+		})
+		leader = err == nil
+		return err
+	}
+
+	err = c.redisClient.ExecuteTransaction(ctx, []string{CredentialsRefreshLeader}, txf)
+	if err == redis.TxFailedErr {
+		return false, nil
+	}
+	return leader, err
+}
+
+func (c *CredentialRefreshRepository) ReleaseLeadership(instanceID string) (err error) {
+	ctx := context.Background()
+	txf := func(tx *redis.Tx) error {
+		current, err := tx.Get(ctx, CredentialsRefreshLeader).Result()
+		if err == redis.Nil || current != instanceID {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(ctx, CredentialsRefreshLeader)
+			return nil
+		})
+		return err
+	}
+
+	err = c.redisClient.ExecuteTransaction(ctx, []string{CredentialsRefreshLeader}, txf)
+	if err == redis.TxFailedErr {
+		return nil
+	}
+	return err
+}
+
+func refreshMember(entry *models.CredentialRefreshEntry) string {
+	return entry.Sub + ":" + entry.ID
+}
+
+func refreshLockKey(credentialID *string) string {
+	return fmt.Sprintf("lock:credential:refresh:%s", *credentialID)
+}
@@ -0,0 +1,87 @@
+package redisclient
+
+import (
+	"encoding/json"
+	"minireipaz/pkg/domain/models"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	DeadLetterStream = "deadletters"
+	// oldest entries are trimmed past this length, the broker topic keeps all of them
+	DeadLetterStreamMaxLen = 100000
+	deadLetterField        = "letter"
+)
+
+type DeadLetterRepository struct {
+	redisClient *RedisClient
+}
+
+func NewDeadLetterRepository(redisClient *RedisClient) *DeadLetterRepository {
+	return &DeadLetterRepository{redisClient: redisClient}
+}
+
+// Save the id of the letter is the id of the stream entry
+func (d *DeadLetterRepository) Save(letter *models.DeadLetter) (id string, err error) {
+	letterJSON, err := json.Marshal(letter)
+	if err != nil {
+		return "", err
+	}
+	return d.redisClient.Client.XAdd(d.redisClient.Ctx, &redis.XAddArgs{
+		Stream: DeadLetterStream,
+		MaxLen: DeadLetterStreamMaxLen,
+		Approx: true,
+		ID:     "*",
+		Values: map[string]interface{}{deadLetterField: letterJSON},
+	}).Result()
+}
+
+// GetAll newest first
+func (d *DeadLetterRepository) GetAll(limit int64) (letters []models.DeadLetter, err error) {
+	entries, err := d.redisClient.Client.XRevRangeN(d.redisClient.Ctx, DeadLetterStream, "+", "-", limit).Result()
+	if err != nil {
+		return nil, err
+	}
+	return decodeDeadLetters(entries), nil
+}
+
+func (d *DeadLetterRepository) Get(id *string) (letter *models.DeadLetter, err error) {
+	entries, err := d.redisClient.Client.XRange(d.redisClient.Ctx, DeadLetterStream, *id, *id).Result()
+	if err != nil {
+		return nil, err
+	}
+	letters := decodeDeadLetters(entries)
+	if len(letters) == 0 {
+		return nil, models.ErrDeadLetterNotFound
+	}
+	return &letters[0], nil
+}
+
+func (d *DeadLetterRepository) Remove(id *string) (err error) {
+	removed, err := d.redisClient.Client.XDel(d.redisClient.Ctx, DeadLetterStream, *id).Result()
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return models.ErrDeadLetterNotFound
+	}
+	return nil
+}
+
+func decodeDeadLetters(entries []redis.XMessage) []models.DeadLetter {
+	letters := make([]models.DeadLetter, 0, len(entries))
+	for _, entry := range entries {
+		value, ok := entry.Values[deadLetterField].(string)
+		if !ok {
+			continue
+		}
+		var letter models.DeadLetter
+		if err := json.Unmarshal([]byte(value), &letter); err != nil {
+			continue
+		}
+		letter.ID = entry.ID
+		letters = append(letters, letter)
+	}
+	return letters
+}
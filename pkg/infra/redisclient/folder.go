@@ -0,0 +1,94 @@
+package redisclient
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"minireipaz/pkg/common"
+	"minireipaz/pkg/domain/models"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+type FolderRepository struct {
+	redisClient *RedisClient
+}
+
+func NewFolderRepository(redisClient *RedisClient) *FolderRepository {
+	return &FolderRepository{redisClient: redisClient}
+}
+
+// Save folders without workflows are only kept here, value is the creation time
+func (f *FolderRepository) Save(userID, path *string) (created bool, err error) {
+	return f.redisClient.HSetNX(folderKey(userID), path, time.Now().UTC().Format(models.LayoutTimestamp))
+}
+
+func (f *FolderRepository) GetAll(userID *string) (paths []string, err error) {
+	entries, err := f.redisClient.HgetAll(folderKey(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	paths = make([]string, 0, len(entries))
+	for path := range entries {
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// Move the folder and its subfolders in one transaction, fails if destination already exists
+func (f *FolderRepository) Move(userID, from, to *string) (err error) {
+	ctx := context.Background()
+	key := folderKey(userID)
+
+	txf := func(tx *redis.Tx) error {
+		entries, err := tx.HGetAll(ctx, key).Result()
+		if err != nil {
+			return err
+		}
+		if _, exist := entries[*to]; exist {
+			return models.ErrFolderExist
+		}
+
+		moved := make(map[string]string)
+		for path, createdAt := range entries {
+			if path == *from || strings.HasPrefix(path, *from+models.FolderSeparator) {
+				moved[path] = createdAt
+			}
+		}
+		if len(moved) == 0 {
+			// folder only exists in the workflows, destination is saved so it stays in the tree
+			moved[*from] = time.Now().UTC().Format(models.LayoutTimestamp)
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			for path, createdAt := range moved {
+				pipe.HDel(ctx, key, path)
+				pipe.HSet(ctx, key, *to+strings.TrimPrefix(path, *from), createdAt)
+			}
+			return nil
+		})
+		return err
+	}
+
+	for i := 1; i < models.MaxAttempts; i++ {
+		err = f.redisClient.ExecuteTransaction(ctx, []string{key}, txf)
+		if err == nil || err == models.ErrFolderExist {
+			return err
+		}
+		if err == redis.TxFailedErr {
+			continue
+		}
+
+		waitTime := common.RandomDuration(models.MaxRangeSleepDuration, models.MinRangeSleepDuration, i)
+		log.Printf("ERROR | Cannot move folder %s to %s in %s, attempt %d: %v. Retrying in %v", *from, *to, key, i, err, waitTime)
+		time.Sleep(waitTime)
+	}
+	return fmt.Errorf("ERROR | cannot move folder %s after %d attempts: %v", *from, models.MaxAttempts, err)
+}
+
+func folderKey(userID *string) string {
+	return fmt.Sprintf("folders:%s", *userID)
+}
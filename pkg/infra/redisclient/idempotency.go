@@ -0,0 +1,64 @@
+package redisclient
+
+import (
+	"context"
+	"encoding/json"
+	"minireipaz/pkg/domain/models"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+type IdempotencyRepository struct {
+	redisClient *RedisClient
+}
+
+func NewIdempotencyRepository(redisClient *RedisClient) *IdempotencyRepository {
+	return &IdempotencyRepository{redisClient: redisClient}
+}
+
+func (i *IdempotencyRepository) Reserve(key string, record *models.IdempotencyRecord, ttl time.Duration) (reserved bool, stored *models.IdempotencyRecord, err error) {
+	ctx := context.Background()
+	recordJSON, err := json.Marshal(record)
+	if err != nil {
+		return false, nil, err
+	}
+
+	txf := func(tx *redis.Tx) error {
+		current, err := tx.Get(ctx, key).Result()
+		if err != nil && err != redis.Nil {
+			return err
+		}
+		if current != "" {
+			stored = &models.IdempotencyRecord{}
+			return json.Unmarshal([]byte(current), stored)
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, key, recordJSON, ttl)
+			return nil
+		})
+		reserved = err == nil
+		return err
+	}
+
+	err = i.redisClient.ExecuteTransaction(ctx, []string{key}, txf)
+	if err == redis.TxFailedErr {
+		// another request with the same key reserved it at the same time
+		return false, nil, models.ErrIdempotencyInProgress
+	}
+	return reserved, stored, err
+}
+
+func (i *IdempotencyRepository) Save(key string, record *models.IdempotencyRecord, ttl time.Duration) (err error) {
+	recordJSON, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	_, err = i.redisClient.SetEx(key, recordJSON, ttl)
+	return err
+}
+
+func (i *IdempotencyRepository) Remove(key string) (err error) {
+	return i.redisClient.Client.Del(i.redisClient.Ctx, key).Err()
+}
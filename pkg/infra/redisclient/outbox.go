@@ -0,0 +1,105 @@
+package redisclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"minireipaz/pkg/domain/models"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	// never trimmed, entries leave the stream only once they are published
+	OutboxStream = "outbox"
+	OutboxLeader = "outbox:leader"
+	outboxField  = "message"
+)
+
+type OutboxRepository struct {
+	redisClient *RedisClient
+}
+
+func NewOutboxRepository(redisClient *RedisClient) *OutboxRepository {
+	return &OutboxRepository{redisClient: redisClient}
+}
+
+func (o *OutboxRepository) GetPending(before time.Time, limit int64) (messages []models.OutboxMessage, err error) {
+	entries, err := o.redisClient.Client.XRangeN(o.redisClient.Ctx, OutboxStream, "-", strconv.FormatInt(before.UnixMilli(), 10), limit).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	messages = make([]models.OutboxMessage, 0, len(entries))
+	for _, entry := range entries {
+		value, ok := entry.Values[outboxField].(string)
+		if !ok {
+			continue
+		}
+		var message models.OutboxMessage
+		if err := json.Unmarshal([]byte(value), &message); err != nil {
+			continue
+		}
+		message.ID = entry.ID
+		messages = append(messages, message)
+	}
+	return messages, nil
+}
+
+func (o *OutboxRepository) IsPublished(dedupKey *string) (published bool, err error) {
+	exist, err := o.redisClient.Exists(outboxPublishedKey(dedupKey))
+	return exist == 1, err
+}
+
+func (o *OutboxRepository) Ack(message *models.OutboxMessage) (err error) {
+	ctx := context.Background()
+	_, err = o.redisClient.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, outboxPublishedKey(&message.DedupKey), message.ID, models.OutboxPublishedTTL)
+		pipe.XDel(ctx, OutboxStream, message.ID)
+		return nil
+	})
+	return err
+}
+
+func (o *OutboxRepository) AcquireLeadership(instanceID string, ttl time.Duration) (leader bool, err error) {
+	return o.redisClient.AcquireLeadership(OutboxLeader, instanceID, ttl)
+}
+
+func (o *OutboxRepository) ReleaseLeadership(instanceID string) (err error) {
+	return o.redisClient.ReleaseLeadership(OutboxLeader, instanceID)
+}
+
+// addToOutbox queues the message in the same MULTI as the state it belongs to,
+// the id of the entry is known once the transaction is executed
+func addToOutbox(ctx context.Context, pipe redis.Pipeliner, message *models.OutboxMessage) (*redis.StringCmd, error) {
+	messageJSON, err := json.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+	return pipe.XAdd(ctx, &redis.XAddArgs{
+		Stream: OutboxStream,
+		ID:     "*",
+		Values: map[string]interface{}{outboxField: messageJSON},
+	}), nil
+}
+
+// queueOutbox like addToOutbox, a nil message writes only the state
+func queueOutbox(ctx context.Context, pipe redis.Pipeliner, message *models.OutboxMessage) (*redis.StringCmd, error) {
+	if message == nil {
+		return nil, nil
+	}
+	return addToOutbox(ctx, pipe, message)
+}
+
+// setOutboxID called once the transaction that queued the message is executed
+func setOutboxID(message *models.OutboxMessage, outboxCmd *redis.StringCmd) {
+	if message != nil && outboxCmd != nil {
+		message.ID = outboxCmd.Val()
+	}
+}
+
+func outboxPublishedKey(dedupKey *string) string {
+	return fmt.Sprintf("outbox:published:%s", *dedupKey)
+}
@@ -64,6 +64,40 @@ func (r *RedisClient) Hget(key string, field string) error {
 	return r.Client.HGet(r.Ctx, key, field).Err()
 }
 
+// HgetValue empty value when the field not exist
+func (r *RedisClient) HgetValue(key string, field string) (string, error) {
+	result, err := r.Client.HGet(r.Ctx, key, field).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	return result, err
+}
+
+func (r *RedisClient) ScanKeys(pattern string) (keys []string, err error) {
+	iter := r.Client.Scan(r.Ctx, 0, pattern, 100).Iterator()
+	for iter.Next(r.Ctx) {
+		keys = append(keys, iter.Val())
+	}
+	return keys, iter.Err()
+}
+
+// ZRangeByScore members with score up to max, lowest first
+func (r *RedisClient) ZRangeByScore(key, max string, limit int64) ([]string, error) {
+	return r.Client.ZRangeByScore(r.Ctx, key, &redis.ZRangeBy{Min: "-inf", Max: max, Count: limit}).Result()
+}
+
+func (r *RedisClient) ZRem(key string, member string) (int64, error) {
+	return r.Client.ZRem(r.Ctx, key, member).Result()
+}
+
+func (r *RedisClient) Incr(key string) (int64, error) {
+	return r.Client.Incr(r.Ctx, key).Result()
+}
+
+func (r *RedisClient) HgetAll(key string) (map[string]string, error) {
+	return r.Client.HGetAll(r.Ctx, key).Result()
+}
+
 func (r *RedisClient) Hexists(key string, field string) (bool, error) {
 	return r.Client.HExists(r.Ctx, key, field).Result()
 }
@@ -83,13 +117,14 @@ func (r *RedisClient) Get(key string) (string, error) {
 	return result, nil
 }
 
-func (r *RedisClient) WatchWorkflow(workflow *models.Workflow, operation string) error {
+// WatchWorkflow message is optional, when given it is written to the outbox in the same transaction
+func (r *RedisClient) WatchWorkflow(workflow *models.Workflow, operation string, message *models.OutboxMessage) error {
 	return r.Client.Watch(r.Ctx, func(tx *redis.Tx) error {
-		return r.CheckAndModifyWorkflow(r.Ctx, tx, workflow, operation)
+		return r.CheckAndModifyWorkflow(r.Ctx, tx, workflow, operation, message)
 	})
 }
 
-func (r *RedisClient) CheckAndModifyWorkflow(ctx context.Context, tx *redis.Tx, workflow *models.Workflow, operation string) error {
+func (r *RedisClient) CheckAndModifyWorkflow(ctx context.Context, tx *redis.Tx, workflow *models.Workflow, operation string, message *models.OutboxMessage) error {
 	uuidExists, err := tx.HExists(ctx, "workflows:all", workflow.UUID).Result()
 	if err != nil {
 		log.Printf("ERROR | checking UUID existence: %v", err)
@@ -111,7 +146,7 @@ func (r *RedisClient) CheckAndModifyWorkflow(ctx context.Context, tx *redis.Tx,
 		// if nameExists {
 		// 	return fmt.Errorf(models.WorkflowNameExist)
 		// }
-		return r.setWorkflow(ctx, tx, workflow)
+		return r.setWorkflow(ctx, tx, workflow, message)
 	case operationREMOVE:
 		// if !uuidExists {
 		// 	return fmt.Errorf(models.UUIDNotExist)
@@ -121,18 +156,28 @@ func (r *RedisClient) CheckAndModifyWorkflow(ctx context.Context, tx *redis.Tx,
 		// }
 		return r.removeWorkflow(ctx, tx, workflow)
 	case operationUPDATE:
-		return r.setWorkflow(ctx, tx, nil)
+		return r.setWorkflow(ctx, tx, nil, message)
 	default:
 		return fmt.Errorf("unknown operation: %s", operation)
 	}
 }
 
-func (r *RedisClient) setWorkflow(ctx context.Context, tx *redis.Tx, workflow *models.Workflow) error {
+func (r *RedisClient) setWorkflow(ctx context.Context, tx *redis.Tx, workflow *models.Workflow, message *models.OutboxMessage) error {
+	var outboxCmd *redis.StringCmd
 	_, err := tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
 		pipe.HSet(ctx, fmt.Sprintf("users:%s", workflow.UserID), workflow.Name, workflow.UUID)
 		pipe.HSet(ctx, "workflows:all", workflow.UUID, workflow.UserID)
-		return nil
+		pipe.HSet(ctx, "workflows:state", workflow.UUID, uint8(workflow.IsActive))
+		if message == nil {
+			return nil
+		}
+		var err error
+		outboxCmd, err = addToOutbox(ctx, pipe, message)
+		return err
 	})
+	if err == nil && outboxCmd != nil {
+		message.ID = outboxCmd.Val()
+	}
 	return err
 }
 
@@ -149,21 +194,22 @@ func (r *RedisClient) removeWorkflow(ctx context.Context, tx *redis.Tx, workflow
 	_, err := tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
 		pipe.HDel(ctx, fmt.Sprintf("users:%s", workflow.UserID), workflow.Name)
 		pipe.HDel(ctx, "workflows:all", workflow.UUID)
+		pipe.HDel(ctx, "workflows:state", workflow.UUID)
 		return nil
 	})
 	return err
 }
 
-func (r *RedisClient) SetWorkflow(workflow *models.Workflow) error {
-	return r.WatchWorkflow(workflow, operationSET)
+func (r *RedisClient) SetWorkflow(workflow *models.Workflow, message *models.OutboxMessage) error {
+	return r.WatchWorkflow(workflow, operationSET, message)
 }
 
 func (r *RedisClient) UpdateWorkflow(workflow *models.Workflow) error {
-	return r.WatchWorkflow(workflow, operationUPDATE)
+	return r.WatchWorkflow(workflow, operationUPDATE, nil)
 }
 
 func (r *RedisClient) RemoveWorkflow(workflow *models.Workflow) error {
-	return r.WatchWorkflow(workflow, operationREMOVE)
+	return r.WatchWorkflow(workflow, operationREMOVE, nil)
 }
 
 func (r *RedisClient) WatchUser(user *models.SyncUserRequest, lockKey, userKey string, duration time.Duration) (inserted bool, lockExists bool, userExists bool, err error) {
@@ -267,6 +313,61 @@ func (r *RedisClient) RemoveLock(key string) (int64, error) {
 	return result, err
 }
 
+// AcquireLeadership takes the leader key when free or renews it when it is ours
+func (r *RedisClient) AcquireLeadership(leaderKey, instanceID string, ttl time.Duration) (leader bool, err error) {
+	ctx := context.Background()
+	txf := func(tx *redis.Tx) error {
+		current, err := tx.Get(ctx, leaderKey).Result()
+		if err != nil && err != redis.Nil {
+			return err
+		}
+		if current != "" && current != instanceID {
+			leader = false
+			return nil
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, leaderKey, instanceID, ttl)
+			return nil
+		})
+		leader = err == nil
+		return err
+	}
+
+	err = r.ExecuteTransaction(ctx, []string{leaderKey}, txf)
+	if err == redis.TxFailedErr {
+		// another instance took it at the same time
+		return false, nil
+	}
+	return leader, err
+}
+
+// ReleaseLeadership only when the leader key is still ours
+func (r *RedisClient) ReleaseLeadership(leaderKey, instanceID string) (err error) {
+	ctx := context.Background()
+	txf := func(tx *redis.Tx) error {
+		current, err := tx.Get(ctx, leaderKey).Result()
+		if err == redis.Nil || current != instanceID {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(ctx, leaderKey)
+			return nil
+		})
+		return err
+	}
+
+	err = r.ExecuteTransaction(ctx, []string{leaderKey}, txf)
+	if err == redis.TxFailedErr {
+		return nil
+	}
+	return err
+}
+
 func (r *RedisClient) Hdel(key string, field string) (int64, error) {
 	result, err := r.Client.HDel(r.Ctx, key, field).Result()
 	return result, err
@@ -0,0 +1,59 @@
+package redisclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"minireipaz/pkg/domain/models"
+
+	"github.com/go-redis/redis/v8"
+)
+
+type RunRepository struct {
+	redisClient *RedisClient
+}
+
+func NewRunRepository(redisClient *RedisClient) *RunRepository {
+	return &RunRepository{redisClient: redisClient}
+}
+
+// Save the command of the run is written to the outbox in the same transaction, a nil message writes only the run
+func (r *RunRepository) Save(run *models.Run, message *models.OutboxMessage) (err error) {
+	runJSON, err := json.Marshal(run)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	var outboxCmd *redis.StringCmd
+	_, err = r.redisClient.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, runKey(&run.RunID), runJSON, models.RunRecordTTL)
+		outboxCmd, err = queueOutbox(ctx, pipe, message)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	setOutboxID(message, outboxCmd)
+	return nil
+}
+
+func (r *RunRepository) Get(runID *string) (run *models.Run, err error) {
+	entry, err := r.redisClient.Get(runKey(runID))
+	if err != nil {
+		return nil, err
+	}
+	if entry == "" {
+		return nil, models.ErrRunNotFound
+	}
+
+	run = &models.Run{}
+	if err := json.Unmarshal([]byte(entry), run); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+func runKey(runID *string) string {
+	return fmt.Sprintf("runs:%s", *runID)
+}
@@ -0,0 +1,201 @@
+package redisclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"minireipaz/pkg/domain/models"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	SchedulesDue    = "schedules:due"
+	SchedulerLeader = "scheduler:leader"
+)
+
+type SchedulerRepository struct {
+	redisClient *RedisClient
+}
+
+func NewSchedulerRepository(redisClient *RedisClient) *SchedulerRepository {
+	return &SchedulerRepository{redisClient: redisClient}
+}
+
+// Save schedules of a workflow are kept in one hash, fire times in a sorted set shared by all
+func (s *SchedulerRepository) Save(schedule *models.Schedule) (err error) {
+	ctx := context.Background()
+	scheduleJSON, err := json.Marshal(schedule)
+	if err != nil {
+		return err
+	}
+	nextRunAt, err := time.Parse(models.LayoutTimestamp, schedule.NextRunAt)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.redisClient.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, scheduleKey(&schedule.WorkflowID), schedule.NodeID, scheduleJSON)
+		if schedule.Disabled {
+			pipe.ZRem(ctx, SchedulesDue, dueMember(schedule))
+			return nil
+		}
+		pipe.ZAdd(ctx, SchedulesDue, &redis.Z{Score: float64(nextRunAt.Unix()), Member: dueMember(schedule)})
+		return nil
+	})
+	return err
+}
+
+func (s *SchedulerRepository) Get(workflowID, nodeID *string) (schedule *models.Schedule, err error) {
+	entry, err := s.redisClient.HgetValue(scheduleKey(workflowID), *nodeID)
+	if err != nil {
+		return nil, err
+	}
+	if entry == "" {
+		return nil, models.ErrScheduleNotFound
+	}
+
+	schedule = &models.Schedule{}
+	if err := json.Unmarshal([]byte(entry), schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func (s *SchedulerRepository) GetByWorkflow(workflowID *string) (schedules []models.Schedule, err error) {
+	entries, err := s.redisClient.HgetAll(scheduleKey(workflowID))
+	if err != nil {
+		return nil, err
+	}
+
+	schedules = make([]models.Schedule, 0, len(entries))
+	for nodeID, entry := range entries {
+		var current models.Schedule
+		if err := json.Unmarshal([]byte(entry), &current); err != nil {
+			log.Printf("ERROR | Cannot decode schedule of node %s in workflow %s: %v", nodeID, *workflowID, err)
+			continue
+		}
+		schedules = append(schedules, current)
+	}
+	return schedules, nil
+}
+
+func (s *SchedulerRepository) Remove(workflowID, nodeID *string) (err error) {
+	ctx := context.Background()
+	_, err = s.redisClient.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HDel(ctx, scheduleKey(workflowID), *nodeID)
+		pipe.ZRem(ctx, SchedulesDue, *workflowID+":"+*nodeID)
+		return nil
+	})
+	return err
+}
+
+// RemoveByWorkflow every schedule of the workflow and its fire times
+func (s *SchedulerRepository) RemoveByWorkflow(workflowID *string) (err error) {
+	ctx := context.Background()
+	key := scheduleKey(workflowID)
+	nodeIDs, err := s.redisClient.Client.HKeys(ctx, key).Result()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.redisClient.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, nodeID := range nodeIDs {
+			pipe.ZRem(ctx, SchedulesDue, *workflowID+":"+nodeID)
+		}
+		pipe.Del(ctx, key)
+		return nil
+	})
+	return err
+}
+
+// GetDue oldest fire times first, members without schedule are removed on the way
+func (s *SchedulerRepository) GetDue(now time.Time, limit int64) (schedules []models.Schedule, err error) {
+	members, err := s.redisClient.ZRangeByScore(SchedulesDue, fmt.Sprintf("%d", now.Unix()), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules = make([]models.Schedule, 0, len(members))
+	for _, member := range members {
+		workflowID, nodeID, found := strings.Cut(member, ":")
+		if !found {
+			continue
+		}
+		schedule, err := s.Get(&workflowID, &nodeID)
+		if err == models.ErrScheduleNotFound {
+			s.redisClient.ZRem(SchedulesDue, member)
+			continue
+		}
+		if err != nil {
+			log.Printf("ERROR | Cannot get due schedule %s: %v", member, err)
+			continue
+		}
+		schedules = append(schedules, *schedule)
+	}
+	return schedules, nil
+}
+
+func (s *SchedulerRepository) Advance(schedule *models.Schedule, expectedNextRunAt string) (advanced bool, err error) {
+	ctx := context.Background()
+	key := scheduleKey(&schedule.WorkflowID)
+	scheduleJSON, err := json.Marshal(schedule)
+	if err != nil {
+		return false, err
+	}
+	nextRunAt, err := time.Parse(models.LayoutTimestamp, schedule.NextRunAt)
+	if err != nil {
+		return false, err
+	}
+
+	txf := func(tx *redis.Tx) error {
+		entry, err := tx.HGet(ctx, key, schedule.NodeID).Result()
+		if err == redis.Nil {
+			return models.ErrScheduleNotFound
+		}
+		if err != nil {
+			return err
+		}
+		var current models.Schedule
+		if err := json.Unmarshal([]byte(entry), &current); err != nil {
+			return err
+		}
+		// disabled meanwhile, it cannot go back to the due set
+		if current.NextRunAt != expectedNextRunAt || current.Disabled {
+			return redis.TxFailedErr
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.HSet(ctx, key, schedule.NodeID, scheduleJSON)
+			pipe.ZAdd(ctx, SchedulesDue, &redis.Z{Score: float64(nextRunAt.Unix()), Member: dueMember(schedule)})
+			return nil
+		})
+		return err
+	}
+
+	err = s.redisClient.ExecuteTransaction(ctx, []string{key}, txf)
+	// other instance fired it or the schedule was changed meanwhile
+	if err == redis.TxFailedErr || err == models.ErrScheduleNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (s *SchedulerRepository) AcquireLeadership(instanceID string, ttl time.Duration) (leader bool, err error) {
+	return s.redisClient.AcquireLeadership(SchedulerLeader, instanceID, ttl)
+}
+
+func (s *SchedulerRepository) ReleaseLeadership(instanceID string) (err error) {
+	return s.redisClient.ReleaseLeadership(SchedulerLeader, instanceID)
+}
+
+func scheduleKey(workflowID *string) string {
+	return fmt.Sprintf("schedules:%s", *workflowID)
+}
+
+func dueMember(schedule *models.Schedule) string {
+	return schedule.WorkflowID + ":" + schedule.NodeID
+}
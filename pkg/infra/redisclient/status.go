@@ -0,0 +1,68 @@
+package redisclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"minireipaz/pkg/domain/models"
+)
+
+type StatusRepository struct {
+	redisClient *RedisClient
+}
+
+func NewStatusRepository(redisClient *RedisClient) *StatusRepository {
+	return &StatusRepository{redisClient: redisClient}
+}
+
+// Publish executors publish to the same channel, one channel per user
+func (s *StatusRepository) Publish(event *models.StatusEvent) (err error) {
+	eventJSON, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return s.redisClient.Client.Publish(s.redisClient.Ctx, statusChannel(&event.UserID), eventJSON).Err()
+}
+
+func (s *StatusRepository) Subscribe(ctx context.Context, userID *string) (<-chan models.StatusEvent, error) {
+	pubsub := s.redisClient.Client.Subscribe(ctx, statusChannel(userID))
+	// first reply confirms the subscription, without it messages published meanwhile are lost
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, err
+	}
+
+	events := make(chan models.StatusEvent, models.StatusEventBuffer)
+	go func() {
+		defer close(events)
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case message, ok := <-messages:
+				if !ok {
+					return
+				}
+				var event models.StatusEvent
+				if err := json.Unmarshal([]byte(message.Payload), &event); err != nil {
+					log.Printf("ERROR | Cannot decode status event of user %s: %v", *userID, err)
+					continue
+				}
+				select {
+				case events <- event:
+				default:
+					log.Printf("WARN | Status subscriber of user %s is slow, event %s of %s dropped", *userID, event.Status, event.ID)
+				}
+			}
+		}
+	}()
+	return events, nil
+}
+
+func statusChannel(userID *string) string {
+	return fmt.Sprintf("status:%s", *userID)
+}
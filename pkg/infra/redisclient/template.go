@@ -0,0 +1,64 @@
+package redisclient
+
+import (
+	"encoding/json"
+	"log"
+	"minireipaz/pkg/domain/models"
+)
+
+const TemplatesPublished = "templates:published"
+
+type TemplateRepository struct {
+	redisClient *RedisClient
+}
+
+func NewTemplateRepository(redisClient *RedisClient) *TemplateRepository {
+	return &TemplateRepository{redisClient: redisClient}
+}
+
+func (t *TemplateRepository) Save(template *models.WorkflowTemplate) (saved bool, err error) {
+	templateJSON, err := json.Marshal(template)
+	if err != nil {
+		return false, err
+	}
+	return t.redisClient.Hset(TemplatesPublished, template.ID, templateJSON), nil
+}
+
+func (t *TemplateRepository) GetAll() (templates []models.WorkflowTemplate, err error) {
+	entries, err := t.redisClient.HgetAll(TemplatesPublished)
+	if err != nil {
+		return nil, err
+	}
+
+	templates = make([]models.WorkflowTemplate, 0, len(entries))
+	for templateID, entry := range entries {
+		var current models.WorkflowTemplate
+		if err := json.Unmarshal([]byte(entry), &current); err != nil {
+			log.Printf("ERROR | Cannot decode template %s: %v", templateID, err)
+			continue
+		}
+		templates = append(templates, current)
+	}
+	return templates, nil
+}
+
+func (t *TemplateRepository) Get(templateID *string) (template *models.WorkflowTemplate, err error) {
+	entry, err := t.redisClient.HgetValue(TemplatesPublished, *templateID)
+	if err != nil {
+		return nil, err
+	}
+	if entry == "" {
+		return nil, models.ErrTemplateNotFound
+	}
+
+	template = &models.WorkflowTemplate{}
+	if err := json.Unmarshal([]byte(entry), template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+func (t *TemplateRepository) Remove(templateID *string) (removed bool, err error) {
+	count, err := t.redisClient.Hdel(TemplatesPublished, *templateID)
+	return count > 0, err
+}
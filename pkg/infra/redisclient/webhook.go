@@ -0,0 +1,147 @@
+package redisclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"minireipaz/pkg/common"
+	"minireipaz/pkg/domain/models"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// WebhooksByToken every request to a hook is looked up by its token
+const WebhooksByToken = "webhooks:tokens"
+
+type WebhookRepository struct {
+	redisClient *RedisClient
+}
+
+func NewWebhookRepository(redisClient *RedisClient) *WebhookRepository {
+	return &WebhookRepository{redisClient: redisClient}
+}
+
+func (w *WebhookRepository) Save(webhook *models.Webhook) (err error) {
+	ctx := context.Background()
+	key := webhookKey(&webhook.WorkflowID)
+	webhookJSON, err := json.Marshal(webhook)
+	if err != nil {
+		return err
+	}
+
+	txf := func(tx *redis.Tx) error {
+		previousToken, err := tx.HGet(ctx, key, webhook.NodeID).Result()
+		if err != nil && err != redis.Nil {
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			// old url stops working as soon as the new one exists
+			if previousToken != "" {
+				pipe.HDel(ctx, WebhooksByToken, previousToken)
+			}
+			pipe.HSet(ctx, WebhooksByToken, webhook.Token, webhookJSON)
+			pipe.HSet(ctx, key, webhook.NodeID, webhook.Token)
+			return nil
+		})
+		return err
+	}
+
+	for i := 1; i < models.MaxAttempts; i++ {
+		err = w.redisClient.ExecuteTransaction(ctx, []string{key}, txf)
+		if err == nil {
+			return nil
+		}
+		if err == redis.TxFailedErr {
+			continue
+		}
+
+		waitTime := common.RandomDuration(models.MaxRangeSleepDuration, models.MinRangeSleepDuration, i)
+		log.Printf("ERROR | Cannot save webhook of node %s in workflow %s, attempt %d: %v. Retrying in %v", webhook.NodeID, webhook.WorkflowID, i, err, waitTime)
+		time.Sleep(waitTime)
+	}
+	return fmt.Errorf("ERROR | cannot save webhook after %d attempts: %v", models.MaxAttempts, err)
+}
+
+func (w *WebhookRepository) GetByToken(token *string) (webhook *models.Webhook, err error) {
+	entry, err := w.redisClient.HgetValue(WebhooksByToken, *token)
+	if err != nil {
+		return nil, err
+	}
+	if entry == "" {
+		return nil, models.ErrWebhookNotFound
+	}
+
+	webhook = &models.Webhook{}
+	if err := json.Unmarshal([]byte(entry), webhook); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+func (w *WebhookRepository) GetByWorkflow(workflowID *string) (webhooks []models.Webhook, err error) {
+	tokens, err := w.redisClient.HgetAll(webhookKey(workflowID))
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks = make([]models.Webhook, 0, len(tokens))
+	for nodeID, token := range tokens {
+		webhook, err := w.GetByToken(&token)
+		if err != nil {
+			log.Printf("ERROR | Cannot get webhook of node %s in workflow %s: %v", nodeID, *workflowID, err)
+			continue
+		}
+		webhooks = append(webhooks, *webhook)
+	}
+	return webhooks, nil
+}
+
+func (w *WebhookRepository) Remove(workflowID, nodeID *string) (err error) {
+	ctx := context.Background()
+	key := webhookKey(workflowID)
+	token, err := w.redisClient.HgetValue(key, *nodeID)
+	if err != nil {
+		return err
+	}
+	if token == "" {
+		return models.ErrWebhookNotFound
+	}
+
+	_, err = w.redisClient.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HDel(ctx, WebhooksByToken, token)
+		pipe.HDel(ctx, key, *nodeID)
+		return nil
+	})
+	return err
+}
+
+// RemoveByWorkflow every url of the workflow stops working
+func (w *WebhookRepository) RemoveByWorkflow(workflowID *string) (err error) {
+	ctx := context.Background()
+	key := webhookKey(workflowID)
+	tokens, err := w.redisClient.HgetAll(key)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.redisClient.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, token := range tokens {
+			pipe.HDel(ctx, WebhooksByToken, token)
+		}
+		pipe.Del(ctx, key)
+		return nil
+	})
+	return err
+}
+
+func (w *WebhookRepository) SaveNonce(token, nonce *string, ttl time.Duration) (saved bool, err error) {
+	key := fmt.Sprintf("webhooks:nonces:%s:%s", *token, *nonce)
+	return w.redisClient.Client.SetNX(w.redisClient.Ctx, key, "1", ttl).Result()
+}
+
+func webhookKey(workflowID *string) string {
+	return fmt.Sprintf("webhooks:%s", *workflowID)
+}
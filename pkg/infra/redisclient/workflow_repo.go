@@ -1,13 +1,18 @@
 package redisclient
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
+	"maps"
 	"minireipaz/pkg/common"
 	"minireipaz/pkg/domain/models"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/go-redis/redis/v8"
 	"github.com/google/uuid"
 )
 
@@ -22,8 +27,9 @@ func NewWorkflowRepository(redisClient *RedisClient) *WorkflowRepository {
 	return &WorkflowRepository{redisClient: redisClient}
 }
 
-func (r *WorkflowRepository) Create(workflow *models.Workflow) (created bool, exist bool) {
-	err := r.redisClient.SetWorkflow(workflow)
+// Create the create command is written to the outbox together with the index
+func (r *WorkflowRepository) Create(workflow *models.Workflow, message *models.OutboxMessage) (created bool, exist bool) {
+	err := r.redisClient.SetWorkflow(workflow, message)
 	if err != nil {
 		return false, true
 	}
@@ -55,7 +61,7 @@ func (r *WorkflowRepository) ValidateWorkflowGlobalUUID(uuid *string) bool {
 }
 
 func (r *WorkflowRepository) ValidateUserWorkflowUUID(userID, name *string) bool {
-	exist, err := r.redisClient.Hexists(fmt.Sprintf("users:%s", *userID), *name)
+	exist, err := r.NameExists(userID, name)
 	if err != nil {
 		log.Printf("ERROR | Redis HExists error: %v", err)
 		return true
@@ -63,6 +69,10 @@ func (r *WorkflowRepository) ValidateUserWorkflowUUID(userID, name *string) bool
 	return exist
 }
 
+func (r *WorkflowRepository) NameExists(userID, name *string) (exist bool, err error) {
+	return r.redisClient.Hexists(fmt.Sprintf("users:%s", *userID), *name)
+}
+
 func (r *WorkflowRepository) GetByUUID(id uuid.UUID) (*models.Workflow, error) {
 	workflowJSON, err := r.redisClient.Get(fmt.Sprintf("workflow:%d", id))
 	if err != nil {
@@ -112,3 +122,486 @@ func (r *WorkflowRepository) RemoveLock(key string) bool {
 	}
 	return false
 }
+
+// MoveToTrash drops the workflow from the users:<id> and workflows:all indexes and keeps it in trash:<id>,
+// the delete command is written to the outbox in the same transaction
+func (r *WorkflowRepository) MoveToTrash(trashed *models.TrashedWorkflow, message *models.OutboxMessage) (moved bool, err error) {
+	ctx := context.Background()
+	userKey := fmt.Sprintf("users:%s", trashed.UserID)
+	trashKey := fmt.Sprintf("trash:%s", trashed.UserID)
+	trashedJSON, err := json.Marshal(trashed)
+	if err != nil {
+		return false, err
+	}
+
+	var outboxCmd *redis.StringCmd
+	txf := func(tx *redis.Tx) error {
+		owner, err := tx.HGet(ctx, "workflows:all", trashed.UUID).Result()
+		if err != nil && err != redis.Nil {
+			return err
+		}
+		if owner != trashed.UserID {
+			return models.ErrUUIDInvalid
+		}
+
+		// name index can point to another workflow with the same name
+		indexedUUID, err := tx.HGet(ctx, userKey, trashed.Name).Result()
+		if err != nil && err != redis.Nil {
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			if indexedUUID == trashed.UUID {
+				pipe.HDel(ctx, userKey, trashed.Name)
+			}
+			pipe.HDel(ctx, "workflows:all", trashed.UUID)
+			pipe.HSet(ctx, trashKey, trashed.UUID, trashedJSON)
+			outboxCmd, err = queueOutbox(ctx, pipe, message)
+			return err
+		})
+		return err
+	}
+
+	err = r.redisClient.ExecuteTransaction(ctx, []string{"workflows:all", userKey, trashKey}, txf)
+	if err != nil {
+		log.Printf("ERROR | Cannot move workflow %s to trash: %v", trashed.UUID, err)
+		return false, err
+	}
+	setOutboxID(message, outboxCmd)
+	return true, nil
+}
+
+func (r *WorkflowRepository) GetTrash(userID *string) (trashed []models.TrashedWorkflow, err error) {
+	entries, err := r.redisClient.HgetAll(fmt.Sprintf("trash:%s", *userID))
+	if err != nil {
+		return nil, err
+	}
+
+	trashed = make([]models.TrashedWorkflow, 0, len(entries))
+	for workflowID, entry := range entries {
+		var current models.TrashedWorkflow
+		if err := json.Unmarshal([]byte(entry), &current); err != nil {
+			log.Printf("ERROR | Cannot decode trash entry %s for user %s: %v", workflowID, *userID, err)
+			continue
+		}
+		trashed = append(trashed, current)
+	}
+	return trashed, nil
+}
+
+// RestoreFromTrash puts back the index entries, fails when the name was taken in the meantime,
+// the restore command is written to the outbox in the same transaction
+func (r *WorkflowRepository) RestoreFromTrash(userID, workflowID *string, message *models.OutboxMessage) (restored *models.TrashedWorkflow, err error) {
+	ctx := context.Background()
+	userKey := fmt.Sprintf("users:%s", *userID)
+	trashKey := fmt.Sprintf("trash:%s", *userID)
+
+	var outboxCmd *redis.StringCmd
+	txf := func(tx *redis.Tx) error {
+		entry, err := tx.HGet(ctx, trashKey, *workflowID).Result()
+		if err == redis.Nil {
+			return models.ErrWorkflowNotInTrash
+		}
+		if err != nil {
+			return err
+		}
+
+		var current models.TrashedWorkflow
+		if err := json.Unmarshal([]byte(entry), &current); err != nil {
+			return err
+		}
+
+		nameExists, err := tx.HExists(ctx, userKey, current.Name).Result()
+		if err != nil {
+			return err
+		}
+		if nameExists {
+			return models.ErrWorkflowNameExist
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.HSet(ctx, userKey, current.Name, current.UUID)
+			pipe.HSet(ctx, "workflows:all", current.UUID, current.UserID)
+			pipe.HDel(ctx, trashKey, current.UUID)
+			outboxCmd, err = queueOutbox(ctx, pipe, message)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		restored = &current
+		return nil
+	}
+
+	err = r.redisClient.ExecuteTransaction(ctx, []string{"workflows:all", userKey, trashKey}, txf)
+	if err != nil {
+		return nil, err
+	}
+	setOutboxID(message, outboxCmd)
+	return restored, nil
+}
+
+func (r *WorkflowRepository) GetTrashUsers() (userIDs []string, err error) {
+	keys, err := r.redisClient.ScanKeys("trash:*")
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs = make([]string, 0, len(keys))
+	for _, key := range keys {
+		userIDs = append(userIDs, strings.TrimPrefix(key, "trash:"))
+	}
+	return userIDs, nil
+}
+
+// RemoveFromTrash the purge command is only written when the entry was still in the trash
+func (r *WorkflowRepository) RemoveFromTrash(userID, workflowID *string, message *models.OutboxMessage) (removed bool, err error) {
+	ctx := context.Background()
+	trashKey := fmt.Sprintf("trash:%s", *userID)
+
+	var outboxCmd *redis.StringCmd
+	txf := func(tx *redis.Tx) error {
+		exists, err := tx.HExists(ctx, trashKey, *workflowID).Result()
+		if err != nil || !exists {
+			removed = false
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.HDel(ctx, trashKey, *workflowID)
+			pipe.HDel(ctx, "workflows:state", *workflowID)
+			outboxCmd, err = queueOutbox(ctx, pipe, message)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		removed = true
+		return nil
+	}
+
+	err = r.redisClient.ExecuteTransaction(ctx, []string{trashKey}, txf)
+	if err != nil {
+		return false, err
+	}
+	if removed {
+		setOutboxID(message, outboxCmd)
+	}
+	return removed, nil
+}
+
+// NextVersion gives the first revision number of a new workflow
+func (r *WorkflowRepository) NextVersion(workflowID *string) (version uint32, err error) {
+	key := fmt.Sprintf("workflow:version:%s", *workflowID)
+	for i := 1; i < models.MaxAttempts; i++ {
+		current, err := r.redisClient.Incr(key)
+		if err == nil {
+			return uint32(current), nil
+		}
+
+		waitTime := common.RandomDuration(models.MaxRangeSleepDuration, models.MinRangeSleepDuration, i)
+		log.Printf("ERROR | Cannot connect to redis for key %s, attempt %d: %v. Retrying in %v", key, i, err, waitTime)
+		time.Sleep(waitTime)
+	}
+	return 0, fmt.Errorf("ERROR | Cannot get next version for key %s. More than 10 intents", key)
+}
+
+// GetVersion current version of the workflow, ClickHouse only sees it once the update command is consumed
+func (r *WorkflowRepository) GetVersion(workflowID *string) (version uint32, err error) {
+	key := fmt.Sprintf("workflow:version:%s", *workflowID)
+	current, err := r.redisClient.Get(key)
+	if err != nil || current == "" {
+		return 0, err
+	}
+	parsed, err := strconv.ParseUint(current, 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	return uint32(parsed), nil
+}
+
+// CompareAndIncrVersion moves to the next version only when the current one is the expected,
+// models.AnyVersion is the only unconditional write, 0 matches workflows without version yet
+func (r *WorkflowRepository) CompareAndIncrVersion(workflowID *string, expected uint32) (version uint32, matched bool, err error) {
+	ctx := context.Background()
+	key := fmt.Sprintf("workflow:version:%s", *workflowID)
+
+	txf := func(tx *redis.Tx) error {
+		current, err := tx.Get(ctx, key).Uint64()
+		if err != nil && err != redis.Nil {
+			return err
+		}
+		if expected != models.AnyVersion && uint64(expected) != current {
+			version, matched = uint32(current), false
+			return nil
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, key, current+1, 0)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		version, matched = uint32(current+1), true
+		return nil
+	}
+
+	for i := 1; i < models.MaxAttempts; i++ {
+		err = r.redisClient.ExecuteTransaction(ctx, []string{key}, txf)
+		if err == nil {
+			return version, matched, nil
+		}
+		// another writer changed the version, next attempt will see it
+		if err == redis.TxFailedErr {
+			continue
+		}
+
+		waitTime := common.RandomDuration(models.MaxRangeSleepDuration, models.MinRangeSleepDuration, i)
+		log.Printf("ERROR | Cannot connect to redis for key %s, attempt %d: %v. Retrying in %v", key, i, err, waitTime)
+		time.Sleep(waitTime)
+	}
+	return 0, false, fmt.Errorf("ERROR | Cannot compare version for key %s. More than 10 intents", key)
+}
+
+// RevertVersion goes back one version in case the update was not published
+func (r *WorkflowRepository) RevertVersion(workflowID *string, version uint32) (reverted bool) {
+	ctx := context.Background()
+	key := fmt.Sprintf("workflow:version:%s", *workflowID)
+
+	txf := func(tx *redis.Tx) error {
+		current, err := tx.Get(ctx, key).Uint64()
+		if err != nil {
+			return err
+		}
+		// someone else already moved forward
+		if current != uint64(version) {
+			return nil
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, key, current-1, 0)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		reverted = true
+		return nil
+	}
+
+	err := r.redisClient.ExecuteTransaction(ctx, []string{key}, txf)
+	if err != nil {
+		log.Printf("ERROR | Cannot revert version %d for key %s: %v", version, key, err)
+		return false
+	}
+	return reverted
+}
+
+// RenameInIndex moves the name index entry to the current name of the workflow,
+// fails when the new name belongs to another workflow of the user.
+// The update command is written to the outbox in the same transaction, renamed or not
+func (r *WorkflowRepository) RenameInIndex(workflow *models.Workflow, message *models.OutboxMessage) (previousName string, err error) {
+	ctx := context.Background()
+	userKey := fmt.Sprintf("users:%s", workflow.UserID)
+
+	var outboxCmd *redis.StringCmd
+	txf := func(tx *redis.Tx) error {
+		owner, err := tx.HGet(ctx, "workflows:all", workflow.UUID).Result()
+		if err != nil && err != redis.Nil {
+			return err
+		}
+		if owner != workflow.UserID {
+			return models.ErrWorkflowNotFound
+		}
+
+		names, err := tx.HGetAll(ctx, userKey).Result()
+		if err != nil {
+			return err
+		}
+		if owner, exist := names[workflow.Name]; exist && owner != workflow.UUID {
+			return models.ErrWorkflowNameExist
+		}
+
+		previousName = ""
+		staleNames := []string{}
+		for name, workflowID := range names {
+			if workflowID == workflow.UUID && name != workflow.Name {
+				staleNames = append(staleNames, name)
+				previousName = name
+			}
+		}
+		if len(staleNames) == 0 && names[workflow.Name] == workflow.UUID && message == nil {
+			return nil
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			if len(staleNames) > 0 {
+				pipe.HDel(ctx, userKey, staleNames...)
+			}
+			pipe.HSet(ctx, userKey, workflow.Name, workflow.UUID)
+			outboxCmd, err = queueOutbox(ctx, pipe, message)
+			return err
+		})
+		return err
+	}
+
+	for i := 1; i < models.MaxAttempts; i++ {
+		err = r.redisClient.ExecuteTransaction(ctx, []string{userKey}, txf)
+		if err == nil {
+			setOutboxID(message, outboxCmd)
+			return previousName, nil
+		}
+		if err == models.ErrWorkflowNameExist || err == models.ErrWorkflowNotFound {
+			return previousName, err
+		}
+		// another writer changed the index, next attempt will see it
+		if err == redis.TxFailedErr {
+			continue
+		}
+
+		waitTime := common.RandomDuration(models.MaxRangeSleepDuration, models.MinRangeSleepDuration, i)
+		log.Printf("ERROR | Cannot rename workflow %s in %s, attempt %d: %v. Retrying in %v", workflow.UUID, userKey, i, err, waitTime)
+		time.Sleep(waitTime)
+	}
+	return "", fmt.Errorf("ERROR | Cannot rename workflow %s in %s. More than 10 intents", workflow.UUID, userKey)
+}
+
+// GetUserIndex name to uuid of all workflows of the user, without the dummy field
+func (r *WorkflowRepository) GetUserIndex(userID *string) (names map[string]string, err error) {
+	names, err = r.redisClient.HgetAll(fmt.Sprintf("users:%s", *userID))
+	if err != nil {
+		return nil, err
+	}
+	delete(names, EmptyValue)
+	return names, nil
+}
+
+// GetWorkflowsIndex uuid to owner of every workflow
+func (r *WorkflowRepository) GetWorkflowsIndex() (owners map[string]string, err error) {
+	return r.redisClient.HgetAll("workflows:all")
+}
+
+func (r *WorkflowRepository) GetIndexedUsers() (userIDs []string, err error) {
+	keys, err := r.redisClient.ScanKeys("users:*")
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs = make([]string, 0, len(keys))
+	for _, key := range keys {
+		userIDs = append(userIDs, strings.TrimPrefix(key, "users:"))
+	}
+	return userIDs, nil
+}
+
+// ApplyUserIndex writes the changes only if the name index still is the snapshot used to compute them
+func (r *WorkflowRepository) ApplyUserIndex(change *models.UserIndexChange) (err error) {
+	ctx := context.Background()
+	userKey := fmt.Sprintf("users:%s", change.UserID)
+
+	txf := func(tx *redis.Tx) error {
+		names, err := tx.HGetAll(ctx, userKey).Result()
+		if err != nil {
+			return err
+		}
+		delete(names, EmptyValue)
+		if !maps.Equal(names, change.Snapshot) {
+			return models.ErrIndexChanged
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			if len(change.RemoveNames) > 0 {
+				pipe.HDel(ctx, userKey, change.RemoveNames...)
+			}
+			for name, workflowID := range change.SetNames {
+				pipe.HSet(ctx, userKey, name, workflowID)
+			}
+			if len(change.RemoveWorkflows) > 0 {
+				pipe.HDel(ctx, "workflows:all", change.RemoveWorkflows...)
+			}
+			for _, workflowID := range change.SetWorkflows {
+				pipe.HSet(ctx, "workflows:all", workflowID, change.UserID)
+			}
+			return nil
+		})
+		return err
+	}
+
+	err = r.redisClient.ExecuteTransaction(ctx, []string{userKey}, txf)
+	if err == redis.TxFailedErr {
+		return models.ErrIndexChanged
+	}
+	return err
+}
+
+// TrackMissing keeps since when every uuid of the user is missing in database, the ones found again are forgotten
+func (r *WorkflowRepository) TrackMissing(userID *string, workflowIDs []string, now time.Time) (since map[string]time.Time, err error) {
+	ctx := context.Background()
+	missingKey := fmt.Sprintf("index:missing:%s", *userID)
+
+	txf := func(tx *redis.Tx) error {
+		stored, err := tx.HGetAll(ctx, missingKey).Result()
+		if err != nil {
+			return err
+		}
+		since = make(map[string]time.Time, len(workflowIDs))
+		fields := make(map[string]interface{}, len(workflowIDs))
+		for _, workflowID := range workflowIDs {
+			first := now
+			if unix, err := strconv.ParseInt(stored[workflowID], 10, 64); err == nil {
+				first = time.Unix(unix, 0).UTC()
+			}
+			since[workflowID] = first
+			fields[workflowID] = first.Unix()
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(ctx, missingKey)
+			if len(fields) > 0 {
+				pipe.HSet(ctx, missingKey, fields)
+			}
+			return nil
+		})
+		return err
+	}
+
+	err = r.redisClient.ExecuteTransaction(ctx, []string{missingKey}, txf)
+	if err != nil {
+		return nil, err
+	}
+	return since, nil
+}
+
+// GetLifecycle state written on create and by every transition, workflows created before it was
+// stored have no entry and are returned as active like they always were
+func (r *WorkflowRepository) GetLifecycle(workflowID *string) (state models.IsActive, err error) {
+	value, err := r.redisClient.HgetValue("workflows:state", *workflowID)
+	if err != nil {
+		return 0, err
+	}
+	if value == "" {
+		return models.Active, nil
+	}
+	parsed, err := strconv.ParseUint(value, 10, 8)
+	if err != nil {
+		return 0, fmt.Errorf("invalid state %q for workflow %s: %w", value, *workflowID, err)
+	}
+	return models.IsActive(parsed), nil
+}
+
+// SetLifecycle the transition command is written to the outbox in the same transaction
+func (r *WorkflowRepository) SetLifecycle(workflowID *string, state models.IsActive, message *models.OutboxMessage) (err error) {
+	ctx := context.Background()
+	var outboxCmd *redis.StringCmd
+	_, err = r.redisClient.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, "workflows:state", *workflowID, uint8(state))
+		outboxCmd, err = queueOutbox(ctx, pipe, message)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	setOutboxID(message, outboxCmd)
+	return nil
+}
@@ -0,0 +1,109 @@
+package secretclient
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"log"
+	"minireipaz/pkg/common"
+	"minireipaz/pkg/config"
+	"minireipaz/pkg/domain/models"
+	"strings"
+)
+
+const ephemeralKeyID = "ephemeral"
+
+// LocalKeyProvider key encryption keys read from the environment, meant for development and tests,
+// a kms can take its place behind repos.SecretKeyProvider
+type LocalKeyProvider struct {
+	keys         map[string][]byte
+	currentKeyID string
+}
+
+// NewLocalKeyProvider the instance does not start without keys, what another instance or a restart
+// cannot decrypt must not reach kafka or clickhouse
+func NewLocalKeyProvider(secretsConfig config.SecretsConfig) *LocalKeyProvider {
+	if strings.TrimSpace(secretsConfig.GetCredentialKeys()) == "" && secretsConfig.GetEphemeralCredentialKey() {
+		log.Printf("WARN | CREDENTIAL_KEYS_EPHEMERAL set, credential secrets are encrypted with a key that only lives in this instance")
+		provider, err := NewEphemeralKeyProvider()
+		if err != nil {
+			log.Panicf("ERROR | Cannot generate ephemeral credential key: %v", err)
+		}
+		return provider
+	}
+
+	provider, err := ParseLocalKeys(secretsConfig.GetCredentialKeys(), secretsConfig.GetCredentialKeyID())
+	if err != nil {
+		log.Panicf("ERROR | Cannot load credential keys from CREDENTIAL_KEYS: %v", err)
+	}
+	return provider
+}
+
+// NewEphemeralKeyProvider what it encrypts cannot be read after a restart
+func NewEphemeralKeyProvider() (*LocalKeyProvider, error) {
+	key := make([]byte, models.SecretDataKeyBytes)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return &LocalKeyProvider{keys: map[string][]byte{ephemeralKeyID: key}, currentKeyID: ephemeralKeyID}, nil
+}
+
+// OAuthStateSecret a state signed by one instance is verified by another one, a secret of
+// its own per instance would reject it
+func OAuthStateSecret(secretsConfig config.SecretsConfig) []byte {
+	secret := secretsConfig.GetOAuthStateSecret()
+	if secret == "" {
+		log.Panicf("ERROR | OAUTH_STATE_SECRET not set, oauth states cannot be signed")
+	}
+	return []byte(secret)
+}
+
+// ParseLocalKeys keys as id:base64 separated by commas, at least one is needed
+func ParseLocalKeys(keys, currentKeyID string) (*LocalKeyProvider, error) {
+	provider := &LocalKeyProvider{keys: map[string][]byte{}, currentKeyID: currentKeyID}
+	if strings.TrimSpace(keys) == "" {
+		return nil, models.ErrSecretKeysMissing
+	}
+
+	for _, entry := range strings.Split(keys, ",") {
+		keyID, encoded, found := strings.Cut(strings.TrimSpace(entry), ":")
+		if !found || keyID == "" {
+			return nil, models.ErrSecretKeyInvalid
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || (len(key) != 16 && len(key) != 24 && len(key) != 32) {
+			return nil, models.ErrSecretKeyInvalid
+		}
+		provider.keys[keyID] = key
+		if provider.currentKeyID == "" {
+			provider.currentKeyID = keyID
+		}
+	}
+	if _, ok := provider.keys[provider.currentKeyID]; !ok {
+		return nil, models.ErrSecretKeyUnknown
+	}
+	return provider, nil
+}
+
+func (l *LocalKeyProvider) CurrentKeyID() string {
+	return l.currentKeyID
+}
+
+func (l *LocalKeyProvider) WrapKey(keyID string, dataKey []byte) (wrapped []byte, err error) {
+	key, ok := l.keys[keyID]
+	if !ok {
+		return nil, models.ErrSecretKeyUnknown
+	}
+	return common.SealAESGCM(key, dataKey)
+}
+
+func (l *LocalKeyProvider) UnwrapKey(keyID string, wrapped []byte) (dataKey []byte, err error) {
+	key, ok := l.keys[keyID]
+	if !ok {
+		return nil, models.ErrSecretKeyUnknown
+	}
+	dataKey, err = common.OpenAESGCM(key, wrapped)
+	if err != nil {
+		return nil, models.ErrSecretMalformed
+	}
+	return dataKey, nil
+}
@@ -1,10 +1,12 @@
 package controllers
 
 import (
+	"errors"
 	"fmt"
 	"minireipaz/pkg/domain/models"
 	"minireipaz/pkg/domain/repos"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -86,3 +88,30 @@ func (a *ActionsController) CreateActionsNotion(ctx *gin.Context) {
 		Data:   *actionID,
 	})
 }
+
+func (a *ActionsController) GetActionResult(ctx *gin.Context) {
+	userID := ctx.Param("iduser")
+	actionID := ctx.Param("idaction")
+	query := ctx.MustGet(models.ActionResultKey).(models.ActionResultQuery)
+	result, err := a.actionsService.GetActionResult(ctx.Request.Context(), &userID, &actionID, time.Duration(query.Wait)*time.Second)
+	if errors.Is(err, models.ErrActionNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error":  models.ActionNotFound,
+			"status": http.StatusNotFound,
+		})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":  err.Error(),
+			"status": http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"error":  "",
+		"status": http.StatusOK,
+		"action": result,
+	})
+}
@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"errors"
 	"log"
 	"minireipaz/pkg/domain/models"
 	"minireipaz/pkg/domain/repos"
@@ -14,10 +15,12 @@ type CredentialController struct {
 	credentialService repos.CredentialService
 	authService       repos.AuthService
 	workflowService   repos.WorkflowService
+	deadLetterService repos.DeadLetterService
+	refreshService    repos.CredentialRefreshService
 }
 
-func NewCredentialController(credServ repos.CredentialService, authService repos.AuthService, workflowServ repos.WorkflowService) *CredentialController {
-	return &CredentialController{credentialService: credServ, authService: authService, workflowService: workflowServ}
+func NewCredentialController(credServ repos.CredentialService, authService repos.AuthService, workflowServ repos.WorkflowService, deadLetterServ repos.DeadLetterService, refreshServ repos.CredentialRefreshService) *CredentialController {
+	return &CredentialController{credentialService: credServ, authService: authService, workflowService: workflowServ, deadLetterService: deadLetterServ, refreshService: refreshServ}
 }
 
 func (c *CredentialController) CreateCredential(ctx *gin.Context) {
@@ -37,12 +40,20 @@ func (c *CredentialController) CreateCredential(ctx *gin.Context) {
 	})
 }
 
-func (c *CredentialController) ExchangeGoogleCode(ctx *gin.Context) {
+func (c *CredentialController) ExchangeOAuthCode(ctx *gin.Context) {
 	currentCredential := ctx.MustGet(models.CredentialExchangeContextKey).(models.RequestExchangeCredential)
-	// this token and refresh expire in 1hr
+	// access tokens of most providers expire in 1hr
 	// stateinfo all returned because dont know what values are necessary in this controller
 	// will lock workflow
-	token, tokenRefresh, _, stateInfo, err := c.credentialService.ExchangeGoogleCredential(&currentCredential)
+	token, tokenRefresh, _, stateInfo, err := c.credentialService.ExchangeOAuthCredential(&currentCredential)
+	// the frontend starts the consent again
+	if errors.Is(err, models.ErrOAuthStateInvalid) || errors.Is(err, models.ErrOAuthVerifierMissing) {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":  models.OAuthStateInvalid,
+			"status": http.StatusBadRequest,
+		})
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"error":  models.CredNameNotGenerate,
@@ -61,14 +72,18 @@ func (c *CredentialController) ExchangeGoogleCode(ctx *gin.Context) {
 			if !exist {
 				log.Printf("ERROR | workflow not found for sub: %s and workflowid: %s", currentCredential.Sub, currentCredential.WorkflowID)
 				done <- true // goroutine ended
+				return
 			}
 			workflow = c.credentialService.TransformWorkflow(&currentCredential, workflow)
 			// block workflow key with retries
 			// max retries 10
-			updated, _ := c.workflowService.UpdateWorkflow(workflow)
+			updated, _, err := c.workflowService.UpdateWorkflow(workflow)
 			if !updated {
-				log.Printf("ERROR | failed to update workflow: %s", workflow.UUID)
-				// TODO: dead letter
+				log.Printf("ERROR | failed to update workflow: %s %v, added to dead letter", workflow.UUID, err)
+				// controllers can be built without dead letters, the failure is only logged then
+				if c.deadLetterService != nil {
+					c.deadLetterService.Add(models.DeadLetterOriginWorkflowUpdate, workflow, err, models.DeadLetterRetriedAttempts)
+				}
 			}
 			done <- true // goroutine ended
 		}()
@@ -120,3 +135,57 @@ func (c *CredentialController) CreateTokenCredential(ctx *gin.Context) {
 		"id":     *transformedCredentialID,
 	})
 }
+
+// RefreshCredential the access token is only refreshed when it is about to expire, the current one is returned otherwise
+func (c *CredentialController) RefreshCredential(ctx *gin.Context) {
+	userID := ctx.Param("iduser")
+	credentialID := ctx.Param("idcredential")
+	credential, refreshed, err := c.refreshService.RefreshCredential(&userID, &credentialID)
+	switch {
+	case errors.Is(err, models.ErrCredentialNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error":  models.CredentialNotFound,
+			"status": http.StatusNotFound,
+		})
+		return
+	case errors.Is(err, models.ErrOAuthRefreshUnsupported), errors.Is(err, models.ErrOAuthProviderUnknown):
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":  models.OAuthRefreshUnsupported,
+			"status": http.StatusBadRequest,
+		})
+		return
+	case errors.Is(err, models.ErrOAuthRefreshRejected):
+		ctx.JSON(http.StatusConflict, gin.H{
+			"error":  models.OAuthRefreshRejected,
+			"status": http.StatusConflict,
+		})
+		return
+	case errors.Is(err, models.ErrOAuthRefreshInProgress):
+		ctx.JSON(http.StatusConflict, gin.H{
+			"error":  models.OAuthRefreshInProgress,
+			"status": http.StatusConflict,
+		})
+		return
+	case errors.Is(err, models.ErrOAuthClientRejected):
+		ctx.JSON(http.StatusBadGateway, gin.H{
+			"error":  models.OAuthClientRejected,
+			"status": http.StatusBadGateway,
+		})
+		return
+	case err != nil:
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":  err.Error(),
+			"status": http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"error":      "",
+		"status":     http.StatusOK,
+		"id":         credential.ID,
+		"token":      credential.Data.Token,
+		"expires_at": credential.ExpiresAt,
+		"refreshed":  refreshed,
+	})
+}
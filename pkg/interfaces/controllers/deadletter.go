@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"errors"
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/domain/repos"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DeadLetterController struct {
+	deadLetterService repos.DeadLetterService
+}
+
+func NewDeadLetterController(deadLetterService repos.DeadLetterService) *DeadLetterController {
+	return &DeadLetterController{
+		deadLetterService: deadLetterService,
+	}
+}
+
+func (d *DeadLetterController) GetDeadLetters(ctx *gin.Context) {
+	query := ctx.MustGet(models.DeadLetterListKey).(models.DeadLetterQuery)
+	letters, err := d.deadLetterService.GetDeadLetters(&query)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":  err.Error(),
+			"status": http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"error":        "",
+		"status":       http.StatusOK,
+		"dead_letters": letters,
+	})
+}
+
+func (d *DeadLetterController) GetDeadLetter(ctx *gin.Context) {
+	id := ctx.Param("id")
+	letter, err := d.deadLetterService.GetDeadLetter(&id)
+	if d.respondDeadLetterError(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"error":       "",
+		"status":      http.StatusOK,
+		"dead_letter": letter,
+	})
+}
+
+func (d *DeadLetterController) ReplayDeadLetter(ctx *gin.Context) {
+	id := ctx.Param("id")
+	letter, err := d.deadLetterService.Replay(&id)
+	if errors.Is(err, models.ErrDeadLetterOriginUnknown) {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":  models.DeadLetterOriginUnknown,
+			"status": http.StatusUnprocessableEntity,
+		})
+		return
+	}
+	if err != nil && !errors.Is(err, models.ErrDeadLetterNotFound) {
+		ctx.JSON(http.StatusBadGateway, gin.H{
+			"error":       models.DeadLetterCannotReplay,
+			"status":      http.StatusBadGateway,
+			"cause":       err.Error(),
+			"dead_letter": letter,
+		})
+		return
+	}
+	if d.respondDeadLetterError(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"error":       "",
+		"status":      http.StatusOK,
+		"dead_letter": letter,
+	})
+}
+
+func (d *DeadLetterController) DiscardDeadLetter(ctx *gin.Context) {
+	id := ctx.Param("id")
+	err := d.deadLetterService.Discard(&id)
+	if d.respondDeadLetterError(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"error":  "",
+		"status": http.StatusOK,
+	})
+}
+
+func (d *DeadLetterController) respondDeadLetterError(ctx *gin.Context, err error) (responded bool) {
+	if errors.Is(err, models.ErrDeadLetterNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error":  models.DeadLetterNotFound,
+			"status": http.StatusNotFound,
+		})
+		return true
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":  err.Error(),
+			"status": http.StatusInternalServerError,
+		})
+		return true
+	}
+	return false
+}
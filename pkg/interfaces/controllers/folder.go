@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"errors"
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/domain/repos"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FolderController struct {
+	folderService repos.FolderService
+}
+
+func NewFolderController(folderService repos.FolderService) *FolderController {
+	return &FolderController{
+		folderService: folderService,
+	}
+}
+
+func (f *FolderController) GetFolderTree(ctx *gin.Context) {
+	userID := ctx.Param("iduser")
+	root, err := f.folderService.GetFolderTree(&userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":  err.Error(),
+			"status": http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"error":  "",
+		"status": http.StatusOK,
+		"folder": root,
+	})
+}
+
+func (f *FolderController) CreateFolder(ctx *gin.Context) {
+	request := ctx.MustGet(models.FolderCreateKey).(models.RequestCreateFolder)
+	folder, err := f.folderService.CreateFolder(&request.UserID, &request.Path)
+	if err != nil {
+		status := folderErrorStatus(err)
+		ctx.JSON(status, gin.H{
+			"error":  err.Error(),
+			"status": status,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"error":  "",
+		"status": http.StatusCreated,
+		"path":   folder,
+	})
+}
+
+func (f *FolderController) RenameFolder(ctx *gin.Context) {
+	request := ctx.MustGet(models.FolderRenameKey).(models.RequestRenameFolder)
+	report, err := f.folderService.RenameFolder(&request)
+	f.respondMoveReport(ctx, report, err)
+}
+
+func (f *FolderController) MoveFolder(ctx *gin.Context) {
+	request := ctx.MustGet(models.FolderMoveKey).(models.RequestMoveFolder)
+	report, err := f.folderService.MoveFolder(&request)
+	f.respondMoveReport(ctx, report, err)
+}
+
+func (f *FolderController) MoveWorkflows(ctx *gin.Context) {
+	request := ctx.MustGet(models.FolderMoveWorkflowsKey).(models.RequestMoveWorkflows)
+	report, err := f.folderService.MoveWorkflows(&request)
+	f.respondMoveReport(ctx, report, err)
+}
+
+func (f *FolderController) respondMoveReport(ctx *gin.Context, report *models.FolderMoveReport, err error) {
+	if errors.Is(err, models.ErrFolderMoveReverted) {
+		ctx.JSON(http.StatusConflict, gin.H{
+			"error":  err.Error(),
+			"status": http.StatusConflict,
+			"report": report,
+		})
+		return
+	}
+	if err != nil {
+		status := folderErrorStatus(err)
+		ctx.JSON(status, gin.H{
+			"error":  err.Error(),
+			"status": status,
+		})
+		return
+	}
+
+	// only workflows moved one by one get here, frontend can retry the failed ones
+	if len(report.Failed) > 0 {
+		ctx.JSON(http.StatusMultiStatus, gin.H{
+			"error":  models.FolderCannotMoveWorkflow,
+			"status": http.StatusMultiStatus,
+			"report": report,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"error":  "",
+		"status": http.StatusOK,
+		"report": report,
+	})
+}
+
+func folderErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrFolderInvalid), errors.Is(err, models.ErrFolderMoveInside):
+		return http.StatusBadRequest
+	case errors.Is(err, models.ErrFolderNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrFolderExist):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
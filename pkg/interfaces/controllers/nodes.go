@@ -0,0 +1,27 @@
+package controllers
+
+import (
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/domain/repos"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NodesController struct {
+	nodeService repos.NodeService
+}
+
+func NewNodesController(nodeService repos.NodeService) *NodesController {
+	return &NodesController{
+		nodeService: nodeService,
+	}
+}
+
+func (n *NodesController) GetCatalog(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, models.ResponseNodeCatalog{
+		Error:     "",
+		NodeTypes: n.nodeService.GetCatalog(),
+		Status:    http.StatusOK,
+	})
+}
@@ -0,0 +1,117 @@
+package controllers
+
+import (
+	"errors"
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/domain/repos"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RunController struct {
+	runService repos.RunService
+}
+
+func NewRunController(runService repos.RunService) *RunController {
+	return &RunController{
+		runService: runService,
+	}
+}
+
+func (r *RunController) CreateRun(ctx *gin.Context) {
+	workflowID := ctx.Param("id")
+	request := ctx.MustGet(models.RunCreateKey).(models.RequestCreateRun)
+	run, graphErrors, err := r.runService.CreateRun(&request.UserID, &workflowID, request.DryRun)
+
+	switch {
+	case errors.Is(err, models.ErrWorkflowNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error":  models.WorkflowNotFound,
+			"status": http.StatusNotFound,
+		})
+		return
+	case errors.Is(err, models.ErrRunGraphInvalid):
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":  models.RunGraphInvalid,
+			"status": http.StatusBadRequest,
+			"errors": graphErrors,
+		})
+		return
+	case err != nil:
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":  err.Error(),
+			"status": http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, gin.H{
+		"error":  "",
+		"status": http.StatusAccepted,
+		"run_id": run.RunID,
+		"run":    run,
+	})
+}
+
+func (r *RunController) GetWorkflowRuns(ctx *gin.Context) {
+	query := ctx.MustGet(models.RunListKey).(models.RunListQuery)
+	query.UserID = ctx.Param("iduser")
+	query.WorkflowID = ctx.Param("idworkflow")
+	page, err := r.runService.GetWorkflowRuns(&query)
+	if errors.Is(err, models.ErrWorkflowCursorInvalid) {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":  models.WorkflowCursorInvalid,
+			"status": http.StatusBadRequest,
+		})
+		return
+	}
+	if errors.Is(err, models.ErrWorkflowNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error":  models.WorkflowNotFound,
+			"status": http.StatusNotFound,
+		})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":  err.Error(),
+			"status": http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"error":       "",
+		"status":      http.StatusOK,
+		"runs":        page.Runs,
+		"next_cursor": page.NextCursor,
+		"has_more":    page.HasMore,
+	})
+}
+
+func (r *RunController) GetRun(ctx *gin.Context) {
+	userID := ctx.Param("iduser")
+	runID := ctx.Param("idrun")
+	run, err := r.runService.GetRun(&userID, &runID)
+	if errors.Is(err, models.ErrRunNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error":  models.RunNotFound,
+			"status": http.StatusNotFound,
+		})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":  err.Error(),
+			"status": http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"error":  "",
+		"status": http.StatusOK,
+		"run":    run,
+	})
+}
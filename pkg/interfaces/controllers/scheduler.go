@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"errors"
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/domain/repos"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SchedulerController struct {
+	schedulerService repos.SchedulerService
+}
+
+func NewSchedulerController(schedulerService repos.SchedulerService) *SchedulerController {
+	return &SchedulerController{
+		schedulerService: schedulerService,
+	}
+}
+
+func (s *SchedulerController) SaveSchedule(ctx *gin.Context) {
+	request := ctx.MustGet(models.ScheduleCreateKey).(models.RequestCreateSchedule)
+	schedule, err := s.schedulerService.SaveSchedule(&request)
+	if errors.Is(err, models.ErrScheduleInvalid) || errors.Is(err, models.ErrTimezoneInvalid) || errors.Is(err, models.ErrScheduleNeverFires) {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":  err.Error(),
+			"status": http.StatusBadRequest,
+		})
+		return
+	}
+	if errors.Is(err, models.ErrScheduleNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error":  models.ScheduleNotFound,
+			"status": http.StatusNotFound,
+		})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":  err.Error(),
+			"status": http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"error":    "",
+		"status":   http.StatusOK,
+		"schedule": schedule,
+	})
+}
+
+func (s *SchedulerController) GetSchedules(ctx *gin.Context) {
+	userID := ctx.Param("iduser")
+	workflowID := ctx.Param("idworkflow")
+	schedules, err := s.schedulerService.GetSchedules(&userID, &workflowID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":  err.Error(),
+			"status": http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"error":     "",
+		"status":    http.StatusOK,
+		"schedules": schedules,
+	})
+}
+
+func (s *SchedulerController) RemoveSchedule(ctx *gin.Context) {
+	userID := ctx.Param("iduser")
+	workflowID := ctx.Param("idworkflow")
+	nodeID := ctx.Param("idnode")
+	err := s.schedulerService.RemoveSchedule(&userID, &workflowID, &nodeID)
+	if errors.Is(err, models.ErrScheduleNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error":  models.ScheduleNotFound,
+			"status": http.StatusNotFound,
+		})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":  err.Error(),
+			"status": http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"error":  "",
+		"status": http.StatusOK,
+	})
+}
@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	"io"
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/domain/repos"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StatusController struct {
+	statusService repos.StatusService
+}
+
+func NewStatusController(statusService repos.StatusService) *StatusController {
+	return &StatusController{
+		statusService: statusService,
+	}
+}
+
+// StreamStatus server sent events, the event name is the kind so the client can listen only to actions or runs
+func (s *StatusController) StreamStatus(ctx *gin.Context) {
+	userID := ctx.Param("iduser")
+	events, err := s.statusService.Subscribe(ctx.Request.Context(), &userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":  models.StatusCannotOpen,
+			"status": http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	// proxies buffering the response would hold the events
+	ctx.Header("X-Accel-Buffering", "no")
+
+	heartbeat := time.NewTicker(models.StatusHeartbeat)
+	defer heartbeat.Stop()
+
+	ctx.Stream(func(_ io.Writer) bool {
+		select {
+		case <-ctx.Request.Context().Done():
+			return false
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			ctx.SSEvent(event.Kind, event)
+			return true
+		case <-heartbeat.C:
+			ctx.SSEvent("heartbeat", time.Now().UTC().Format(models.LayoutTimestamp))
+			return true
+		}
+	})
+}
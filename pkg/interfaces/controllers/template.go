@@ -0,0 +1,154 @@
+package controllers
+
+import (
+	"errors"
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/domain/repos"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TemplateController struct {
+	templateService repos.TemplateService
+}
+
+func NewTemplateController(templateService repos.TemplateService) *TemplateController {
+	return &TemplateController{
+		templateService: templateService,
+	}
+}
+
+func (t *TemplateController) GetTemplates(ctx *gin.Context) {
+	templates, err := t.templateService.GetTemplates(ctx.Query("category"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":  err.Error(),
+			"status": http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"error":     "",
+		"status":    http.StatusOK,
+		"templates": templates,
+	})
+}
+
+func (t *TemplateController) GetTemplate(ctx *gin.Context) {
+	templateID := ctx.Param("idtemplate")
+	template, err := t.templateService.GetTemplate(&templateID)
+	if errors.Is(err, models.ErrTemplateNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error":  models.TemplateNotFound,
+			"status": http.StatusNotFound,
+		})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":  err.Error(),
+			"status": http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"error":    "",
+		"status":   http.StatusOK,
+		"template": template,
+	})
+}
+
+func (t *TemplateController) PublishTemplate(ctx *gin.Context) {
+	request := ctx.MustGet(models.TemplatePublishKey).(models.RequestPublishTemplate)
+	template, err := t.templateService.PublishTemplate(&request)
+	if errors.Is(err, models.ErrWorkflowNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error":  models.WorkflowNotFound,
+			"status": http.StatusNotFound,
+		})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":  models.TemplateCannotPublish,
+			"status": http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"error":    "",
+		"status":   http.StatusCreated,
+		"template": template,
+	})
+}
+
+func (t *TemplateController) DeleteTemplate(ctx *gin.Context) {
+	userID := ctx.Param("iduser")
+	templateID := ctx.Param("idtemplate")
+	err := t.templateService.DeleteTemplate(&userID, &templateID)
+
+	switch {
+	case errors.Is(err, models.ErrTemplateNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error":  models.TemplateNotFound,
+			"status": http.StatusNotFound,
+		})
+	case errors.Is(err, models.ErrTemplateNotOwner):
+		ctx.JSON(http.StatusForbidden, gin.H{
+			"error":  models.TemplateNotOwner,
+			"status": http.StatusForbidden,
+		})
+	case err != nil:
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":  models.TemplateCannotDelete,
+			"status": http.StatusInternalServerError,
+		})
+	default:
+		ctx.JSON(http.StatusOK, gin.H{
+			"error":  "",
+			"status": http.StatusOK,
+		})
+	}
+}
+
+func (t *TemplateController) CreateWorkflowFromTemplate(ctx *gin.Context) {
+	templateID := ctx.Param("idtemplate")
+	request := ctx.MustGet(models.TemplateCreateFromKey).(models.RequestCreateFromTemplate)
+	imported, graphErrors, err := t.templateService.CreateWorkflowFromTemplate(&templateID, &request)
+
+	switch {
+	case errors.Is(err, models.ErrTemplateNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error":  models.TemplateNotFound,
+			"status": http.StatusNotFound,
+		})
+	case len(graphErrors) > 0:
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":  models.WorkflowGraphInvalid,
+			"status": http.StatusBadRequest,
+			"errors": graphErrors,
+		})
+	case errors.Is(err, models.ErrWorkflowNameExist):
+		ctx.JSON(http.StatusConflict, gin.H{
+			"error":  models.WorkflowNameExist,
+			"status": http.StatusConflict,
+		})
+	case err != nil:
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":  models.TemplateCannotUse,
+			"status": http.StatusInternalServerError,
+		})
+	default:
+		setWorkflowETag(ctx, imported.Workflow.Version)
+		ctx.JSON(http.StatusCreated, gin.H{
+			"error":       "",
+			"status":      http.StatusCreated,
+			"workflow":    imported.Workflow,
+			"credentials": imported.Credentials,
+		})
+	}
+}
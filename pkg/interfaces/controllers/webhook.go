@@ -0,0 +1,152 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/domain/repos"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookController struct {
+	webhookService repos.WebhookService
+}
+
+func NewWebhookController(webhookService repos.WebhookService) *WebhookController {
+	return &WebhookController{
+		webhookService: webhookService,
+	}
+}
+
+func (w *WebhookController) CreateWebhook(ctx *gin.Context) {
+	request := ctx.MustGet(models.WebhookCreateKey).(models.RequestCreateWebhook)
+	webhook, err := w.webhookService.CreateWebhook(&request)
+	if errors.Is(err, models.ErrWorkflowNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error":  models.WorkflowNotFound,
+			"status": http.StatusNotFound,
+		})
+		return
+	}
+	if errors.Is(err, models.ErrWebhookNodeInvalid) {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":  models.WebhookNodeInvalid,
+			"status": http.StatusBadRequest,
+		})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":  err.Error(),
+			"status": http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"error":   "",
+		"status":  http.StatusCreated,
+		"webhook": webhook,
+	})
+}
+
+func (w *WebhookController) GetWebhooks(ctx *gin.Context) {
+	userID := ctx.Param("iduser")
+	workflowID := ctx.Param("idworkflow")
+	webhooks, err := w.webhookService.GetWebhooks(&userID, &workflowID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":  err.Error(),
+			"status": http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"error":    "",
+		"status":   http.StatusOK,
+		"webhooks": webhooks,
+	})
+}
+
+func (w *WebhookController) RemoveWebhook(ctx *gin.Context) {
+	userID := ctx.Param("iduser")
+	workflowID := ctx.Param("idworkflow")
+	nodeID := ctx.Param("idnode")
+	err := w.webhookService.RemoveWebhook(&userID, &workflowID, &nodeID)
+	if errors.Is(err, models.ErrWebhookNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error":  models.WebhookNotFound,
+			"status": http.StatusNotFound,
+		})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":  err.Error(),
+			"status": http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"error":  "",
+		"status": http.StatusOK,
+	})
+}
+
+// ReceiveWebhook public endpoint, the token in the url is the only authentication besides the optional signature
+func (w *WebhookController) ReceiveWebhook(ctx *gin.Context) {
+	token := ctx.Param("token")
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, models.MaxWebhookBodySize)
+	body, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"error":  models.WebhookBodyTooLarge,
+			"status": http.StatusRequestEntityTooLarge,
+		})
+		return
+	}
+
+	event, err := w.webhookService.ReceiveWebhook(&token, body, ctx.ContentType(), ctx.GetHeader(models.WebhookSignatureHeader), ctx.GetHeader(models.WebhookTimestampHeader))
+	switch {
+	case errors.Is(err, models.ErrWebhookNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error":  models.WebhookNotFound,
+			"status": http.StatusNotFound,
+		})
+		return
+	case errors.Is(err, models.ErrWebhookDisabled):
+		ctx.JSON(http.StatusConflict, gin.H{
+			"error":  models.WebhookDisabled,
+			"status": http.StatusConflict,
+		})
+		return
+	case errors.Is(err, models.ErrWebhookReplayed):
+		ctx.JSON(http.StatusConflict, gin.H{
+			"error":  models.WebhookReplayed,
+			"status": http.StatusConflict,
+		})
+		return
+	case errors.Is(err, models.ErrWebhookSignatureFailed):
+		ctx.JSON(http.StatusUnauthorized, gin.H{
+			"error":  models.WebhookSignatureFailed,
+			"status": http.StatusUnauthorized,
+		})
+		return
+	case err != nil:
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":  models.WebhookCannotPublish,
+			"status": http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, gin.H{
+		"error":    "",
+		"status":   http.StatusAccepted,
+		"event_id": event.EventID,
+	})
+}
@@ -1,9 +1,15 @@
 package controllers
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"minireipaz/pkg/common"
 	"minireipaz/pkg/domain/models"
 	"minireipaz/pkg/domain/repos"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -72,6 +78,7 @@ func (c *WorkflowController) GetWorkflow(ctx *gin.Context) {
 		})
 		return
 	}
+	setWorkflowETag(ctx, newWorkflow.Version)
 	ctx.JSON(http.StatusOK, gin.H{
 		"error":       "",
 		"status":      http.StatusOK,
@@ -81,8 +88,17 @@ func (c *WorkflowController) GetWorkflow(ctx *gin.Context) {
 }
 
 func (c *WorkflowController) GetAllWorkflows(ctx *gin.Context) {
-	userID := ctx.Param("iduser")
-	allWorkflows, err := c.workflowService.GetAllWorkflows(&userID)
+	query := ctx.MustGet(models.WorkflowListKey).(models.WorkflowListQuery)
+	query.UserID = ctx.Param("iduser")
+	page, err := c.workflowService.ListWorkflows(&query)
+	if errors.Is(err, models.ErrWorkflowCursorInvalid) {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":  models.WorkflowCursorInvalid,
+			"status": http.StatusBadRequest,
+		})
+		return
+	}
+
 	if err != nil {
 		ctx.JSON(http.StatusNotFound, gin.H{
 			"error":  models.UUIDInvalid,
@@ -92,17 +108,48 @@ func (c *WorkflowController) GetAllWorkflows(ctx *gin.Context) {
 	}
 
 	ctx.JSON(http.StatusOK, gin.H{
-		"error":    "",
-		"status":   http.StatusOK,
-		"workflow": allWorkflows,
+		"error":       "",
+		"status":      http.StatusOK,
+		"workflow":    page.Workflows,
+		"next_cursor": page.NextCursor,
+		"has_more":    page.HasMore,
 	})
 }
 
 func (c *WorkflowController) UpdateWorkflow(ctx *gin.Context) {
 	workflowFrontend := ctx.MustGet("workflow").(models.Workflow)
-	updated, exist := c.workflowService.UpdateWorkflow(&workflowFrontend)
+	updated, exist, err := c.workflowService.UpdateWorkflow(&workflowFrontend)
 
-	if !exist {
+	var graphInvalid *models.GraphInvalidError
+	if errors.As(err, &graphInvalid) {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":  models.WorkflowGraphInvalid,
+			"status": http.StatusBadRequest,
+			"errors": graphInvalid.Errors,
+		})
+		return
+	}
+
+	if errors.Is(err, models.ErrVersionConflict) {
+		// frontend merges with the server copy
+		current, _ := c.workflowService.GetWorkflow(&workflowFrontend.UserID, &workflowFrontend.UUID)
+		ctx.JSON(http.StatusConflict, gin.H{
+			"error":    models.WorkflowVersionConflict,
+			"status":   http.StatusConflict,
+			"workflow": current,
+		})
+		return
+	}
+
+	if errors.Is(err, models.ErrWorkflowNameExist) {
+		ctx.JSON(http.StatusConflict, gin.H{
+			"error":  models.WorkflowNameExist,
+			"status": http.StatusConflict,
+		})
+		return
+	}
+
+	if !exist || errors.Is(err, models.ErrWorkflowNotFound) {
 		ctx.JSON(http.StatusAlreadyReported, gin.H{
 			"error":  models.WorkflowNameExist,
 			"status": http.StatusNotFound,
@@ -118,8 +165,455 @@ func (c *WorkflowController) UpdateWorkflow(ctx *gin.Context) {
 		return
 	}
 
+	setWorkflowETag(ctx, workflowFrontend.Version)
+	ctx.JSON(http.StatusOK, gin.H{
+		"error":   "",
+		"status":  http.StatusOK,
+		"version": workflowFrontend.Version,
+	})
+}
+
+func (c *WorkflowController) ValidateWorkflow(ctx *gin.Context) {
+	workflowFrontend := ctx.MustGet("workflow").(models.Workflow)
+	graphErrors := c.workflowService.ValidateWorkflowGraph(&workflowFrontend)
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"error":  "",
+		"status": http.StatusOK,
+		"valid":  len(graphErrors) == 0,
+		"errors": graphErrors,
+	})
+}
+
+func (c *WorkflowController) ExportWorkflow(ctx *gin.Context) {
+	userID := ctx.Param("iduser")
+	workflowID := ctx.Param("idworkflow")
+	format := ctx.DefaultQuery("format", models.FormatJSON)
+	if format != models.FormatJSON && format != models.FormatYAML {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":  models.WorkflowExportFormatInvalid,
+			"status": http.StatusBadRequest,
+		})
+		return
+	}
+
+	document, exist := c.workflowService.ExportWorkflow(&userID, &workflowID)
+	if !exist {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error":  models.UUIDInvalid,
+			"status": http.StatusNotFound,
+		})
+		return
+	}
+
+	content, err := json.MarshalIndent(document, "", "  ")
+	if err == nil && format == models.FormatYAML {
+		content, err = common.JSONToYAML(content)
+	}
+	if err != nil {
+		log.Printf("ERROR | Cannot encode workflow %s as %s: %v", workflowID, format, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":  models.WorkflowDocumentInvalid,
+			"status": http.StatusInternalServerError,
+		})
+		return
+	}
+
+	contentType := "application/json"
+	if format == models.FormatYAML {
+		contentType = "application/yaml"
+	}
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("%s.%s", workflowID, format)))
+	ctx.Data(http.StatusOK, contentType, content)
+}
+
+func (c *WorkflowController) ImportWorkflow(ctx *gin.Context) {
+	request := ctx.MustGet(models.WorkflowImportKey).(models.RequestImportWorkflow)
+	imported, graphErrors, err := c.workflowService.ImportWorkflow(&request.UserID, &request.DirectoryToSave, &request.Document)
+
+	switch {
+	case len(graphErrors) > 0:
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":  models.WorkflowGraphInvalid,
+			"status": http.StatusBadRequest,
+			"errors": graphErrors,
+		})
+	case errors.Is(err, models.ErrWorkflowDocumentInvalid):
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":  models.WorkflowDocumentInvalid,
+			"status": http.StatusBadRequest,
+		})
+	case errors.Is(err, models.ErrWorkflowNameExist):
+		ctx.JSON(http.StatusConflict, gin.H{
+			"error":  models.WorkflowNameExist,
+			"status": http.StatusConflict,
+		})
+	case err != nil:
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":  models.WorkflowCannotImport,
+			"status": http.StatusInternalServerError,
+		})
+	default:
+		setWorkflowETag(ctx, imported.Workflow.Version)
+		ctx.JSON(http.StatusCreated, gin.H{
+			"error":       "",
+			"status":      http.StatusCreated,
+			"workflow":    imported.Workflow,
+			"node_ids":    imported.NodeIDs,
+			"credentials": imported.Credentials,
+		})
+	}
+}
+
+func (c *WorkflowController) DeleteWorkflow(ctx *gin.Context) {
+	userID := ctx.Param("iduser")
+	workflowID := ctx.Param("idworkflow")
+	deleted, exist := c.workflowService.DeleteWorkflow(&userID, &workflowID)
+
+	if !exist {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error":  models.UUIDInvalid,
+			"status": http.StatusNotFound,
+		})
+		return
+	}
+
+	if !deleted {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":  models.WorkflowCannotDelete,
+			"status": http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"error":  "",
+		"status": http.StatusOK,
+	})
+}
+
+func (c *WorkflowController) GetWorkflowsTrash(ctx *gin.Context) {
+	userID := ctx.Param("iduser")
+	trashed, err := c.workflowService.GetWorkflowsTrash(&userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":  err.Error(),
+			"status": http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"error":     "",
+		"status":    http.StatusOK,
+		"workflows": trashed,
+	})
+}
+
+func (c *WorkflowController) RestoreWorkflow(ctx *gin.Context) {
+	userID := ctx.Param("iduser")
+	workflowID := ctx.Param("idworkflow")
+	restored, err := c.workflowService.RestoreWorkflow(&userID, &workflowID)
+
+	switch {
+	case errors.Is(err, models.ErrWorkflowNotInTrash):
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error":  models.WorkflowNotInTrash,
+			"status": http.StatusNotFound,
+		})
+		return
+	case errors.Is(err, models.ErrWorkflowNameExist):
+		ctx.JSON(http.StatusConflict, gin.H{
+			"error":  models.WorkflowNameExist,
+			"status": http.StatusConflict,
+		})
+		return
+	case err != nil || !restored:
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":  models.WorkflowCannotRestore,
+			"status": http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"error":  "",
+		"status": http.StatusOK,
+	})
+}
+
+func (c *WorkflowController) PurgeWorkflow(ctx *gin.Context) {
+	userID := ctx.Param("iduser")
+	workflowID := ctx.Param("idworkflow")
+	purged, exist := c.workflowService.PurgeWorkflow(&userID, &workflowID)
+
+	if !exist {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error":  models.WorkflowNotInTrash,
+			"status": http.StatusNotFound,
+		})
+		return
+	}
+
+	if !purged {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":  models.WorkflowCannotDelete,
+			"status": http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"error":  "",
+		"status": http.StatusOK,
+	})
+}
+
+func (c *WorkflowController) GetWorkflowRevisions(ctx *gin.Context) {
+	userID := ctx.Param("iduser")
+	workflowID := ctx.Param("idworkflow")
+	revisions, err := c.workflowService.GetWorkflowRevisions(&userID, &workflowID)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error":  models.WorkflowRevisionNotFound,
+			"status": http.StatusNotFound,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"error":     "",
+		"status":    http.StatusOK,
+		"revisions": revisions,
+	})
+}
+
+func (c *WorkflowController) GetWorkflowRevision(ctx *gin.Context) {
+	userID := ctx.Param("iduser")
+	workflowID := ctx.Param("idworkflow")
+	version, valid := parseVersionParam(ctx, "version")
+	if !valid {
+		return
+	}
+
+	revision, err := c.workflowService.GetWorkflowRevision(&userID, &workflowID, version)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error":  models.WorkflowRevisionNotFound,
+			"status": http.StatusNotFound,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"error":    "",
+		"status":   http.StatusOK,
+		"workflow": revision,
+	})
+}
+
+func (c *WorkflowController) DiffWorkflowRevisions(ctx *gin.Context) {
+	userID := ctx.Param("iduser")
+	workflowID := ctx.Param("idworkflow")
+	fromVersion, valid := parseVersionParam(ctx, "fromversion")
+	if !valid {
+		return
+	}
+	toVersion, valid := parseVersionParam(ctx, "toversion")
+	if !valid {
+		return
+	}
+
+	diff, err := c.workflowService.DiffWorkflowRevisions(&userID, &workflowID, fromVersion, toVersion)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error":  models.WorkflowRevisionNotFound,
+			"status": http.StatusNotFound,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"error":  "",
+		"status": http.StatusOK,
+		"diff":   diff,
+	})
+}
+
+func (c *WorkflowController) RollbackWorkflow(ctx *gin.Context) {
+	workflowID := ctx.Param("idworkflow")
+	rollback := ctx.MustGet(models.WorkflowRollbackKey).(models.RequestRollbackWorkflow)
+	updated, exist, err := c.workflowService.RollbackWorkflow(&rollback.UserID, &workflowID, rollback.Version)
+
+	var graphInvalid *models.GraphInvalidError
+	switch {
+	case errors.As(err, &graphInvalid):
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":  models.WorkflowGraphInvalid,
+			"status": http.StatusBadRequest,
+			"errors": graphInvalid.Errors,
+		})
+		return
+	case errors.Is(err, models.ErrVersionConflict):
+		ctx.JSON(http.StatusConflict, gin.H{
+			"error":  models.WorkflowVersionConflict,
+			"status": http.StatusConflict,
+		})
+		return
+	case !exist || errors.Is(err, models.ErrWorkflowNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error":  models.WorkflowRevisionNotFound,
+			"status": http.StatusNotFound,
+		})
+		return
+	case err != nil || !updated:
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":  models.WorkflowCannotRollback,
+			"status": http.StatusInternalServerError,
+		})
+		return
+	}
+
 	ctx.JSON(http.StatusOK, gin.H{
 		"error":  "",
 		"status": http.StatusOK,
 	})
 }
+
+func (c *WorkflowController) DuplicateWorkflow(ctx *gin.Context) {
+	workflowID := ctx.Param("id")
+	duplicate := ctx.MustGet(models.WorkflowDuplicateKey).(models.RequestDuplicateWorkflow)
+	created, exist, workflow, err := c.workflowService.DuplicateWorkflow(&duplicate.UserID, &workflowID, duplicate.KeepCredentials)
+	if err != nil {
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{
+			"error":  models.WorkflowNameNotGenerate,
+			"status": http.StatusServiceUnavailable,
+		})
+		return
+	}
+
+	if !exist {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error":  models.UUIDInvalid,
+			"status": http.StatusNotFound,
+		})
+		return
+	}
+
+	if !created {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":  models.WorkflowNameNotGenerate,
+			"status": http.StatusInternalServerError,
+		})
+		return
+	}
+
+	setWorkflowETag(ctx, workflow.Version)
+	ctx.JSON(http.StatusCreated, gin.H{
+		"error":    "",
+		"workflow": workflow,
+		"status":   http.StatusCreated,
+	})
+}
+
+func (c *WorkflowController) ActivateWorkflow(ctx *gin.Context) {
+	c.changeLifecycle(ctx, models.TransitionActivate)
+}
+
+func (c *WorkflowController) PauseWorkflow(ctx *gin.Context) {
+	c.changeLifecycle(ctx, models.TransitionPause)
+}
+
+func (c *WorkflowController) ResumeWorkflow(ctx *gin.Context) {
+	c.changeLifecycle(ctx, models.TransitionResume)
+}
+
+func (c *WorkflowController) DraftWorkflow(ctx *gin.Context) {
+	c.changeLifecycle(ctx, models.TransitionDraft)
+}
+
+func (c *WorkflowController) changeLifecycle(ctx *gin.Context, transition string) {
+	workflowID := ctx.Param("id")
+	lifecycle := ctx.MustGet(models.WorkflowLifecycleKey).(models.RequestLifecycleWorkflow)
+	workflow, graphErrors, err := c.workflowService.ChangeLifecycle(&lifecycle.UserID, &workflowID, transition)
+
+	switch {
+	case errors.Is(err, models.ErrWorkflowNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error":  models.UUIDInvalid,
+			"status": http.StatusNotFound,
+		})
+		return
+	case errors.Is(err, models.ErrWorkflowTransitionInvalid):
+		ctx.JSON(http.StatusConflict, gin.H{
+			"error":     models.WorkflowTransitionInvalid,
+			"status":    http.StatusConflict,
+			"is_active": workflow.IsActive,
+		})
+		return
+	case errors.Is(err, models.ErrWorkflowCannotActivate):
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":  models.WorkflowCannotActivate,
+			"status": http.StatusBadRequest,
+			"errors": graphErrors,
+		})
+		return
+	case errors.Is(err, models.ErrVersionConflict):
+		ctx.JSON(http.StatusConflict, gin.H{
+			"error":  models.WorkflowVersionConflict,
+			"status": http.StatusConflict,
+		})
+		return
+	case err != nil:
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":  models.WorkflowCannotChangeState,
+			"status": http.StatusInternalServerError,
+		})
+		return
+	}
+
+	setWorkflowETag(ctx, workflow.Version)
+	ctx.JSON(http.StatusOK, gin.H{
+		"error":    "",
+		"status":   http.StatusOK,
+		"workflow": workflow,
+	})
+}
+
+func (c *WorkflowController) ReconcileIndexes(ctx *gin.Context) {
+	reconcile := ctx.MustGet(models.WorkflowReconcileKey).(models.RequestReconcileIndexes)
+	reports, err := c.workflowService.ReconcileIndexes(&reconcile.UserID, reconcile.Prune)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":   err.Error(),
+			"status":  http.StatusInternalServerError,
+			"reports": reports,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"error":   "",
+		"status":  http.StatusOK,
+		"reports": reports,
+	})
+}
+
+func parseVersionParam(ctx *gin.Context, param string) (version uint32, valid bool) {
+	parsed, err := strconv.ParseUint(ctx.Param(param), 10, 32)
+	if err != nil || parsed == 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":  models.WorkflowRevisionInvalid,
+			"status": http.StatusBadRequest,
+		})
+		return 0, false
+	}
+	return uint32(parsed), true
+}
+
+// setWorkflowETag version sent back by clients in If-Match header when updating
+func setWorkflowETag(ctx *gin.Context, version uint32) {
+	if version > 0 {
+		ctx.Header("ETag", fmt.Sprintf("%q", strconv.FormatUint(uint64(version), 10)))
+	}
+}
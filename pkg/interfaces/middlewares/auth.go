@@ -1,6 +1,7 @@
 package middlewares
 
 import (
+	"crypto/subtle"
 	"minireipaz/pkg/domain/models"
 	"minireipaz/pkg/domain/repos"
 
@@ -13,6 +14,12 @@ import (
 
 func AuthMiddleware(authService *repos.AuthService) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
+		// external systems calling hooks have no service user token, the hook token authenticates them
+		if strings.HasPrefix(ctx.Request.URL.Path, models.HooksPath) {
+			ctx.Next()
+			return
+		}
+
 		if ctx.ContentType() != "application/json" {
 			ctx.JSON(http.StatusUnsupportedMediaType, NewUnsupportedMediaTypeError("Only application/json is supported"))
 			ctx.Abort()
@@ -44,6 +51,19 @@ func AuthMiddleware(authService *repos.AuthService) gin.HandlerFunc {
 	}
 }
 
+// RequireAdmin operator endpoints act on every user, the service token is not enough for them
+func RequireAdmin(adminToken string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token := ctx.GetHeader(models.AdminTokenHeader)
+		if adminToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) != 1 {
+			ctx.JSON(http.StatusForbidden, NewInvalidRequestError(models.AdminInvalid, http.StatusForbidden))
+			ctx.Abort()
+			return
+		}
+		ctx.Next()
+	}
+}
+
 // TODO: can be expired btw rightnow not rotated
 func verifyServiceUserToken(authService repos.AuthService, token string) (bool, error) {
 	isValid, err := authService.VerifyServiceUserToken(token)
@@ -0,0 +1,155 @@
+package middlewares
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/domain/repos"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// idempotencyWriter keeps a copy of the response to replay it for duplicates
+type idempotencyWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *idempotencyWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *idempotencyWriter) WriteString(data string) (int, error) {
+	w.body.WriteString(data)
+	return w.ResponseWriter.WriteString(data)
+}
+
+// IdempotencyMiddleware requests without the header run as always, with it the first response
+// of the key is stored and sent again to every retry with the same body
+func IdempotencyMiddleware(idempotencyService repos.IdempotencyService) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		key := ctx.GetHeader(models.IdempotencyKeyHeader)
+		// hooks limit the size of the body in the controller, reading it here would skip that check
+		if idempotencyService == nil || key == "" || !isMutating(ctx.Request.Method) || strings.HasPrefix(ctx.Request.URL.Path, models.HooksPath) {
+			ctx.Next()
+			return
+		}
+		if len(key) > models.MaxIdempotencyKeyLength {
+			ctx.JSON(http.StatusBadRequest, NewInvalidRequestError(models.IdempotencyKeyInvalid, http.StatusBadRequest))
+			ctx.Abort()
+			return
+		}
+
+		body, err := ctx.GetRawData()
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, NewInvalidRequestError(models.IdempotencyBodyInvalid, http.StatusBadRequest))
+			ctx.Abort()
+			return
+		}
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		scope := ctx.Request.Method + " " + idempotencyRoute(ctx) + " " + idempotencySubject(ctx, body)
+		hash := requestHash(ctx.Request.URL.RawQuery, ctx.GetHeader("If-Match"), body)
+		stored, err := idempotencyService.Begin(scope, key, hash)
+		switch {
+		case errors.Is(err, models.ErrIdempotencyKeyReused):
+			ctx.JSON(http.StatusUnprocessableEntity, NewInvalidRequestError(models.IdempotencyKeyReused, http.StatusUnprocessableEntity))
+			ctx.Abort()
+			return
+		case errors.Is(err, models.ErrIdempotencyInProgress):
+			ctx.JSON(http.StatusConflict, NewInvalidRequestError(models.IdempotencyInProgress, http.StatusConflict))
+			ctx.Abort()
+			return
+		case err != nil:
+			log.Printf("WARN | Idempotency key %s not checked, request runs without it: %v", key, err)
+			ctx.Next()
+			return
+		case stored != nil:
+			ctx.Header(models.IdempotencyReplayedHeader, "true")
+			ctx.Data(stored.Status, stored.ContentType, []byte(stored.Body))
+			ctx.Abort()
+			return
+		}
+
+		writer := &idempotencyWriter{ResponseWriter: ctx.Writer}
+		ctx.Writer = writer
+		ctx.Next()
+
+		// server errors are not stored, the retry of the client must run again
+		if writer.Status() >= http.StatusInternalServerError {
+			if err := idempotencyService.Release(scope, key); err != nil {
+				log.Printf("WARN | Cannot release idempotency key %s, retries wait until it expires: %v", key, err)
+			}
+			return
+		}
+		record := &models.IdempotencyRecord{
+			RequestHash: hash,
+			ContentType: writer.Header().Get("Content-Type"),
+			Body:        writer.body.String(),
+			Status:      writer.Status(),
+		}
+		if err := idempotencyService.Complete(scope, key, record); err != nil {
+			log.Printf("ERROR | Cannot store response of idempotency key %s: %v", key, err)
+		}
+	}
+}
+
+func isMutating(method string) bool {
+	return method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch || method == http.MethodDelete
+}
+
+// requestHash If-Match is part of the request, a retry with a newer version must not get the old response
+func requestHash(query, ifMatch string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(query))
+	hash.Write([]byte{0})
+	hash.Write([]byte(ifMatch))
+	hash.Write([]byte{0})
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// idempotencyRoute the template of the route, some paths carry the token of the user and the scope is stored.
+// Values of the params are hashed so keys of different resources never meet
+func idempotencyRoute(ctx *gin.Context) string {
+	route := ctx.FullPath()
+	if len(ctx.Params) == 0 {
+		return route
+	}
+	hash := sha256.New()
+	for _, param := range ctx.Params {
+		hash.Write([]byte(param.Value))
+		hash.Write([]byte{0})
+	}
+	return route + " " + hex.EncodeToString(hash.Sum(nil))
+}
+
+// idempotencySubject user the request acts for, from the path or else from the body, so keys
+// of different users never meet. Without user the caller token is used
+func idempotencySubject(ctx *gin.Context, body []byte) string {
+	if userID := ctx.Param("iduser"); userID != "" {
+		return userID
+	}
+	var owner struct {
+		UserID string `json:"user_id"`
+		Sub    string `json:"sub"`
+	}
+	if err := json.Unmarshal(body, &owner); err == nil {
+		if owner.UserID != "" {
+			return owner.UserID
+		}
+		if owner.Sub != "" {
+			return owner.Sub
+		}
+	}
+	caller := sha256.Sum256([]byte(ctx.GetHeader("Authorization")))
+	return hex.EncodeToString(caller[:])
+}
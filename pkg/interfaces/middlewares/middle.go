@@ -1,6 +1,7 @@
 package middlewares
 
 import (
+	"minireipaz/pkg/domain/models"
 	"minireipaz/pkg/domain/repos"
 
 	"github.com/gin-contrib/cors"
@@ -8,17 +9,20 @@ import (
 	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
-func Register(app *gin.Engine, authService *repos.AuthService) {
+func Register(app *gin.Engine, authService *repos.AuthService, idempotencyService repos.IdempotencyService) {
 	app.Use(otelgin.Middleware("backend-vercel"))
 	// allowedOriginsEnv := config.GetEnv("CORS_ALLOWED_ORIGINS", "http://localhost:3020,http://localhost:3010")
 	// allowedOrigins := strings.Split(allowedOriginsEnv, ",")
 	app.Use(cors.New(cors.Config{
 		AllowAllOrigins: true,
 		// AllowOrigins:     allowedOrigins,
-		AllowMethods:     []string{"POST", "PUT", "GET", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept"},
+		AllowMethods:     []string{"POST", "PUT", "GET", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "If-Match", models.IdempotencyKeyHeader},
+		ExposeHeaders:    []string{"ETag", models.IdempotencyReplayedHeader},
 		AllowCredentials: true,
 	}))
 
 	app.Use(AuthMiddleware(authService))
+	// after auth, a request without a valid token never holds an idempotency key
+	app.Use(IdempotencyMiddleware(idempotencyService))
 }
@@ -1,8 +1,11 @@
 package middlewares
 
 import (
+	"encoding/json"
+	"minireipaz/pkg/common"
 	"minireipaz/pkg/domain/models"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -48,6 +51,34 @@ func ValidateOnGetWorkflow() gin.HandlerFunc {
 	}
 }
 
+func ValidateOnListWorkflows() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var query models.WorkflowListQuery
+		if err := ctx.ShouldBindQuery(&query); err != nil {
+			ctx.JSON(http.StatusBadRequest, NewInvalidRequestError(models.WorkflowListInvalid, http.StatusBadRequest))
+			ctx.Abort()
+			return
+		}
+
+		ctx.Set(models.WorkflowListKey, query)
+		ctx.Next()
+	}
+}
+
+func ValidateOnListRuns() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var query models.RunListQuery
+		if err := ctx.ShouldBindQuery(&query); err != nil {
+			ctx.JSON(http.StatusBadRequest, NewInvalidRequestError(models.RunListInvalid, http.StatusBadRequest))
+			ctx.Abort()
+			return
+		}
+
+		ctx.Set(models.RunListKey, query)
+		ctx.Next()
+	}
+}
+
 func ValidateOnUpdateWorkflow() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		var workflow models.Workflow
@@ -77,11 +108,251 @@ func ValidateOnUpdateWorkflow() gin.HandlerFunc {
 			return
 		}
 
+		// If-Match has priority over the version in body
+		if !validateIfMatch(ctx.GetHeader("If-Match"), &workflow.Version, ctx) {
+			return
+		}
+
 		ctx.Set("workflow", workflow)
 		ctx.Next()
 	}
 }
 
+func ValidateOnGraphWorkflow() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var workflow models.Workflow
+		if err := ctx.ShouldBindJSON(&workflow); err != nil {
+			ctx.JSON(http.StatusBadRequest, NewInvalidRequestError(models.InvalidJSON, http.StatusBadRequest))
+			ctx.Abort()
+			return
+		}
+
+		ctx.Set("workflow", workflow)
+		ctx.Next()
+	}
+}
+
+func ValidateOnRollbackWorkflow() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var rollback models.RequestRollbackWorkflow
+		if err := ctx.ShouldBindJSON(&rollback); err != nil {
+			ctx.JSON(http.StatusBadRequest, NewInvalidRequestError(models.InvalidJSON, http.StatusBadRequest))
+			ctx.Abort()
+			return
+		}
+
+		userID := ctx.Param("iduser")
+		if rollback.UserID != "" && rollback.UserID != userID {
+			ctx.JSON(http.StatusBadRequest, NewInvalidRequestError(models.UUIDInvalid, http.StatusBadRequest))
+			ctx.Abort()
+			return
+		}
+		rollback.UserID = userID
+		if !validateSub(rollback.UserID, ctx) {
+			return
+		}
+
+		ctx.Set(models.WorkflowRollbackKey, rollback)
+		ctx.Next()
+	}
+}
+
+func ValidateOnDuplicateWorkflow() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var duplicate models.RequestDuplicateWorkflow
+		if err := ctx.ShouldBindJSON(&duplicate); err != nil {
+			ctx.JSON(http.StatusBadRequest, NewInvalidRequestError(models.InvalidJSON, http.StatusBadRequest))
+			ctx.Abort()
+			return
+		}
+
+		if !validateSub(duplicate.UserID, ctx) {
+			return
+		}
+
+		ctx.Set(models.WorkflowDuplicateKey, duplicate)
+		ctx.Next()
+	}
+}
+
+func ValidateOnLifecycleWorkflow() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var lifecycle models.RequestLifecycleWorkflow
+		if err := ctx.ShouldBindJSON(&lifecycle); err != nil {
+			ctx.JSON(http.StatusBadRequest, NewInvalidRequestError(models.InvalidJSON, http.StatusBadRequest))
+			ctx.Abort()
+			return
+		}
+
+		if !validateSub(lifecycle.UserID, ctx) {
+			return
+		}
+
+		ctx.Set(models.WorkflowLifecycleKey, lifecycle)
+		ctx.Next()
+	}
+}
+
+func ValidateOnGetActionResult() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var query models.ActionResultQuery
+		if err := ctx.ShouldBindQuery(&query); err != nil {
+			ctx.JSON(http.StatusBadRequest, NewInvalidRequestError(models.ActionResultInvalid, http.StatusBadRequest))
+			ctx.Abort()
+			return
+		}
+
+		ctx.Set(models.ActionResultKey, query)
+		ctx.Next()
+	}
+}
+
+func ValidateOnListDeadLetters() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var query models.DeadLetterQuery
+		if err := ctx.ShouldBindQuery(&query); err != nil {
+			ctx.JSON(http.StatusBadRequest, NewInvalidRequestError(models.DeadLetterListInvalid, http.StatusBadRequest))
+			ctx.Abort()
+			return
+		}
+
+		ctx.Set(models.DeadLetterListKey, query)
+		ctx.Next()
+	}
+}
+
+func ValidateOnCreateRun() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var run models.RequestCreateRun
+		if err := ctx.ShouldBindJSON(&run); err != nil {
+			ctx.JSON(http.StatusBadRequest, NewInvalidRequestError(models.InvalidJSON, http.StatusBadRequest))
+			ctx.Abort()
+			return
+		}
+
+		if !validateSub(run.UserID, ctx) {
+			return
+		}
+
+		ctx.Set(models.RunCreateKey, run)
+		ctx.Next()
+	}
+}
+
+func ValidateOnReconcileIndexes() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var reconcile models.RequestReconcileIndexes
+		if err := ctx.ShouldBindJSON(&reconcile); err != nil {
+			ctx.JSON(http.StatusBadRequest, NewInvalidRequestError(models.InvalidJSON, http.StatusBadRequest))
+			ctx.Abort()
+			return
+		}
+
+		ctx.Set(models.WorkflowReconcileKey, reconcile)
+		ctx.Next()
+	}
+}
+
+// ValidateOnImportWorkflow document can be sent as json or yaml, directory comes in query because document is portable
+func ValidateOnImportWorkflow() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		body, err := ctx.GetRawData()
+		if err == nil && strings.Contains(ctx.ContentType(), models.FormatYAML) {
+			body, err = common.YAMLToJSON(body)
+		}
+
+		var document models.WorkflowDocument
+		if err == nil {
+			err = json.Unmarshal(body, &document)
+		}
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, NewInvalidRequestError(models.InvalidJSON, http.StatusBadRequest))
+			ctx.Abort()
+			return
+		}
+
+		userID := ctx.Param("iduser")
+		if !validateSub(userID, ctx) {
+			return
+		}
+
+		if name := ctx.Query("name"); name != "" {
+			document.Name = name
+		}
+		if !validateWorkflowName(document.Name, ctx) {
+			return
+		}
+
+		directoryToSave := ctx.Query("directory_to_save")
+		if !validateDirectoryToSave(directoryToSave, ctx) {
+			return
+		}
+
+		ctx.Set(models.WorkflowImportKey, models.RequestImportWorkflow{
+			Document:        document,
+			UserID:          userID,
+			DirectoryToSave: directoryToSave,
+		})
+		ctx.Next()
+	}
+}
+
+func ValidateOnPublishTemplate() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var request models.RequestPublishTemplate
+		if err := ctx.ShouldBindJSON(&request); err != nil {
+			ctx.JSON(http.StatusBadRequest, NewInvalidRequestError(models.InvalidJSON, http.StatusBadRequest))
+			ctx.Abort()
+			return
+		}
+
+		// only workflows of the user of the token can be published
+		userID := ctx.Param("iduser")
+		if request.UserID != "" && request.UserID != userID {
+			ctx.JSON(http.StatusBadRequest, NewInvalidRequestError(models.UUIDInvalid, http.StatusBadRequest))
+			ctx.Abort()
+			return
+		}
+		request.UserID = userID
+		if !validateSub(request.UserID, ctx) {
+			return
+		}
+
+		if !validateWorkflowName(request.Name, ctx) {
+			return
+		}
+
+		ctx.Set(models.TemplatePublishKey, request)
+		ctx.Next()
+	}
+}
+
+func ValidateOnCreateFromTemplate() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var request models.RequestCreateFromTemplate
+		if err := ctx.ShouldBindJSON(&request); err != nil {
+			ctx.JSON(http.StatusBadRequest, NewInvalidRequestError(models.InvalidJSON, http.StatusBadRequest))
+			ctx.Abort()
+			return
+		}
+
+		if !validateSub(request.UserID, ctx) {
+			return
+		}
+
+		if !validateWorkflowName(request.Name, ctx) {
+			return
+		}
+
+		if !validateDirectoryToSave(request.DirectoryToSave, ctx) {
+			return
+		}
+
+		ctx.Set(models.TemplateCreateFromKey, request)
+		ctx.Next()
+	}
+}
+
 func ValidateUser() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		var currentUser models.SyncUserRequest
@@ -112,12 +383,116 @@ func ValidateUser() gin.HandlerFunc {
 	}
 }
 
+func ValidateOnCreateFolder() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var request models.RequestCreateFolder
+		if err := ctx.ShouldBindJSON(&request); err != nil {
+			ctx.JSON(http.StatusBadRequest, NewInvalidRequestError(models.InvalidJSON, http.StatusBadRequest))
+			ctx.Abort()
+			return
+		}
+		if !validateUserOfPath(&request.UserID, ctx) {
+			return
+		}
+
+		ctx.Set(models.FolderCreateKey, request)
+		ctx.Next()
+	}
+}
+
+func ValidateOnRenameFolder() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var request models.RequestRenameFolder
+		if err := ctx.ShouldBindJSON(&request); err != nil {
+			ctx.JSON(http.StatusBadRequest, NewInvalidRequestError(models.InvalidJSON, http.StatusBadRequest))
+			ctx.Abort()
+			return
+		}
+		if !validateUserOfPath(&request.UserID, ctx) {
+			return
+		}
+
+		ctx.Set(models.FolderRenameKey, request)
+		ctx.Next()
+	}
+}
+
+func ValidateOnMoveFolder() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var request models.RequestMoveFolder
+		if err := ctx.ShouldBindJSON(&request); err != nil {
+			ctx.JSON(http.StatusBadRequest, NewInvalidRequestError(models.InvalidJSON, http.StatusBadRequest))
+			ctx.Abort()
+			return
+		}
+		if !validateUserOfPath(&request.UserID, ctx) {
+			return
+		}
+
+		ctx.Set(models.FolderMoveKey, request)
+		ctx.Next()
+	}
+}
+
+func ValidateOnMoveWorkflows() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var request models.RequestMoveWorkflows
+		if err := ctx.ShouldBindJSON(&request); err != nil {
+			ctx.JSON(http.StatusBadRequest, NewInvalidRequestError(models.InvalidJSON, http.StatusBadRequest))
+			ctx.Abort()
+			return
+		}
+		if !validateUserOfPath(&request.UserID, ctx) {
+			return
+		}
+
+		ctx.Set(models.FolderMoveWorkflowsKey, request)
+		ctx.Next()
+	}
+}
+
 func ValidateUserAuth() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		ctx.Next()
 	}
 }
 
+func ValidateOnCreateSchedule() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var schedule models.RequestCreateSchedule
+		if err := ctx.ShouldBindJSON(&schedule); err != nil {
+			ctx.JSON(http.StatusBadRequest, NewInvalidRequestError(models.InvalidJSON, http.StatusBadRequest))
+			ctx.Abort()
+			return
+		}
+
+		if !validateSub(schedule.UserID, ctx) {
+			return
+		}
+
+		ctx.Set(models.ScheduleCreateKey, schedule)
+		ctx.Next()
+	}
+}
+
+func ValidateOnCreateWebhook() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var webhook models.RequestCreateWebhook
+		if err := ctx.ShouldBindJSON(&webhook); err != nil {
+			ctx.JSON(http.StatusBadRequest, NewInvalidRequestError(models.InvalidJSON, http.StatusBadRequest))
+			ctx.Abort()
+			return
+		}
+
+		if !validateSub(webhook.UserID, ctx) {
+			return
+		}
+
+		ctx.Set(models.WebhookCreateKey, webhook)
+		ctx.Next()
+	}
+}
+
 func ValidateOnCreateCredential() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		var currentReq models.RequestCreateCredential
@@ -3,6 +3,7 @@ package middlewares
 import (
 	"minireipaz/pkg/domain/models"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -17,6 +18,18 @@ func validateSub(sub string, ctx *gin.Context) bool {
 	return true
 }
 
+// validateUserOfPath the token verified is the one of the user in the path, the user in the body must match it
+func validateUserOfPath(userID *string, ctx *gin.Context) bool {
+	pathUserID := ctx.Param("iduser")
+	if *userID != "" && *userID != pathUserID {
+		ctx.JSON(http.StatusBadRequest, NewInvalidRequestError(models.UUIDInvalid, http.StatusBadRequest))
+		ctx.Abort()
+		return false
+	}
+	*userID = pathUserID
+	return validateSub(*userID, ctx)
+}
+
 func validateWorkflowName(name string, ctx *gin.Context) bool {
 	if strings.TrimSpace(name) == "" || len(name) > 255 {
 		ctx.JSON(http.StatusBadRequest, NewInvalidRequestError(models.WorkflowNameInvalid, http.StatusBadRequest))
@@ -53,6 +66,32 @@ func validateDates(createdAt, updatedAt string, ctx *gin.Context) bool {
 	return true
 }
 
+// validateIfMatch a save without version would silently overwrite concurrent edits, * is the explicit overwrite
+func validateIfMatch(ifMatch string, version *uint32, ctx *gin.Context) bool {
+	if ifMatch == "" {
+		if *version == 0 {
+			ctx.JSON(http.StatusPreconditionRequired, NewInvalidRequestError(models.WorkflowVersionRequired, http.StatusPreconditionRequired))
+			ctx.Abort()
+			return false
+		}
+		return true
+	}
+	if strings.TrimSpace(ifMatch) == "*" {
+		*version = models.AnyVersion
+		return true
+	}
+
+	etag := strings.Trim(strings.TrimPrefix(strings.TrimSpace(ifMatch), "W/"), "\"")
+	parsed, err := strconv.ParseUint(etag, 10, 32)
+	if err != nil || parsed == 0 {
+		ctx.JSON(http.StatusBadRequest, NewInvalidRequestError(models.WorkflowRevisionInvalid, http.StatusBadRequest))
+		ctx.Abort()
+		return false
+	}
+	*version = uint32(parsed)
+	return true
+}
+
 func validateAccessToken(token string, ctx *gin.Context) bool {
 	if token != "" && (len(token) > 1000 || len(token) < 100) {
 		ctx.JSON(http.StatusBadRequest, NewInvalidRequestError(models.UserAccessTokenInvalid, http.StatusBadRequest))
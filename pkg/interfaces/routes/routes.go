@@ -20,9 +20,33 @@ func Register(app *gin.Engine, dependencies *dimodel.Dependencies) {
 		workflows := api.Group("/workflows")
 		{
 			workflows.GET("/:iduser/workflow/:idworkflow/:usertoken", dependencies.AuthController.VerifyUserTokenForMiddleware, middlewares.ValidateOnGetWorkflow(), dependencies.WorkflowController.GetWorkflow)
-			workflows.GET("/:iduser/:usertoken", dependencies.AuthController.VerifyUserTokenForMiddleware, middlewares.ValidateOnGetWorkflow(), dependencies.WorkflowController.GetAllWorkflows)
+			workflows.GET("/:iduser/:usertoken", dependencies.AuthController.VerifyUserTokenForMiddleware, middlewares.ValidateOnListWorkflows(), dependencies.WorkflowController.GetAllWorkflows)
 			workflows.POST("", middlewares.ValidateOnCreateWorkflow(), dependencies.WorkflowController.CreateWorkflow)
+			workflows.POST("/validate", middlewares.ValidateOnGraphWorkflow(), dependencies.WorkflowController.ValidateWorkflow)
+			workflows.POST("/reconcile", middlewares.RequireAdmin(dependencies.AdminToken), middlewares.ValidateOnReconcileIndexes(), dependencies.WorkflowController.ReconcileIndexes)
 			workflows.PUT("/:id", middlewares.ValidateOnUpdateWorkflow(), dependencies.WorkflowController.UpdateWorkflow)
+			workflows.GET("/:iduser/workflow/:idworkflow/revisions/:usertoken", dependencies.AuthController.VerifyUserTokenForMiddleware, middlewares.ValidateOnGetWorkflow(), dependencies.WorkflowController.GetWorkflowRevisions)
+			workflows.GET("/:iduser/workflow/:idworkflow/revision/:version/:usertoken", dependencies.AuthController.VerifyUserTokenForMiddleware, middlewares.ValidateOnGetWorkflow(), dependencies.WorkflowController.GetWorkflowRevision)
+			workflows.GET("/:iduser/workflow/:idworkflow/diff/:fromversion/:toversion/:usertoken", dependencies.AuthController.VerifyUserTokenForMiddleware, middlewares.ValidateOnGetWorkflow(), dependencies.WorkflowController.DiffWorkflowRevisions)
+			workflows.POST("/rollback/:iduser/:idworkflow/:usertoken", dependencies.AuthController.VerifyUserTokenForMiddleware, middlewares.ValidateOnRollbackWorkflow(), dependencies.WorkflowController.RollbackWorkflow)
+			workflows.POST("/:id/duplicate", middlewares.ValidateOnDuplicateWorkflow(), dependencies.WorkflowController.DuplicateWorkflow)
+			workflows.POST("/:id/activate", middlewares.ValidateOnLifecycleWorkflow(), dependencies.WorkflowController.ActivateWorkflow)
+			workflows.POST("/:id/pause", middlewares.ValidateOnLifecycleWorkflow(), dependencies.WorkflowController.PauseWorkflow)
+			workflows.POST("/:id/resume", middlewares.ValidateOnLifecycleWorkflow(), dependencies.WorkflowController.ResumeWorkflow)
+			workflows.POST("/:id/draft", middlewares.ValidateOnLifecycleWorkflow(), dependencies.WorkflowController.DraftWorkflow)
+			workflows.POST("/:id/runs", middlewares.ValidateOnCreateRun(), dependencies.RunController.CreateRun)
+			workflows.GET("/:iduser/workflow/:idworkflow/runs/:usertoken", dependencies.AuthController.VerifyUserTokenForMiddleware, middlewares.ValidateOnGetWorkflow(), middlewares.ValidateOnListRuns(), dependencies.RunController.GetWorkflowRuns)
+			workflows.GET("/:iduser/workflow/:idworkflow/export/:usertoken", dependencies.AuthController.VerifyUserTokenForMiddleware, middlewares.ValidateOnGetWorkflow(), dependencies.WorkflowController.ExportWorkflow)
+			workflows.POST("/import/:iduser/:usertoken", dependencies.AuthController.VerifyUserTokenForMiddleware, middlewares.ValidateOnImportWorkflow(), dependencies.WorkflowController.ImportWorkflow)
+			workflows.POST("/from-template/:idtemplate", middlewares.ValidateOnCreateFromTemplate(), dependencies.TemplateController.CreateWorkflowFromTemplate)
+			workflows.DELETE("/:iduser/workflow/:idworkflow/:usertoken", dependencies.AuthController.VerifyUserTokenForMiddleware, middlewares.ValidateOnGetWorkflow(), dependencies.WorkflowController.DeleteWorkflow)
+		}
+
+		trash := api.Group("/trash")
+		{
+			trash.GET("/:iduser/:usertoken", dependencies.AuthController.VerifyUserTokenForMiddleware, middlewares.ValidateOnGetWorkflow(), dependencies.WorkflowController.GetWorkflowsTrash)
+			trash.POST("/:iduser/:idworkflow/restore/:usertoken", dependencies.AuthController.VerifyUserTokenForMiddleware, middlewares.ValidateOnGetWorkflow(), dependencies.WorkflowController.RestoreWorkflow)
+			trash.DELETE("/:iduser/:idworkflow/:usertoken", dependencies.AuthController.VerifyUserTokenForMiddleware, middlewares.ValidateOnGetWorkflow(), dependencies.WorkflowController.PurgeWorkflow)
 		}
 
 		users := api.Group("/users")
@@ -35,6 +59,7 @@ func Register(app *gin.Engine, dependencies *dimodel.Dependencies) {
 		{
 			// credentials.POST("", middlewares.ValidateUser(), userController.SyncUseWrithIDProvider)
 			credentials.GET("/:iduser/:usertoken", dependencies.AuthController.VerifyUserTokenForMiddleware, dependencies.CredentialController.GetAllCredentials)
+			credentials.POST("/:iduser/:idcredential/refresh/:usertoken", dependencies.AuthController.VerifyUserTokenForMiddleware, dependencies.CredentialController.RefreshCredential)
 		}
 
 		dashboard := api.Group("/dashboard")
@@ -47,10 +72,17 @@ func Register(app *gin.Engine, dependencies *dimodel.Dependencies) {
 			auth.GET("/verify/:usertoken", dependencies.AuthController.VerifyUserToken)
 		}
 
+		credentialsOAuth := api.Group("/oauth")
+		{
+			credentialsOAuth.POST("/credential", middlewares.ValidateOnCreateCredential(), dependencies.CredentialController.CreateCredential)
+			credentialsOAuth.POST("/exchange", middlewares.ValidateOnExchangeCredential(), dependencies.CredentialController.ExchangeOAuthCode)
+		}
+
+		// same handlers as /oauth, kept for clients built when only google was supported
 		credentialsGoogle := api.Group("/google")
 		{
 			credentialsGoogle.POST("/credential", middlewares.ValidateOnCreateCredential(), dependencies.CredentialController.CreateCredential)
-			credentialsGoogle.POST("/exchange", middlewares.ValidateOnExchangeCredential(), dependencies.CredentialController.ExchangeGoogleCode)
+			credentialsGoogle.POST("/exchange", middlewares.ValidateOnExchangeCredential(), dependencies.CredentialController.ExchangeOAuthCode)
 		}
 
 		credentialsTokens := api.Group("/tokens")
@@ -58,14 +90,74 @@ func Register(app *gin.Engine, dependencies *dimodel.Dependencies) {
 			credentialsTokens.POST("/credential", middlewares.ValidateOnCreateCredential(), dependencies.CredentialController.CreateTokenCredential)
 		}
 
+		folders := api.Group("/folders")
+		{
+			folders.GET("/:iduser/:usertoken", dependencies.AuthController.VerifyUserTokenForMiddleware, dependencies.FolderController.GetFolderTree)
+			folders.POST("/:iduser/:usertoken", dependencies.AuthController.VerifyUserTokenForMiddleware, middlewares.ValidateOnCreateFolder(), dependencies.FolderController.CreateFolder)
+			folders.POST("/rename/:iduser/:usertoken", dependencies.AuthController.VerifyUserTokenForMiddleware, middlewares.ValidateOnRenameFolder(), dependencies.FolderController.RenameFolder)
+			folders.POST("/move/:iduser/:usertoken", dependencies.AuthController.VerifyUserTokenForMiddleware, middlewares.ValidateOnMoveFolder(), dependencies.FolderController.MoveFolder)
+			folders.POST("/move-workflows/:iduser/:usertoken", dependencies.AuthController.VerifyUserTokenForMiddleware, middlewares.ValidateOnMoveWorkflows(), dependencies.FolderController.MoveWorkflows)
+		}
+
+		schedules := api.Group("/schedules")
+		{
+			schedules.GET("/:iduser/workflow/:idworkflow/:usertoken", dependencies.AuthController.VerifyUserTokenForMiddleware, dependencies.SchedulerController.GetSchedules)
+			schedules.POST("", middlewares.ValidateOnCreateSchedule(), dependencies.SchedulerController.SaveSchedule)
+			schedules.DELETE("/:iduser/workflow/:idworkflow/node/:idnode/:usertoken", dependencies.AuthController.VerifyUserTokenForMiddleware, dependencies.SchedulerController.RemoveSchedule)
+		}
+
+		// operators only, letters hold the payloads of every user
+		deadLetters := api.Group("/deadletters", middlewares.RequireAdmin(dependencies.AdminToken))
+		{
+			deadLetters.GET("", middlewares.ValidateOnListDeadLetters(), dependencies.DeadLetterController.GetDeadLetters)
+			deadLetters.GET("/:id", dependencies.DeadLetterController.GetDeadLetter)
+			deadLetters.POST("/:id/replay", dependencies.DeadLetterController.ReplayDeadLetter)
+			deadLetters.DELETE("/:id", dependencies.DeadLetterController.DiscardDeadLetter)
+		}
+
+		status := api.Group("/status")
+		{
+			status.GET("/:iduser/:usertoken", dependencies.AuthController.VerifyUserTokenForMiddleware, dependencies.StatusController.StreamStatus)
+		}
+
+		runs := api.Group("/runs")
+		{
+			runs.GET("/:iduser/run/:idrun/:usertoken", dependencies.AuthController.VerifyUserTokenForMiddleware, dependencies.RunController.GetRun)
+		}
+
+		webhooks := api.Group("/webhooks")
+		{
+			webhooks.GET("/:iduser/workflow/:idworkflow/:usertoken", dependencies.AuthController.VerifyUserTokenForMiddleware, dependencies.WebhookController.GetWebhooks)
+			webhooks.POST("", middlewares.ValidateOnCreateWebhook(), dependencies.WebhookController.CreateWebhook)
+			webhooks.DELETE("/:iduser/workflow/:idworkflow/node/:idnode/:usertoken", dependencies.AuthController.VerifyUserTokenForMiddleware, dependencies.WebhookController.RemoveWebhook)
+		}
+
+		// public ingestion, skipped by the auth middleware
+		hooks := api.Group("/hooks")
+		{
+			hooks.POST("/:token", dependencies.WebhookController.ReceiveWebhook)
+		}
+
+		templates := api.Group("/templates")
+		{
+			templates.GET("", dependencies.TemplateController.GetTemplates)
+			templates.GET("/:idtemplate", dependencies.TemplateController.GetTemplate)
+			templates.POST("/:iduser/:usertoken", dependencies.AuthController.VerifyUserTokenForMiddleware, middlewares.ValidateOnPublishTemplate(), dependencies.TemplateController.PublishTemplate)
+			templates.DELETE("/:iduser/:idtemplate/:usertoken", dependencies.AuthController.VerifyUserTokenForMiddleware, dependencies.TemplateController.DeleteTemplate)
+		}
+
+		nodes := api.Group("/nodes")
+		{
+			nodes.GET("/catalog", dependencies.NodesController.GetCatalog)
+		}
+
 		actions := api.Group("/actions")
 		{
 			actions.POST("/google/sheets", middlewares.ValidateGetGoogleSheet(), dependencies.ActionsController.CreateActionsGoogleSheet)
 			actions.POST("/notion", middlewares.ValidateNotionFields(), dependencies.ActionsController.CreateActionsNotion)
 
-			// polling from client
-			// maybe needs to move to another service
-			// actions.GET("/google/sheets/:iduser/:idaction", middlewares.ValidateGetGoogleSheet(), dependencies.ActionsController.GetGoogleSheetByID)
+			// polling from client, wait in query keeps the request open until the action finishes
+			actions.GET("/:iduser/:idaction/:usertoken", dependencies.AuthController.VerifyUserTokenForMiddleware, middlewares.ValidateOnGetActionResult(), dependencies.ActionsController.GetActionResult)
 		}
 	}
 }
@@ -0,0 +1,87 @@
+package tests
+
+import (
+	"context"
+	"minireipaz/mocks"
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/domain/services"
+	"minireipaz/pkg/infra/memoryclient"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestActionsService_GetActionResultWaitsUntilFinished(t *testing.T) {
+	userID := "user_1"
+	actionID := "action_1"
+	redisRepo := mocks.NewActionsRedisRepoInterface(t)
+	httpRepo := mocks.NewActionsHTTPRepository(t)
+	redisRepo.On("GetActionOwner", &actionID).Return(userID, nil)
+	httpRepo.On("GetActionByID", &userID, &actionID).Return(&models.InfoActions{}, nil).Once()
+	httpRepo.On("GetActionByID", &userID, &actionID).Return(&models.InfoActions{Data: []models.ActionResult{
+		{ActionID: actionID, UserID: userID, Status: models.ActionStatusCompleted, Data: `{"rows":2}`},
+	}}, nil).Once()
+
+	service := services.NewActionsService(redisRepo, mocks.NewActionsBrokerRepository(t), httpRepo, mocks.NewSchedulerService(t), memoryclient.NewStatusPublisher(), nil, nil, nil)
+	result, err := service.GetActionResult(context.Background(), &userID, &actionID, 5*time.Second)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.ActionStatusCompleted, result.Status)
+	assert.Equal(t, `{"rows":2}`, result.Data)
+}
+
+func TestActionsService_GetActionResultWithoutWait(t *testing.T) {
+	userID := "user_1"
+	actionID := "action_1"
+	redisRepo := mocks.NewActionsRedisRepoInterface(t)
+	httpRepo := mocks.NewActionsHTTPRepository(t)
+	redisRepo.On("GetActionOwner", &actionID).Return(userID, nil)
+	httpRepo.On("GetActionByID", &userID, &actionID).Return(&models.InfoActions{}, nil).Once()
+
+	service := services.NewActionsService(redisRepo, mocks.NewActionsBrokerRepository(t), httpRepo, mocks.NewSchedulerService(t), memoryclient.NewStatusPublisher(), nil, nil, nil)
+	result, err := service.GetActionResult(context.Background(), &userID, &actionID, 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.ActionStatusPending, result.Status)
+}
+
+func TestActionsService_GetActionResultOtherUser(t *testing.T) {
+	userID := "user_2"
+	actionID := "action_1"
+	redisRepo := mocks.NewActionsRedisRepoInterface(t)
+	redisRepo.On("GetActionOwner", &actionID).Return("user_1", nil)
+
+	service := services.NewActionsService(redisRepo, mocks.NewActionsBrokerRepository(t), mocks.NewActionsHTTPRepository(t), mocks.NewSchedulerService(t), memoryclient.NewStatusPublisher(), nil, nil, nil)
+	_, err := service.GetActionResult(context.Background(), &userID, &actionID, time.Second)
+
+	assert.ErrorIs(t, err, models.ErrActionNotFound)
+}
+
+func TestActionsService_CreateScheduledAction(t *testing.T) {
+	token := "service_token"
+	action := models.RequestGoogleAction{Type: models.GoogleSheets, Pollmode: "5m", Sub: "user_1", WorkflowID: "wf_1", NodeID: "sheet"}
+	message := &models.OutboxMessage{Key: "wf_1"}
+
+	redisRepo := mocks.NewActionsRedisRepoInterface(t)
+	brokerRepo := mocks.NewActionsBrokerRepository(t)
+	httpRepo := mocks.NewActionsHTTPRepository(t)
+	authService := mocks.NewAuthService(t)
+	scheduler := mocks.NewSchedulerService(t)
+	authService.On("GetActionUserAccessToken").Return(&token, nil)
+	redisRepo.On("AcquireLock", mock.Anything, "1", models.MaxTimeForLocks).Return(true, nil)
+	redisRepo.On("RemoveLock", mock.Anything).Return(true)
+	brokerRepo.On("CreateCommand", mock.Anything).Return(message, nil)
+	// owner and outbox command are written together, like the actions of the users
+	redisRepo.On("Create", mock.MatchedBy(func(newAction *models.RequestGoogleAction) bool { return newAction.ActionID != "" }), message).Return(true, false, nil)
+	httpRepo.On("SendAction", mock.Anything, &token).Return(true)
+
+	service := services.NewActionsService(redisRepo, brokerRepo, httpRepo, scheduler, memoryclient.NewStatusPublisher(), authService, nil, nil)
+	actionID, err := service.CreateScheduledAction(&action)
+
+	assert.NoError(t, err)
+	assert.Equal(t, action.ActionID, *actionID)
+	// a run of the schedule does not schedule it again
+	scheduler.AssertNotCalled(t, "ScheduleAction", mock.Anything)
+}
@@ -0,0 +1,53 @@
+package tests
+
+import (
+	"encoding/json"
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/infra/brokerclient"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type capturingKafkaClient struct {
+	topic string
+	value []byte
+}
+
+func (c *capturingKafkaClient) Produce(topic string, _ []byte, value []byte) error {
+	c.topic, c.value = topic, value
+	return nil
+}
+
+func (c *capturingKafkaClient) ProduceWithHeaders(topic string, key []byte, value []byte, _ map[string]string) error {
+	return c.Produce(topic, key, value)
+}
+
+func (c *capturingKafkaClient) Close() {}
+
+func TestCredentialKafkaRepository_PublishesEncryptedSecrets(t *testing.T) {
+	client := &capturingKafkaClient{}
+	secrets := newSecretService(t, "key_1:"+testKey(1), "")
+	repo := brokerclient.NewCredentialKafkaRepository(client, secrets)
+	credential := &models.RequestExchangeCredential{ID: "credential_1", Data: models.DataCredential{ClientID: "client_1", ClientSecret: "secret_1"}}
+	token, refresh, expire := "access_1", "refresh_1", time.Now()
+
+	assert.True(t, repo.CreateCredential(&token, &refresh, &expire, credential))
+	assert.Equal(t, "credentials.command", client.topic)
+	for _, secret := range []string{"secret_1", "access_1", "refresh_1"} {
+		assert.NotContains(t, string(client.value), secret)
+	}
+	// the caller keeps the plaintext
+	assert.Equal(t, "access_1", credential.Data.Token)
+
+	var command brokerclient.CredentialCommand
+	assert.NoError(t, json.Unmarshal(client.value, &command))
+	// the data string is decoded into the embedded credential
+	data := command.Credential.RequestExchangeCredential.Data
+	assert.Equal(t, "client_1", data.ClientID)
+	assert.True(t, strings.HasPrefix(data.Token, models.SecretPrefix))
+	assert.NoError(t, secrets.DecryptCredential(&data))
+	assert.Equal(t, "refresh_1", data.TokenRefresh)
+}
@@ -0,0 +1,177 @@
+package tests
+
+import (
+	"errors"
+	"minireipaz/mocks"
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/domain/repos"
+	"minireipaz/pkg/domain/services"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type refreshMocks struct {
+	oauthRepo  *mocks.CredentialOAuthHTTPRepository
+	redisRepo  *mocks.CredentialRefreshRedisRepository
+	brokerRepo *mocks.CredentialBrokerRepository
+	httpRepo   *mocks.CredentialHTTPRepository
+	secrets    repos.SecretService
+	t          *testing.T
+}
+
+func newRefreshMocks(t *testing.T) *refreshMocks {
+	return &refreshMocks{
+		oauthRepo:  mocks.NewCredentialOAuthHTTPRepository(t),
+		redisRepo:  mocks.NewCredentialRefreshRedisRepository(t),
+		brokerRepo: mocks.NewCredentialBrokerRepository(t),
+		httpRepo:   mocks.NewCredentialHTTPRepository(t),
+		secrets:    newSecretService(t, "key_1:"+testKey(1), ""),
+		t:          t,
+	}
+}
+
+func (m *refreshMocks) service() repos.CredentialRefreshService {
+	registry := services.NewOAuthProviderRegistry(models.DefaultOAuthProviders(), "")
+	return services.NewCredentialRefreshService(m.oauthRepo, registry, m.redisRepo, m.brokerRepo, m.httpRepo, m.secrets, nil)
+}
+
+func (m *refreshMocks) withCredential(expiresAt time.Time) *models.RequestExchangeCredential {
+	credential := models.RequestExchangeCredential{
+		ID:        "credential_1",
+		Sub:       "user_1",
+		Type:      models.GoogleSheets,
+		ExpiresAt: &models.CustomTime{Time: expiresAt},
+		IsActive:  true,
+		Data:      models.DataCredential{Token: "access_1", TokenRefresh: "refresh_1", ClientSecret: "secret_1"},
+	}
+	// as saved, the service only sees the secrets once it decrypts them
+	assert.NoError(m.t, m.secrets.EncryptCredential(&credential.Data))
+	m.httpRepo.On("GetCredentialByID", strPtr("user_1"), strPtr("credential_1"), uint64(1)).Return(&[]models.RequestExchangeCredential{credential}, nil)
+	return &credential
+}
+
+func TestCredentialRefreshService_KeepsValidToken(t *testing.T) {
+	m := newRefreshMocks(t)
+	m.withCredential(time.Now().Add(time.Hour))
+
+	credential, refreshed, err := m.service().RefreshCredential(strPtr("user_1"), strPtr("credential_1"))
+	assert.NoError(t, err)
+	assert.False(t, refreshed)
+	assert.Equal(t, "access_1", credential.Data.Token)
+	m.oauthRepo.AssertNotCalled(t, "RefreshToken", mock.Anything, mock.Anything)
+}
+
+func TestCredentialRefreshService_RefreshesExpiringToken(t *testing.T) {
+	m := newRefreshMocks(t)
+	m.withCredential(time.Now().Add(time.Minute))
+	expiry := time.Now().Add(time.Hour).UTC()
+	m.redisRepo.On("LockRefresh", strPtr("credential_1"), models.CredentialRefreshLockTTL).Return(true, nil)
+	// google does not send a new refresh token, the previous one is kept
+	m.oauthRepo.On("RefreshToken", mock.MatchedBy(func(provider *models.OAuthProvider) bool { return provider.Name == "google" }), mock.MatchedBy(func(credential *models.RequestExchangeCredential) bool {
+		return credential.Data.TokenRefresh == "refresh_1" && credential.Data.ClientSecret == "secret_1"
+	})).
+		Return(&models.OAuthToken{AccessToken: "access_2", Expiry: expiry}, nil)
+	m.brokerRepo.On("CreateCredential", strPtr("access_2"), strPtr("refresh_1"), &expiry, mock.MatchedBy(func(credential *models.RequestExchangeCredential) bool {
+		return !credential.CredentialCreatedNew && credential.IsActive && credential.Data.Token == "access_2" && credential.ExpiresAt.Time.Equal(expiry)
+	})).Return(true)
+	m.redisRepo.On("ScheduleRefresh", &models.CredentialRefreshEntry{Sub: "user_1", ID: "credential_1"}, expiry.Add(-models.CredentialRefreshAhead)).Return(nil)
+
+	credential, refreshed, err := m.service().RefreshCredential(strPtr("user_1"), strPtr("credential_1"))
+	assert.NoError(t, err)
+	assert.True(t, refreshed)
+	assert.Equal(t, "access_2", credential.Data.Token)
+	m.redisRepo.AssertNotCalled(t, "UnlockRefresh", mock.Anything)
+}
+
+func TestCredentialRefreshService_RejectedMarksInactive(t *testing.T) {
+	m := newRefreshMocks(t)
+	m.withCredential(time.Now().Add(-time.Minute))
+	m.redisRepo.On("LockRefresh", strPtr("credential_1"), models.CredentialRefreshLockTTL).Return(true, nil)
+	m.oauthRepo.On("RefreshToken", mock.Anything, mock.Anything).Return(nil, models.ErrOAuthRefreshRejected).Once()
+	m.brokerRepo.On("CreateCredential", strPtr("access_1"), strPtr("refresh_1"), mock.Anything, mock.MatchedBy(func(credential *models.RequestExchangeCredential) bool {
+		return !credential.IsActive && credential.RevokedAt != nil
+	})).Return(true)
+	m.redisRepo.On("RemoveRefresh", &models.CredentialRefreshEntry{Sub: "user_1", ID: "credential_1"}).Return(nil)
+
+	_, _, err := m.service().RefreshCredential(strPtr("user_1"), strPtr("credential_1"))
+	assert.ErrorIs(t, err, models.ErrOAuthRefreshRejected)
+}
+
+func TestCredentialRefreshService_RejectedClientKeepsCredential(t *testing.T) {
+	m := newRefreshMocks(t)
+	m.withCredential(time.Now().Add(-time.Minute))
+	m.redisRepo.On("LockRefresh", strPtr("credential_1"), models.CredentialRefreshLockTTL).Return(true, nil)
+	m.oauthRepo.On("RefreshToken", mock.Anything, mock.Anything).Return(nil, models.ErrOAuthClientRejected).Once()
+	m.redisRepo.On("UnlockRefresh", strPtr("credential_1")).Return(nil)
+
+	_, _, err := m.service().RefreshCredential(strPtr("user_1"), strPtr("credential_1"))
+	assert.ErrorIs(t, err, models.ErrOAuthClientRejected)
+	m.brokerRepo.AssertNotCalled(t, "CreateCredential", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	m.redisRepo.AssertNotCalled(t, "RemoveRefresh", mock.Anything)
+}
+
+func TestCredentialRefreshService_InProgress(t *testing.T) {
+	m := newRefreshMocks(t)
+	m.withCredential(time.Now().Add(time.Minute))
+	m.redisRepo.On("LockRefresh", strPtr("credential_1"), models.CredentialRefreshLockTTL).Return(false, nil)
+
+	_, _, err := m.service().RefreshCredential(strPtr("user_1"), strPtr("credential_1"))
+	assert.ErrorIs(t, err, models.ErrOAuthRefreshInProgress)
+}
+
+func TestCredentialRefreshService_TickRemovesMissingCredentials(t *testing.T) {
+	m := newRefreshMocks(t)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	missing := models.CredentialRefreshEntry{Sub: "user_1", ID: "credential_2"}
+	broken := models.CredentialRefreshEntry{Sub: "user_1", ID: "credential_3"}
+	m.redisRepo.On("AcquireLeadership", mock.Anything, models.CredentialRefreshLeaderTTL).Return(true, nil)
+	m.redisRepo.On("GetDueRefreshes", now, int64(models.MaxCredentialRefreshPerTick)).Return([]models.CredentialRefreshEntry{missing, broken}, nil)
+	m.httpRepo.On("GetCredentialByID", strPtr("user_1"), strPtr("credential_2"), uint64(1)).Return(&[]models.RequestExchangeCredential{}, nil)
+	m.httpRepo.On("GetCredentialByID", strPtr("user_1"), strPtr("credential_3"), uint64(1)).Return(nil, errors.New("clickhouse down"))
+	m.redisRepo.On("RemoveRefresh", &missing).Return(nil)
+
+	refreshed, err := m.service().Tick(now)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, refreshed)
+	// kept for the next tick
+	m.redisRepo.AssertNotCalled(t, "RemoveRefresh", &broken)
+}
+
+func TestCredentialRefreshService_TickOnlyLeaderRefreshes(t *testing.T) {
+	m := newRefreshMocks(t)
+	m.redisRepo.On("AcquireLeadership", mock.Anything, models.CredentialRefreshLeaderTTL).Return(false, nil)
+
+	refreshed, err := m.service().Tick(time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, refreshed)
+}
+
+func TestCredentialRefreshService_DeadLetterIsEncrypted(t *testing.T) {
+	m := newRefreshMocks(t)
+	m.withCredential(time.Now().Add(-time.Minute))
+	m.redisRepo.On("LockRefresh", strPtr("credential_1"), models.CredentialRefreshLockTTL).Return(true, nil)
+	m.oauthRepo.On("RefreshToken", mock.Anything, mock.Anything).Return(nil, models.ErrOAuthRefreshRejected).Once()
+	m.brokerRepo.On("CreateCredential", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(false)
+	m.redisRepo.On("RemoveRefresh", mock.Anything).Return(nil)
+	deadLetters := mocks.NewDeadLetterService(t)
+	var saved models.DeadLetterCredential
+	deadLetters.On("Add", models.DeadLetterOriginCredentialSave, mock.Anything, models.ErrOAuthRefreshRejected, models.DeadLetterRetriedAttempts).
+		Run(func(args mock.Arguments) { saved = args.Get(1).(models.DeadLetterCredential) }).Return()
+
+	registry := services.NewOAuthProviderRegistry(models.DefaultOAuthProviders(), "")
+	service := services.NewCredentialRefreshService(m.oauthRepo, registry, m.redisRepo, m.brokerRepo, m.httpRepo, m.secrets, deadLetters)
+	credential, _, err := service.RefreshCredential(strPtr("user_1"), strPtr("credential_1"))
+	assert.ErrorIs(t, err, models.ErrOAuthRefreshRejected)
+	assert.Nil(t, credential)
+
+	for _, value := range []string{saved.Token, saved.Refresh, saved.Credential.Data.Token, saved.Credential.Data.TokenRefresh, saved.Credential.Data.ClientSecret} {
+		assert.True(t, strings.HasPrefix(value, models.SecretPrefix))
+	}
+	plaintext, err := m.secrets.Decrypt(saved.Refresh)
+	assert.NoError(t, err)
+	assert.Equal(t, "refresh_1", plaintext)
+}
@@ -91,7 +91,7 @@ func TestCredentialController_CreateCredential(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockCredService := mocks.NewCredentialService(t)
 			tt.setupMocks(mockCredService)
-			controller := controllers.NewCredentialController(mockCredService, nil, nil)
+			controller := controllers.NewCredentialController(mockCredService, nil, nil, nil, nil)
 			w := httptest.NewRecorder()
 			ctx, _ := gin.CreateTestContext(w)
 			tt.setupContext(ctx)
@@ -105,3 +105,22 @@ func TestCredentialController_CreateCredential(t *testing.T) {
 		})
 	}
 }
+
+func TestCredentialController_ExchangeOAuthCodeInvalidState(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, stateErr := range []error{models.ErrOAuthStateInvalid, models.ErrOAuthVerifierMissing} {
+		mockCredService := mocks.NewCredentialService(t)
+		mockCredService.On("ExchangeOAuthCredential", mock.AnythingOfType("*models.RequestExchangeCredential")).
+			Return(nil, nil, nil, nil, stateErr)
+		controller := controllers.NewCredentialController(mockCredService, nil, nil, nil, nil)
+		w := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(w)
+		ctx.Set(models.CredentialExchangeContextKey, models.RequestExchangeCredential{Sub: "test-sub"})
+
+		controller.ExchangeOAuthCode(ctx)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, fmt.Sprintf(`{"error": "%s","status": 400}`, models.OAuthStateInvalid), w.Body.String())
+	}
+}
@@ -0,0 +1,139 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"minireipaz/mocks"
+	"minireipaz/pkg/dimodel"
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/domain/services"
+	"minireipaz/pkg/interfaces/routes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDeadLetterService_AddSavesAndPublishes(t *testing.T) {
+	redisRepo := mocks.NewDeadLetterRedisRepository(t)
+	brokerRepo := mocks.NewDeadLetterBrokerRepository(t)
+	workflow := &models.Workflow{UUID: "wf_1", UserID: "user_1"}
+	redisRepo.On("Save", mock.MatchedBy(func(letter *models.DeadLetter) bool {
+		var payload models.Workflow
+		return json.Unmarshal(letter.Payload, &payload) == nil &&
+			payload.UUID == "wf_1" &&
+			letter.Origin == models.DeadLetterOriginWorkflowCreate &&
+			letter.Error == "broker down" &&
+			letter.Attempts == models.DeadLetterRetriedAttempts
+	})).Return("1700000000000-0", nil)
+	brokerRepo.On("Publish", mock.MatchedBy(func(letter *models.DeadLetter) bool {
+		return letter.ID == "1700000000000-0"
+	})).Return(true)
+
+	service := services.NewDeadLetterService(redisRepo, brokerRepo)
+	service.Add(models.DeadLetterOriginWorkflowCreate, workflow, errors.New("broker down"), models.DeadLetterRetriedAttempts)
+}
+
+func TestDeadLetterService_ReplayRemovesOnSuccess(t *testing.T) {
+	id := "1700000000000-0"
+	letter := &models.DeadLetter{ID: id, Origin: models.DeadLetterOriginUserSync, Payload: json.RawMessage(`{}`)}
+	redisRepo := mocks.NewDeadLetterRedisRepository(t)
+	replayer := mocks.NewDeadLetterReplayer(t)
+	redisRepo.On("Get", &id).Return(letter, nil)
+	replayer.On("ReplayDeadLetter", letter).Return(nil)
+	redisRepo.On("Remove", &id).Return(nil)
+
+	service := services.NewDeadLetterService(redisRepo, mocks.NewDeadLetterBrokerRepository(t))
+	service.RegisterReplayer(models.DeadLetterOriginUserSync, replayer)
+	replayed, err := service.Replay(&id)
+	assert.NoError(t, err)
+	assert.Equal(t, letter, replayed)
+}
+
+func TestDeadLetterService_ReplayKeepsLetterOnFailure(t *testing.T) {
+	id := "1700000000000-0"
+	letter := &models.DeadLetter{ID: id, Origin: models.DeadLetterOriginUserSync}
+	redisRepo := mocks.NewDeadLetterRedisRepository(t)
+	replayer := mocks.NewDeadLetterReplayer(t)
+	redisRepo.On("Get", &id).Return(letter, nil)
+	replayer.On("ReplayDeadLetter", letter).Return(errors.New("clickhouse down"))
+
+	service := services.NewDeadLetterService(redisRepo, mocks.NewDeadLetterBrokerRepository(t))
+	service.RegisterReplayer(models.DeadLetterOriginUserSync, replayer)
+	_, err := service.Replay(&id)
+	assert.EqualError(t, err, "clickhouse down")
+	redisRepo.AssertNotCalled(t, "Remove", mock.Anything)
+}
+
+func TestDeadLetterService_ReplayUnknownOrigin(t *testing.T) {
+	id := "1700000000000-0"
+	redisRepo := mocks.NewDeadLetterRedisRepository(t)
+	redisRepo.On("Get", &id).Return(&models.DeadLetter{ID: id, Origin: "unknown"}, nil)
+
+	service := services.NewDeadLetterService(redisRepo, mocks.NewDeadLetterBrokerRepository(t))
+	_, err := service.Replay(&id)
+	assert.ErrorIs(t, err, models.ErrDeadLetterOriginUnknown)
+}
+
+func TestDeadLetterService_GetDeadLettersFiltersOrigin(t *testing.T) {
+	redisRepo := mocks.NewDeadLetterRedisRepository(t)
+	redisRepo.On("GetAll", int64(models.MaxDeadLettersPage)).Return([]models.DeadLetter{
+		{ID: "3-0", Origin: models.DeadLetterOriginActionCreate},
+		{ID: "2-0", Origin: models.DeadLetterOriginUserSync},
+		{ID: "1-0", Origin: models.DeadLetterOriginActionCreate},
+	}, nil)
+
+	service := services.NewDeadLetterService(redisRepo, mocks.NewDeadLetterBrokerRepository(t))
+	letters, err := service.GetDeadLetters(&models.DeadLetterQuery{Origin: models.DeadLetterOriginActionCreate, Limit: 1})
+	assert.NoError(t, err)
+	assert.Len(t, letters, 1)
+	assert.Equal(t, "3-0", letters[0].ID)
+}
+
+func TestDeadLetterService_GetDeadLetterRedactsCredentials(t *testing.T) {
+	id := "1700000000000-0"
+	payload, _ := json.Marshal(models.DeadLetterCredential{
+		Token:      "access_1",
+		Refresh:    "refresh_1",
+		Credential: &models.RequestExchangeCredential{ID: "credential_1", Data: models.DataCredential{ClientID: "client_1", ClientSecret: "secret_1", CodeVerifier: "verifier_1"}},
+	})
+	redisRepo := mocks.NewDeadLetterRedisRepository(t)
+	redisRepo.On("Get", &id).Return(&models.DeadLetter{ID: id, Origin: models.DeadLetterOriginCredentialSave, Payload: payload}, nil)
+
+	service := services.NewDeadLetterService(redisRepo, mocks.NewDeadLetterBrokerRepository(t))
+	letter, err := service.GetDeadLetter(&id)
+	assert.NoError(t, err)
+
+	var saved models.DeadLetterCredential
+	assert.NoError(t, json.Unmarshal(letter.Payload, &saved))
+	assert.Equal(t, models.DeadLetterRedacted, saved.Token)
+	assert.Equal(t, models.DeadLetterRedacted, saved.Refresh)
+	assert.Equal(t, models.DeadLetterRedacted, saved.Credential.Data.ClientSecret)
+	assert.Equal(t, models.DeadLetterRedacted, saved.Credential.Data.CodeVerifier)
+	assert.Empty(t, saved.Credential.Data.Token)
+	assert.Equal(t, "client_1", saved.Credential.Data.ClientID)
+	assert.NotContains(t, string(letter.Payload), "secret_1")
+}
+
+func TestRoutes_DeadLettersRequireAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	routes.Register(router, &dimodel.Dependencies{AdminToken: "secret"})
+
+	for _, endpoint := range []struct{ method, path string }{
+		{http.MethodGet, "/api/v1/deadletters"},
+		{http.MethodGet, "/api/v1/deadletters/letter_1"},
+		{http.MethodPost, "/api/v1/deadletters/letter_1/replay"},
+		{http.MethodDelete, "/api/v1/deadletters/letter_1"},
+	} {
+		req, _ := http.NewRequest(endpoint.method, endpoint.path, nil)
+		req.Header.Set(models.AdminTokenHeader, "other")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code, endpoint.method+" "+endpoint.path)
+	}
+}
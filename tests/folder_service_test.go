@@ -0,0 +1,142 @@
+package tests
+
+import (
+	"minireipaz/mocks"
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/domain/services"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestFolderService_GetFolderTree(t *testing.T) {
+	userID := "user_1"
+	workflowService := mocks.NewWorkflowService(t)
+	folderRepo := mocks.NewFolderRedisRepository(t)
+	workflowService.On("GetAllWorkflows", &userID).Return([]models.Workflow{
+		{UUID: "wf_1", DirectoryToSave: "/sales/"},
+		{UUID: "wf_2", DirectoryToSave: "sales/leads"},
+		{UUID: "wf_3", DirectoryToSave: "sales/leads"},
+		{UUID: "wf_4", DirectoryToSave: "/"},
+	}, nil)
+	folderRepo.On("GetAll", &userID).Return([]string{"archive", "sales/leads/old"}, nil)
+
+	service := services.NewFolderService(folderRepo, workflowService)
+	root, err := service.GetFolderTree(&userID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, root.WorkflowCount)
+	assert.Equal(t, 4, root.TotalWorkflows)
+	assert.Len(t, root.Children, 2)
+	assert.Equal(t, "archive", root.Children[0].Path)
+	assert.Equal(t, 0, root.Children[0].TotalWorkflows)
+
+	sales := root.Children[1]
+	assert.Equal(t, "sales", sales.Path)
+	assert.Equal(t, 1, sales.WorkflowCount)
+	assert.Equal(t, 3, sales.TotalWorkflows)
+	assert.Equal(t, "sales/leads", sales.Children[0].Path)
+	assert.Equal(t, 2, sales.Children[0].WorkflowCount)
+	assert.Equal(t, "old", sales.Children[0].Children[0].Name)
+}
+
+func TestFolderService_RenameFolderMovesWorkflows(t *testing.T) {
+	userID := "user_1"
+	workflows := []models.Workflow{
+		{UUID: "wf_1", DirectoryToSave: "sales"},
+		{UUID: "wf_2", DirectoryToSave: "sales/leads"},
+		{UUID: "wf_3", DirectoryToSave: "salesforce"},
+	}
+	workflowService := mocks.NewWorkflowService(t)
+	folderRepo := mocks.NewFolderRedisRepository(t)
+	workflowService.On("GetAllWorkflows", &userID).Return(workflows, nil)
+	folderRepo.On("GetAll", &userID).Return([]string{}, nil)
+	folderRepo.On("Move", &userID, strPtr("sales"), strPtr("revenue")).Return(nil).Once()
+	workflowService.On("UpdateWorkflow", mock.MatchedBy(func(workflow *models.Workflow) bool {
+		return workflow.UUID == "wf_1" && workflow.DirectoryToSave == "revenue"
+	})).Return(true, true, nil).Once()
+	workflowService.On("UpdateWorkflow", mock.MatchedBy(func(workflow *models.Workflow) bool {
+		return workflow.UUID == "wf_2" && workflow.DirectoryToSave == "revenue/leads"
+	})).Return(true, true, nil).Once()
+
+	service := services.NewFolderService(folderRepo, workflowService)
+	report, err := service.RenameFolder(&models.RequestRenameFolder{UserID: userID, Path: "/sales", NewName: "revenue"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "revenue", report.Path)
+	assert.Equal(t, []string{"wf_1", "wf_2"}, report.Moved)
+	assert.Empty(t, report.Failed)
+}
+
+func TestFolderService_RenameFolderRevertsOnFailure(t *testing.T) {
+	userID := "user_1"
+	workflows := []models.Workflow{
+		{UUID: "wf_1", DirectoryToSave: "sales", Version: 1},
+		{UUID: "wf_2", DirectoryToSave: "sales/leads", Version: 1},
+	}
+	workflowService := mocks.NewWorkflowService(t)
+	folderRepo := mocks.NewFolderRedisRepository(t)
+	workflowService.On("GetAllWorkflows", &userID).Return(workflows, nil)
+	folderRepo.On("GetAll", &userID).Return([]string{}, nil)
+	workflowService.On("UpdateWorkflow", mock.MatchedBy(func(workflow *models.Workflow) bool {
+		return workflow.UUID == "wf_1" && workflow.DirectoryToSave == "revenue"
+	})).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.Workflow).Version = 2
+	}).Return(true, true, nil).Once()
+	workflowService.On("UpdateWorkflow", mock.MatchedBy(func(workflow *models.Workflow) bool {
+		return workflow.UUID == "wf_2" && workflow.DirectoryToSave == "revenue/leads"
+	})).Return(false, true, models.ErrVersionConflict).Once()
+	workflowService.On("UpdateWorkflow", mock.MatchedBy(func(workflow *models.Workflow) bool {
+		return workflow.UUID == "wf_1" && workflow.DirectoryToSave == "sales" && workflow.Version == 2
+	})).Return(true, true, nil).Once()
+
+	service := services.NewFolderService(folderRepo, workflowService)
+	report, err := service.RenameFolder(&models.RequestRenameFolder{UserID: userID, Path: "sales", NewName: "revenue"})
+
+	assert.ErrorIs(t, err, models.ErrFolderMoveReverted)
+	assert.Equal(t, []string{"wf_2"}, report.Failed)
+	assert.Equal(t, []string{"wf_1"}, report.Reverted)
+	assert.Empty(t, report.Moved)
+	folderRepo.AssertNotCalled(t, "Move", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestFolderService_MoveWorkflows(t *testing.T) {
+	userID := "user_1"
+	workflowService := mocks.NewWorkflowService(t)
+	folderRepo := mocks.NewFolderRedisRepository(t)
+	folderRepo.On("Save", &userID, strPtr("archive/2024")).Return(true, nil)
+	workflowService.On("GetWorkflow", &userID, strPtr("wf_1")).Return(&models.Workflow{UUID: "wf_1"}, true)
+	workflowService.On("GetWorkflow", &userID, strPtr("wf_2")).Return(nil, false)
+	workflowService.On("GetWorkflow", &userID, strPtr("wf_3")).Return(&models.Workflow{UUID: "wf_3"}, true)
+	workflowService.On("UpdateWorkflow", mock.MatchedBy(func(workflow *models.Workflow) bool {
+		return workflow.UUID == "wf_1" && workflow.DirectoryToSave == "archive/2024"
+	})).Return(true, true, nil)
+	workflowService.On("UpdateWorkflow", mock.MatchedBy(func(workflow *models.Workflow) bool {
+		return workflow.UUID == "wf_3"
+	})).Return(false, true, models.ErrVersionConflict)
+
+	service := services.NewFolderService(folderRepo, workflowService)
+	report, err := service.MoveWorkflows(&models.RequestMoveWorkflows{UserID: userID, Path: "/archive/2024/", WorkflowIDs: []string{"wf_1", "wf_2", "wf_3"}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "archive/2024", report.Path)
+	assert.Equal(t, []string{"wf_1"}, report.Moved)
+	assert.Equal(t, []string{"wf_2", "wf_3"}, report.Failed)
+}
+
+func TestFolderService_MoveWorkflowsInvalidPath(t *testing.T) {
+	service := services.NewFolderService(mocks.NewFolderRedisRepository(t), mocks.NewWorkflowService(t))
+	_, err := service.MoveWorkflows(&models.RequestMoveWorkflows{UserID: "user_1", Path: strings.Repeat("a", models.MaxFolderPathLength+1), WorkflowIDs: []string{"wf_1"}})
+	assert.ErrorIs(t, err, models.ErrFolderInvalid)
+}
+
+func TestFolderService_MoveFolderInsideItself(t *testing.T) {
+	service := services.NewFolderService(mocks.NewFolderRedisRepository(t), mocks.NewWorkflowService(t))
+	_, err := service.MoveFolder(&models.RequestMoveFolder{UserID: "user_1", Path: "sales", NewParent: "sales/leads"})
+	assert.ErrorIs(t, err, models.ErrFolderMoveInside)
+
+	_, err = service.MoveFolder(&models.RequestMoveFolder{UserID: "user_1", Path: "sales/../x"})
+	assert.ErrorIs(t, err, models.ErrFolderInvalid)
+}
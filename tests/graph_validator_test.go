@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/domain/services"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraphValidator_Validate(t *testing.T) {
+	validator := services.NewGraphValidator(nil)
+
+	tests := []struct {
+		name          string
+		workflow      models.Workflow
+		expectedCodes []string
+	}{
+		{
+			name: "Valid - Start node with a chain",
+			workflow: models.Workflow{
+				Nodes: []models.Node{{ID: models.InitialNodeID}, {ID: "a"}, {ID: "b"}},
+				Edges: []models.Edge{
+					{ID: strPtr("e1"), Source: strPtr(models.InitialNodeID), Target: strPtr("a")},
+					{ID: strPtr("e2"), Source: strPtr("a"), Target: strPtr("b")},
+				},
+			},
+			expectedCodes: []string{},
+		},
+		{
+			name: "Error - Missing start node and duplicated node",
+			workflow: models.Workflow{
+				Nodes: []models.Node{{ID: "a"}, {ID: "a"}, {ID: ""}},
+			},
+			expectedCodes: []string{models.GraphErrorDuplicateNodeID, models.GraphErrorEmptyNodeID, models.GraphErrorMissingStartNode},
+		},
+		{
+			name: "Error - Edges to unknown nodes and into start node",
+			workflow: models.Workflow{
+				Nodes: []models.Node{{ID: models.InitialNodeID}, {ID: "a"}},
+				Edges: []models.Edge{
+					{ID: strPtr("e1"), Source: strPtr("ghost"), Target: strPtr("a")},
+					{ID: strPtr("e2"), Source: strPtr("a"), Target: strPtr("nowhere")},
+					{ID: strPtr("e3"), Source: strPtr("a"), Target: strPtr(models.InitialNodeID)},
+				},
+			},
+			expectedCodes: []string{models.GraphErrorEdgeUnknownSource, models.GraphErrorEdgeUnknownTarget, models.GraphErrorStartNodeInbound},
+		},
+		{
+			name: "Error - Second node of start type",
+			workflow: models.Workflow{
+				Nodes: []models.Node{{ID: models.InitialNodeID}, {ID: "a", Data: &models.DataNode{Type: models.NodeTypeStart}}},
+			},
+			expectedCodes: []string{models.GraphErrorMultipleStartNodes},
+		},
+		{
+			name: "Error - Initial node is not of start type",
+			workflow: models.Workflow{
+				Nodes: []models.Node{{ID: models.InitialNodeID, Data: &models.DataNode{Type: models.NodeTypeWebhook}}},
+			},
+			expectedCodes: []string{models.GraphErrorMissingStartNode},
+		},
+		{
+			name: "Error - Cycle between nodes",
+			workflow: models.Workflow{
+				Nodes: []models.Node{{ID: models.InitialNodeID}, {ID: "a"}, {ID: "b"}},
+				Edges: []models.Edge{
+					{ID: strPtr("e1"), Source: strPtr(models.InitialNodeID), Target: strPtr("a")},
+					{ID: strPtr("e2"), Source: strPtr("a"), Target: strPtr("b")},
+					{ID: strPtr("e3"), Source: strPtr("b"), Target: strPtr("a")},
+				},
+			},
+			expectedCodes: []string{models.GraphErrorCycle},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			graphErrors := validator.Validate(&tt.workflow)
+
+			codes := []string{}
+			for _, graphError := range graphErrors {
+				codes = append(codes, graphError.Code)
+			}
+			assert.Equal(t, tt.expectedCodes, codes)
+		})
+	}
+}
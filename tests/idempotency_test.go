@@ -0,0 +1,182 @@
+package tests
+
+import (
+	"minireipaz/mocks"
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/domain/services"
+	"minireipaz/pkg/interfaces/middlewares"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestIdempotencyService_Begin(t *testing.T) {
+	tests := []struct {
+		name     string
+		reserved bool
+		stored   *models.IdempotencyRecord
+		wantErr  error
+		replay   bool
+	}{
+		{name: "first request reserves the key", reserved: true},
+		{name: "same body completed is replayed", stored: &models.IdempotencyRecord{RequestHash: "hash_1", Status: http.StatusCreated, Completed: true}, replay: true},
+		{name: "same body still running", stored: &models.IdempotencyRecord{RequestHash: "hash_1"}, wantErr: models.ErrIdempotencyInProgress},
+		{name: "different body", stored: &models.IdempotencyRecord{RequestHash: "hash_2", Completed: true}, wantErr: models.ErrIdempotencyKeyReused},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redisRepo := mocks.NewIdempotencyRedisRepository(t)
+			redisRepo.On("Reserve", "idempotency:POST /api/v1/workflows user_1:key_1", mock.AnythingOfType("*models.IdempotencyRecord"), models.IdempotencyLockTTL).Return(tt.reserved, tt.stored, nil)
+
+			service := services.NewIdempotencyService(redisRepo)
+			stored, err := service.Begin("POST /api/v1/workflows user_1", "key_1", "hash_1")
+			assert.ErrorIs(t, err, tt.wantErr)
+			if tt.replay {
+				assert.Equal(t, tt.stored, stored)
+			} else {
+				assert.Nil(t, stored)
+			}
+		})
+	}
+}
+
+func newIdempotentRouter(idempotencyService *mocks.IdempotencyService, calls *int, status int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middlewares.IdempotencyMiddleware(idempotencyService))
+	router.POST("/api/v1/workflows", func(ctx *gin.Context) {
+		*calls++
+		ctx.JSON(status, gin.H{"uuid": "wf_1"})
+	})
+	return router
+}
+
+func postIdempotent(router *gin.Engine, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/workflows", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(models.IdempotencyKeyHeader, "key_1")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotencyMiddleware_StoresFirstResponse(t *testing.T) {
+	calls := 0
+	idempotencyService := mocks.NewIdempotencyService(t)
+	idempotencyService.On("Begin", "POST /api/v1/workflows user_1", "key_1", mock.Anything).Return(nil, nil)
+	idempotencyService.On("Complete", "POST /api/v1/workflows user_1", "key_1", mock.MatchedBy(func(record *models.IdempotencyRecord) bool {
+		return record.Status == http.StatusCreated && record.Body == `{"uuid":"wf_1"}`
+	})).Return(nil)
+
+	w := postIdempotent(newIdempotentRouter(idempotencyService, &calls, http.StatusCreated), `{"name":"flow","user_id":"user_1"}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, 1, calls)
+}
+
+func TestIdempotencyMiddleware_ReplaysStoredResponse(t *testing.T) {
+	calls := 0
+	idempotencyService := mocks.NewIdempotencyService(t)
+	idempotencyService.On("Begin", "POST /api/v1/workflows user_1", "key_1", mock.Anything).Return(&models.IdempotencyRecord{
+		Status:      http.StatusCreated,
+		ContentType: "application/json; charset=utf-8",
+		Body:        `{"uuid":"wf_1"}`,
+		Completed:   true,
+	}, nil)
+
+	w := postIdempotent(newIdempotentRouter(idempotencyService, &calls, http.StatusCreated), `{"name":"flow","user_id":"user_1"}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, `{"uuid":"wf_1"}`, w.Body.String())
+	assert.Equal(t, "true", w.Header().Get(models.IdempotencyReplayedHeader))
+	assert.Equal(t, 0, calls)
+}
+
+func TestIdempotencyMiddleware_RejectsDifferentBody(t *testing.T) {
+	calls := 0
+	idempotencyService := mocks.NewIdempotencyService(t)
+	idempotencyService.On("Begin", "POST /api/v1/workflows user_1", "key_1", mock.Anything).Return(nil, models.ErrIdempotencyKeyReused)
+
+	w := postIdempotent(newIdempotentRouter(idempotencyService, &calls, http.StatusCreated), `{"name":"other","user_id":"user_1"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, 0, calls)
+}
+
+func TestIdempotencyMiddleware_ReleasesOnServerError(t *testing.T) {
+	calls := 0
+	idempotencyService := mocks.NewIdempotencyService(t)
+	idempotencyService.On("Begin", "POST /api/v1/workflows user_1", "key_1", mock.Anything).Return(nil, nil)
+	idempotencyService.On("Release", "POST /api/v1/workflows user_1", "key_1").Return(nil)
+
+	w := postIdempotent(newIdempotentRouter(idempotencyService, &calls, http.StatusInternalServerError), `{"name":"flow","user_id":"user_1"}`)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	idempotencyService.AssertNotCalled(t, "Complete", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestIdempotencyMiddleware_ScopesKeyByUser(t *testing.T) {
+	calls := 0
+	idempotencyService := mocks.NewIdempotencyService(t)
+	for _, userID := range []string{"user_1", "user_2"} {
+		scope := "POST /api/v1/workflows " + userID
+		idempotencyService.On("Begin", scope, "key_1", mock.Anything).Return(nil, nil).Once()
+		idempotencyService.On("Complete", scope, "key_1", mock.Anything).Return(nil).Once()
+	}
+
+	router := newIdempotentRouter(idempotencyService, &calls, http.StatusCreated)
+	postIdempotent(router, `{"name":"flow","user_id":"user_1"}`)
+	postIdempotent(router, `{"name":"flow","user_id":"user_2"}`)
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotencyMiddleware_HashesIfMatch(t *testing.T) {
+	calls := 0
+	hashes := []string{}
+	idempotencyService := mocks.NewIdempotencyService(t)
+	idempotencyService.On("Begin", "POST /api/v1/workflows user_1", "key_1", mock.Anything).Run(func(args mock.Arguments) {
+		hashes = append(hashes, args.String(2))
+	}).Return(nil, nil)
+	idempotencyService.On("Complete", "POST /api/v1/workflows user_1", "key_1", mock.Anything).Return(nil)
+
+	router := newIdempotentRouter(idempotencyService, &calls, http.StatusOK)
+	for _, etag := range []string{`"1"`, `"2"`} {
+		req, _ := http.NewRequest(http.MethodPost, "/api/v1/workflows", strings.NewReader(`{"user_id":"user_1"}`))
+		req.Header.Set(models.IdempotencyKeyHeader, "key_1")
+		req.Header.Set("If-Match", etag)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	assert.Len(t, hashes, 2)
+	assert.NotEqual(t, hashes[0], hashes[1])
+}
+
+func TestIdempotencyMiddleware_ScopeWithoutPathToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	scopes := []string{}
+	idempotencyService := mocks.NewIdempotencyService(t)
+	idempotencyService.On("Begin", mock.Anything, "key_1", mock.Anything).Run(func(args mock.Arguments) {
+		scopes = append(scopes, args.String(0))
+	}).Return(nil, nil)
+	idempotencyService.On("Complete", mock.Anything, "key_1", mock.Anything).Return(nil)
+
+	router := gin.New()
+	router.Use(middlewares.IdempotencyMiddleware(idempotencyService))
+	router.POST("/api/v1/workflows/rollback/:iduser/:idworkflow/:usertoken", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+	for _, workflowID := range []string{"wf_1", "wf_2"} {
+		req, _ := http.NewRequest(http.MethodPost, "/api/v1/workflows/rollback/user_1/"+workflowID+"/secret_token", strings.NewReader(`{}`))
+		req.Header.Set(models.IdempotencyKeyHeader, "key_1")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	assert.Len(t, scopes, 2)
+	assert.NotEqual(t, scopes[0], scopes[1])
+	for _, scope := range scopes {
+		assert.True(t, strings.HasPrefix(scope, "POST /api/v1/workflows/rollback/:iduser/:idworkflow/:usertoken "))
+		assert.NotContains(t, scope, "secret_token")
+	}
+}
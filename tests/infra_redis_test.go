@@ -27,7 +27,7 @@ func TestSingleRequest(t *testing.T) {
 	rc := &redisclient.RedisClient{Client: testRedisClient}
 	repo := redisclient.NewActionsRepository(rc)
 	defer testRedisClient.Del(context.Background(), repo.GetActionsGlobalAll(), "lock:"+actionID)
-	created, existed, err := repo.Create(newAction)
+	created, existed, err := repo.Create(newAction, nil)
 
 	assert.Error(t, err)
 	assert.False(t, created)
@@ -54,7 +54,7 @@ func TestConcurrentRequests(t *testing.T) {
 	for i := 0; i < numRoutines; i++ {
 		go func() {
 			defer wg.Done()
-			repo.Create(newAction)
+			repo.Create(newAction, nil)
 		}()
 	}
 	wg.Wait()
@@ -91,11 +91,11 @@ func TestRetryMechanism(t *testing.T) {
 	wg.Add(2)
 	go func() {
 		defer wg.Done()
-		repo.Create(newAction)
+		repo.Create(newAction, nil)
 	}()
 	go func() {
 		defer wg.Done()
-		repo.Create(newAction)
+		repo.Create(newAction, nil)
 	}()
 	wg.Wait()
 	// Verify only one action exists in Redis
@@ -125,14 +125,14 @@ func TestLockExpiry(t *testing.T) {
 	repo := redisclient.NewActionsRepository(rc)
 	defer testRedisClient.Del(context.Background(), repo.GetActionsGlobalAll(), "lock:"+actionID)
 	// Create an action with a lock that expires after a short time
-	created, existed, err := repo.Create(newAction)
+	created, existed, err := repo.Create(newAction, nil)
 	assert.Error(t, err)
 	assert.False(t, created)
 	assert.False(t, existed)
 	// Wait for the lock to expire
 	time.Sleep(1*time.Second)
 	// Create the action again
-	createdAgain, existedAgain, err := repo.Create(newAction)
+	createdAgain, existedAgain, err := repo.Create(newAction, nil)
 	assert.Error(t, err)
 	assert.False(t, createdAgain)
 	assert.False(t, existedAgain)
@@ -164,7 +164,7 @@ func TestErrorHandling(t *testing.T) {
 	// Introduce an error in Redis operations
 	// For example, by closing the Redis connection
 	testRedisClient.Close()
-	created, existed, err := repo.Create(newAction)
+	created, existed, err := repo.Create(newAction, nil)
 	assert.Error(t, err)
 	assert.False(t, created)
 	assert.False(t, existed)
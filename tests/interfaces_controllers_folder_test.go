@@ -0,0 +1,141 @@
+package tests
+
+import (
+	"errors"
+	"minireipaz/mocks"
+	"minireipaz/pkg/dimodel"
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/interfaces/controllers"
+	"minireipaz/pkg/interfaces/middlewares"
+	"minireipaz/pkg/interfaces/routes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFolderController_GetFolderTree(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	userID := "user_1"
+	folderService := mocks.NewFolderService(t)
+	folderService.On("GetFolderTree", &userID).Return(&models.Folder{Children: []*models.Folder{}, TotalWorkflows: 2}, nil)
+	controller := controllers.NewFolderController(folderService)
+
+	w := httptest.NewRecorder()
+	ctx := newWorkflowTestContext(w, userID, "")
+	controller.GetFolderTree(ctx)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"total_workflows":2`)
+}
+
+func TestFolderController_MoveWorkflows(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	request := models.RequestMoveWorkflows{UserID: "user_1", Path: "archive", WorkflowIDs: []string{"wf_1", "wf_2"}}
+
+	tests := []struct {
+		name               string
+		report             *models.FolderMoveReport
+		err                error
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name:               "Success - All workflows moved",
+			report:             &models.FolderMoveReport{Path: "archive", Moved: []string{"wf_1", "wf_2"}, Failed: []string{}},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"error":"","status":200,"report":{"path":"archive","moved":["wf_1","wf_2"],"failed":[]}}`,
+		},
+		{
+			name:               "Partial - Some workflows failed",
+			report:             &models.FolderMoveReport{Path: "archive", Moved: []string{"wf_1"}, Failed: []string{"wf_2"}},
+			expectedStatusCode: http.StatusMultiStatus,
+			expectedResponse:   `{"error":"some workflows could not be moved","status":207,"report":{"path":"archive","moved":["wf_1"],"failed":["wf_2"]}}`,
+		},
+		{
+			name:               "Error - Invalid folder",
+			err:                models.ErrFolderInvalid,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"error":"` + models.FolderInvalid + `","status":400}`,
+		},
+		{
+			name:               "Error - Redis failure",
+			err:                errors.New("redis down"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse:   `{"error":"redis down","status":500}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			folderService := mocks.NewFolderService(t)
+			folderService.On("MoveWorkflows", &request).Return(tt.report, tt.err)
+			controller := controllers.NewFolderController(folderService)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Set(models.FolderMoveWorkflowsKey, request)
+			controller.MoveWorkflows(ctx)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.JSONEq(t, tt.expectedResponse, w.Body.String())
+		})
+	}
+}
+
+func TestFolderController_RenameFolderReverted(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	request := models.RequestRenameFolder{UserID: "user_1", Path: "sales", NewName: "revenue"}
+	folderService := mocks.NewFolderService(t)
+	folderService.On("RenameFolder", &request).Return(&models.FolderMoveReport{
+		Path: "revenue", Moved: []string{}, Failed: []string{"wf_2"}, Reverted: []string{"wf_1"},
+	}, models.ErrFolderMoveReverted)
+	controller := controllers.NewFolderController(folderService)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Set(models.FolderRenameKey, request)
+	controller.RenameFolder(ctx)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.JSONEq(t, `{"error":"`+models.FolderMoveReverted+`","status":409,"report":{"path":"revenue","moved":[],"failed":["wf_2"],"reverted":["wf_1"]}}`, w.Body.String())
+}
+
+func TestRoutes_FolderWritesVerifyUserToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	routes.Register(router, &dimodel.Dependencies{})
+
+	writes := 0
+	for _, route := range router.Routes() {
+		if route.Method != http.MethodPost || !strings.HasPrefix(route.Path, "/api/v1/folders") {
+			continue
+		}
+		writes++
+		assert.True(t, strings.HasSuffix(route.Path, "/:iduser/:usertoken"), route.Path)
+	}
+	assert.Equal(t, 4, writes)
+}
+
+func TestValidateOnCreateFolder_UserOfPath(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var userID string
+	router := gin.New()
+	router.POST("/folders/:iduser/:usertoken", middlewares.ValidateOnCreateFolder(), func(ctx *gin.Context) {
+		userID = ctx.MustGet(models.FolderCreateKey).(models.RequestCreateFolder).UserID
+		ctx.Status(http.StatusCreated)
+	})
+
+	create := func(body string) int {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/folders/user_1/token", strings.NewReader(body)))
+		return w.Code
+	}
+	assert.Equal(t, http.StatusCreated, create(`{"path":"sales"}`))
+	assert.Equal(t, "user_1", userID)
+	// the token of user_1 cannot create folders of another user
+	assert.Equal(t, http.StatusBadRequest, create(`{"user_id":"user_2","path":"sales"}`))
+}
@@ -25,6 +25,11 @@ func (m *MockUserService) SynUser(user *models.SyncUserRequest) (created, exist
 	return args.Bool(0), args.Bool(1)
 }
 
+func (m *MockUserService) ReplayDeadLetter(letter *models.DeadLetter) error {
+	args := m.Called(letter)
+	return args.Error(0)
+}
+
 func TestSyncUseWrithIDProvider(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -0,0 +1,326 @@
+package tests
+
+import (
+	"errors"
+	"minireipaz/mocks"
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/interfaces/controllers"
+	"minireipaz/pkg/interfaces/middlewares"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newWorkflowTestContext(w *httptest.ResponseRecorder, userID, workflowID string) *gin.Context {
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Params = gin.Params{
+		{Key: "iduser", Value: userID},
+		{Key: "idworkflow", Value: workflowID},
+	}
+	return ctx
+}
+
+func TestWorkflowController_DeleteWorkflow(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	userID, workflowID := "user_1", "wf_1"
+
+	tests := []struct {
+		name               string
+		setupMocks         func(*mocks.WorkflowService)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name: "Success - Workflow moved to trash",
+			setupMocks: func(m *mocks.WorkflowService) {
+				m.On("DeleteWorkflow", &userID, &workflowID).Return(true, true)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"error":"","status":200}`,
+		},
+		{
+			name: "Error - Workflow not found",
+			setupMocks: func(m *mocks.WorkflowService) {
+				m.On("DeleteWorkflow", &userID, &workflowID).Return(false, false)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   `{"error":"UUID must be a valid UUID","status":404}`,
+		},
+		{
+			name: "Error - Cannot publish delete command",
+			setupMocks: func(m *mocks.WorkflowService) {
+				m.On("DeleteWorkflow", &userID, &workflowID).Return(false, true)
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse:   `{"error":"cannot delete workflow","status":500}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockWorkflowService := mocks.NewWorkflowService(t)
+			tt.setupMocks(mockWorkflowService)
+			controller := controllers.NewWorkflowController(mockWorkflowService, mocks.NewCredentialService(t), mocks.NewAuthService(t))
+
+			w := httptest.NewRecorder()
+			ctx := newWorkflowTestContext(w, userID, workflowID)
+			controller.DeleteWorkflow(ctx)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.JSONEq(t, tt.expectedResponse, w.Body.String())
+		})
+	}
+}
+
+func TestWorkflowController_RestoreWorkflow(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	userID, workflowID := "user_1", "wf_1"
+
+	tests := []struct {
+		name               string
+		setupMocks         func(*mocks.WorkflowService)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name: "Success - Workflow restored",
+			setupMocks: func(m *mocks.WorkflowService) {
+				m.On("RestoreWorkflow", &userID, &workflowID).Return(true, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"error":"","status":200}`,
+		},
+		{
+			name: "Error - Not in trash",
+			setupMocks: func(m *mocks.WorkflowService) {
+				m.On("RestoreWorkflow", &userID, &workflowID).Return(false, models.ErrWorkflowNotInTrash)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   `{"error":"workflow not found in trash","status":404}`,
+		},
+		{
+			name: "Error - Name taken while in trash",
+			setupMocks: func(m *mocks.WorkflowService) {
+				m.On("RestoreWorkflow", &userID, &workflowID).Return(false, models.ErrWorkflowNameExist)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedResponse:   `{"error":"workflow name already exists for this user","status":409}`,
+		},
+		{
+			name: "Error - Broker failure",
+			setupMocks: func(m *mocks.WorkflowService) {
+				m.On("RestoreWorkflow", &userID, &workflowID).Return(false, errors.New("broker down"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse:   `{"error":"cannot restore workflow","status":500}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockWorkflowService := mocks.NewWorkflowService(t)
+			tt.setupMocks(mockWorkflowService)
+			controller := controllers.NewWorkflowController(mockWorkflowService, mocks.NewCredentialService(t), mocks.NewAuthService(t))
+
+			w := httptest.NewRecorder()
+			ctx := newWorkflowTestContext(w, userID, workflowID)
+			controller.RestoreWorkflow(ctx)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.JSONEq(t, tt.expectedResponse, w.Body.String())
+		})
+	}
+}
+
+func TestWorkflowController_PurgeWorkflow(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	userID, workflowID := "user_1", "wf_1"
+
+	tests := []struct {
+		name               string
+		purged             bool
+		exist              bool
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name:               "Success - Workflow purged",
+			purged:             true,
+			exist:              true,
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"error":"","status":200}`,
+		},
+		{
+			name:               "Error - Not in trash",
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   `{"error":"` + models.WorkflowNotInTrash + `","status":404}`,
+		},
+		{
+			name:               "Error - Purge not published",
+			exist:              true,
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse:   `{"error":"` + models.WorkflowCannotDelete + `","status":500}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockWorkflowService := mocks.NewWorkflowService(t)
+			mockWorkflowService.On("PurgeWorkflow", &userID, &workflowID).Return(tt.purged, tt.exist)
+			controller := controllers.NewWorkflowController(mockWorkflowService, mocks.NewCredentialService(t), mocks.NewAuthService(t))
+
+			w := httptest.NewRecorder()
+			ctx := newWorkflowTestContext(w, userID, workflowID)
+			controller.PurgeWorkflow(ctx)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.JSONEq(t, tt.expectedResponse, w.Body.String())
+		})
+	}
+}
+
+func TestWorkflowController_UpdateWorkflowConflict(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	userID, workflowID := "user_1", "wf_1"
+	stale := models.Workflow{UUID: workflowID, UserID: userID, Name: "flow", Version: 2}
+	current := &models.Workflow{UUID: workflowID, UserID: userID, Name: "flow", Version: 3}
+
+	mockWorkflowService := mocks.NewWorkflowService(t)
+	mockWorkflowService.On("UpdateWorkflow", &stale).Return(false, true, models.ErrVersionConflict)
+	mockWorkflowService.On("GetWorkflow", &userID, &workflowID).Return(current, true)
+	controller := controllers.NewWorkflowController(mockWorkflowService, mocks.NewCredentialService(t), mocks.NewAuthService(t))
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Set("workflow", stale)
+	controller.UpdateWorkflow(ctx)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.JSONEq(t, `{"error":"workflow was updated by someone else, merge with the current version","status":409,"workflow":{"id":"wf_1","user_id":"user_1","name":"flow","directory_to_save":"","version":3,"workflow_init":"0001-01-01T00:00:00Z","workflow_completed":"0001-01-01T00:00:00Z"}}`, w.Body.String())
+}
+
+func TestWorkflowController_UpdateWorkflowGraphInvalid(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	workflow := models.Workflow{UUID: "wf_1", UserID: "user_1", Name: "flow", Version: 2}
+	graphErrors := []models.GraphValidationError{{Code: models.GraphErrorMissingStartNode, Message: "missing"}}
+
+	mockWorkflowService := mocks.NewWorkflowService(t)
+	mockWorkflowService.On("UpdateWorkflow", &workflow).Return(false, true, &models.GraphInvalidError{Errors: graphErrors})
+	controller := controllers.NewWorkflowController(mockWorkflowService, mocks.NewCredentialService(t), mocks.NewAuthService(t))
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Set("workflow", workflow)
+	controller.UpdateWorkflow(ctx)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error":"workflow graph is not valid","status":400,"errors":[{"code":"missing_start_node","message":"missing"}]}`, w.Body.String())
+}
+
+func TestWorkflowController_RollbackWorkflow(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	userID, workflowID := "user_1", "wf_1"
+
+	tests := []struct {
+		name               string
+		updated            bool
+		exist              bool
+		err                error
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{"Success - Rolled back", true, true, nil, http.StatusOK, `{"error":"","status":200}`},
+		{"Error - Revision not found", false, false, nil, http.StatusNotFound, `{"error":"workflow revision not found","status":404}`},
+		{"Error - Version conflict", false, true, models.ErrVersionConflict, http.StatusConflict, `{"error":"` + models.WorkflowVersionConflict + `","status":409}`},
+		{"Error - Graph invalid", false, true, &models.GraphInvalidError{Errors: []models.GraphValidationError{{Code: models.GraphErrorMissingStartNode, Message: "missing"}}},
+			http.StatusBadRequest, `{"error":"workflow graph is not valid","status":400,"errors":[{"code":"missing_start_node","message":"missing"}]}`},
+		{"Error - Cannot publish update", false, true, nil, http.StatusInternalServerError, `{"error":"cannot rollback workflow","status":500}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockWorkflowService := mocks.NewWorkflowService(t)
+			mockWorkflowService.On("RollbackWorkflow", &userID, &workflowID, uint32(2)).Return(tt.updated, tt.exist, tt.err)
+			controller := controllers.NewWorkflowController(mockWorkflowService, mocks.NewCredentialService(t), mocks.NewAuthService(t))
+
+			w := httptest.NewRecorder()
+			ctx := newWorkflowTestContext(w, userID, workflowID)
+			ctx.Set(models.WorkflowRollbackKey, models.RequestRollbackWorkflow{UserID: userID, Version: 2})
+			controller.RollbackWorkflow(ctx)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.JSONEq(t, tt.expectedResponse, w.Body.String())
+		})
+	}
+}
+
+func TestWorkflowController_DiffWorkflowRevisions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	userID, workflowID := "user_1", "wf_1"
+	diff := &models.WorkflowDiff{FromVersion: 1, ToVersion: 2}
+
+	mockWorkflowService := mocks.NewWorkflowService(t)
+	mockWorkflowService.On("DiffWorkflowRevisions", &userID, &workflowID, uint32(1), uint32(2)).Return(diff, nil)
+	mockWorkflowService.On("DiffWorkflowRevisions", &userID, &workflowID, uint32(1), uint32(9)).Return(nil, models.ErrRevisionNotFound)
+	controller := controllers.NewWorkflowController(mockWorkflowService, mocks.NewCredentialService(t), mocks.NewAuthService(t))
+
+	diffVersions := func(from, to string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		ctx := newWorkflowTestContext(w, userID, workflowID)
+		ctx.Params = append(ctx.Params, gin.Param{Key: "fromversion", Value: from}, gin.Param{Key: "toversion", Value: to})
+		controller.DiffWorkflowRevisions(ctx)
+		return w
+	}
+
+	w := diffVersions("1", "2")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"from_version":1`)
+
+	w = diffVersions("1", "9")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.JSONEq(t, `{"error":"workflow revision not found","status":404}`, w.Body.String())
+
+	w = diffVersions("one", "2")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestValidateOnUpdateWorkflow_RequiresVersion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name               string
+		body               string
+		ifMatch            string
+		expectedStatusCode int
+		expectedVersion    uint32
+	}{
+		{"Error - No version", `{"id":"wf_1","name":"flow","directory_to_save":"/"}`, "", http.StatusPreconditionRequired, 0},
+		{"Success - Version in body", `{"id":"wf_1","name":"flow","directory_to_save":"/","version":3}`, "", http.StatusOK, 3},
+		{"Success - If-Match over body", `{"id":"wf_1","name":"flow","directory_to_save":"/","version":3}`, `"2"`, http.StatusOK, 2},
+		{"Success - Explicit overwrite", `{"id":"wf_1","name":"flow","directory_to_save":"/"}`, "*", http.StatusOK, models.AnyVersion},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var version uint32
+			router := gin.New()
+			router.PUT("/workflows/:id", middlewares.ValidateOnUpdateWorkflow(), func(ctx *gin.Context) {
+				version = ctx.MustGet("workflow").(models.Workflow).Version
+				ctx.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPut, "/workflows/wf_1", strings.NewReader(tt.body))
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedVersion, version)
+		})
+	}
+}
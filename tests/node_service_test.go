@@ -0,0 +1,101 @@
+package tests
+
+import (
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/domain/services"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNodeService_GetCatalog(t *testing.T) {
+	catalog := services.NewNodeService().GetCatalog()
+
+	types := []string{}
+	for _, definition := range catalog {
+		types = append(types, definition.Type)
+	}
+	assert.Equal(t, []string{models.NodeTypeStart, models.NodeTypeWebhook, models.GoogleSheets, models.NotionToken, models.NotionOAuth}, types)
+	assert.Equal(t, models.GoogleSheets, catalog[2].CredentialType)
+}
+
+func TestNodeService_ValidateNodes(t *testing.T) {
+	service := services.NewNodeService()
+	startNode := models.Node{ID: models.InitialNodeID}
+	sheetNode := func(formData models.FormData, credentialID string) models.Node {
+		return models.Node{ID: "sheet", Data: &models.DataNode{
+			Type:           models.GoogleSheets,
+			ContentData:    formData,
+			CredentialData: models.RequestCreateCredential{ID: credentialID},
+		}}
+	}
+	completeForm := models.FormData{Pollmode: models.NopollNode, Document: "doc", Sheet: "sheet1"}
+
+	tests := []struct {
+		name          string
+		workflow      models.Workflow
+		expectedCodes []string
+	}{
+		{
+			name: "Valid - Active workflow fully configured",
+			workflow: models.Workflow{
+				IsActive: models.Active,
+				Nodes:    []models.Node{startNode, sheetNode(completeForm, "cred_1")},
+				Edges:    []models.Edge{{ID: strPtr("e1"), Source: strPtr(models.InitialNodeID), Target: strPtr("sheet")}},
+			},
+			expectedCodes: []string{},
+		},
+		{
+			name: "Valid - Draft workflow without form and credential",
+			workflow: models.Workflow{
+				IsActive: models.Draft,
+				Nodes:    []models.Node{startNode, sheetNode(models.FormData{}, "")},
+			},
+			expectedCodes: []string{},
+		},
+		{
+			name: "Error - Active workflow missing form fields and credential",
+			workflow: models.Workflow{
+				IsActive: models.Active,
+				Nodes:    []models.Node{startNode, sheetNode(models.FormData{Pollmode: models.NopollNode, Document: "doc"}, "")},
+			},
+			expectedCodes: []string{models.GraphErrorMissingFormField, models.GraphErrorMissingCredential},
+		},
+		{
+			name: "Error - Unknown node type",
+			workflow: models.Workflow{
+				Nodes: []models.Node{startNode, {ID: "x", Data: &models.DataNode{Type: "ftp"}}},
+			},
+			expectedCodes: []string{models.GraphErrorUnknownNodeType},
+		},
+		{
+			name: "Valid - Legacy nodes saved without type",
+			workflow: models.Workflow{
+				IsActive: models.Active,
+				Nodes:    []models.Node{startNode, {ID: "legacy"}, {ID: "legacy_data", Data: &models.DataNode{}}},
+				Edges:    []models.Edge{{ID: strPtr("e1"), Source: strPtr(models.InitialNodeID), Target: strPtr("legacy")}},
+			},
+			expectedCodes: []string{},
+		},
+		{
+			name: "Error - Credential of another type",
+			workflow: models.Workflow{
+				Nodes: []models.Node{startNode, {ID: "notion", Data: &models.DataNode{
+					Type:           models.NotionToken,
+					CredentialData: models.RequestCreateCredential{ID: "cred_1", Type: models.GoogleSheets},
+				}}},
+			},
+			expectedCodes: []string{models.GraphErrorInvalidCredential},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			codes := []string{}
+			for _, nodeError := range service.ValidateNodes(&tt.workflow) {
+				codes = append(codes, nodeError.Code)
+			}
+			assert.Equal(t, tt.expectedCodes, codes)
+		})
+	}
+}
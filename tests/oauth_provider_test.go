@@ -0,0 +1,185 @@
+package tests
+
+import (
+	"minireipaz/mocks"
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/domain/repos"
+	"minireipaz/pkg/domain/services"
+	"minireipaz/pkg/infra/httpclient"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testStateSecret = []byte("state_secret")
+
+func TestOAuthProviderRegistry_GetByCredentialType(t *testing.T) {
+	registry := services.NewOAuthProviderRegistry(models.DefaultOAuthProviders(), "")
+
+	provider, err := registry.Get(models.GoogleSheets)
+	assert.NoError(t, err)
+	assert.Equal(t, "google", provider.Name)
+	assert.True(t, provider.PKCE)
+
+	provider, err = registry.Get(models.NotionOAuth)
+	assert.NoError(t, err)
+	assert.Equal(t, "notion", provider.Name)
+
+	_, err = registry.Get(models.NotionToken)
+	assert.ErrorIs(t, err, models.ErrOAuthProviderUnknown)
+}
+
+func TestOAuthProviderRegistry_ConfiguredProviders(t *testing.T) {
+	configured := `[
+		{"name": "github", "auth_url": "https://github.example.com/authorize", "token_url": "https://github.example.com/token", "credential_types": ["githubenterprise"]},
+		{"name": "gitlab", "auth_url": "https://gitlab.com/oauth/authorize", "token_url": "https://gitlab.com/oauth/token", "credential_types": ["gitlab"], "scopes": ["api"], "pkce": true, "refresh": true},
+		{"name": "broken", "auth_url": "https://broken.example.com"}
+	]`
+	registry := services.NewOAuthProviderRegistry(models.DefaultOAuthProviders(), configured)
+
+	provider, err := registry.Get("githubenterprise")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://github.example.com/token", provider.TokenURL)
+	// replaced by name, the types of the default github are gone
+	_, err = registry.Get("github")
+	assert.ErrorIs(t, err, models.ErrOAuthProviderUnknown)
+
+	provider, err = registry.Get("gitlab")
+	assert.NoError(t, err)
+	assert.True(t, provider.Refresh)
+
+	names := []string{}
+	for _, provider := range registry.GetAll() {
+		names = append(names, provider.Name)
+	}
+	assert.Equal(t, []string{"facebook", "github", "gitlab", "google", "notion", "slack"}, names)
+}
+
+func TestOAuthProviderRegistry_InvalidConfigKeepsDefaults(t *testing.T) {
+	registry := services.NewOAuthProviderRegistry(models.DefaultOAuthProviders(), `{not json`)
+	assert.Len(t, registry.GetAll(), len(models.DefaultOAuthProviders()))
+}
+
+func TestOAuthCredentialRepository_GenerateAuthURL(t *testing.T) {
+	repo := httpclient.NewOAuthCredentialRepository(nil)
+	registry := services.NewOAuthProviderRegistry(models.DefaultOAuthProviders(), "")
+	credential := &models.RequestExchangeCredential{Data: models.DataCredential{
+		ClientID:     "client_1",
+		RedirectURL:  "https://app.example.com/callback",
+		CodeVerifier: "verifier_1",
+	}}
+
+	google, _ := registry.Get(models.GoogleSheets)
+	credential.Data.Scopes = google.Scopes
+	authURL, err := url.Parse(*repo.GenerateAuthURL(google, credential, "state_1"))
+	assert.NoError(t, err)
+	query := authURL.Query()
+	assert.Equal(t, "accounts.google.com", authURL.Host)
+	assert.Equal(t, "state_1", query.Get("state"))
+	assert.Equal(t, "offline", query.Get("access_type"))
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+	assert.NotEmpty(t, query.Get("code_challenge"))
+
+	slack, _ := registry.Get("slack")
+	credential.Data.Scopes = []string{"chat:write", "channels:read"}
+	authURL, err = url.Parse(*repo.GenerateAuthURL(slack, credential, "state_2"))
+	assert.NoError(t, err)
+	assert.Equal(t, "chat:write,channels:read", authURL.Query().Get("scope"))
+	assert.Empty(t, authURL.Query().Get("code_challenge"))
+}
+
+func TestCredentialService_OAuthStateRoundTrip(t *testing.T) {
+	oauthRepo := mocks.NewCredentialOAuthHTTPRepository(t)
+	redisRepo := mocks.NewCredentialRedisRepository(t)
+	brokerRepo := mocks.NewCredentialBrokerRepository(t)
+	httpRepo := mocks.NewCredentialHTTPRepository(t)
+	registry := services.NewOAuthProviderRegistry(models.DefaultOAuthProviders(), "")
+	request := &models.RequestCreateCredential{ID: "none", Sub: "user_1", Type: models.GoogleSheets, WorkflowID: "wf_1", NodeID: "sheet"}
+
+	httpRepo.On("GetCredentialByID", &request.Sub, &request.ID, uint64(1)).Return(&[]models.RequestExchangeCredential{}, nil)
+	var state string
+	oauthRepo.On("GenerateAuthURL", mock.MatchedBy(func(provider *models.OAuthProvider) bool { return provider.Name == "google" }), mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { state = args.String(2) }).
+		Return(strPtr("https://accounts.google.com/o/oauth2/auth?state=x"))
+
+	var saved *models.RequestExchangeCredential
+	redisRepo.On("SaveTemporalAuthURLData", mock.Anything, mock.Anything, models.OAuthStateTTL).
+		Run(func(args mock.Arguments) { saved = args.Get(1).(*models.RequestExchangeCredential) }).
+		Return(true, nil)
+
+	refresher := mocks.NewCredentialRefreshService(t)
+	refresher.On("Schedule", mock.MatchedBy(func(credential *models.RequestExchangeCredential) bool { return credential.ID == saved.ID })).Return()
+
+	service := services.NewCredentialService(oauthRepo, registry, redisRepo, brokerRepo, httpRepo, newSecretService(t, "key_1:"+testKey(1), ""), testStateSecret, nil, refresher)
+	created, err := service.CreateCredential(request)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, created.Data.CodeVerifier)
+	assert.Equal(t, []string{"https://www.googleapis.com/auth/spreadsheets.readonly"}, created.Data.Scopes)
+
+	redisRepo.On("PopTemporalAuthURLData", mock.Anything).Return(func(*string) (*models.RequestExchangeCredential, error) { return saved, nil }).Once()
+	expiry := time.Now().Add(time.Hour)
+	redisRepo.On("AddLock", strPtr("user_1")).Return(true, nil)
+	oauthRepo.On("ExchangeCode", mock.Anything, mock.MatchedBy(func(stateInfo *models.RequestExchangeCredential) bool {
+		return stateInfo.ID == created.ID && stateInfo.Data.CodeVerifier == created.Data.CodeVerifier
+	}), "code_1").Return(&models.OAuthToken{AccessToken: "access_1", RefreshToken: "refresh_1", Expiry: expiry}, nil)
+	brokerRepo.On("CreateCredential", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(true)
+
+	token, refresh, _, stateInfo, err := service.ExchangeOAuthCredential(&models.RequestExchangeCredential{
+		Sub:  "user_1",
+		Data: models.DataCredential{State: state, Code: "code_1"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "access_1", *token)
+	assert.Equal(t, "refresh_1", *refresh)
+	assert.Equal(t, "code_1", stateInfo.Data.Code)
+}
+
+func TestCredentialService_ExchangeRejectsInvalidState(t *testing.T) {
+	service := services.NewCredentialService(mocks.NewCredentialOAuthHTTPRepository(t), services.NewOAuthProviderRegistry(models.DefaultOAuthProviders(), ""),
+		mocks.NewCredentialRedisRepository(t), mocks.NewCredentialBrokerRepository(t), mocks.NewCredentialHTTPRepository(t), newSecretService(t, "key_1:"+testKey(1), ""), testStateSecret, nil, nil)
+
+	_, _, _, _, err := service.ExchangeOAuthCredential(&models.RequestExchangeCredential{Data: models.DataCredential{State: "%%%"}})
+	assert.ErrorIs(t, err, models.ErrOAuthStateInvalid)
+}
+
+func newStatefulCredentialService(t *testing.T) (service repos.CredentialService, redisRepo *mocks.CredentialRedisRepository, state string) {
+	oauthRepo := mocks.NewCredentialOAuthHTTPRepository(t)
+	redisRepo = mocks.NewCredentialRedisRepository(t)
+	httpRepo := mocks.NewCredentialHTTPRepository(t)
+	request := &models.RequestCreateCredential{ID: "none", Sub: "user_1", Type: models.GoogleSheets}
+	httpRepo.On("GetCredentialByID", &request.Sub, &request.ID, uint64(1)).Return(&[]models.RequestExchangeCredential{}, nil)
+	oauthRepo.On("GenerateAuthURL", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { state = args.String(2) }).
+		Return(strPtr("https://accounts.google.com/o/oauth2/auth"))
+	redisRepo.On("SaveTemporalAuthURLData", mock.Anything, mock.Anything, models.OAuthStateTTL).Return(true, nil)
+
+	service = services.NewCredentialService(oauthRepo, services.NewOAuthProviderRegistry(models.DefaultOAuthProviders(), ""),
+		redisRepo, mocks.NewCredentialBrokerRepository(t), httpRepo, newSecretService(t, "key_1:"+testKey(1), ""), testStateSecret, nil, nil)
+	_, err := service.CreateCredential(request)
+	assert.NoError(t, err)
+	return service, redisRepo, state
+}
+
+func TestCredentialService_ExchangeRejectsTamperedState(t *testing.T) {
+	service, redisRepo, state := newStatefulCredentialService(t)
+
+	_, _, _, _, err := service.ExchangeOAuthCredential(&models.RequestExchangeCredential{Sub: "user_1", Data: models.DataCredential{State: state + "x"}})
+	assert.ErrorIs(t, err, models.ErrOAuthStateInvalid)
+	// the state of one user cannot be used by another one
+	_, _, _, _, err = service.ExchangeOAuthCredential(&models.RequestExchangeCredential{Sub: "user_2", Data: models.DataCredential{State: state}})
+	assert.ErrorIs(t, err, models.ErrOAuthStateInvalid)
+	redisRepo.AssertNotCalled(t, "PopTemporalAuthURLData", mock.Anything)
+}
+
+func TestCredentialService_ExchangeRejectsUsedOrExpiredState(t *testing.T) {
+	service, redisRepo, state := newStatefulCredentialService(t)
+	stateID, _, _ := strings.Cut(state, ".")
+	redisRepo.On("PopTemporalAuthURLData", &stateID).Return(nil, models.ErrOAuthStateInvalid)
+
+	_, _, _, _, err := service.ExchangeOAuthCredential(&models.RequestExchangeCredential{Sub: "user_1", Data: models.DataCredential{State: state}})
+	assert.ErrorIs(t, err, models.ErrOAuthStateInvalid)
+}
@@ -0,0 +1,73 @@
+package tests
+
+import (
+	"minireipaz/mocks"
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/domain/services"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestOutboxService_TickRelaysInStreamOrder(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	// an older command left by a failed publish goes before the one just written
+	pending := []models.OutboxMessage{
+		{ID: "1-0", Key: "wf_1", DedupKey: "workflow:update:wf_1:2"},
+		{ID: "2-0", Key: "wf_1", DedupKey: "workflow:pause:wf_1:3"},
+	}
+	redisRepo := mocks.NewOutboxRedisRepository(t)
+	brokerRepo := mocks.NewOutboxBrokerRepository(t)
+	redisRepo.On("AcquireLeadership", mock.Anything, models.OutboxLeaderTTL).Return(true, nil)
+	redisRepo.On("GetPending", now, int64(models.MaxOutboxPerTick)).Return(pending, nil)
+	redisRepo.On("IsPublished", mock.Anything).Return(false, nil)
+	redisRepo.On("Ack", mock.Anything).Return(nil)
+	published := []string{}
+	brokerRepo.On("Publish", mock.Anything).Run(func(args mock.Arguments) {
+		published = append(published, args.Get(0).(*models.OutboxMessage).ID)
+	}).Return(true)
+
+	service := services.NewOutboxService(redisRepo, brokerRepo)
+	relayed, err := service.Tick(now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, relayed)
+	assert.Equal(t, []string{"1-0", "2-0"}, published)
+}
+
+func TestOutboxService_RelaySkipsPublishedAndStopsOnFailure(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	pending := []models.OutboxMessage{
+		{ID: "1-0", DedupKey: "workflow:create:wf_1:1"},
+		{ID: "2-0", DedupKey: "workflow:create:wf_2:1"},
+		{ID: "3-0", DedupKey: "workflow:create:wf_3:1"},
+		{ID: "4-0", DedupKey: "workflow:create:wf_4:1"},
+	}
+	redisRepo := mocks.NewOutboxRedisRepository(t)
+	brokerRepo := mocks.NewOutboxBrokerRepository(t)
+	redisRepo.On("GetPending", now, int64(models.MaxOutboxPerTick)).Return(pending, nil)
+	redisRepo.On("IsPublished", strPtr("workflow:create:wf_1:1")).Return(true, nil)
+	redisRepo.On("IsPublished", mock.Anything).Return(false, nil)
+	redisRepo.On("Ack", mock.Anything).Return(nil)
+	brokerRepo.On("Publish", mock.MatchedBy(func(message *models.OutboxMessage) bool { return message.ID == "2-0" })).Return(true)
+	brokerRepo.On("Publish", mock.MatchedBy(func(message *models.OutboxMessage) bool { return message.ID == "3-0" })).Return(false)
+
+	service := services.NewOutboxService(redisRepo, brokerRepo)
+	relayed, err := service.Relay(now)
+	assert.ErrorIs(t, err, models.ErrOutboxCannotPublish)
+	assert.Equal(t, 1, relayed)
+	redisRepo.AssertNumberOfCalls(t, "Ack", 2)
+	brokerRepo.AssertNotCalled(t, "Publish", mock.MatchedBy(func(message *models.OutboxMessage) bool { return message.ID == "4-0" }))
+}
+
+func TestOutboxService_TickOnlyLeaderRelays(t *testing.T) {
+	redisRepo := mocks.NewOutboxRedisRepository(t)
+	redisRepo.On("AcquireLeadership", mock.Anything, models.OutboxLeaderTTL).Return(false, nil)
+
+	service := services.NewOutboxService(redisRepo, mocks.NewOutboxBrokerRepository(t))
+	relayed, err := service.Tick(time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, relayed)
+}
@@ -0,0 +1,180 @@
+package tests
+
+import (
+	"minireipaz/mocks"
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/domain/services"
+	"minireipaz/pkg/infra/memoryclient"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRunService_CreateRunResolvesGraph(t *testing.T) {
+	userID := "user_1"
+	workflowID := "wf_1"
+	edge := func(source, target string) models.Edge {
+		return models.Edge{ID: strPtr(source + "-" + target), Source: strPtr(source), Target: strPtr(target)}
+	}
+	workflow := &models.Workflow{
+		UUID:    workflowID,
+		UserID:  userID,
+		Version: 3,
+		Nodes: []models.Node{
+			{ID: "sheet", Data: &models.DataNode{Type: models.GoogleSheets}},
+			{ID: "notion", Data: &models.DataNode{Type: models.NotionToken}},
+			{ID: models.InitialNodeID},
+			{ID: "orphan", Data: &models.DataNode{Type: models.NotionToken}},
+		},
+		Edges: []models.Edge{edge("sheet", "notion"), edge(models.InitialNodeID, "sheet"), edge(models.InitialNodeID, "notion")},
+	}
+	workflowService := mocks.NewWorkflowService(t)
+	redisRepo := mocks.NewRunRedisRepository(t)
+	brokerRepo := mocks.NewRunBrokerRepository(t)
+	workflowService.On("GetWorkflow", &userID, &workflowID).Return(workflow, true)
+	workflowService.On("ValidateWorkflowGraph", workflow).Return([]models.GraphValidationError{})
+	message := &models.OutboxMessage{DedupKey: "run"}
+	brokerRepo.On("CreateCommand", mock.MatchedBy(func(run *models.Run) bool {
+		return run.DryRun && run.Version == 3
+	}), mock.MatchedBy(func(graph []models.RunNode) bool {
+		return len(graph) == 3 && graph[0].ID == models.InitialNodeID && graph[1].ID == "sheet" && graph[2].ID == "notion"
+	})).Return(message, nil)
+	redisRepo.On("Save", mock.AnythingOfType("*models.Run"), message).Return(nil)
+	outbox := mocks.NewOutboxService(t)
+	outbox.On("Tick", mock.Anything).Return(1, nil)
+
+	service := services.NewRunService(redisRepo, brokerRepo, mocks.NewRunHTTPRepository(t), workflowService, memoryclient.NewStatusPublisher(), outbox)
+	run, graphErrors, err := service.CreateRun(&userID, &workflowID, true)
+
+	assert.NoError(t, err)
+	assert.Empty(t, graphErrors)
+	assert.NotEmpty(t, run.RunID)
+	assert.Equal(t, models.RunStatusQueued, run.Status)
+	assert.Len(t, run.Steps, 3)
+	assert.Equal(t, models.GoogleSheets, run.Steps[1].Type)
+}
+
+func TestRunService_CreateRunInvalidGraph(t *testing.T) {
+	userID := "user_1"
+	workflowID := "wf_1"
+	workflow := &models.Workflow{UUID: workflowID, UserID: userID}
+	graphErrors := []models.GraphValidationError{{Code: models.GraphErrorMissingStartNode}}
+	workflowService := mocks.NewWorkflowService(t)
+	workflowService.On("GetWorkflow", &userID, &workflowID).Return(workflow, true)
+	workflowService.On("ValidateWorkflowGraph", workflow).Return(graphErrors)
+
+	service := services.NewRunService(mocks.NewRunRedisRepository(t), mocks.NewRunBrokerRepository(t), mocks.NewRunHTTPRepository(t), workflowService, memoryclient.NewStatusPublisher(), nil)
+	run, returned, err := service.CreateRun(&userID, &workflowID, false)
+
+	assert.ErrorIs(t, err, models.ErrRunGraphInvalid)
+	assert.Nil(t, run)
+	assert.Equal(t, graphErrors, returned)
+}
+
+func TestRunService_CreateRunSaveFails(t *testing.T) {
+	userID := "user_1"
+	workflowID := "wf_1"
+	workflow := &models.Workflow{UUID: workflowID, UserID: userID, Nodes: []models.Node{{ID: models.InitialNodeID}}}
+	workflowService := mocks.NewWorkflowService(t)
+	redisRepo := mocks.NewRunRedisRepository(t)
+	brokerRepo := mocks.NewRunBrokerRepository(t)
+	workflowService.On("GetWorkflow", &userID, &workflowID).Return(workflow, true)
+	workflowService.On("ValidateWorkflowGraph", workflow).Return([]models.GraphValidationError{})
+	brokerRepo.On("CreateCommand", mock.Anything, mock.Anything).Return(&models.OutboxMessage{DedupKey: "run"}, nil)
+	redisRepo.On("Save", mock.AnythingOfType("*models.Run"), mock.Anything).Return(assert.AnError)
+
+	// nothing is relayed when the run was not saved
+	service := services.NewRunService(redisRepo, brokerRepo, mocks.NewRunHTTPRepository(t), workflowService, memoryclient.NewStatusPublisher(), mocks.NewOutboxService(t))
+	run, _, err := service.CreateRun(&userID, &workflowID, false)
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Nil(t, run)
+}
+
+func TestRunService_GetRunFromHistory(t *testing.T) {
+	userID := "user_1"
+	runID := "run_1"
+	httpRepo := mocks.NewRunHTTPRepository(t)
+	httpRepo.On("GetRun", &userID, &runID).Return(&models.InfoRuns{Data: []models.RunSummary{
+		{RunID: runID, WorkflowID: "wf_1", UserID: userID, Status: models.RunStatusFailed, DurationMs: 1200},
+	}}, nil)
+	httpRepo.On("GetRunSteps", &userID, &runID).Return(&models.InfoRunSteps{Data: []models.RunStepResult{
+		{NodeID: models.InitialNodeID, Status: models.RunStatusSucceeded, Attempts: 1},
+		{NodeID: "sheet", Status: models.RunStatusFailed, Error: "quota exceeded", Attempts: 3},
+	}}, nil)
+
+	service := services.NewRunService(mocks.NewRunRedisRepository(t), mocks.NewRunBrokerRepository(t), httpRepo, mocks.NewWorkflowService(t), memoryclient.NewStatusPublisher(), nil)
+	run, err := service.GetRun(&userID, &runID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.RunStatusFailed, run.Status)
+	assert.Len(t, run.Steps, 2)
+	assert.Equal(t, uint32(3), run.Steps[1].Attempts)
+	assert.Equal(t, "quota exceeded", run.Steps[1].Error)
+}
+
+func TestRunService_GetRunNotPickedYet(t *testing.T) {
+	userID := "user_1"
+	otherUserID := "user_2"
+	runID := "run_1"
+	httpRepo := mocks.NewRunHTTPRepository(t)
+	redisRepo := mocks.NewRunRedisRepository(t)
+	httpRepo.On("GetRun", mock.Anything, &runID).Return(&models.InfoRuns{}, nil)
+	redisRepo.On("Get", &runID).Return(&models.Run{
+		RunID:  runID,
+		UserID: userID,
+		Status: models.RunStatusQueued,
+		Steps:  []models.RunStep{{NodeID: models.InitialNodeID, Type: models.NodeTypeStart, Status: models.StepStatusPending}},
+	}, nil)
+
+	service := services.NewRunService(redisRepo, mocks.NewRunBrokerRepository(t), httpRepo, mocks.NewWorkflowService(t), memoryclient.NewStatusPublisher(), nil)
+	run, err := service.GetRun(&userID, &runID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.RunStatusQueued, run.Status)
+	assert.Equal(t, models.StepStatusPending, run.Steps[0].Status)
+
+	_, err = service.GetRun(&otherUserID, &runID)
+	assert.ErrorIs(t, err, models.ErrRunNotFound)
+}
+
+func TestRunService_GetWorkflowRunsPages(t *testing.T) {
+	userID := "user_1"
+	workflowID := "wf_1"
+	startedAt := &models.CustomTime{Time: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}
+	workflowService := mocks.NewWorkflowService(t)
+	httpRepo := mocks.NewRunHTTPRepository(t)
+	workflowService.On("ValidateWorkflowGlobalUUID", &workflowID).Return(true)
+	httpRepo.On("GetWorkflowRuns", &userID, &workflowID, (*models.WorkflowCursor)(nil), uint64(3)).Return(&models.InfoRuns{Data: []models.RunSummary{
+		{RunID: "run_3"}, {RunID: "run_2", StartedAt: startedAt}, {RunID: "run_1"},
+	}}, nil).Once()
+	httpRepo.On("GetWorkflowRuns", &userID, &workflowID, mock.MatchedBy(func(cursor *models.WorkflowCursor) bool {
+		return cursor != nil && cursor.ID == "run_2" && cursor.Value == "2026-01-02T03:04:05Z"
+	}), uint64(3)).Return(&models.InfoRuns{Data: []models.RunSummary{{RunID: "run_1"}}}, nil).Once()
+
+	service := services.NewRunService(mocks.NewRunRedisRepository(t), mocks.NewRunBrokerRepository(t), httpRepo, workflowService, memoryclient.NewStatusPublisher(), nil)
+	page, err := service.GetWorkflowRuns(&models.RunListQuery{UserID: userID, WorkflowID: workflowID, Limit: 2})
+
+	assert.NoError(t, err)
+	assert.Len(t, page.Runs, 2)
+	assert.True(t, page.HasMore)
+
+	page, err = service.GetWorkflowRuns(&models.RunListQuery{UserID: userID, WorkflowID: workflowID, Limit: 2, Cursor: page.NextCursor})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "run_1", page.Runs[0].RunID)
+	assert.False(t, page.HasMore)
+	assert.Empty(t, page.NextCursor)
+}
+
+func TestRunService_GetWorkflowRunsInvalidCursor(t *testing.T) {
+	workflowService := mocks.NewWorkflowService(t)
+	workflowService.On("ValidateWorkflowGlobalUUID", strPtr("wf_1")).Return(true)
+
+	service := services.NewRunService(mocks.NewRunRedisRepository(t), mocks.NewRunBrokerRepository(t), mocks.NewRunHTTPRepository(t), workflowService, memoryclient.NewStatusPublisher(), nil)
+	_, err := service.GetWorkflowRuns(&models.RunListQuery{UserID: "user_1", WorkflowID: "wf_1", Cursor: "not-a-cursor"})
+
+	assert.ErrorIs(t, err, models.ErrWorkflowCursorInvalid)
+}
@@ -0,0 +1,261 @@
+package tests
+
+import (
+	"minireipaz/mocks"
+	"minireipaz/pkg/common"
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/domain/services"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCron_Next(t *testing.T) {
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		expression string
+		after      time.Time
+		location   *time.Location
+		expected   time.Time
+	}{
+		{
+			name:       "Every 15 minutes",
+			expression: "*/15 * * * *",
+			after:      time.Date(2024, 5, 10, 10, 7, 30, 0, time.UTC),
+			location:   time.UTC,
+			expected:   time.Date(2024, 5, 10, 10, 15, 0, 0, time.UTC),
+		},
+		{
+			name:       "Weekdays at 9 in Madrid",
+			expression: "0 9 * * 1-5",
+			after:      time.Date(2024, 5, 10, 8, 0, 0, 0, time.UTC), // friday 10:00 in Madrid
+			location:   madrid,
+			expected:   time.Date(2024, 5, 13, 7, 0, 0, 0, time.UTC), // monday 09:00 CEST
+		},
+		{
+			name:       "Daily across daylight saving change",
+			expression: "@daily",
+			after:      time.Date(2024, 3, 30, 12, 0, 0, 0, time.UTC),
+			location:   madrid,
+			expected:   time.Date(2024, 3, 30, 23, 0, 0, 0, time.UTC), // 31th 00:00 CET
+		},
+		{
+			name:       "Day of month or day of week",
+			expression: "0 0 1 * 0",
+			after:      time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), // saturday 1st
+			location:   time.UTC,
+			expected:   time.Date(2024, 6, 2, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cron, err := common.ParseCron(tt.expression)
+			assert.NoError(t, err)
+			assert.True(t, tt.expected.Equal(cron.Next(tt.after, tt.location)), "got %v", cron.Next(tt.after, tt.location).UTC())
+		})
+	}
+
+	for _, invalid := range []string{"* * * *", "60 * * * *", "*/0 * * * *", "5-1 * * * *"} {
+		_, err := common.ParseCron(invalid)
+		assert.Error(t, err, invalid)
+	}
+
+	never, _ := common.ParseCron("0 0 30 2 *")
+	assert.True(t, never.Next(time.Now(), time.UTC).IsZero())
+}
+
+func TestSchedulerService_SaveScheduleInvalid(t *testing.T) {
+	service := services.NewSchedulerService(mocks.NewSchedulerRedisRepository(t), mocks.NewWorkflowService(t))
+
+	_, err := service.SaveSchedule(&models.RequestCreateSchedule{UserID: "user_1", WorkflowID: "wf_1", NodeID: "n1", Kind: models.ScheduleKindInterval, IntervalSeconds: 10})
+	assert.ErrorIs(t, err, models.ErrScheduleInvalid)
+
+	_, err = service.SaveSchedule(&models.RequestCreateSchedule{UserID: "user_1", WorkflowID: "wf_1", NodeID: "n1", Kind: models.ScheduleKindCron, Expression: "0 9 * * *", Timezone: "Mars/Olympus"})
+	assert.ErrorIs(t, err, models.ErrTimezoneInvalid)
+}
+
+func TestSchedulerService_SaveScheduleOwner(t *testing.T) {
+	request := &models.RequestCreateSchedule{UserID: "user_1", WorkflowID: "wf_1", NodeID: "n1", Kind: models.ScheduleKindInterval, IntervalSeconds: 300}
+
+	t.Run("Error - Workflow of another user", func(t *testing.T) {
+		redisRepo := mocks.NewSchedulerRedisRepository(t)
+		workflowService := mocks.NewWorkflowService(t)
+		workflowService.On("GetWorkflow", &request.UserID, &request.WorkflowID).Return(nil, false)
+
+		service := services.NewSchedulerService(redisRepo, workflowService)
+		_, err := service.SaveSchedule(request)
+
+		assert.ErrorIs(t, err, models.ErrScheduleNotFound)
+		redisRepo.AssertNotCalled(t, "Save", mock.Anything)
+	})
+
+	t.Run("Error - Schedule of the node owned by another user", func(t *testing.T) {
+		redisRepo := mocks.NewSchedulerRedisRepository(t)
+		workflowService := mocks.NewWorkflowService(t)
+		workflowService.On("GetWorkflow", &request.UserID, &request.WorkflowID).Return(&models.Workflow{UUID: "wf_1"}, true)
+		redisRepo.On("Get", &request.WorkflowID, &request.NodeID).Return(&models.Schedule{WorkflowID: "wf_1", NodeID: "n1", UserID: "user_2"}, nil)
+
+		service := services.NewSchedulerService(redisRepo, workflowService)
+		_, err := service.SaveSchedule(request)
+
+		assert.ErrorIs(t, err, models.ErrScheduleNotFound)
+		redisRepo.AssertNotCalled(t, "Save", mock.Anything)
+	})
+
+	t.Run("Success - New schedule of the owner", func(t *testing.T) {
+		redisRepo := mocks.NewSchedulerRedisRepository(t)
+		workflowService := mocks.NewWorkflowService(t)
+		workflowService.On("GetWorkflow", &request.UserID, &request.WorkflowID).Return(&models.Workflow{UUID: "wf_1"}, true)
+		workflowService.On("IsWorkflowActive", &request.WorkflowID).Return(true, nil)
+		redisRepo.On("Get", &request.WorkflowID, &request.NodeID).Return(nil, models.ErrScheduleNotFound)
+		redisRepo.On("Save", mock.MatchedBy(func(schedule *models.Schedule) bool {
+			return schedule.UserID == "user_1" && schedule.Action.Sub == "user_1" && !schedule.Disabled
+		})).Return(nil).Once()
+
+		service := services.NewSchedulerService(redisRepo, workflowService)
+		_, err := service.SaveSchedule(request)
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestSchedulerService_TickFiresDueSchedules(t *testing.T) {
+	now := time.Date(2024, 5, 10, 10, 0, 20, 0, time.UTC)
+	due := []models.Schedule{
+		{
+			Action:          models.RequestGoogleAction{Type: models.GoogleSheets, Pollmode: "5m", Testmode: true},
+			WorkflowID:      "wf_1",
+			NodeID:          "sheet",
+			UserID:          "user_1",
+			Kind:            models.ScheduleKindInterval,
+			Timezone:        models.DefaultTimezone,
+			IntervalSeconds: 300,
+			NextRunAt:       "2024-05-10T09:50:00Z",
+		},
+		{
+			WorkflowID: "wf_2",
+			NodeID:     "sheet",
+			UserID:     "user_1",
+			Kind:       models.ScheduleKindCron,
+			Expression: "0 * * * *",
+			Timezone:   models.DefaultTimezone,
+			NextRunAt:  "2024-05-10T10:00:00Z",
+		},
+	}
+
+	redisRepo := mocks.NewSchedulerRedisRepository(t)
+	creator := mocks.NewScheduledActionCreator(t)
+	workflowService := mocks.NewWorkflowService(t)
+	redisRepo.On("AcquireLeadership", mock.Anything, models.SchedulerLeaderTTL).Return(true, nil)
+	redisRepo.On("GetDue", now, int64(models.MaxSchedulesPerTick)).Return(due, nil)
+	workflowService.On("IsWorkflowActive", mock.Anything).Return(true, nil)
+	// missed runs of the interval are skipped keeping the cadence
+	redisRepo.On("Advance", mock.MatchedBy(func(schedule *models.Schedule) bool {
+		return schedule.WorkflowID == "wf_1" && schedule.NextRunAt == "2024-05-10T10:05:00Z"
+	}), "2024-05-10T09:50:00Z").Return(true, nil)
+	// already fired by another instance
+	redisRepo.On("Advance", mock.MatchedBy(func(schedule *models.Schedule) bool {
+		return schedule.WorkflowID == "wf_2" && schedule.NextRunAt == "2024-05-10T11:00:00Z"
+	}), "2024-05-10T10:00:00Z").Return(false, nil)
+	// the creator gives the action id
+	creator.On("CreateScheduledAction", mock.MatchedBy(func(action *models.RequestGoogleAction) bool {
+		return action.Type == models.GoogleSheets && action.ActionID == "" && !action.Testmode && action.CreatedAt == "2024-05-10T10:00:20Z"
+	})).Return(strPtr("action_1"), nil).Once()
+
+	service := services.NewSchedulerService(redisRepo, workflowService)
+	service.RegisterCreator(creator)
+	fired, err := service.Tick(now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, fired)
+}
+
+func TestSchedulerService_TickNotLeader(t *testing.T) {
+	redisRepo := mocks.NewSchedulerRedisRepository(t)
+	redisRepo.On("AcquireLeadership", mock.Anything, models.SchedulerLeaderTTL).Return(false, nil)
+
+	service := services.NewSchedulerService(redisRepo, mocks.NewWorkflowService(t))
+	fired, err := service.Tick(time.Now())
+
+	assert.NoError(t, err)
+	assert.Zero(t, fired)
+	redisRepo.AssertNotCalled(t, "GetDue", mock.Anything, mock.Anything)
+}
+
+func TestSchedulerService_RunDueSkipsInactiveWorkflows(t *testing.T) {
+	now := time.Date(2024, 5, 10, 10, 0, 20, 0, time.UTC)
+	paused := models.Schedule{WorkflowID: "wf_paused", NodeID: "sheet", UserID: "user_1", Kind: models.ScheduleKindCron, Expression: "0 * * * *", Timezone: models.DefaultTimezone, NextRunAt: "2024-05-10T10:00:00Z"}
+	otherNode := paused
+	otherNode.NodeID = "notion"
+
+	redisRepo := mocks.NewSchedulerRedisRepository(t)
+	workflowService := mocks.NewWorkflowService(t)
+	redisRepo.On("GetDue", now, int64(models.MaxSchedulesPerTick)).Return([]models.Schedule{paused, otherNode}, nil)
+	workflowService.On("IsWorkflowActive", &paused.WorkflowID).Return(false, nil).Once()
+	// kept out of the due set until the workflow runs again
+	redisRepo.On("Save", mock.MatchedBy(func(schedule *models.Schedule) bool { return schedule.Disabled })).Return(nil).Twice()
+
+	service := services.NewSchedulerService(redisRepo, workflowService)
+	fired, err := service.RunDue(now)
+
+	assert.NoError(t, err)
+	assert.Zero(t, fired)
+	redisRepo.AssertNotCalled(t, "Advance", mock.Anything, mock.Anything)
+}
+
+func TestSchedulerService_SyncTriggers(t *testing.T) {
+	workflowID := "wf_1"
+	disabled := models.Schedule{WorkflowID: workflowID, NodeID: "sheet", Kind: models.ScheduleKindInterval, Timezone: models.DefaultTimezone, IntervalSeconds: 300, NextRunAt: "2024-05-10T09:50:00Z", Disabled: true}
+	enabled := models.Schedule{WorkflowID: workflowID, NodeID: "notion", Kind: models.ScheduleKindInterval, Timezone: models.DefaultTimezone, IntervalSeconds: 300, NextRunAt: "2024-05-10T09:50:00Z"}
+
+	t.Run("Enable - runs missed while disabled are skipped", func(t *testing.T) {
+		redisRepo := mocks.NewSchedulerRedisRepository(t)
+		redisRepo.On("GetByWorkflow", &workflowID).Return([]models.Schedule{disabled, enabled}, nil)
+		redisRepo.On("Save", mock.MatchedBy(func(schedule *models.Schedule) bool {
+			next, err := time.Parse(models.LayoutTimestamp, schedule.NextRunAt)
+			return schedule.NodeID == "sheet" && !schedule.Disabled && err == nil && next.After(time.Now())
+		})).Return(nil).Once()
+
+		service := services.NewSchedulerService(redisRepo, mocks.NewWorkflowService(t))
+		assert.NoError(t, service.SyncTriggers(&workflowID, true))
+	})
+
+	t.Run("Disable", func(t *testing.T) {
+		redisRepo := mocks.NewSchedulerRedisRepository(t)
+		redisRepo.On("GetByWorkflow", &workflowID).Return([]models.Schedule{disabled, enabled}, nil)
+		redisRepo.On("Save", mock.MatchedBy(func(schedule *models.Schedule) bool {
+			return schedule.NodeID == "notion" && schedule.Disabled && schedule.NextRunAt == "2024-05-10T09:50:00Z"
+		})).Return(nil).Once()
+
+		service := services.NewSchedulerService(redisRepo, mocks.NewWorkflowService(t))
+		assert.NoError(t, service.SyncTriggers(&workflowID, false))
+	})
+}
+
+func TestSchedulerService_RunDueCreatorFailedIsDueAgain(t *testing.T) {
+	now := time.Date(2024, 5, 10, 10, 0, 20, 0, time.UTC)
+	due := models.Schedule{WorkflowID: "wf_1", NodeID: "sheet", UserID: "user_1", Kind: models.ScheduleKindCron, Expression: "0 * * * *", Timezone: models.DefaultTimezone, NextRunAt: "2024-05-10T10:00:00Z"}
+
+	redisRepo := mocks.NewSchedulerRedisRepository(t)
+	workflowService := mocks.NewWorkflowService(t)
+	creator := mocks.NewScheduledActionCreator(t)
+	redisRepo.On("GetDue", now, int64(models.MaxSchedulesPerTick)).Return([]models.Schedule{due}, nil)
+	workflowService.On("IsWorkflowActive", &due.WorkflowID).Return(true, nil)
+	redisRepo.On("Advance", mock.MatchedBy(func(schedule *models.Schedule) bool { return schedule.NextRunAt == "2024-05-10T11:00:00Z" }), "2024-05-10T10:00:00Z").Return(true, nil).Once()
+	creator.On("CreateScheduledAction", mock.Anything).Return(nil, models.ErrScheduledActionFailed).Once()
+	// back to the previous fire time
+	redisRepo.On("Advance", mock.MatchedBy(func(schedule *models.Schedule) bool { return schedule.NextRunAt == "2024-05-10T10:00:00Z" }), "2024-05-10T11:00:00Z").Return(true, nil).Once()
+
+	service := services.NewSchedulerService(redisRepo, workflowService)
+	service.RegisterCreator(creator)
+	fired, err := service.RunDue(now)
+
+	assert.NoError(t, err)
+	assert.Zero(t, fired)
+}
@@ -0,0 +1,110 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/base64"
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/domain/repos"
+	"minireipaz/pkg/domain/services"
+	"minireipaz/pkg/infra/secretclient"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testKey(seed byte) string {
+	return base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{seed}, 32))
+}
+
+func newSecretService(t *testing.T, keys, currentKeyID string) repos.SecretService {
+	provider, err := secretclient.ParseLocalKeys(keys, currentKeyID)
+	assert.NoError(t, err)
+	return services.NewSecretService(provider)
+}
+
+func TestSecretService_EncryptCredential(t *testing.T) {
+	secrets := newSecretService(t, "key_1:"+testKey(1), "")
+	data := models.DataCredential{ClientID: "client_1", ClientSecret: "secret_1", Token: "access_1", TokenRefresh: "refresh_1"}
+
+	assert.NoError(t, secrets.EncryptCredential(&data))
+	assert.Equal(t, "client_1", data.ClientID)
+	for _, value := range []string{data.ClientSecret, data.Token, data.TokenRefresh} {
+		assert.True(t, strings.HasPrefix(value, models.SecretPrefix+"key_1:"))
+	}
+	// already encrypted values are left as they are
+	encrypted := data
+	assert.NoError(t, secrets.EncryptCredential(&data))
+	assert.Equal(t, encrypted, data)
+
+	assert.NoError(t, secrets.DecryptCredential(&data))
+	assert.Equal(t, models.DataCredential{ClientID: "client_1", ClientSecret: "secret_1", Token: "access_1", TokenRefresh: "refresh_1"}, data)
+}
+
+func TestSecretService_DecryptAfterRotation(t *testing.T) {
+	old, err := newSecretService(t, "key_1:"+testKey(1), "").Encrypt("access_1")
+	assert.NoError(t, err)
+
+	rotated := newSecretService(t, "key_1:"+testKey(1)+",key_2:"+testKey(2), "key_2")
+	plaintext, err := rotated.Decrypt(old)
+	assert.NoError(t, err)
+	assert.Equal(t, "access_1", plaintext)
+
+	current, err := rotated.Encrypt("access_1")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(current, models.SecretPrefix+"key_2:"))
+
+	_, err = newSecretService(t, "key_2:"+testKey(2), "").Decrypt(old)
+	assert.ErrorIs(t, err, models.ErrSecretKeyUnknown)
+}
+
+func TestSecretService_DecryptRejectsModified(t *testing.T) {
+	secrets := newSecretService(t, "key_1:"+testKey(1), "")
+	encrypted, err := secrets.Encrypt("access_1")
+	assert.NoError(t, err)
+
+	sealed := encrypted[strings.LastIndex(encrypted, ":")+1:]
+	modified := encrypted[:strings.LastIndex(encrypted, ":")+1] + strings.Repeat("A", len(sealed))
+	_, err = secrets.Decrypt(modified)
+	assert.ErrorIs(t, err, models.ErrSecretMalformed)
+
+	// saved before encryption was enabled
+	plaintext, err := secrets.Decrypt("access_1")
+	assert.NoError(t, err)
+	assert.Equal(t, "access_1", plaintext)
+}
+
+func TestParseLocalKeys_Invalid(t *testing.T) {
+	_, err := secretclient.ParseLocalKeys("key_1:"+base64.StdEncoding.EncodeToString([]byte("short")), "")
+	assert.ErrorIs(t, err, models.ErrSecretKeyInvalid)
+
+	_, err = secretclient.ParseLocalKeys("key_1:"+testKey(1), "key_2")
+	assert.ErrorIs(t, err, models.ErrSecretKeyUnknown)
+
+	_, err = secretclient.ParseLocalKeys(" ", "")
+	assert.ErrorIs(t, err, models.ErrSecretKeysMissing)
+}
+
+type staticSecretsConfig struct {
+	keys      string
+	ephemeral bool
+}
+
+func (s *staticSecretsConfig) GetCredentialKeys() string        { return s.keys }
+func (s *staticSecretsConfig) GetCredentialKeyID() string       { return "" }
+func (s *staticSecretsConfig) GetEphemeralCredentialKey() bool  { return s.ephemeral }
+func (s *staticSecretsConfig) GetOAuthStateSecret() string      { return "" }
+func (s *staticSecretsConfig) GetEnv(_, fallback string) string { return fallback }
+
+func TestNewLocalKeyProvider_RequiresKeys(t *testing.T) {
+	assert.Panics(t, func() { secretclient.NewLocalKeyProvider(&staticSecretsConfig{}) })
+
+	// only when asked for, what it encrypts is lost with the instance
+	provider := secretclient.NewLocalKeyProvider(&staticSecretsConfig{ephemeral: true})
+	secrets := services.NewSecretService(provider)
+	encrypted, err := secrets.Encrypt("secret_1")
+	assert.NoError(t, err)
+	plaintext, err := secrets.Decrypt(encrypted)
+	assert.NoError(t, err)
+	assert.Equal(t, "secret_1", plaintext)
+}
@@ -0,0 +1,71 @@
+package tests
+
+import (
+	"context"
+	"io"
+	"minireipaz/mocks"
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/infra/memoryclient"
+	"minireipaz/pkg/interfaces/controllers"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestStatusPublisher_OnlySubscribedUser(t *testing.T) {
+	userID := "user_1"
+	publisher := memoryclient.NewStatusPublisher()
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := publisher.Subscribe(ctx, &userID)
+	assert.NoError(t, err)
+
+	assert.NoError(t, publisher.Publish(&models.StatusEvent{Kind: models.StatusKindAction, ID: "action_2", UserID: "user_2", Status: models.ActionStatusPending}))
+	assert.NoError(t, publisher.Publish(&models.StatusEvent{Kind: models.StatusKindAction, ID: "action_1", UserID: userID, Status: models.ActionStatusCompleted}))
+
+	select {
+	case event := <-events:
+		assert.Equal(t, "action_1", event.ID)
+		assert.Equal(t, models.ActionStatusCompleted, event.Status)
+	case <-time.After(time.Second):
+		t.Fatal("event not received")
+	}
+
+	cancel()
+	select {
+	case _, ok := <-events:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("events not closed after cancel")
+	}
+}
+
+func TestStatusController_StreamStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	userID := "user_1"
+	events := make(chan models.StatusEvent, 1)
+	events <- models.StatusEvent{Kind: models.StatusKindRun, ID: "run_1", UserID: userID, Status: models.RunStatusQueued}
+	close(events)
+
+	statusService := mocks.NewStatusService(t)
+	statusService.On("Subscribe", mock.Anything, &userID).Return((<-chan models.StatusEvent)(events), nil)
+	controller := controllers.NewStatusController(statusService)
+	router := gin.New()
+	router.GET("/status/:iduser", controller.StreamStatus)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/status/" + userID)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	assert.Contains(t, string(body), "event:run")
+	assert.Contains(t, string(body), `"id":"run_1"`)
+}
@@ -0,0 +1,153 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"minireipaz/mocks"
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/domain/services"
+	"minireipaz/pkg/interfaces/controllers"
+	"minireipaz/pkg/interfaces/middlewares"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTemplateService_GetTemplates(t *testing.T) {
+	redisRepo := mocks.NewTemplateRedisRepository(t)
+	redisRepo.On("GetAll").Return([]models.WorkflowTemplate{
+		{ID: "old", Category: models.TemplateCategoryGoogle, CreatedAt: "2024-01-01 00:00:00"},
+		{ID: "new", Category: models.TemplateCategoryGoogle, CreatedAt: "2024-06-01 00:00:00"},
+		{ID: "other", Category: "marketing", CreatedAt: "2024-03-01 00:00:00"},
+	}, nil)
+
+	service := services.NewTemplateService(redisRepo, mocks.NewWorkflowService(t), services.NewUUIDService())
+	templates, err := service.GetTemplates(models.TemplateCategoryGoogle)
+
+	assert.NoError(t, err)
+	ids := []string{}
+	for _, template := range templates {
+		ids = append(ids, template.ID)
+	}
+	assert.Equal(t, []string{"builtin-read-sheet", "new", "old"}, ids)
+}
+
+func TestTemplateService_BuiltinGraphsAreValid(t *testing.T) {
+	validator := services.NewGraphValidator(services.NewNodeService())
+	service := services.NewTemplateService(mocks.NewTemplateRedisRepository(t), mocks.NewWorkflowService(t), services.NewUUIDService())
+
+	for _, templateID := range []string{"builtin-blank", "builtin-read-sheet", "builtin-sheet-to-notion"} {
+		template, err := service.GetTemplate(&templateID)
+		assert.NoError(t, err)
+		workflow := models.Workflow{IsActive: models.Draft, Nodes: template.Graph.Nodes, Edges: template.Graph.Edges}
+		assert.Empty(t, validator.Validate(&workflow), templateID)
+	}
+}
+
+func TestTemplateService_DeleteTemplate(t *testing.T) {
+	userID := "user_1"
+
+	tests := []struct {
+		name        string
+		templateID  string
+		setupMocks  func(*mocks.TemplateRedisRepository)
+		expectedErr error
+	}{
+		{
+			name:        "Error - Built-in template",
+			templateID:  "builtin-blank",
+			setupMocks:  func(_ *mocks.TemplateRedisRepository) {},
+			expectedErr: models.ErrTemplateNotOwner,
+		},
+		{
+			name:       "Error - Published by another user",
+			templateID: "tpl_1",
+			setupMocks: func(m *mocks.TemplateRedisRepository) {
+				m.On("Get", mock.Anything).Return(&models.WorkflowTemplate{ID: "tpl_1", AuthorID: "user_2"}, nil)
+			},
+			expectedErr: models.ErrTemplateNotOwner,
+		},
+		{
+			name:       "Success - Author removes template",
+			templateID: "tpl_1",
+			setupMocks: func(m *mocks.TemplateRedisRepository) {
+				m.On("Get", mock.Anything).Return(&models.WorkflowTemplate{ID: "tpl_1", AuthorID: userID}, nil)
+				m.On("Remove", mock.Anything).Return(true, nil)
+			},
+			expectedErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redisRepo := mocks.NewTemplateRedisRepository(t)
+			tt.setupMocks(redisRepo)
+			service := services.NewTemplateService(redisRepo, mocks.NewWorkflowService(t), services.NewUUIDService())
+
+			err := service.DeleteTemplate(&userID, &tt.templateID)
+			if tt.expectedErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestTemplateService_CreateWorkflowFromTemplate(t *testing.T) {
+	templateID := "builtin-read-sheet"
+	request := models.RequestCreateFromTemplate{UserID: "user_1", Name: "my flow", DirectoryToSave: "/"}
+	imported := &models.ImportedWorkflow{Workflow: &models.Workflow{UUID: "wf_1", Name: "my flow"}}
+
+	workflowService := mocks.NewWorkflowService(t)
+	workflowService.On("ImportWorkflow", &request.UserID, &request.DirectoryToSave, mock.MatchedBy(func(document *models.WorkflowDocument) bool {
+		return document.Name == "my flow" && len(document.Nodes) == 2
+	})).Return(imported, nil, nil)
+
+	service := services.NewTemplateService(mocks.NewTemplateRedisRepository(t), workflowService, services.NewUUIDService())
+	result, graphErrors, err := service.CreateWorkflowFromTemplate(&templateID, &request)
+
+	assert.NoError(t, err)
+	assert.Empty(t, graphErrors)
+	assert.Equal(t, imported, result)
+}
+
+func TestTemplateController_PublishTemplateUsesUserOfPath(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	templateService := mocks.NewTemplateService(t)
+	templateService.On("PublishTemplate", mock.MatchedBy(func(request *models.RequestPublishTemplate) bool {
+		return request.UserID == "user_1" && request.WorkflowID == "wf_1"
+	})).Return(&models.WorkflowTemplate{ID: "tpl_1"}, nil).Once()
+
+	router := gin.New()
+	router.POST("/templates/:iduser/:usertoken", middlewares.ValidateOnPublishTemplate(), controllers.NewTemplateController(templateService).PublishTemplate)
+
+	publish := func(body string) int {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/templates/user_1/token", strings.NewReader(body)))
+		return w.Code
+	}
+	assert.Equal(t, http.StatusCreated, publish(`{"workflow_id":"wf_1","name":"flow","category":"basic"}`))
+	// a workflow of another user cannot be published with this token
+	assert.Equal(t, http.StatusBadRequest, publish(`{"user_id":"user_2","workflow_id":"wf_1","name":"flow","category":"basic"}`))
+}
+
+func TestTemplateController_CreateWorkflowFromTemplateFailure(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	templateService := mocks.NewTemplateService(t)
+	templateService.On("CreateWorkflowFromTemplate", mock.Anything, mock.Anything).Return(nil, nil, errors.New("broker down"))
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Params = gin.Params{{Key: "idtemplate", Value: "tpl_1"}}
+	ctx.Set(models.TemplateCreateFromKey, models.RequestCreateFromTemplate{UserID: "user_1", Name: "flow"})
+	controllers.NewTemplateController(templateService).CreateWorkflowFromTemplate(ctx)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.JSONEq(t, fmt.Sprintf(`{"error": "%s","status": 500}`, models.TemplateCannotUse), w.Body.String())
+}
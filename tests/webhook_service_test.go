@@ -0,0 +1,176 @@
+package tests
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"minireipaz/mocks"
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/domain/services"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func signBody(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return models.WebhookSignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestWebhookService_CreateWebhookRequiresWebhookNode(t *testing.T) {
+	request := &models.RequestCreateWebhook{UserID: "user_1", WorkflowID: "wf_1", NodeID: "node_2", Signed: true}
+	workflowService := mocks.NewWorkflowService(t)
+	redisRepo := mocks.NewWebhookRedisRepository(t)
+	brokerRepo := mocks.NewWebhookBrokerRepository(t)
+	workflowService.On("GetWorkflow", &request.UserID, &request.WorkflowID).Return(&models.Workflow{
+		UUID: "wf_1",
+		Nodes: []models.Node{
+			{ID: "node_1", Data: &models.DataNode{Type: models.NodeTypeWebhook}},
+			{ID: "node_2", Data: &models.DataNode{Type: "notion"}},
+		},
+	}, true)
+
+	service := services.NewWebhookService(redisRepo, brokerRepo, workflowService, newSecretService(t, "key_1:"+testKey(1), ""))
+	_, err := service.CreateWebhook(request)
+	assert.ErrorIs(t, err, models.ErrWebhookNodeInvalid)
+
+	request.NodeID = "node_1"
+	workflowService.On("IsWorkflowActive", &request.WorkflowID).Return(false, nil)
+	var stored *models.Webhook
+	redisRepo.On("Save", mock.AnythingOfType("*models.Webhook")).Run(func(args mock.Arguments) {
+		stored = args.Get(0).(*models.Webhook)
+	}).Return(nil)
+	webhook, err := service.CreateWebhook(request)
+
+	assert.NoError(t, err)
+	// the caller gets the plain secret, redis only the encrypted one
+	assert.True(t, strings.HasPrefix(stored.Secret, models.SecretPrefix))
+	assert.NotEqual(t, webhook.Secret, stored.Secret)
+	// a draft workflow cannot be triggered until it is activated
+	assert.True(t, webhook.Disabled)
+	assert.NotEmpty(t, webhook.Token)
+	assert.NotEmpty(t, webhook.Secret)
+	assert.Contains(t, webhook.URL, models.HooksPath+webhook.Token)
+}
+
+func TestWebhookService_ReceiveWebhookVerifiesSignature(t *testing.T) {
+	token := "token_1"
+	body := []byte(`{"order":42}`)
+	secrets := newSecretService(t, "key_1:"+testKey(1), "")
+	secret, err := secrets.Encrypt("secret")
+	assert.NoError(t, err)
+	webhook := &models.Webhook{Token: token, WorkflowID: "wf_1", NodeID: "node_1", UserID: "user_1", Secret: secret}
+	workflowService := mocks.NewWorkflowService(t)
+	redisRepo := mocks.NewWebhookRedisRepository(t)
+	brokerRepo := mocks.NewWebhookBrokerRepository(t)
+	redisRepo.On("GetByToken", &token).Return(webhook, nil)
+	workflowService.On("IsWorkflowActive", &webhook.WorkflowID).Return(true, nil)
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	expired := strconv.FormatInt(time.Now().Add(-2*models.WebhookTimestampTolerance).Unix(), 10)
+
+	service := services.NewWebhookService(redisRepo, brokerRepo, workflowService, secrets)
+	_, err = service.ReceiveWebhook(&token, body, "application/json", signBody("other", now, body), now)
+	assert.ErrorIs(t, err, models.ErrWebhookSignatureFailed)
+	_, err = service.ReceiveWebhook(&token, body, "application/json", "", now)
+	assert.ErrorIs(t, err, models.ErrWebhookSignatureFailed)
+	_, err = service.ReceiveWebhook(&token, body, "application/json", signBody("secret", expired, body), expired)
+	assert.ErrorIs(t, err, models.ErrWebhookSignatureFailed)
+	// the timestamp is signed, it cannot be refreshed without the secret
+	_, err = service.ReceiveWebhook(&token, body, "application/json", signBody("secret", expired, body), now)
+	assert.ErrorIs(t, err, models.ErrWebhookSignatureFailed)
+	brokerRepo.AssertNotCalled(t, "PublishTrigger", mock.Anything)
+
+	signature := signBody("secret", now, body)
+	redisRepo.On("SaveNonce", &token, &signature, 2*models.WebhookTimestampTolerance).Return(true, nil).Once()
+	brokerRepo.On("PublishTrigger", mock.MatchedBy(func(event *models.TriggerEvent) bool {
+		return event.WorkflowID == "wf_1" && event.NodeID == "node_1" && event.Body == string(body) && event.Source == models.TriggerSourceWebhook
+	})).Return(true).Once()
+	event, err := service.ReceiveWebhook(&token, body, "application/json", signature, now)
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, event.EventID)
+}
+
+func TestWebhookService_ReceiveWebhookReplayed(t *testing.T) {
+	token := "token_1"
+	body := []byte(`{"order":42}`)
+	webhook := &models.Webhook{Token: token, WorkflowID: "wf_1", NodeID: "node_1", UserID: "user_1", Secret: "secret"}
+	workflowService := mocks.NewWorkflowService(t)
+	redisRepo := mocks.NewWebhookRedisRepository(t)
+	brokerRepo := mocks.NewWebhookBrokerRepository(t)
+	redisRepo.On("GetByToken", &token).Return(webhook, nil)
+	workflowService.On("IsWorkflowActive", &webhook.WorkflowID).Return(true, nil)
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	signature := signBody("secret", now, body)
+	redisRepo.On("SaveNonce", &token, &signature, 2*models.WebhookTimestampTolerance).Return(false, nil)
+
+	service := services.NewWebhookService(redisRepo, brokerRepo, workflowService, newSecretService(t, "key_1:"+testKey(1), ""))
+	_, err := service.ReceiveWebhook(&token, body, "application/json", signature, now)
+
+	assert.ErrorIs(t, err, models.ErrWebhookReplayed)
+	brokerRepo.AssertNotCalled(t, "PublishTrigger", mock.Anything)
+}
+
+func TestWebhookService_ReceiveWebhookInactiveWorkflow(t *testing.T) {
+	token := "token_1"
+	// paused or trashed before the webhook was disabled
+	webhook := &models.Webhook{Token: token, WorkflowID: "wf_1", NodeID: "node_1", UserID: "user_1"}
+	workflowService := mocks.NewWorkflowService(t)
+	redisRepo := mocks.NewWebhookRedisRepository(t)
+	brokerRepo := mocks.NewWebhookBrokerRepository(t)
+	redisRepo.On("GetByToken", &token).Return(webhook, nil)
+	workflowService.On("IsWorkflowActive", &webhook.WorkflowID).Return(false, nil)
+
+	service := services.NewWebhookService(redisRepo, brokerRepo, workflowService, newSecretService(t, "key_1:"+testKey(1), ""))
+	_, err := service.ReceiveWebhook(&token, []byte(`{}`), "application/json", "", "")
+
+	assert.ErrorIs(t, err, models.ErrWebhookDisabled)
+	brokerRepo.AssertNotCalled(t, "PublishTrigger", mock.Anything)
+}
+
+func TestWebhookService_ReceiveWebhookUnknownToken(t *testing.T) {
+	token := "missing"
+	redisRepo := mocks.NewWebhookRedisRepository(t)
+	redisRepo.On("GetByToken", &token).Return(nil, models.ErrWebhookNotFound)
+
+	service := services.NewWebhookService(redisRepo, mocks.NewWebhookBrokerRepository(t), mocks.NewWorkflowService(t), newSecretService(t, "key_1:"+testKey(1), ""))
+	_, err := service.ReceiveWebhook(&token, []byte("{}"), "application/json", "", "")
+
+	assert.ErrorIs(t, err, models.ErrWebhookNotFound)
+}
+
+func TestWebhookService_ReceiveWebhookDisabled(t *testing.T) {
+	token := "token_1"
+	webhook := &models.Webhook{Token: token, WorkflowID: "wf_1", NodeID: "node_1", UserID: "user_1", Disabled: true}
+	redisRepo := mocks.NewWebhookRedisRepository(t)
+	brokerRepo := mocks.NewWebhookBrokerRepository(t)
+	redisRepo.On("GetByToken", &token).Return(webhook, nil)
+
+	service := services.NewWebhookService(redisRepo, brokerRepo, mocks.NewWorkflowService(t), newSecretService(t, "key_1:"+testKey(1), ""))
+	_, err := service.ReceiveWebhook(&token, []byte(`{}`), "application/json", "", "")
+
+	assert.ErrorIs(t, err, models.ErrWebhookDisabled)
+	brokerRepo.AssertNotCalled(t, "PublishTrigger", mock.Anything)
+}
+
+func TestWebhookService_SyncTriggers(t *testing.T) {
+	workflowID := "wf_1"
+	redisRepo := mocks.NewWebhookRedisRepository(t)
+	redisRepo.On("GetByWorkflow", &workflowID).Return([]models.Webhook{
+		{Token: "token_1", WorkflowID: workflowID, NodeID: "node_1", Disabled: true},
+		{Token: "token_2", WorkflowID: workflowID, NodeID: "node_2"},
+	}, nil)
+	// same token and secret, only the flag changes
+	redisRepo.On("Save", mock.MatchedBy(func(webhook *models.Webhook) bool {
+		return webhook.Token == "token_1" && !webhook.Disabled
+	})).Return(nil).Once()
+
+	service := services.NewWebhookService(redisRepo, mocks.NewWebhookBrokerRepository(t), mocks.NewWorkflowService(t), newSecretService(t, "key_1:"+testKey(1), ""))
+	assert.NoError(t, service.SyncTriggers(&workflowID, true))
+}
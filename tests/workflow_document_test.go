@@ -0,0 +1,124 @@
+package tests
+
+import (
+	"minireipaz/mocks"
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/domain/services"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestWorkflowService_ExportWorkflow(t *testing.T) {
+	userID, workflowID := "user_1", "wf_1"
+	stored := models.Workflow{
+		UUID:   workflowID,
+		UserID: userID,
+		Name:   "flow",
+		Nodes: []models.Node{
+			{ID: models.InitialNodeID},
+			{ID: "sheet", Data: &models.DataNode{
+				Type:           models.GoogleSheets,
+				WorkflowID:     workflowID,
+				ContentData:    models.FormData{Document: "doc"},
+				CredentialData: models.RequestCreateCredential{ID: "cred_1", Data: models.DataCredential{ClientSecret: "secret"}},
+			}},
+		},
+		Edges: []models.Edge{{ID: strPtr("e1"), Source: strPtr(models.InitialNodeID), Target: strPtr("sheet")}},
+	}
+
+	redisRepo := mocks.NewWorkflowRedisRepoInterface(t)
+	httpRepo := mocks.NewWorkflowHTTPRepository(t)
+	redisRepo.On("ValidateWorkflowGlobalUUID", mock.Anything).Return(true)
+	redisRepo.On("AcquireLock", "lock:"+workflowID, "", models.RateLimitUpdate).Return(true, nil)
+	redisRepo.On("RemoveLock", "lock:"+workflowID).Return(true)
+	httpRepo.On("GetWorkflowDataByID", &userID, &workflowID, uint64(1)).Return(&models.InfoWorkflow{Data: []models.Workflow{stored}}, nil)
+
+	service := services.NewWorkflowService(redisRepo, mocks.NewWorkflowBrokerRepository(t), services.NewUUIDService(), httpRepo, services.NewNodeService(), nil, nil)
+	document, exist := service.ExportWorkflow(&userID, &workflowID)
+
+	assert.True(t, exist)
+	assert.Equal(t, models.WorkflowDocumentKind, document.Kind)
+	assert.Equal(t, "flow", document.Name)
+	assert.Equal(t, []models.CredentialPlaceholder{{NodeID: "sheet", Type: models.GoogleSheets}}, document.Credentials)
+	assert.Equal(t, models.RequestCreateCredential{Type: models.GoogleSheets}, document.Nodes[1].Data.CredentialData)
+	assert.Empty(t, document.Nodes[1].Data.WorkflowID)
+	assert.Equal(t, "doc", document.Nodes[1].Data.ContentData.Document)
+	// stored workflow is not modified
+	assert.Equal(t, "cred_1", stored.Nodes[1].Data.CredentialData.ID)
+}
+
+func TestWorkflowService_ImportWorkflow(t *testing.T) {
+	userID, directory := "user_1", "/"
+	document := models.WorkflowDocument{
+		Kind:          models.WorkflowDocumentKind,
+		SchemaVersion: models.WorkflowDocumentSchemaVersion,
+		Name:          "flow",
+		Nodes: []models.Node{
+			{ID: models.InitialNodeID},
+			{ID: "sheet", Data: &models.DataNode{ID: "sheet", NodeID: "sheet", Type: models.GoogleSheets}},
+		},
+		Edges: []models.Edge{{ID: strPtr("e1"), Source: strPtr(models.InitialNodeID), Target: strPtr("sheet")}},
+	}
+
+	redisRepo := mocks.NewWorkflowRedisRepoInterface(t)
+	brokerRepo := mocks.NewWorkflowBrokerRepository(t)
+	redisRepo.On("ValidateUserWorkflowUUID", mock.Anything, mock.Anything).Return(false)
+	redisRepo.On("AcquireLock", mock.Anything, mock.Anything, models.MaxTimeForLocks).Return(true, nil)
+	redisRepo.On("RemoveLock", mock.Anything).Return(true)
+	redisRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.OutboxMessage")).Return(true, false)
+	redisRepo.On("NextVersion", mock.Anything).Return(uint32(1), nil)
+	// the graph goes with the create command, no update follows it
+	brokerRepo.On("CreateCommand", mock.MatchedBy(func(workflow *models.Workflow) bool {
+		return len(workflow.Nodes) == 2 && len(workflow.Edges) == 1 && workflow.IsActive == models.Draft
+	}), models.CommandTypeCreate).Return(&models.OutboxMessage{Topic: "workflows.command"}, nil)
+
+	service := services.NewWorkflowService(redisRepo, brokerRepo, services.NewUUIDService(), mocks.NewWorkflowHTTPRepository(t), services.NewNodeService(), nil, nil)
+	imported, graphErrors, err := service.ImportWorkflow(&userID, &directory, &document)
+
+	assert.NoError(t, err)
+	assert.Empty(t, graphErrors)
+	assert.Equal(t, models.Draft, imported.Workflow.IsActive)
+	assert.Equal(t, uint32(1), imported.Workflow.Version)
+
+	newSheetID := imported.NodeIDs["sheet"]
+	assert.NotEqual(t, "sheet", newSheetID)
+	assert.Equal(t, models.InitialNodeID, imported.NodeIDs[models.InitialNodeID])
+	assert.Equal(t, newSheetID, imported.Workflow.Nodes[1].ID)
+	assert.Equal(t, newSheetID, imported.Workflow.Nodes[1].Data.NodeID)
+	assert.Equal(t, imported.Workflow.UUID, imported.Workflow.Nodes[1].Data.WorkflowID)
+	assert.Equal(t, newSheetID, *imported.Workflow.Edges[0].Target)
+	assert.Equal(t, []models.CredentialPlaceholder{{NodeID: newSheetID, Type: models.GoogleSheets}}, imported.Credentials)
+}
+
+func TestWorkflowService_ImportWorkflowNameExists(t *testing.T) {
+	userID, directory := "user_1", "/"
+	document := models.WorkflowDocument{
+		Kind:          models.WorkflowDocumentKind,
+		SchemaVersion: models.WorkflowDocumentSchemaVersion,
+		Name:          "flow",
+		Nodes:         []models.Node{{ID: models.InitialNodeID}},
+	}
+
+	redisRepo := mocks.NewWorkflowRedisRepoInterface(t)
+	redisRepo.On("ValidateUserWorkflowUUID", &userID, strPtr("flow")).Return(true)
+
+	service := services.NewWorkflowService(redisRepo, mocks.NewWorkflowBrokerRepository(t), services.NewUUIDService(), mocks.NewWorkflowHTTPRepository(t), services.NewNodeService(), nil, nil)
+	imported, _, err := service.ImportWorkflow(&userID, &directory, &document)
+
+	assert.ErrorIs(t, err, models.ErrWorkflowNameExist)
+	assert.Nil(t, imported)
+	redisRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestWorkflowService_ImportWorkflowInvalidDocument(t *testing.T) {
+	userID, directory := "user_1", "/"
+	document := models.WorkflowDocument{Kind: "other", SchemaVersion: 1, Name: "flow"}
+
+	service := services.NewWorkflowService(mocks.NewWorkflowRedisRepoInterface(t), mocks.NewWorkflowBrokerRepository(t), services.NewUUIDService(), mocks.NewWorkflowHTTPRepository(t), services.NewNodeService(), nil, nil)
+	imported, _, err := service.ImportWorkflow(&userID, &directory, &document)
+
+	assert.ErrorIs(t, err, models.ErrWorkflowDocumentInvalid)
+	assert.Nil(t, imported)
+}
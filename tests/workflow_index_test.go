@@ -0,0 +1,242 @@
+package tests
+
+import (
+	"minireipaz/mocks"
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/domain/services"
+	"minireipaz/pkg/interfaces/middlewares"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestWorkflowService_UpdateWorkflowNameTaken(t *testing.T) {
+	workflow := models.Workflow{UUID: "wf_1", UserID: "user_1", Name: "taken", Version: 1, Nodes: []models.Node{{ID: models.InitialNodeID}}}
+
+	redisRepo := mocks.NewWorkflowRedisRepoInterface(t)
+	redisRepo.On("ValidateWorkflowGlobalUUID", mock.Anything).Return(true)
+	redisRepo.On("GetLifecycle", &workflow.UUID).Return(models.Draft, nil)
+	redisRepo.On("AcquireLock", "lock:wf_1", "", models.MaxTimeForLocks).Return(true, nil)
+	redisRepo.On("RemoveLock", "lock:wf_1").Return(true)
+	redisRepo.On("CompareAndIncrVersion", &workflow.UUID, uint32(1)).Return(uint32(2), true, nil).Once()
+	brokerRepo := mocks.NewWorkflowBrokerRepository(t)
+	message := &models.OutboxMessage{Topic: "workflows.command"}
+	brokerRepo.On("CreateCommand", &workflow, models.CommandTypeUpdate).Return(message, nil).Once()
+	// the update command is only written with the new name, a taken name drops both
+	redisRepo.On("RenameInIndex", &workflow, message).Return("", models.ErrWorkflowNameExist).Once()
+	redisRepo.On("RevertVersion", &workflow.UUID, uint32(2)).Return(true).Once()
+
+	service := services.NewWorkflowService(redisRepo, brokerRepo, services.NewUUIDService(), mocks.NewWorkflowHTTPRepository(t), nil, nil, nil)
+	updated, exist, err := service.UpdateWorkflow(&workflow)
+
+	assert.False(t, updated)
+	assert.True(t, exist)
+	assert.ErrorIs(t, err, models.ErrWorkflowNameExist)
+	assert.Equal(t, uint32(1), workflow.Version)
+}
+
+func TestWorkflowService_UpdateWorkflowStaleKeepsName(t *testing.T) {
+	workflow := models.Workflow{UUID: "wf_1", UserID: "user_1", Name: "new name", Version: 3, Nodes: []models.Node{{ID: models.InitialNodeID}}}
+
+	redisRepo := mocks.NewWorkflowRedisRepoInterface(t)
+	redisRepo.On("ValidateWorkflowGlobalUUID", mock.Anything).Return(true)
+	redisRepo.On("GetLifecycle", &workflow.UUID).Return(models.Draft, nil)
+	redisRepo.On("AcquireLock", "lock:wf_1", "", models.MaxTimeForLocks).Return(true, nil)
+	redisRepo.On("RemoveLock", "lock:wf_1").Return(true)
+	redisRepo.On("CompareAndIncrVersion", &workflow.UUID, uint32(3)).Return(uint32(4), false, nil).Once()
+
+	service := services.NewWorkflowService(redisRepo, mocks.NewWorkflowBrokerRepository(t), services.NewUUIDService(), mocks.NewWorkflowHTTPRepository(t), nil, nil, nil)
+	_, _, err := service.UpdateWorkflow(&workflow)
+
+	assert.ErrorIs(t, err, models.ErrVersionConflict)
+	// a stale copy never reaches the name index
+	redisRepo.AssertNotCalled(t, "RenameInIndex", mock.Anything, mock.Anything)
+}
+
+func TestWorkflowService_UpdateWorkflowValidatesGraph(t *testing.T) {
+	start := models.Node{ID: models.InitialNodeID}
+	secondStart := models.Node{ID: "other", Data: &models.DataNode{Type: models.NodeTypeStart}}
+	workflow := models.Workflow{UUID: "wf_1", UserID: "user_1", Name: "flow", Version: 1, Nodes: []models.Node{start, secondStart}}
+
+	redisRepo := mocks.NewWorkflowRedisRepoInterface(t)
+	redisRepo.On("ValidateWorkflowGlobalUUID", mock.Anything).Return(true)
+	redisRepo.On("GetLifecycle", &workflow.UUID).Return(models.Draft, nil)
+
+	service := services.NewWorkflowService(redisRepo, mocks.NewWorkflowBrokerRepository(t), services.NewUUIDService(), mocks.NewWorkflowHTTPRepository(t), nil, nil, nil)
+	updated, exist, err := service.UpdateWorkflow(&workflow)
+
+	var graphInvalid *models.GraphInvalidError
+	assert.False(t, updated)
+	assert.True(t, exist)
+	assert.ErrorIs(t, err, models.ErrWorkflowGraphInvalid)
+	assert.ErrorAs(t, err, &graphInvalid)
+	assert.Equal(t, models.GraphErrorMultipleStartNodes, graphInvalid.Errors[0].Code)
+	assert.Equal(t, "other", graphInvalid.Errors[0].NodeID)
+	redisRepo.AssertNotCalled(t, "AcquireLock", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestWorkflowService_UpdateWorkflowUsesStoredState(t *testing.T) {
+	halfConfigured := func(isActive models.IsActive) models.Workflow {
+		return models.Workflow{UUID: "wf_1", UserID: "user_1", Name: "flow", Version: 1, IsActive: isActive, Nodes: []models.Node{
+			{ID: models.InitialNodeID},
+			{ID: "sheet", Data: &models.DataNode{Type: models.GoogleSheets, ContentData: models.FormData{Pollmode: models.NopollNode}}},
+		}}
+	}
+
+	t.Run("Error - Active workflow saved as draft by the client", func(t *testing.T) {
+		workflow := halfConfigured(models.Draft)
+		redisRepo := mocks.NewWorkflowRedisRepoInterface(t)
+		redisRepo.On("ValidateWorkflowGlobalUUID", mock.Anything).Return(true)
+		redisRepo.On("GetLifecycle", &workflow.UUID).Return(models.Active, nil)
+
+		service := services.NewWorkflowService(redisRepo, mocks.NewWorkflowBrokerRepository(t), services.NewUUIDService(), mocks.NewWorkflowHTTPRepository(t), services.NewNodeService(), nil, nil)
+		updated, _, err := service.UpdateWorkflow(&workflow)
+
+		var graphInvalid *models.GraphInvalidError
+		assert.False(t, updated)
+		assert.ErrorAs(t, err, &graphInvalid)
+		assert.Equal(t, models.GraphErrorMissingFormField, graphInvalid.Errors[0].Code)
+	})
+
+	t.Run("Success - Draft saved half configured", func(t *testing.T) {
+		workflow := halfConfigured(models.Active)
+		redisRepo := mocks.NewWorkflowRedisRepoInterface(t)
+		brokerRepo := mocks.NewWorkflowBrokerRepository(t)
+		redisRepo.On("ValidateWorkflowGlobalUUID", mock.Anything).Return(true)
+		redisRepo.On("GetLifecycle", &workflow.UUID).Return(models.Draft, nil)
+		redisRepo.On("AcquireLock", "lock:wf_1", "", models.MaxTimeForLocks).Return(true, nil)
+		redisRepo.On("RemoveLock", "lock:wf_1").Return(true)
+		redisRepo.On("CompareAndIncrVersion", &workflow.UUID, uint32(1)).Return(uint32(2), true, nil)
+		message := &models.OutboxMessage{Topic: "workflows.command"}
+		brokerRepo.On("CreateCommand", mock.MatchedBy(func(w *models.Workflow) bool { return w.IsActive == models.Draft }), models.CommandTypeUpdate).Return(message, nil)
+		redisRepo.On("RenameInIndex", &workflow, message).Return("", nil)
+
+		service := services.NewWorkflowService(redisRepo, brokerRepo, services.NewUUIDService(), mocks.NewWorkflowHTTPRepository(t), services.NewNodeService(), nil, nil)
+		updated, _, err := service.UpdateWorkflow(&workflow)
+
+		assert.NoError(t, err)
+		assert.True(t, updated)
+	})
+}
+
+func TestWorkflowService_ReconcileIndexes(t *testing.T) {
+	userID := "user_1"
+	owners := map[string]string{
+		"wf_renamed": userID,
+		"wf_pending": userID,
+		"wf_other":   "user_2",
+	}
+	snapshot := map[string]string{
+		"old name": "wf_renamed",
+		"new name": "wf_renamed",
+		"pending":  "wf_pending",
+	}
+	rows := []models.Workflow{
+		{UUID: "wf_renamed", Name: "new name", Version: 2, UpdatedAt: "2024-02-01 00:00:00"},
+		{UUID: "wf_renamed", Name: "old name", Version: 1, UpdatedAt: "2024-01-01 00:00:00"},
+		{UUID: "wf_unindexed", Name: "lost", Version: 1, UpdatedAt: "2024-01-05 00:00:00"},
+		{UUID: "wf_dup", Name: "new name", Version: 1, UpdatedAt: "2023-12-01 00:00:00"},
+	}
+
+	tests := []struct {
+		name                    string
+		prune                   bool
+		missingSince            time.Time
+		expectedRemoveNames     []string
+		expectedRemoveWorkflows []string
+		expectedPending         []string
+	}{
+		{
+			name:                    "Keeps workflows waiting in broker",
+			prune:                   false,
+			missingSince:            time.Now().Add(-2 * models.IndexPruneGrace),
+			expectedRemoveNames:     []string{"old name"},
+			expectedRemoveWorkflows: []string{},
+			expectedPending:         []string{},
+		},
+		{
+			name:                    "Prune removes workflows missing since the grace",
+			prune:                   true,
+			missingSince:            time.Now().Add(-2 * models.IndexPruneGrace),
+			expectedRemoveNames:     []string{"old name", "pending"},
+			expectedRemoveWorkflows: []string{"wf_pending"},
+			expectedPending:         []string{},
+		},
+		{
+			name:                    "Prune keeps workflows missing just now",
+			prune:                   true,
+			missingSince:            time.Now(),
+			expectedRemoveNames:     []string{"old name"},
+			expectedRemoveWorkflows: []string{},
+			expectedPending:         []string{"wf_pending"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redisRepo := mocks.NewWorkflowRedisRepoInterface(t)
+			httpRepo := mocks.NewWorkflowHTTPRepository(t)
+			redisRepo.On("GetWorkflowsIndex").Return(owners, nil)
+			redisRepo.On("GetTrash", &userID).Return([]models.TrashedWorkflow{}, nil)
+			redisRepo.On("GetUserIndex", &userID).Return(snapshot, nil)
+			redisRepo.On("TrackMissing", &userID, []string{"wf_pending"}, mock.AnythingOfType("time.Time")).Return(map[string]time.Time{"wf_pending": tt.missingSince}, nil)
+			httpRepo.On("GetWorkflowsPage", mock.MatchedBy(func(query *models.WorkflowListQuery) bool {
+				return query.UserID == userID
+			}), (*models.WorkflowCursor)(nil), uint64(models.MaxRowsFromDB+1)).Return(&models.InfoWorkflow{Data: rows}, nil)
+
+			var applied *models.UserIndexChange
+			redisRepo.On("ApplyUserIndex", mock.Anything).Run(func(args mock.Arguments) {
+				applied = args.Get(0).(*models.UserIndexChange)
+			}).Return(nil)
+
+			service := services.NewWorkflowService(redisRepo, mocks.NewWorkflowBrokerRepository(t), services.NewUUIDService(), httpRepo, nil, nil, nil)
+			reports, err := service.ReconcileIndexes(&userID, tt.prune)
+
+			assert.NoError(t, err)
+			assert.Len(t, reports, 1)
+			assert.Equal(t, []string{"lost"}, reports[0].Fixed)
+			assert.Equal(t, []string{"wf_dup"}, reports[0].Conflicts)
+			assert.Equal(t, []string{"wf_pending"}, reports[0].Missing)
+			assert.Equal(t, tt.expectedPending, reports[0].Pending)
+			assert.Equal(t, map[string]string{"lost": "wf_unindexed"}, applied.SetNames)
+			assert.ElementsMatch(t, tt.expectedRemoveNames, applied.RemoveNames)
+			assert.ElementsMatch(t, []string{"wf_unindexed", "wf_dup"}, applied.SetWorkflows)
+			assert.ElementsMatch(t, tt.expectedRemoveWorkflows, applied.RemoveWorkflows)
+		})
+	}
+}
+
+func TestRequireAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name               string
+		adminToken         string
+		header             string
+		expectedStatusCode int
+	}{
+		{name: "Valid admin token", adminToken: "secret", header: "secret", expectedStatusCode: http.StatusOK},
+		{name: "Wrong admin token", adminToken: "secret", header: "other", expectedStatusCode: http.StatusForbidden},
+		{name: "Admin token not configured", adminToken: "", header: "", expectedStatusCode: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.POST("/reconcile", middlewares.RequireAdmin(tt.adminToken), func(ctx *gin.Context) {
+				ctx.Status(http.StatusOK)
+			})
+
+			req, _ := http.NewRequest(http.MethodPost, "/reconcile", nil)
+			req.Header.Set(models.AdminTokenHeader, tt.header)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+		})
+	}
+}
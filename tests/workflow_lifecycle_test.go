@@ -0,0 +1,203 @@
+package tests
+
+import (
+	"errors"
+	"minireipaz/mocks"
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/domain/services"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newLifecycleMocks(t *testing.T, stored models.Workflow) (*mocks.WorkflowRedisRepoInterface, *mocks.WorkflowHTTPRepository, *mocks.WorkflowBrokerRepository) {
+	redisRepo := mocks.NewWorkflowRedisRepoInterface(t)
+	httpRepo := mocks.NewWorkflowHTTPRepository(t)
+	brokerRepo := mocks.NewWorkflowBrokerRepository(t)
+	redisRepo.On("ValidateWorkflowGlobalUUID", mock.Anything).Return(true)
+	redisRepo.On("AcquireLock", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+	redisRepo.On("RemoveLock", mock.Anything).Return(true)
+	redisRepo.On("GetLifecycle", &stored.UUID).Return(stored.IsActive, nil).Maybe()
+	redisRepo.On("GetVersion", &stored.UUID).Return(stored.Version, nil).Maybe()
+	httpRepo.On("GetWorkflowDataByID", &stored.UserID, &stored.UUID, uint64(1)).Return(&models.InfoWorkflow{Data: []models.Workflow{stored}}, nil)
+	return redisRepo, httpRepo, brokerRepo
+}
+
+func TestWorkflowService_ChangeLifecycleActivate(t *testing.T) {
+	userID, workflowID := "user_1", "wf_1"
+	stored := models.Workflow{
+		UUID:     workflowID,
+		UserID:   userID,
+		Version:  4,
+		IsActive: models.Draft,
+		Nodes:    []models.Node{{ID: models.InitialNodeID, Data: &models.DataNode{Type: models.NodeTypeStart}}},
+	}
+	redisRepo, httpRepo, brokerRepo := newLifecycleMocks(t, stored)
+	redisRepo.On("CompareAndIncrVersion", &stored.UUID, uint32(4)).Return(uint32(5), true, nil)
+	message := &models.OutboxMessage{Topic: "workflows.command"}
+	brokerRepo.On("CreateCommand", mock.MatchedBy(func(workflow *models.Workflow) bool {
+		return workflow.IsActive == models.Active && workflow.Version == 5
+	}), models.CommandTypeActivate).Return(message, nil)
+	redisRepo.On("SetLifecycle", &stored.UUID, models.Active, message).Return(nil)
+	// the command is already in the outbox, the relay publishes it later
+	outbox := mocks.NewOutboxService(t)
+	outbox.On("Tick", mock.Anything).Return(0, models.ErrOutboxCannotPublish)
+
+	service := services.NewWorkflowService(redisRepo, brokerRepo, services.NewUUIDService(), httpRepo, services.NewNodeService(), nil, outbox)
+	workflow, graphErrors, err := service.ChangeLifecycle(&userID, &workflowID, models.TransitionActivate)
+
+	assert.NoError(t, err)
+	assert.Empty(t, graphErrors)
+	assert.Equal(t, models.Active, workflow.IsActive)
+	assert.Equal(t, uint32(5), workflow.Version)
+}
+
+func TestWorkflowService_ChangeLifecycleAfterUpdate(t *testing.T) {
+	userID, workflowID := "user_1", "wf_1"
+	// ClickHouse has not consumed the last update yet
+	stored := models.Workflow{UUID: workflowID, UserID: userID, Version: 2, IsActive: models.Active}
+	redisRepo := mocks.NewWorkflowRedisRepoInterface(t)
+	httpRepo := mocks.NewWorkflowHTTPRepository(t)
+	brokerRepo := mocks.NewWorkflowBrokerRepository(t)
+	redisRepo.On("ValidateWorkflowGlobalUUID", mock.Anything).Return(true)
+	redisRepo.On("AcquireLock", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+	redisRepo.On("RemoveLock", mock.Anything).Return(true)
+	redisRepo.On("GetLifecycle", &stored.UUID).Return(stored.IsActive, nil)
+	redisRepo.On("GetVersion", &stored.UUID).Return(uint32(3), nil)
+	httpRepo.On("GetWorkflowDataByID", &userID, &workflowID, uint64(1)).Return(&models.InfoWorkflow{Data: []models.Workflow{stored}}, nil)
+	redisRepo.On("CompareAndIncrVersion", &stored.UUID, uint32(3)).Return(uint32(4), true, nil)
+	message := &models.OutboxMessage{Topic: "workflows.command"}
+	brokerRepo.On("CreateCommand", mock.Anything, models.CommandTypePause).Return(message, nil)
+	redisRepo.On("SetLifecycle", &stored.UUID, models.Paused, message).Return(nil)
+
+	service := services.NewWorkflowService(redisRepo, brokerRepo, services.NewUUIDService(), httpRepo, services.NewNodeService(), nil, nil)
+	workflow, _, err := service.ChangeLifecycle(&userID, &workflowID, models.TransitionPause)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.Paused, workflow.IsActive)
+	assert.Equal(t, uint32(4), workflow.Version)
+}
+
+func TestWorkflowService_ChangeLifecycleInvalidTransition(t *testing.T) {
+	userID, workflowID := "user_1", "wf_1"
+	stored := models.Workflow{UUID: workflowID, UserID: userID, IsActive: models.Draft}
+	redisRepo, httpRepo, brokerRepo := newLifecycleMocks(t, stored)
+
+	service := services.NewWorkflowService(redisRepo, brokerRepo, services.NewUUIDService(), httpRepo, services.NewNodeService(), nil, nil)
+	workflow, _, err := service.ChangeLifecycle(&userID, &workflowID, models.TransitionPause)
+
+	assert.ErrorIs(t, err, models.ErrWorkflowTransitionInvalid)
+	assert.Equal(t, models.Draft, workflow.IsActive)
+	brokerRepo.AssertNotCalled(t, "CreateCommand", mock.Anything, mock.Anything)
+}
+
+func TestWorkflowService_ChangeLifecycleActivateInvalidGraph(t *testing.T) {
+	userID, workflowID := "user_1", "wf_1"
+	stored := models.Workflow{UUID: workflowID, UserID: userID, IsActive: models.Draft}
+	redisRepo, httpRepo, brokerRepo := newLifecycleMocks(t, stored)
+
+	service := services.NewWorkflowService(redisRepo, brokerRepo, services.NewUUIDService(), httpRepo, services.NewNodeService(), nil, nil)
+	workflow, graphErrors, err := service.ChangeLifecycle(&userID, &workflowID, models.TransitionActivate)
+
+	assert.ErrorIs(t, err, models.ErrWorkflowCannotActivate)
+	assert.Equal(t, models.GraphErrorMissingStartNode, graphErrors[0].Code)
+	assert.Equal(t, models.Draft, workflow.IsActive)
+}
+
+func TestWorkflowService_ChangeLifecycleStoreFailedRevertsVersion(t *testing.T) {
+	userID, workflowID := "user_1", "wf_1"
+	stored := models.Workflow{UUID: workflowID, UserID: userID, Version: 2, IsActive: models.Active}
+	redisRepo, httpRepo, brokerRepo := newLifecycleMocks(t, stored)
+	redisRepo.On("CompareAndIncrVersion", &stored.UUID, uint32(2)).Return(uint32(3), true, nil).Once()
+	// the retry finds a newer version and stops
+	redisRepo.On("CompareAndIncrVersion", &stored.UUID, uint32(2)).Return(uint32(3), false, nil).Once()
+	message := &models.OutboxMessage{Topic: "workflows.command"}
+	brokerRepo.On("CreateCommand", mock.Anything, models.CommandTypePause).Return(message, nil).Once()
+	// state and command are written together, neither is left behind
+	redisRepo.On("SetLifecycle", &stored.UUID, models.Paused, message).Return(errors.New("redis down")).Once()
+	redisRepo.On("RevertVersion", &stored.UUID, uint32(3)).Return(true).Once()
+
+	service := services.NewWorkflowService(redisRepo, brokerRepo, services.NewUUIDService(), httpRepo, services.NewNodeService(), nil, nil)
+	workflow, _, err := service.ChangeLifecycle(&userID, &workflowID, models.TransitionPause)
+
+	assert.ErrorIs(t, err, models.ErrVersionConflict)
+	assert.Equal(t, models.Active, workflow.IsActive)
+	assert.Equal(t, uint32(2), workflow.Version)
+}
+
+func TestWorkflowService_PurgeWorkflowRemovesTriggers(t *testing.T) {
+	userID, workflowID := "user_1", "wf_1"
+	trashed := models.TrashedWorkflow{UUID: workflowID, UserID: userID, Name: "flow", Workflow: &models.Workflow{UUID: workflowID, UserID: userID, Name: "flow"}}
+	redisRepo := mocks.NewWorkflowRedisRepoInterface(t)
+	brokerRepo := mocks.NewWorkflowBrokerRepository(t)
+	triggers := mocks.NewWorkflowTriggers(t)
+	redisRepo.On("GetTrash", &userID).Return([]models.TrashedWorkflow{trashed}, nil)
+	message := &models.OutboxMessage{Topic: "workflows.command"}
+	brokerRepo.On("CreateCommand", mock.Anything, models.CommandTypePurge).Return(message, nil)
+	redisRepo.On("RemoveFromTrash", &userID, &workflowID, message).Return(true, nil)
+	triggers.On("RemoveTriggers", &workflowID).Return(nil).Once()
+
+	service := services.NewWorkflowService(redisRepo, brokerRepo, services.NewUUIDService(), mocks.NewWorkflowHTTPRepository(t), services.NewNodeService(), nil, nil)
+	service.RegisterTriggers(triggers)
+	purged, exist := service.PurgeWorkflow(&userID, &workflowID)
+
+	assert.True(t, purged)
+	assert.True(t, exist)
+}
+
+func TestWorkflowService_DeleteWorkflowDisablesTriggers(t *testing.T) {
+	userID, workflowID := "user_1", "wf_1"
+	stored := models.Workflow{UUID: workflowID, UserID: userID, Name: "flow", IsActive: models.Active}
+	redisRepo, httpRepo, brokerRepo := newLifecycleMocks(t, stored)
+	triggers := mocks.NewWorkflowTriggers(t)
+	message := &models.OutboxMessage{Topic: "workflows.command"}
+	brokerRepo.On("CreateCommand", mock.Anything, models.CommandTypeDelete).Return(message, nil)
+	redisRepo.On("MoveToTrash", mock.MatchedBy(func(trashed *models.TrashedWorkflow) bool { return trashed.UUID == workflowID }), message).Return(true, nil)
+	triggers.On("SyncTriggers", &workflowID, false).Return(nil).Once()
+
+	service := services.NewWorkflowService(redisRepo, brokerRepo, services.NewUUIDService(), httpRepo, services.NewNodeService(), nil, nil)
+	service.RegisterTriggers(triggers)
+	deleted, exist := service.DeleteWorkflow(&userID, &workflowID)
+
+	assert.True(t, deleted)
+	assert.True(t, exist)
+}
+
+func TestWorkflowService_ChangeLifecycleSyncsTriggers(t *testing.T) {
+	tests := []struct {
+		name       string
+		stored     models.IsActive
+		transition string
+		active     bool
+	}{
+		{name: "Pause disables triggers", stored: models.Active, transition: models.TransitionPause, active: false},
+		{name: "Draft disables triggers", stored: models.Paused, transition: models.TransitionDraft, active: false},
+		{name: "Resume enables triggers", stored: models.Paused, transition: models.TransitionResume, active: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID, workflowID := "user_1", "wf_1"
+			stored := models.Workflow{UUID: workflowID, UserID: userID, Version: 2, IsActive: tt.stored,
+				Nodes: []models.Node{{ID: models.InitialNodeID}}}
+			redisRepo, httpRepo, brokerRepo := newLifecycleMocks(t, stored)
+			triggers := mocks.NewWorkflowTriggers(t)
+			redisRepo.On("CompareAndIncrVersion", &stored.UUID, uint32(2)).Return(uint32(3), true, nil)
+			message := &models.OutboxMessage{Topic: "workflows.command"}
+			brokerRepo.On("CreateCommand", mock.Anything, map[string]string{
+				models.TransitionPause:  models.CommandTypePause,
+				models.TransitionDraft:  models.CommandTypeDraft,
+				models.TransitionResume: models.CommandTypeResume,
+			}[tt.transition]).Return(message, nil)
+			redisRepo.On("SetLifecycle", &stored.UUID, models.LifecycleTransitions[tt.transition].To, message).Return(nil)
+			triggers.On("SyncTriggers", &workflowID, tt.active).Return(nil).Once()
+
+			service := services.NewWorkflowService(redisRepo, brokerRepo, services.NewUUIDService(), httpRepo, services.NewNodeService(), nil, nil)
+			service.RegisterTriggers(triggers)
+			_, _, err := service.ChangeLifecycle(&userID, &workflowID, tt.transition)
+
+			assert.NoError(t, err)
+		})
+	}
+}
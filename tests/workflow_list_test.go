@@ -0,0 +1,86 @@
+package tests
+
+import (
+	"fmt"
+	"minireipaz/mocks"
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/domain/services"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestWorkflowService_ListWorkflows(t *testing.T) {
+	rows := []models.Workflow{
+		{UUID: "wf_3", Name: "c", UpdatedAt: "2024-03-03T00:00:00Z"},
+		{UUID: "wf_2", Name: "b", UpdatedAt: "2024-03-02T00:00:00Z"},
+		{UUID: "wf_1", Name: "a", UpdatedAt: "2024-03-01T00:00:00Z"},
+	}
+	firstPage := func(query *models.WorkflowListQuery) bool {
+		return query.SortBy == models.SortByUpdatedAt && query.Order == models.SortOrderDesc && query.Status == models.Completed
+	}
+
+	httpRepo := mocks.NewWorkflowHTTPRepository(t)
+	httpRepo.On("GetWorkflowsPage", mock.MatchedBy(firstPage), (*models.WorkflowCursor)(nil), uint64(3)).
+		Return(&models.InfoWorkflow{Data: rows}, nil).Once()
+	httpRepo.On("GetWorkflowsPage", mock.MatchedBy(firstPage), &models.WorkflowCursor{
+		SortBy: models.SortByUpdatedAt,
+		Order:  models.SortOrderDesc,
+		Value:  "2024-03-02T00:00:00Z",
+		ID:     "wf_2",
+	}, uint64(3)).Return(&models.InfoWorkflow{Data: rows[2:]}, nil).Once()
+
+	service := services.NewWorkflowService(mocks.NewWorkflowRedisRepoInterface(t), mocks.NewWorkflowBrokerRepository(t), services.NewUUIDService(), httpRepo, services.NewNodeService(), nil, nil)
+
+	query := &models.WorkflowListQuery{UserID: "user_1", Status: models.Completed, Limit: 2}
+	page, err := service.ListWorkflows(query)
+	assert.NoError(t, err)
+	assert.True(t, page.HasMore)
+	assert.Len(t, page.Workflows, 2)
+	assert.NotEmpty(t, page.NextCursor)
+
+	next := &models.WorkflowListQuery{UserID: "user_1", Status: models.Completed, Limit: 2, Cursor: page.NextCursor}
+	page, err = service.ListWorkflows(next)
+	assert.NoError(t, err)
+	assert.False(t, page.HasMore)
+	assert.Empty(t, page.NextCursor)
+	assert.Equal(t, "wf_1", page.Workflows[0].UUID)
+}
+
+func TestWorkflowService_ListWorkflowsCursorOtherSort(t *testing.T) {
+	rows := []models.Workflow{{UUID: "wf_1", Name: "a"}, {UUID: "wf_2", Name: "b"}}
+	httpRepo := mocks.NewWorkflowHTTPRepository(t)
+	httpRepo.On("GetWorkflowsPage", mock.Anything, mock.Anything, uint64(2)).Return(&models.InfoWorkflow{Data: rows}, nil)
+
+	service := services.NewWorkflowService(mocks.NewWorkflowRedisRepoInterface(t), mocks.NewWorkflowBrokerRepository(t), services.NewUUIDService(), httpRepo, services.NewNodeService(), nil, nil)
+	page, err := service.ListWorkflows(&models.WorkflowListQuery{UserID: "user_1", SortBy: models.SortByName, Order: models.SortOrderAsc, Limit: 1})
+	assert.NoError(t, err)
+
+	// same cursor with the default sorting must be rejected
+	_, err = service.ListWorkflows(&models.WorkflowListQuery{UserID: "user_1", Limit: 1, Cursor: page.NextCursor})
+	assert.ErrorIs(t, err, models.ErrWorkflowCursorInvalid)
+
+	_, err = service.ListWorkflows(&models.WorkflowListQuery{UserID: "user_1", Cursor: "not-a-cursor"})
+	assert.ErrorIs(t, err, models.ErrWorkflowCursorInvalid)
+}
+
+func TestWorkflowService_ListWorkflowsWithoutLimitReturnsAll(t *testing.T) {
+	rows := make([]models.Workflow, 0, models.MaxRowsFromDB+1)
+	for i := 0; i <= models.MaxRowsFromDB; i++ {
+		rows = append(rows, models.Workflow{UUID: fmt.Sprintf("wf_%d", i), UpdatedAt: "2024-03-01T00:00:00Z"})
+	}
+	httpRepo := mocks.NewWorkflowHTTPRepository(t)
+	httpRepo.On("GetWorkflowsPage", mock.Anything, (*models.WorkflowCursor)(nil), uint64(models.MaxRowsFromDB+1)).
+		Return(&models.InfoWorkflow{Data: rows}, nil).Once()
+	httpRepo.On("GetWorkflowsPage", mock.Anything, mock.MatchedBy(func(cursor *models.WorkflowCursor) bool {
+		return cursor != nil && cursor.ID == fmt.Sprintf("wf_%d", models.MaxRowsFromDB-1)
+	}), uint64(models.MaxRowsFromDB+1)).Return(&models.InfoWorkflow{Data: rows[models.MaxRowsFromDB:]}, nil).Once()
+
+	service := services.NewWorkflowService(mocks.NewWorkflowRedisRepoInterface(t), mocks.NewWorkflowBrokerRepository(t), services.NewUUIDService(), httpRepo, services.NewNodeService(), nil, nil)
+	page, err := service.ListWorkflows(&models.WorkflowListQuery{UserID: "user_1"})
+	assert.NoError(t, err)
+	assert.Len(t, page.Workflows, models.MaxRowsFromDB+1)
+	assert.False(t, page.HasMore)
+	assert.Empty(t, page.NextCursor)
+}
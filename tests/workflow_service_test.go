@@ -0,0 +1,231 @@
+package tests
+
+import (
+	"errors"
+	"minireipaz/mocks"
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/domain/services"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func strPtr(value string) *string {
+	return &value
+}
+
+func TestWorkflowService_DiffWorkflowRevisions(t *testing.T) {
+	userID, workflowID := "user_1", "wf_1"
+	from := models.Workflow{
+		Version: 1,
+		Nodes: []models.Node{
+			{ID: "initial-node", Type: "wrapperNode"},
+			{ID: "sheet-node", Type: "googlesheets"},
+			{ID: "old-node", Type: "notiontoken"},
+		},
+		Edges: []models.Edge{
+			{ID: strPtr("e1"), Source: strPtr("initial-node"), Target: strPtr("sheet-node")},
+			{ID: strPtr("e2"), Source: strPtr("sheet-node"), Target: strPtr("old-node")},
+		},
+	}
+	to := models.Workflow{
+		Version: 2,
+		Nodes: []models.Node{
+			{ID: "initial-node", Type: "wrapperNode"},
+			{ID: "sheet-node", Type: "googlesheets", Position: &models.Position{X: 10, Y: 20}},
+			{ID: "new-node", Type: "notionoauth"},
+		},
+		Edges: []models.Edge{
+			{ID: strPtr("e1"), Source: strPtr("initial-node"), Target: strPtr("sheet-node")},
+			{ID: strPtr("e3"), Source: strPtr("sheet-node"), Target: strPtr("new-node")},
+		},
+	}
+
+	redisRepo := mocks.NewWorkflowRedisRepoInterface(t)
+	httpRepo := mocks.NewWorkflowHTTPRepository(t)
+	redisRepo.On("ValidateWorkflowGlobalUUID", mock.Anything).Return(true)
+	httpRepo.On("GetWorkflowRevision", &userID, &workflowID, uint32(1)).Return(&models.InfoWorkflow{Data: []models.Workflow{from}}, nil)
+	httpRepo.On("GetWorkflowRevision", &userID, &workflowID, uint32(2)).Return(&models.InfoWorkflow{Data: []models.Workflow{to}}, nil)
+
+	service := services.NewWorkflowService(redisRepo, mocks.NewWorkflowBrokerRepository(t), services.NewUUIDService(), httpRepo, services.NewNodeService(), nil, nil)
+	diff, err := service.DiffWorkflowRevisions(&userID, &workflowID, 1, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(1), diff.FromVersion)
+	assert.Equal(t, uint32(2), diff.ToVersion)
+	assert.Len(t, diff.Nodes.Added, 1)
+	assert.Equal(t, "new-node", diff.Nodes.Added[0].ID)
+	assert.Len(t, diff.Nodes.Removed, 1)
+	assert.Equal(t, "old-node", diff.Nodes.Removed[0].ID)
+	assert.Len(t, diff.Nodes.Changed, 1)
+	assert.Equal(t, "sheet-node", diff.Nodes.Changed[0].ID)
+	assert.Len(t, diff.Edges.Added, 1)
+	assert.Equal(t, "e3", *diff.Edges.Added[0].ID)
+	assert.Len(t, diff.Edges.Removed, 1)
+	assert.Equal(t, "e2", *diff.Edges.Removed[0].ID)
+	assert.Empty(t, diff.Edges.Changed)
+}
+
+func TestWorkflowService_GetWorkflowRevisionNotFound(t *testing.T) {
+	userID, workflowID := "user_1", "wf_1"
+
+	redisRepo := mocks.NewWorkflowRedisRepoInterface(t)
+	httpRepo := mocks.NewWorkflowHTTPRepository(t)
+	redisRepo.On("ValidateWorkflowGlobalUUID", mock.Anything).Return(true)
+	httpRepo.On("GetWorkflowRevision", &userID, &workflowID, uint32(7)).Return(&models.InfoWorkflow{}, nil)
+
+	service := services.NewWorkflowService(redisRepo, mocks.NewWorkflowBrokerRepository(t), services.NewUUIDService(), httpRepo, services.NewNodeService(), nil, nil)
+	revision, err := service.GetWorkflowRevision(&userID, &workflowID, 7)
+
+	assert.ErrorIs(t, err, models.ErrRevisionNotFound)
+	assert.Nil(t, revision)
+}
+
+func TestWorkflowService_DuplicateWorkflow(t *testing.T) {
+	userID, workflowID := "user_1", "wf_1"
+	source := models.Workflow{
+		UUID:   workflowID,
+		UserID: userID,
+		Name:   "flow (copy)",
+		Nodes: []models.Node{
+			{ID: models.InitialNodeID, Data: &models.DataNode{WorkflowID: workflowID}},
+			{ID: "sheet", Data: &models.DataNode{
+				Type:           models.GoogleSheets,
+				WorkflowID:     workflowID,
+				CredentialData: models.RequestCreateCredential{ID: "cred_1"},
+			}},
+		},
+	}
+	isName := func(name string) interface{} {
+		return mock.MatchedBy(func(value *string) bool { return *value == name })
+	}
+
+	redisRepo := mocks.NewWorkflowRedisRepoInterface(t)
+	httpRepo := mocks.NewWorkflowHTTPRepository(t)
+	brokerRepo := mocks.NewWorkflowBrokerRepository(t)
+	redisRepo.On("ValidateWorkflowGlobalUUID", mock.Anything).Return(true)
+	redisRepo.On("AcquireLock", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+	redisRepo.On("RemoveLock", mock.Anything).Return(true)
+	httpRepo.On("GetWorkflowDataByID", &userID, &workflowID, uint64(1)).Return(&models.InfoWorkflow{Data: []models.Workflow{source}}, nil)
+	redisRepo.On("NameExists", &userID, isName("flow (copy)")).Return(true, nil)
+	redisRepo.On("NameExists", mock.Anything, mock.Anything).Return(false, nil)
+	redisRepo.On("ValidateUserWorkflowUUID", mock.Anything, mock.Anything).Return(false)
+	redisRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.OutboxMessage")).Return(true, false)
+	redisRepo.On("NextVersion", mock.Anything).Return(uint32(1), nil)
+	brokerRepo.On("CreateCommand", mock.Anything, models.CommandTypeCreate).Return(&models.OutboxMessage{Topic: "workflows.command"}, nil)
+
+	service := services.NewWorkflowService(redisRepo, brokerRepo, services.NewUUIDService(), httpRepo, services.NewNodeService(), nil, nil)
+	created, exist, clone, err := service.DuplicateWorkflow(&userID, &workflowID, false)
+
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.True(t, exist)
+	assert.Equal(t, "flow (copy 2)", clone.Name)
+	assert.NotEqual(t, workflowID, clone.UUID)
+	assert.Equal(t, clone.UUID, clone.Nodes[1].Data.WorkflowID)
+	assert.Equal(t, models.RequestCreateCredential{Type: models.GoogleSheets}, clone.Nodes[1].Data.CredentialData)
+	assert.Equal(t, workflowID, source.Nodes[1].Data.WorkflowID)
+}
+
+func TestWorkflowService_DuplicateWorkflowMultibyteName(t *testing.T) {
+	userID, workflowID := "user_1", "wf_1"
+	source := models.Workflow{UUID: workflowID, UserID: userID, Name: strings.Repeat("é", 127)}
+
+	redisRepo := mocks.NewWorkflowRedisRepoInterface(t)
+	httpRepo := mocks.NewWorkflowHTTPRepository(t)
+	brokerRepo := mocks.NewWorkflowBrokerRepository(t)
+	redisRepo.On("ValidateWorkflowGlobalUUID", mock.Anything).Return(true)
+	redisRepo.On("AcquireLock", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+	redisRepo.On("RemoveLock", mock.Anything).Return(true)
+	httpRepo.On("GetWorkflowDataByID", &userID, &workflowID, uint64(1)).Return(&models.InfoWorkflow{Data: []models.Workflow{source}}, nil)
+	redisRepo.On("NameExists", mock.Anything, mock.Anything).Return(false, nil)
+	redisRepo.On("ValidateUserWorkflowUUID", mock.Anything, mock.Anything).Return(false)
+	redisRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.OutboxMessage")).Return(true, false)
+	redisRepo.On("NextVersion", mock.Anything).Return(uint32(1), nil)
+	brokerRepo.On("CreateCommand", mock.Anything, models.CommandTypeCreate).Return(&models.OutboxMessage{Topic: "workflows.command"}, nil)
+
+	service := services.NewWorkflowService(redisRepo, brokerRepo, services.NewUUIDService(), httpRepo, services.NewNodeService(), nil, nil)
+	created, _, clone, err := service.DuplicateWorkflow(&userID, &workflowID, false)
+
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.True(t, utf8.ValidString(clone.Name))
+	assert.LessOrEqual(t, len(clone.Name), models.MaxWorkflowNameLength)
+	assert.Equal(t, strings.Repeat("é", 124)+" (copy)", clone.Name)
+}
+
+func TestWorkflowService_DuplicateWorkflowStopsOnRedisError(t *testing.T) {
+	userID, workflowID := "user_1", "wf_1"
+
+	redisRepo := mocks.NewWorkflowRedisRepoInterface(t)
+	httpRepo := mocks.NewWorkflowHTTPRepository(t)
+	redisRepo.On("ValidateWorkflowGlobalUUID", mock.Anything).Return(true)
+	redisRepo.On("AcquireLock", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+	redisRepo.On("RemoveLock", mock.Anything).Return(true)
+	httpRepo.On("GetWorkflowDataByID", &userID, &workflowID, uint64(1)).Return(&models.InfoWorkflow{Data: []models.Workflow{{UUID: workflowID, UserID: userID, Name: "flow"}}}, nil)
+	redisRepo.On("NameExists", mock.Anything, mock.Anything).Return(false, errors.New("redis down")).Once()
+
+	service := services.NewWorkflowService(redisRepo, mocks.NewWorkflowBrokerRepository(t), services.NewUUIDService(), httpRepo, services.NewNodeService(), nil, nil)
+	created, exist, _, err := service.DuplicateWorkflow(&userID, &workflowID, false)
+
+	assert.Error(t, err)
+	assert.False(t, created)
+	assert.True(t, exist)
+	redisRepo.AssertNumberOfCalls(t, "NameExists", 1)
+}
+
+func TestWorkflowService_RollbackWorkflow(t *testing.T) {
+	userID, workflowID := "user_1", "wf_1"
+	// ClickHouse has not consumed the last update yet, redis is at version 3
+	current := models.Workflow{UUID: workflowID, UserID: userID, Name: "flow", Version: 2, Description: "now",
+		Nodes: []models.Node{{ID: models.InitialNodeID}, {ID: "sheet"}}}
+	revision := models.Workflow{UUID: workflowID, UserID: userID, Name: "flow", Version: 1, Description: "before",
+		Nodes: []models.Node{{ID: models.InitialNodeID}}}
+
+	redisRepo := mocks.NewWorkflowRedisRepoInterface(t)
+	httpRepo := mocks.NewWorkflowHTTPRepository(t)
+	brokerRepo := mocks.NewWorkflowBrokerRepository(t)
+	redisRepo.On("ValidateWorkflowGlobalUUID", mock.Anything).Return(true)
+	redisRepo.On("AcquireLock", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+	redisRepo.On("RemoveLock", mock.Anything).Return(true)
+	httpRepo.On("GetWorkflowDataByID", &userID, &workflowID, uint64(1)).Return(&models.InfoWorkflow{Data: []models.Workflow{current}}, nil)
+	httpRepo.On("GetWorkflowRevision", &userID, &workflowID, uint32(1)).Return(&models.InfoWorkflow{Data: []models.Workflow{revision}}, nil)
+	redisRepo.On("GetVersion", &workflowID).Return(uint32(3), nil)
+	redisRepo.On("GetLifecycle", &workflowID).Return(models.Active, nil)
+	redisRepo.On("CompareAndIncrVersion", &workflowID, uint32(3)).Return(uint32(4), true, nil)
+	// the graph of the revision is saved as a new version, it does not replace the history
+	message := &models.OutboxMessage{Topic: "workflows.command"}
+	brokerRepo.On("CreateCommand", mock.MatchedBy(func(workflow *models.Workflow) bool {
+		return workflow.Version == 4 && workflow.Description == "before" && len(workflow.Nodes) == 1
+	}), models.CommandTypeUpdate).Return(message, nil)
+	redisRepo.On("RenameInIndex", mock.Anything, message).Return("", nil)
+
+	service := services.NewWorkflowService(redisRepo, brokerRepo, services.NewUUIDService(), httpRepo, services.NewNodeService(), nil, nil)
+	updated, exist, err := service.RollbackWorkflow(&userID, &workflowID, 1)
+
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	assert.True(t, exist)
+}
+
+func TestWorkflowService_RollbackWorkflowRevisionNotFound(t *testing.T) {
+	userID, workflowID := "user_1", "wf_1"
+
+	redisRepo := mocks.NewWorkflowRedisRepoInterface(t)
+	httpRepo := mocks.NewWorkflowHTTPRepository(t)
+	redisRepo.On("ValidateWorkflowGlobalUUID", mock.Anything).Return(true)
+	redisRepo.On("AcquireLock", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+	redisRepo.On("RemoveLock", mock.Anything).Return(true)
+	httpRepo.On("GetWorkflowDataByID", &userID, &workflowID, uint64(1)).Return(&models.InfoWorkflow{Data: []models.Workflow{{UUID: workflowID, UserID: userID}}}, nil)
+	httpRepo.On("GetWorkflowRevision", &userID, &workflowID, uint32(9)).Return(&models.InfoWorkflow{}, nil)
+
+	service := services.NewWorkflowService(redisRepo, mocks.NewWorkflowBrokerRepository(t), services.NewUUIDService(), httpRepo, services.NewNodeService(), nil, nil)
+	updated, exist, err := service.RollbackWorkflow(&userID, &workflowID, 9)
+
+	assert.NoError(t, err)
+	assert.False(t, updated)
+	assert.False(t, exist)
+}
@@ -0,0 +1,232 @@
+package tests
+
+import (
+	"minireipaz/mocks"
+	"minireipaz/pkg/domain/models"
+	"minireipaz/pkg/domain/services"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTrashedWorkflow(workflowID string, expiresAt time.Time) models.TrashedWorkflow {
+	return models.TrashedWorkflow{
+		UUID:      workflowID,
+		UserID:    "user_1",
+		Name:      "flow " + workflowID,
+		DeletedAt: expiresAt.Add(-models.TrashRetention).Format(models.LayoutTimestamp),
+		ExpiresAt: expiresAt.Format(models.LayoutTimestamp),
+		Workflow: &models.Workflow{UUID: workflowID, UserID: "user_1", Name: "flow " + workflowID,
+			Nodes: []models.Node{{ID: models.InitialNodeID}}, Viewport: &models.Viewport{}},
+	}
+}
+
+func TestWorkflowService_DeleteWorkflow(t *testing.T) {
+	userID, workflowID := "user_1", "wf_1"
+	stored := models.Workflow{UUID: workflowID, UserID: userID, Name: "flow", DirectoryToSave: "sales", Nodes: []models.Node{{ID: models.InitialNodeID}}}
+	redisRepo, httpRepo, brokerRepo := newLifecycleMocks(t, stored)
+	message := &models.OutboxMessage{Topic: "workflows.command"}
+	brokerRepo.On("CreateCommand", mock.Anything, models.CommandTypeDelete).Return(message, nil)
+	redisRepo.On("MoveToTrash", mock.MatchedBy(func(trashed *models.TrashedWorkflow) bool {
+		deletedAt, _ := time.Parse(models.LayoutTimestamp, trashed.DeletedAt)
+		expiresAt, _ := time.Parse(models.LayoutTimestamp, trashed.ExpiresAt)
+		return trashed.Name == "flow" && trashed.DirectoryToSave == "sales" && expiresAt.Sub(deletedAt) == models.TrashRetention &&
+			trashed.Workflow != nil && len(trashed.Workflow.Nodes) == 1
+	}), message).Return(true, nil)
+
+	service := services.NewWorkflowService(redisRepo, brokerRepo, services.NewUUIDService(), httpRepo, services.NewNodeService(), nil, nil)
+	deleted, exist := service.DeleteWorkflow(&userID, &workflowID)
+
+	assert.True(t, deleted)
+	assert.True(t, exist)
+}
+
+func TestWorkflowService_DeleteWorkflowNotFound(t *testing.T) {
+	userID, workflowID := "user_1", "wf_1"
+	redisRepo := mocks.NewWorkflowRedisRepoInterface(t)
+	redisRepo.On("ValidateWorkflowGlobalUUID", &workflowID).Return(false)
+
+	service := services.NewWorkflowService(redisRepo, mocks.NewWorkflowBrokerRepository(t), services.NewUUIDService(), mocks.NewWorkflowHTTPRepository(t), nil, nil, nil)
+	deleted, exist := service.DeleteWorkflow(&userID, &workflowID)
+
+	assert.False(t, deleted)
+	assert.False(t, exist)
+}
+
+func TestWorkflowService_GetWorkflowsTrashHidesExpired(t *testing.T) {
+	userID := "user_1"
+	fresh := newTrashedWorkflow("wf_fresh", time.Now().Add(time.Hour))
+	expired := newTrashedWorkflow("wf_expired", time.Now().Add(-time.Hour))
+	redisRepo := mocks.NewWorkflowRedisRepoInterface(t)
+	brokerRepo := mocks.NewWorkflowBrokerRepository(t)
+	redisRepo.On("GetTrash", &userID).Return([]models.TrashedWorkflow{fresh, expired}, nil)
+
+	service := services.NewWorkflowService(redisRepo, brokerRepo, services.NewUUIDService(), mocks.NewWorkflowHTTPRepository(t), nil, nil, nil)
+	trashed, err := service.GetWorkflowsTrash(&userID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.TrashedWorkflow{fresh}, trashed)
+	// reading the trash never purges
+	brokerRepo.AssertNotCalled(t, "CreateCommand", mock.Anything, mock.Anything)
+	redisRepo.AssertNotCalled(t, "RemoveFromTrash", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestWorkflowService_RestoreWorkflow(t *testing.T) {
+	userID, workflowID := "user_1", "wf_1"
+	trashed := newTrashedWorkflow(workflowID, time.Now().Add(time.Hour))
+
+	t.Run("Success - Triggers follow the stored state", func(t *testing.T) {
+		redisRepo := mocks.NewWorkflowRedisRepoInterface(t)
+		brokerRepo := mocks.NewWorkflowBrokerRepository(t)
+		triggers := mocks.NewWorkflowTriggers(t)
+		redisRepo.On("GetTrash", &userID).Return([]models.TrashedWorkflow{trashed}, nil)
+		message := &models.OutboxMessage{Topic: "workflows.command"}
+		// the command carries the graph, not only the fields of the listing
+		brokerRepo.On("CreateCommand", mock.MatchedBy(func(workflow *models.Workflow) bool {
+			return workflow.Name == trashed.Name && len(workflow.Nodes) == 1 && workflow.Viewport != nil
+		}), models.CommandTypeRestore).Return(message, nil)
+		redisRepo.On("RestoreFromTrash", &userID, &workflowID, message).Return(&trashed, nil)
+		redisRepo.On("ValidateWorkflowGlobalUUID", &workflowID).Return(true)
+		redisRepo.On("GetLifecycle", &workflowID).Return(models.Paused, nil)
+		triggers.On("SyncTriggers", &workflowID, false).Return(nil).Once()
+
+		service := services.NewWorkflowService(redisRepo, brokerRepo, services.NewUUIDService(), mocks.NewWorkflowHTTPRepository(t), nil, nil, nil)
+		service.RegisterTriggers(triggers)
+		restored, err := service.RestoreWorkflow(&userID, &workflowID)
+
+		assert.NoError(t, err)
+		assert.True(t, restored)
+	})
+
+	t.Run("Success - Entry without copy reads the graph", func(t *testing.T) {
+		legacy := newTrashedWorkflow(workflowID, time.Now().Add(time.Hour))
+		legacy.Workflow = nil
+		redisRepo := mocks.NewWorkflowRedisRepoInterface(t)
+		brokerRepo := mocks.NewWorkflowBrokerRepository(t)
+		httpRepo := mocks.NewWorkflowHTTPRepository(t)
+		redisRepo.On("GetTrash", &userID).Return([]models.TrashedWorkflow{legacy}, nil)
+		httpRepo.On("GetWorkflowDataByID", &userID, &workflowID, uint64(1)).Return(&models.InfoWorkflow{Data: []models.Workflow{
+			{UUID: workflowID, UserID: userID, Name: legacy.Name, Nodes: []models.Node{{ID: models.InitialNodeID}}},
+		}}, nil)
+		message := &models.OutboxMessage{Topic: "workflows.command"}
+		brokerRepo.On("CreateCommand", mock.MatchedBy(func(workflow *models.Workflow) bool {
+			return len(workflow.Nodes) == 1
+		}), models.CommandTypeRestore).Return(message, nil)
+		redisRepo.On("RestoreFromTrash", &userID, &workflowID, message).Return(&legacy, nil)
+		redisRepo.On("ValidateWorkflowGlobalUUID", &workflowID).Return(true)
+		redisRepo.On("GetLifecycle", &workflowID).Return(models.Active, nil)
+
+		service := services.NewWorkflowService(redisRepo, brokerRepo, services.NewUUIDService(), httpRepo, nil, nil, nil)
+		restored, err := service.RestoreWorkflow(&userID, &workflowID)
+
+		assert.NoError(t, err)
+		assert.True(t, restored)
+	})
+
+	t.Run("Error - Expired entry waits for the purge", func(t *testing.T) {
+		redisRepo := mocks.NewWorkflowRedisRepoInterface(t)
+		redisRepo.On("GetTrash", &userID).Return([]models.TrashedWorkflow{newTrashedWorkflow(workflowID, time.Now().Add(-time.Hour))}, nil)
+
+		service := services.NewWorkflowService(redisRepo, mocks.NewWorkflowBrokerRepository(t), services.NewUUIDService(), mocks.NewWorkflowHTTPRepository(t), nil, nil, nil)
+		restored, err := service.RestoreWorkflow(&userID, &workflowID)
+
+		assert.ErrorIs(t, err, models.ErrWorkflowNotInTrash)
+		assert.False(t, restored)
+		redisRepo.AssertNotCalled(t, "RestoreFromTrash", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - Name taken keeps it in trash", func(t *testing.T) {
+		redisRepo := mocks.NewWorkflowRedisRepoInterface(t)
+		brokerRepo := mocks.NewWorkflowBrokerRepository(t)
+		triggers := mocks.NewWorkflowTriggers(t)
+		message := &models.OutboxMessage{Topic: "workflows.command"}
+		redisRepo.On("GetTrash", &userID).Return([]models.TrashedWorkflow{trashed}, nil)
+		brokerRepo.On("CreateCommand", mock.Anything, models.CommandTypeRestore).Return(message, nil)
+		// the command is only written when the entry leaves the trash
+		redisRepo.On("RestoreFromTrash", &userID, &workflowID, message).Return(nil, models.ErrWorkflowNameExist)
+
+		service := services.NewWorkflowService(redisRepo, brokerRepo, services.NewUUIDService(), mocks.NewWorkflowHTTPRepository(t), nil, nil, nil)
+		service.RegisterTriggers(triggers)
+		restored, err := service.RestoreWorkflow(&userID, &workflowID)
+
+		assert.ErrorIs(t, err, models.ErrWorkflowNameExist)
+		assert.False(t, restored)
+		triggers.AssertNotCalled(t, "SyncTriggers", mock.Anything, mock.Anything)
+	})
+}
+
+func TestWorkflowService_PurgeWorkflow(t *testing.T) {
+	userID, workflowID := "user_1", "wf_1"
+	trashed := newTrashedWorkflow(workflowID, time.Now().Add(time.Hour))
+
+	t.Run("Error - Not in trash", func(t *testing.T) {
+		redisRepo := mocks.NewWorkflowRedisRepoInterface(t)
+		redisRepo.On("GetTrash", &userID).Return([]models.TrashedWorkflow{}, nil)
+
+		service := services.NewWorkflowService(redisRepo, mocks.NewWorkflowBrokerRepository(t), services.NewUUIDService(), mocks.NewWorkflowHTTPRepository(t), nil, nil, nil)
+		purged, exist := service.PurgeWorkflow(&userID, &workflowID)
+
+		assert.False(t, purged)
+		assert.False(t, exist)
+	})
+
+	t.Run("Skip - Purged by another request", func(t *testing.T) {
+		redisRepo := mocks.NewWorkflowRedisRepoInterface(t)
+		brokerRepo := mocks.NewWorkflowBrokerRepository(t)
+		triggers := mocks.NewWorkflowTriggers(t)
+		message := &models.OutboxMessage{Topic: "workflows.command"}
+		redisRepo.On("GetTrash", &userID).Return([]models.TrashedWorkflow{trashed}, nil)
+		brokerRepo.On("CreateCommand", mock.Anything, models.CommandTypePurge).Return(message, nil)
+		redisRepo.On("RemoveFromTrash", &userID, &workflowID, message).Return(false, nil)
+
+		service := services.NewWorkflowService(redisRepo, brokerRepo, services.NewUUIDService(), mocks.NewWorkflowHTTPRepository(t), nil, nil, nil)
+		service.RegisterTriggers(triggers)
+		purged, exist := service.PurgeWorkflow(&userID, &workflowID)
+
+		assert.False(t, purged)
+		assert.True(t, exist)
+		triggers.AssertNotCalled(t, "RemoveTriggers", mock.Anything)
+	})
+}
+
+func TestWorkflowService_PurgeExpiredTrash(t *testing.T) {
+	now := time.Now().UTC()
+	userA, userB := "user_a", "user_b"
+	expired := newTrashedWorkflow("wf_expired", now.Add(-time.Minute))
+	fresh := newTrashedWorkflow("wf_fresh", now.Add(time.Minute))
+
+	t.Run("Success - Only expired entries are purged", func(t *testing.T) {
+		redisRepo := mocks.NewWorkflowRedisRepoInterface(t)
+		brokerRepo := mocks.NewWorkflowBrokerRepository(t)
+		triggers := mocks.NewWorkflowTriggers(t)
+		redisRepo.On("AcquireLock", models.TrashPurgeLockKey, "_", models.TrashPurgeLockTTL).Return(true, nil)
+		redisRepo.On("GetTrashUsers").Return([]string{userA, userB}, nil)
+		redisRepo.On("GetTrash", &userA).Return([]models.TrashedWorkflow{expired, fresh}, nil)
+		redisRepo.On("GetTrash", &userB).Return([]models.TrashedWorkflow{}, nil)
+		message := &models.OutboxMessage{Topic: "workflows.command"}
+		brokerRepo.On("CreateCommand", mock.MatchedBy(func(workflow *models.Workflow) bool { return workflow.UUID == expired.UUID }), models.CommandTypePurge).Return(message, nil).Once()
+		redisRepo.On("RemoveFromTrash", &expired.UserID, &expired.UUID, message).Return(true, nil).Once()
+		triggers.On("RemoveTriggers", &expired.UUID).Return(nil).Once()
+
+		service := services.NewWorkflowService(redisRepo, brokerRepo, services.NewUUIDService(), mocks.NewWorkflowHTTPRepository(t), nil, nil, nil)
+		service.RegisterTriggers(triggers)
+		purged, err := service.PurgeExpiredTrash(now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, purged)
+	})
+
+	t.Run("Skip - Another instance is purging", func(t *testing.T) {
+		redisRepo := mocks.NewWorkflowRedisRepoInterface(t)
+		redisRepo.On("AcquireLock", models.TrashPurgeLockKey, "_", models.TrashPurgeLockTTL).Return(false, nil)
+
+		service := services.NewWorkflowService(redisRepo, mocks.NewWorkflowBrokerRepository(t), services.NewUUIDService(), mocks.NewWorkflowHTTPRepository(t), nil, nil, nil)
+		purged, err := service.PurgeExpiredTrash(now)
+
+		assert.NoError(t, err)
+		assert.Zero(t, purged)
+		redisRepo.AssertNotCalled(t, "GetTrashUsers")
+	})
+}